@@ -5,8 +5,21 @@ import (
 	"archv1/internal/pkg/config"
 	"archv1/internal/pkg/repo/cache"
 	"archv1/internal/pkg/repo/postgres"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/upload"
+	"archv1/internal/push"
+	chatRepo "archv1/internal/repository/postgres/chat"
+	fileStoreRepo "archv1/internal/repository/postgres/fileStore"
+	pushRepo "archv1/internal/repository/postgres/push"
+	"archv1/internal/retention"
 	"archv1/internal/router"
+	chatService "archv1/internal/service/chat"
+	fileStoreService "archv1/internal/service/fileStore"
+	pushService "archv1/internal/service/push"
+	"archv1/internal/sweeper"
+	fileStoreUseCase "archv1/internal/usecase/fileStore"
 	"archv1/internal/websocket"
+	"context"
 	"fmt"
 	"log"
 )
@@ -14,25 +27,42 @@ import (
 func main() {
 	cfg := config.NewConfig()
 
-	hub := websocket.NewHub()
-
 	redisClient, err := cache.NewRedis(cfg)
 	fmt.Println(redisClient, err)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	go hub.Run()
+	hub := websocket.NewHub(cache.NewEventLog(redisClient, cfg))
 
 	psql := postgres.NewDB(cfg)
 	enforcer := casbin.NewEnforcer(cfg)
 
+	blobStore, err := storage.NewBlobStore(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pushWorker, err := push.NewWorker(pushService.NewPushService(pushRepo.NewPushRepo(psql)), cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go pushWorker.Run(context.Background())
+
+	go retention.NewPurger(chatService.NewChatService(chatRepo.NewChatRepo(psql)), cfg).Run(context.Background())
+
+	fileStore := fileStoreUseCase.NewFilesStoreUseCase(
+		fileStoreService.NewFilesStoreService(fileStoreRepo.NewFileStoreRepo(psql)), blobStore, upload.NewValidator(cfg), cfg)
+	go sweeper.NewUploadSweeper(fileStore, cfg).Run(context.Background())
+	go sweeper.NewBlobVerifier(fileStore, cfg).Run(context.Background())
+
 	engine := router.New(&router.Router{
 		RedisCache: redisClient,
 		Conf:       cfg,
 		Hub:        hub,
 		PostgresDB: psql,
 		Enforcer:   enforcer,
+		BlobStore:  blobStore,
 	})
 
 	if err := engine.Run(fmt.Sprintf("%s:%s", cfg.HttpHost, cfg.HttpPort)); err != nil {
@@ -6,12 +6,16 @@ import (
 	handle "archv1/internal/pkg/errors"
 	"archv1/internal/pkg/repo/cache"
 	"archv1/internal/pkg/repo/postgres"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/tokens"
+	"archv1/internal/pkg/upload"
 	"archv1/internal/pkg/utils"
 	"archv1/internal/usecase/chat"
+	"archv1/internal/usecase/fileStore"
 	"archv1/internal/usecase/user"
 	"archv1/internal/websocket"
 	"context"
-	"encoding/json"
+	"errors"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
@@ -28,6 +32,9 @@ type ChatController struct {
 	Enforcer     *casbin.Enforcer
 	ChatUseCaseI chat.ChatUseCaseI
 	UserUseCase  user.UserUseCaseI
+	BlobStore    storage.BlobStore
+	Uploads      *upload.Validator
+	FileStore    fileStore.FilesStoreUseCaseI
 }
 
 func NewChatController(ch *ChatController) *ChatController {
@@ -39,9 +46,42 @@ func NewChatController(ch *ChatController) *ChatController {
 		Enforcer:     ch.Enforcer,
 		ChatUseCaseI: ch.ChatUseCaseI,
 		UserUseCase:  ch.UserUseCase,
+		BlobStore:    ch.BlobStore,
+		Uploads:      ch.Uploads,
+		FileStore:    ch.FileStore,
 	}
 }
 
+// Connect
+// @Summary 		Chat Websocket
+// @Description 	This API for opening the chat websocket, frames are described by entity.Frame
+// @Tags			chat
+// @Param 			token query string true "Access Token"
+// @Param 			since query int false "Replay events after this sequence number"
+// @Success 		101
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Router 			/ws [GET]
+func (ch *ChatController) Connect(c *gin.Context) {
+	claims, err := tokens.ExtractClaim(c.Query("token"), []byte(ch.Conf.JWTSecret))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var since *int64
+	if c.Query("since") != "" {
+		seq, err := strconv.ParseInt(c.Query("since"), 10, 64)
+		if err != nil {
+			handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		since = &seq
+	}
+
+	websocket.HandleConnection(ch.Hub, c.Writer, c.Request, cast.ToInt(claims["sub"]), since)
+}
+
 // UserGroups
 // @Security 		BearerAuth
 // @Summary 		User Groups
@@ -77,7 +117,7 @@ func (ch *ChatController) UserGroups(c *gin.Context) {
 // GetGroup
 // @Security 		BearerAuth
 // @Summary 		Get Group
-// @Description 	This API for getting group with id
+// @Description 	This API for getting group with id, a private group only for its members
 // @Tags			chat
 // @Accept 			json
 // @Produce 		json
@@ -97,9 +137,15 @@ func (ch *ChatController) GetGroup(c *gin.Context) {
 		return
 	}
 
-	group, err := ch.ChatUseCaseI.GetGroup(context.Background(), int64(groupID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	group, err := ch.ChatUseCaseI.GetGroup(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -177,7 +223,7 @@ func (ch *ChatController) UpdateGroup(c *gin.Context) {
 
 	response, err := ch.ChatUseCaseI.UpdateGroup(context.Background(), request)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -212,11 +258,16 @@ func (ch *ChatController) UpdateGroupColumns(c *gin.Context) {
 		return
 	}
 
+	if request.Fields == nil {
+		request.Fields = make(map[string]string)
+	}
+
+	request.UpdatedBy = cast.ToInt(claims["sub"])
 	request.Fields["updated_by"] = cast.ToString(claims["sub"])
 
 	response, err := ch.ChatUseCaseI.UpdateGroupColumns(context.Background(), request)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -256,7 +307,7 @@ func (ch *ChatController) DeleteGroup(c *gin.Context) {
 
 	response, err := ch.ChatUseCaseI.DeleteGroup(context.Background(), int64(groupID), int64(deletedBy))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -266,7 +317,7 @@ func (ch *ChatController) DeleteGroup(c *gin.Context) {
 // AddUserToGroup
 // @Security 		BearerAuth
 // @Summary 		Add User to Group
-// @Description 	This API for adding a new user to group
+// @Description 	This API for adding a new user to group, any member allowed to invite can add users
 // @Tags			chat
 // @Accept 			json
 // @Produce 		json
@@ -291,9 +342,15 @@ func (ch *ChatController) AddUserToGroup(c *gin.Context) {
 		return
 	}
 
-	err = ch.ChatUseCaseI.AddUserToGroup(context.Background(), int64(userID), int64(groupID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = ch.ChatUseCaseI.AddUserToGroup(context.Background(), cast.ToInt64(claims["sub"]), int64(userID), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -305,7 +362,7 @@ func (ch *ChatController) AddUserToGroup(c *gin.Context) {
 // RemoveUserFromGroup
 // @Security 		BearerAuth
 // @Summary 		Remove User from Group
-// @Description 	This API for removing user from group
+// @Description 	This API for removing a member ranked below the current user from group, or leaving the group with the own user id
 // @Tags			chat
 // @Accept 			json
 // @Produce 		json
@@ -330,9 +387,15 @@ func (ch *ChatController) RemoveUserFromGroup(c *gin.Context) {
 		return
 	}
 
-	err = ch.ChatUseCaseI.RemoveUserFromGroup(context.Background(), int64(userID), int64(groupID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = ch.ChatUseCaseI.RemoveUserFromGroup(context.Background(), cast.ToInt64(claims["sub"]), int64(userID), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
@@ -341,621 +404,621 @@ func (ch *ChatController) RemoveUserFromGroup(c *gin.Context) {
 	})
 }
 
-// UserChats
+// GroupMembers
 // @Security 		BearerAuth
-// @Summary 		User Chats
-// @Description 	This API for getting user chats
+// @Summary 		Group Members
+// @Description 	This API for getting members of a group with their roles
 // @Tags			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			id path int true "User ID"
-// @Success 		200 {object} entity.UserChatsResponse
+// @Param 			id path int true "Group ID"
+// @Success 		200 {object} entity.GroupMembersResponse
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
-// @Failure 		404 {object} errors.Error
-// @Router 			/v1/group/user-chats [GET]
-func (ch *ChatController) UserChats(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Param("id"))
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/members [GET]
+func (ch *ChatController) GroupMembers(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	chats, err := ch.ChatUseCaseI.UserChats(context.Background(), int64(userID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, chats)
+	response, err := ch.ChatUseCaseI.GroupMembers(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// DeleteChat
+// SetGroupRole
 // @Security 		BearerAuth
-// @Summary 		Delete Chat
-// @Description 	This API for deleting chat with id
+// @Summary 		Set Group Role
+// @Description 	This API for changing the role of a group member to admin, moderator or member
 // @Tags			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			id path int true "Chat ID"
-// @Success 		200 {object} entity.ResponseWithMessage
+// @Param 			request body entity.SetGroupRoleRequest true "Set Group Role Model"
+// @Success 		200 {object} entity.ResponseWithStatus
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
 // @Failure 		404 {object} errors.Error
-// @Router 			/v1/group/delete-chat [DELETE]
-func (ch *ChatController) DeleteChat(c *gin.Context) {
-	chatID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/role [PUT]
+func (ch *ChatController) SetGroupRole(c *gin.Context) {
+	var request entity.SetGroupRoleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err = ch.ChatUseCaseI.DeleteChat(context.Background(), int64(chatID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, entity.ResponseWithMessage{
-		Message: "success",
+	request.Role = strings.ToLower(request.Role)
+
+	if err := ch.ChatUseCaseI.SetGroupRole(context.Background(), cast.ToInt64(claims["sub"]), request); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
 	})
 }
 
-// SendMessage
-// @Security		BearerAuth
-// @Summary 		Send Message
-// @Description 	This API for sending a message to chat
-// @Tags 			chat
+// TransferGroupOwnership
+// @Security 		BearerAuth
+// @Summary 		Transfer Group Ownership
+// @Description 	This API for handing a group over to another member, the previous owner becomes an admin
+// @Tags			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			send body entity.SendMessageRequest true "Send Message Model"
+// @Param 			request body entity.TransferOwnershipRequest true "Transfer Ownership Model"
 // @Success 		200 {object} entity.ResponseWithStatus
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
 // @Failure 		500 {object} errors.Error
-// @Router 			/v1/send-message [POST]
-func (ch *ChatController) SendMessage(c *gin.Context) {
-	var message entity.SendMessageRequest
+// @Router 			/v1/group/transfer-ownership [POST]
+func (ch *ChatController) TransferGroupOwnership(c *gin.Context) {
+	var request entity.TransferOwnershipRequest
 
-	if err := c.ShouldBindJSON(&message); err != nil {
+	if err := c.ShouldBindJSON(&request); err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	message.ChatType = strings.ToLower(message.ChatType)
-	if message.ChatType != "private" && message.ChatType != "group" {
-		handle.ErrorResponse(c, http.StatusBadRequest, "property chat type must be 'private' or 'group'")
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	if message.ChatID == 0 {
-		newChat, err := ch.ChatUseCaseI.CreateChat(
-			context.Background(),
-			int64(message.Receiver),
-			int64(message.Sender),
-			message.ChatType,
-		)
-
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		message.ChatID = newChat.ChatId
-	}
-
-	if err := ch.ChatUseCaseI.SendMessage(context.Background(), message); err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	if err := ch.ChatUseCaseI.TransferGroupOwnership(context.Background(), cast.ToInt64(claims["sub"]), request); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
-	if message.ChatType == "private" {
-		userResponse, err := ch.UserUseCase.GetByID(context.Background(), message.Receiver)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		var cacheData entity.NotificationsResponse
-
-		cacheNotification, err := ch.RedisCache.Get(context.Background(), userResponse.Username)
-		if err != nil {
-			cacheData = entity.NotificationsResponse{}
-		} else {
-			err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-
-		var isUpdate = false
-		for _, notification := range cacheData.Notifications {
-			if notification.ChatID == message.ChatID {
-				notification.LatestMessage = message.Message
-				notification.LatestSender = message.Sender
-				notification.TotalMessagesCount = notification.TotalMessagesCount + 1
-				isUpdate = true
-				break
-			}
-		}
-
-		if isUpdate == false {
-			cacheData.Notifications = append(cacheData.Notifications, struct {
-				ChatID             int    `json:"chat_id"`
-				ChatType           string `json:"chat_type"`
-				LatestSender       int    `json:"latest_sender"`
-				LatestMessage      string `json:"latest_message"`
-				TotalMessagesCount int    `json:"total_messages_count"`
-			}{
-				ChatID:             message.ChatID,
-				ChatType:           message.ChatType,
-				LatestSender:       message.Sender,
-				LatestMessage:      message.Message,
-				TotalMessagesCount: 1,
-			})
-		}
-
-		sendingData, err := json.Marshal(&entity.SendMessageData{
-			Action: "new_message",
-			Property: struct {
-				ChatID      int    `json:"chat_id"`
-				ChatType    string `json:"chat_type"`
-				Message     string `json:"message"`
-				MessageType string `json:"message_type"`
-				Sender      int    `json:"sender"`
-				Receiver    int    `json:"receiver"`
-			}{
-				ChatID:      message.ChatID,
-				ChatType:    message.ChatType,
-				Message:     message.Message,
-				MessageType: message.MessageType,
-				Sender:      message.Sender,
-				Receiver:    message.Receiver,
-			},
-		})
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		isSend := false
-		for connection := range ch.Hub.Connections {
-			if connection.Username == userResponse.Username {
-				connection.Send <- sendingData
-				isSend = true
-				break
-			}
-		}
-
-		if isSend == false {
-			err := ch.RedisCache.Set(context.Background(), userResponse.Username, cacheData, 0)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-	} else {
-		groupResponse, err := ch.ChatUseCaseI.GetGroup(context.Background(), int64(message.Receiver))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		groupUsers, err := ch.ChatUseCaseI.GroupUsers(context.Background(), int64(groupResponse.GroupId))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		for _, gettingUser := range groupUsers {
-			var cacheData entity.NotificationsResponse
-
-			cacheNotification, err := ch.RedisCache.Get(context.Background(), gettingUser.Username)
-			if err != nil {
-				cacheData = entity.NotificationsResponse{}
-			} else {
-				err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-				if err != nil {
-					handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-
-			var isUpdate = false
-			for _, notification := range cacheData.Notifications {
-				if notification.ChatID == message.ChatID {
-					notification.LatestMessage = message.Message
-					notification.LatestSender = message.Sender
-					notification.TotalMessagesCount = notification.TotalMessagesCount + 1
-					isUpdate = true
-					break
-				}
-			}
-
-			if isUpdate == false {
-				cacheData.Notifications = append(cacheData.Notifications, struct {
-					ChatID             int    `json:"chat_id"`
-					ChatType           string `json:"chat_type"`
-					LatestSender       int    `json:"latest_sender"`
-					LatestMessage      string `json:"latest_message"`
-					TotalMessagesCount int    `json:"total_messages_count"`
-				}{
-					ChatID:             message.ChatID,
-					ChatType:           message.ChatType,
-					LatestSender:       message.Sender,
-					LatestMessage:      message.Message,
-					TotalMessagesCount: 1,
-				})
-			}
-
-			sendingData, err := json.Marshal(&entity.SendMessageData{
-				Action: "new_message",
-				Property: struct {
-					ChatID      int    `json:"chat_id"`
-					ChatType    string `json:"chat_type"`
-					Message     string `json:"message"`
-					MessageType string `json:"message_type"`
-					Sender      int    `json:"sender"`
-					Receiver    int    `json:"receiver"`
-				}{
-					ChatID:      message.ChatID,
-					ChatType:    message.ChatType,
-					Message:     message.Message,
-					MessageType: message.MessageType,
-					Sender:      message.Sender,
-					Receiver:    message.Receiver,
-				},
-			})
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-
-			isSend := false
-			for connection := range ch.Hub.Connections {
-				if gettingUser.Username == connection.Username {
-					connection.Send <- sendingData
-					isSend = true
-					break
-				}
-			}
-
-			if isSend == false {
-				err := ch.RedisCache.Set(context.Background(), gettingUser.Username, cacheData, 0)
-				if err != nil {
-					handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-		}
-	}
-
 	c.JSON(http.StatusOK, entity.ResponseWithStatus{
 		Status: true,
 	})
 }
 
-// UpdateMessage
-// @Security		BearerAuth
-// @Summary 		Update Message
-// @Description 	This API for updating a message in chat
-// @Tags 			chat
+// CreateInvite
+// @Security 		BearerAuth
+// @Summary 		Create Invite
+// @Description 	This API for creating an invite link to a group, expires_in is in seconds and 0 values mean no limit
+// @Tags			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			send body entity.UpdateMessageRequest true "Update Message Model"
-// @Success 		200 {object} entity.ResponseWithStatus
+// @Param 			id path int true "Group ID"
+// @Param 			request body entity.CreateInviteRequest true "Create Invite Model"
+// @Success 		200 {object} entity.GroupInvite
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
 // @Failure 		404 {object} errors.Error
 // @Failure 		500 {object} errors.Error
-// @Router 			/v1/update-message [PUT]
-func (ch *ChatController) UpdateMessage(c *gin.Context) {
-	var request entity.UpdateMessageRequest
-
-	if err := c.ShouldBindJSON(&request); err != nil {
+// @Router 			/v1/group/{id}/invites [POST]
+func (ch *ChatController) CreateInvite(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	chatResponse, err := ch.ChatUseCaseI.GetChat(context.Background(), int64(request.ChatID))
-	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-		return
-	}
+	var request entity.CreateInviteRequest
 
-	messageResponse, err := ch.ChatUseCaseI.GetMessage(context.Background(), int64(request.MessageID))
-	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err := ch.ChatUseCaseI.UpdateMessage(context.Background(), request); err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	if chatResponse.ChatType == "private" {
-		userResponse, err := ch.UserUseCase.GetByID(context.Background(), chatResponse.ReceiverID)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		var cacheData entity.NotificationsResponse
-
-		cacheNotification, err := ch.RedisCache.Get(context.Background(), userResponse.Username)
-		if err != nil {
-			cacheData = entity.NotificationsResponse{}
-		} else {
-			err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-
-		var updatedCacheNotification entity.NotificationsResponse
-		for _, notification := range cacheData.Notifications {
-			if notification.ChatID == request.ChatID {
-				notification.LatestMessage = request.NewMessage
-			}
-			updatedCacheNotification.Notifications = append(updatedCacheNotification.Notifications, notification)
-		}
-
-		sendingData, err := json.Marshal(&entity.SendMessageData{
-			Action: "update_message",
-			Property: struct {
-				ChatID      int    `json:"chat_id"`
-				ChatType    string `json:"chat_type"`
-				Message     string `json:"message"`
-				MessageType string `json:"message_type"`
-				Sender      int    `json:"sender"`
-				Receiver    int    `json:"receiver"`
-			}{
-				ChatID:      request.ChatID,
-				ChatType:    chatResponse.ChatType,
-				Message:     request.NewMessage,
-				MessageType: messageResponse.MessageType,
-				Sender:      request.Sender,
-				Receiver:    chatResponse.ReceiverID,
-			},
-		})
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		isSend := false
-		for connection := range ch.Hub.Connections {
-			if connection.Username == userResponse.Username {
-				connection.Send <- sendingData
-				isSend = true
-				break
-			}
-		}
-
-		if isSend == false {
-			err := ch.RedisCache.Set(context.Background(), userResponse.Username, updatedCacheNotification, 0)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-	} else {
-		groupResponse, err := ch.ChatUseCaseI.GetGroup(context.Background(), int64(chatResponse.ReceiverID))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		groupUsers, err := ch.ChatUseCaseI.GroupUsers(context.Background(), int64(groupResponse.GroupId))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
+	request.GroupID = groupID
+	request.CreatedBy = cast.ToInt(claims["sub"])
 
-		for _, gettingUser := range groupUsers {
-			var cacheData entity.NotificationsResponse
-
-			cacheNotification, err := ch.RedisCache.Get(context.Background(), gettingUser.Username)
-			if err != nil {
-				cacheData = entity.NotificationsResponse{}
-			} else {
-				err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-				if err != nil {
-					handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-
-			var updatedCacheNotification entity.NotificationsResponse
-			for _, notification := range cacheData.Notifications {
-				if notification.ChatID == request.ChatID {
-					notification.LatestMessage = request.NewMessage
-				}
-				updatedCacheNotification.Notifications = append(updatedCacheNotification.Notifications, notification)
-			}
-
-			sendingData, err := json.Marshal(&entity.SendMessageData{
-				Action: "update_message",
-				Property: struct {
-					ChatID      int    `json:"chat_id"`
-					ChatType    string `json:"chat_type"`
-					Message     string `json:"message"`
-					MessageType string `json:"message_type"`
-					Sender      int    `json:"sender"`
-					Receiver    int    `json:"receiver"`
-				}{
-					ChatID:      request.ChatID,
-					ChatType:    chatResponse.ChatType,
-					Message:     request.NewMessage,
-					MessageType: messageResponse.MessageType,
-					Sender:      request.Sender,
-					Receiver:    chatResponse.ReceiverID,
-				},
-			})
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-
-			isSend := false
-			for connection := range ch.Hub.Connections {
-				if gettingUser.Username == connection.Username {
-					connection.Send <- sendingData
-					isSend = true
-					break
-				}
-			}
-
-			if isSend == false {
-				err := ch.RedisCache.Set(context.Background(), gettingUser.Username, updatedCacheNotification, 0)
-				if err != nil {
-					handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-		}
+	response, err := ch.ChatUseCaseI.CreateInvite(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
 	}
 
-	c.JSON(http.StatusOK, entity.ResponseWithStatus{
-		Status: true,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
-// DeleteMessage
-// @Security		BearerAuth
-// @Summary 		Delete Message
-// @Description 	This API for deleting a message in chat
-// @Tags 			chat
+// GroupInvites
+// @Security 		BearerAuth
+// @Summary 		Group Invites
+// @Description 	This API for getting usable invite links of a group
+// @Tags			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			id path int true "Message ID"
-// @Success 		200 {object} entity.ResponseWithStatus
+// @Param 			id path int true "Group ID"
+// @Success 		200 {object} entity.GroupInvitesResponse
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
 // @Failure 		404 {object} errors.Error
 // @Failure 		500 {object} errors.Error
-// @Router 			/v1/delete-message/{id} [DELETE]
-func (ch *ChatController) DeleteMessage(c *gin.Context) {
-	messageID, err := strconv.Atoi(c.Param("id"))
+// @Router 			/v1/group/{id}/invites [GET]
+func (ch *ChatController) GroupInvites(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	message, err := ch.ChatUseCaseI.GetMessage(context.Background(), int64(messageID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	chatResponse, err := ch.ChatUseCaseI.GetChat(context.Background(), int64(message.ChatId))
+	response, err := ch.ChatUseCaseI.GroupInvites(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
-	chatMessages, err := ch.ChatUseCaseI.GetChatMessages(context.Background(), int64(message.ChatId))
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeInvite
+// @Security 		BearerAuth
+// @Summary 		Revoke Invite
+// @Description 	This API for revoking an invite link of a group
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			invite_id path int true "Invite ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/invites/{invite_id} [DELETE]
+func (ch *ChatController) RevokeInvite(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err = ch.ChatUseCaseI.DeleteMessage(context.Background(), int64(messageID))
+	inviteID, err := strconv.Atoi(c.Param("invite_id"))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if chatResponse.ChatType == "private" {
-		userResponse, err := ch.UserUseCase.GetByID(context.Background(), chatResponse.ReceiverID)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		var cacheData entity.NotificationsResponse
-
-		cacheNotification, err := ch.RedisCache.Get(context.Background(), userResponse.Username)
-		if err != nil {
-			cacheData = entity.NotificationsResponse{}
-		} else {
-			err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-
-		var updatedCacheNotification entity.NotificationsResponse
-		for _, notification := range cacheData.Notifications {
-			if int64(notification.ChatID) == chatResponse.ID {
-				if len(chatMessages.Messages) > 1 {
-					notification.LatestMessage = chatMessages.Messages[len(chatMessages.Messages)-2].Message
-				} else {
-					continue
-				}
-			}
-			updatedCacheNotification.Notifications = append(updatedCacheNotification.Notifications, notification)
-		}
-
-		err = ch.RedisCache.Set(context.Background(), userResponse.Username, updatedCacheNotification, 0)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
-	} else {
-		groupResponse, err := ch.ChatUseCaseI.GetGroup(context.Background(), int64(chatResponse.ReceiverID))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		groupUsers, err := ch.ChatUseCaseI.GroupUsers(context.Background(), int64(groupResponse.GroupId))
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusNotFound, err.Error())
-			return
-		}
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
 
-		var cacheData entity.NotificationsResponse
-
-		for _, gettingUser := range groupUsers {
-			cacheNotification, err := ch.RedisCache.Get(context.Background(), gettingUser.Username)
-			if err != nil {
-				cacheData = entity.NotificationsResponse{}
-			} else {
-				err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-				if err != nil {
-					handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-
-			var updatedCacheNotification entity.NotificationsResponse
-			for _, notification := range cacheData.Notifications {
-				if int64(notification.ChatID) == chatResponse.ID {
-					if len(chatMessages.Messages) > 1 {
-						notification.LatestMessage = chatMessages.Messages[len(chatMessages.Messages)-2].Message
-					} else {
-						continue
-					}
-				}
-				updatedCacheNotification.Notifications = append(updatedCacheNotification.Notifications, notification)
-			}
-
-			err = ch.RedisCache.Set(context.Background(), gettingUser.Username, updatedCacheNotification, 0)
-			if err != nil {
-				handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
+	if err := ch.ChatUseCaseI.RevokeInvite(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID), int64(inviteID)); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// JoinByInvite
+// @Security 		BearerAuth
+// @Summary 		Join By Invite
+// @Description 	This API for joining a group with an invite link token
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			token path string true "Invite Token"
+// @Success 		200 {object} entity.JoinGroupResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/join/{token} [POST]
+func (ch *ChatController) JoinByInvite(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.JoinByInvite(context.Background(), cast.ToInt64(claims["sub"]), c.Param("token"))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// JoinByUsername
+// @Security 		BearerAuth
+// @Summary 		Join By Username
+// @Description 	This API for joining a public group by its username, for a private group a join request is created
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			request body entity.JoinGroupRequest true "Join Group Model"
+// @Success 		200 {object} entity.JoinGroupResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/join [POST]
+func (ch *ChatController) JoinByUsername(c *gin.Context) {
+	var request entity.JoinGroupRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.JoinByUsername(context.Background(), cast.ToInt64(claims["sub"]), strings.TrimPrefix(request.Username, "@"))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// JoinRequests
+// @Security 		BearerAuth
+// @Summary 		Join Requests
+// @Description 	This API for getting pending join requests of a group
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Success 		200 {object} entity.JoinRequestsResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/join-requests [GET]
+func (ch *ChatController) JoinRequests(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.JoinRequests(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReviewJoinRequest
+// @Security 		BearerAuth
+// @Summary 		Review Join Request
+// @Description 	This API for approving or rejecting a pending join request
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Join Request ID"
+// @Param 			request body entity.ReviewJoinRequest true "Review Join Request Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/join-requests/{id} [POST]
+func (ch *ChatController) ReviewJoinRequest(c *gin.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.ReviewJoinRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.ReviewJoinRequest(context.Background(), cast.ToInt64(claims["sub"]), int64(requestID), request.Approve); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// UserChats
+// @Security 		BearerAuth
+// @Summary 		User Chats
+// @Description 	This API for getting all chats of the current user with the counterpart, the last message and unread message counts
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Success 		200 {object} entity.UserChatsResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Router 			/v1/group/user-chats [GET]
+func (ch *ChatController) UserChats(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	chats, err := ch.ChatUseCaseI.UserChats(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, chats)
+}
+
+// OpenDirectChat
+// @Security 		BearerAuth
+// @Summary 		Open Direct Chat
+// @Description 	This API for getting the private chat between the current user and another user, the chat is created on the first call
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			user_id path int true "User ID"
+// @Success 		200 {object} entity.CreatedChatResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/direct/{user_id} [POST]
+func (ch *ChatController) OpenDirectChat(c *gin.Context) {
+	peerID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.OpenDirectChat(context.Background(), cast.ToInt64(claims["sub"]), int64(peerID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteChat
+// @Security 		BearerAuth
+// @Summary 		Delete Chat
+// @Description 	This API for deleting a chat the current user participates in, a group chat only by the members allowed to delete the group
+// @Tags			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Success 		200 {object} entity.ResponseWithMessage
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Router 			/v1/group/delete-chat [DELETE]
+func (ch *ChatController) DeleteChat(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = ch.ChatUseCaseI.DeleteChat(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithMessage{
+		Message: "success",
+	})
+}
+
+// SendMessage
+// @Security		BearerAuth
+// @Summary 		Send Message
+// @Description 	This API for sending a message to chat
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			send body entity.SendMessageRequest true "Send Message Model"
+// @Success 		200 {object} entity.MessageEvent
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/send-message [POST]
+func (ch *ChatController) SendMessage(c *gin.Context) {
+	var message entity.SendMessageRequest
+
+	if err := c.ShouldBindJSON(&message); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	message.Sender = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.SendMessage(context.Background(), message)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateMessage
+// @Security		BearerAuth
+// @Summary 		Update Message
+// @Description 	This API for updating a message in chat
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			send body entity.UpdateMessageRequest true "Update Message Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/update-message [PUT]
+func (ch *ChatController) UpdateMessage(c *gin.Context) {
+	var request entity.UpdateMessageRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.Sender = cast.ToInt(claims["sub"])
+
+	if err := ch.ChatUseCaseI.UpdateMessage(context.Background(), request); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// DeleteMessage
+// @Security		BearerAuth
+// @Summary 		Delete Message
+// @Description 	This API for deleting a message in chat for the caller only or for everyone (the default)
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Param 			scope query string false "Delete for 'me' or 'everyone'"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/delete-message/{id} [DELETE]
+func (ch *ChatController) DeleteMessage(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = ch.ChatUseCaseI.DeleteMessage(context.Background(), int64(messageID), cast.ToInt64(claims["sub"]), c.Query("scope"))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
 	}
 
 	c.JSON(http.StatusOK, entity.ResponseWithStatus{
@@ -966,11 +1029,15 @@ func (ch *ChatController) DeleteMessage(c *gin.Context) {
 // GetChatMessages
 // @Security		BearerAuth
 // @Summary 		Get Chat Messages
-// @Description 	This API for getting chat messages with chat_id
+// @Description 	This API for getting a page of chat messages, use one of before, after or around message ids as cursor
 // @Tags 			chat
 // @Accept 			json
 // @Produce 		json
 // @Param 			id path int true "Chat ID"
+// @Param 			before query int false "Messages older than this message ID"
+// @Param 			after query int false "Messages newer than this message ID"
+// @Param 			around query int false "Messages around this message ID"
+// @Param 			limit query int false "Limit"
 // @Success 		200 {object} entity.ChatMessagesResponse
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
@@ -985,156 +1052,258 @@ func (ch *ChatController) GetChatMessages(c *gin.Context) {
 		return
 	}
 
-	messages, err := ch.ChatUseCaseI.GetChatMessages(context.Background(), int64(chatID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	filter := entity.MessageFilter{
+		ChatID: int64(chatID),
+		UserID: cast.ToInt64(claims["sub"]),
+	}
+
+	cursors := 0
+	for key, value := range map[string]*int64{"before": &filter.Before, "after": &filter.After, "around": &filter.Around} {
+		if c.Query(key) == "" {
+			continue
+		}
+
+		*value, err = strconv.ParseInt(c.Query(key), 10, 64)
+		if err != nil {
+			handle.ErrorResponse(c, http.StatusBadRequest, "invalid `"+key+"` param")
+			return
+		}
+		cursors++
+	}
+
+	if cursors > 1 {
+		handle.ErrorResponse(c, http.StatusBadRequest, "only one of `before`, `after` and `around` params is allowed")
+		return
+	}
+
+	filter.Limit, err = parseLimit(c)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	messages, err := ch.ChatUseCaseI.GetChatMessages(context.Background(), filter)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, messages)
 }
 
-// GetAllNotifications
+// SearchChatMessages
 // @Security		BearerAuth
-// @Summary 		Get All Notifications
-// @Description 	This API for getting all chats notifications for one user
+// @Summary 		Search Chat Messages
+// @Description 	This API for full-text search of messages in one chat
 // @Tags 			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			id path int true "User ID"
-// @Success 		200 {object} entity.NotificationsResponse
+// @Param 			id path int true "Chat ID"
+// @Param 			q query string true "Search Query"
+// @Param 			before query int false "Messages older than this message ID"
+// @Param 			limit query int false "Limit"
+// @Success 		200 {object} entity.SearchMessagesResponse
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
-// @Failure 		404 {object} errors.Error
 // @Failure 		500 {object} errors.Error
-// @Router 			/v1/get-notifications/{id} [GET]
-func (ch *ChatController) GetAllNotifications(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Param("id"))
+// @Router 			/v1/chat/{id}/search [GET]
+func (ch *ChatController) SearchChatMessages(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userData, err := ch.UserUseCase.GetByID(context.Background(), userID)
-	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+	ch.searchMessages(c, int64(chatID))
+}
+
+// SearchMessages
+// @Security		BearerAuth
+// @Summary 		Search Messages
+// @Description 	This API for full-text search of messages in all chats of the current user
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			q query string true "Search Query"
+// @Param 			before query int false "Messages older than this message ID"
+// @Param 			limit query int false "Limit"
+// @Success 		200 {object} entity.SearchMessagesResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/search [GET]
+func (ch *ChatController) SearchMessages(c *gin.Context) {
+	ch.searchMessages(c, 0)
+}
+
+func (ch *ChatController) searchMessages(c *gin.Context, chatID int64) {
+	request := entity.SearchMessagesRequest{
+		Query:  strings.TrimSpace(c.Query("q")),
+		ChatID: chatID,
+	}
+
+	if request.Query == "" {
+		handle.ErrorResponse(c, http.StatusBadRequest, "`q` param is required")
 		return
 	}
 
-	dataJson, err := ch.RedisCache.Get(context.Background(), userData.Username)
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	var response entity.NotificationsResponse
+	request.UserID = cast.ToInt64(claims["sub"])
 
-	err = json.Unmarshal([]byte(dataJson), &response)
+	if c.Query("before") != "" {
+		request.Before, err = strconv.ParseInt(c.Query("before"), 10, 64)
+		if err != nil {
+			handle.ErrorResponse(c, http.StatusBadRequest, "invalid `before` param")
+			return
+		}
+	}
+
+	request.Limit, err = parseLimit(c)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.SearchMessages(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// DeleteChatNotifications
+// ReadChat
 // @Security		BearerAuth
-// @Summary 		Delete Chat Notifications
-// @Description 	This API for deleting chats notifications for one user
+// @Summary 		Read Chat
+// @Description 	This API for marking chat messages as read up to message_id, the latest message when it is 0
 // @Tags 			chat
 // @Accept 			json
 // @Produce 		json
-// @Param 			chat_id query int true "Chat ID"
-// @Param 			user_id query int true "User ID"
-// @Param 			count query int true "Read Message Count"
+// @Param 			id path int true "Chat ID"
+// @Param 			request body entity.MarkReadRequest true "Mark Read Model"
 // @Success 		200 {object} entity.ResponseWithStatus
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
 // @Failure 		404 {object} errors.Error
 // @Failure 		500 {object} errors.Error
-// @Router 			/v1/delete-chat-notifications [DELETE]
-func (ch *ChatController) DeleteChatNotifications(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Query("user_id"))
+// @Router 			/v1/chat/{id}/read [POST]
+func (ch *ChatController) ReadChat(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	chatID, err := strconv.Atoi(c.Query("chat_id"))
-	if err != nil {
+	var request entity.MarkReadRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
 		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	count, err := strconv.Atoi(c.Query("count"))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	userResponse, err := ch.UserUseCase.GetByID(context.Background(), userID)
+	err = ch.ChatUseCaseI.MarkRead(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID), int64(request.MessageID))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
 		return
 	}
 
-	chatResponse, err := ch.ChatUseCaseI.GetChat(context.Background(), int64(chatID))
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// ChatReceipts
+// @Security		BearerAuth
+// @Summary 		Chat Receipts
+// @Description 	This API for getting delivered and read cursors of every chat member
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Success 		200 {object} entity.ChatReceiptsResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/{id}/receipts [GET]
+func (ch *ChatController) ChatReceipts(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	chatMessages, err := ch.ChatUseCaseI.GetChatMessages(context.Background(), int64(chatID))
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
 	if err != nil {
-		handle.ErrorResponse(c, http.StatusNotFound, err.Error())
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	var cacheData entity.NotificationsResponse
-
-	cacheNotification, err := ch.RedisCache.Get(context.Background(), userResponse.Username)
+	response, err := ch.ChatUseCaseI.ChatReceipts(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID))
 	if err != nil {
-		cacheData = entity.NotificationsResponse{}
-	} else {
-		err = json.Unmarshal([]byte(cacheNotification), &cacheData)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
 	}
 
-	if len(cacheData.Notifications) > count {
-		var updatedCacheNotification entity.NotificationsResponse
+	c.JSON(http.StatusOK, response)
+}
 
-		for _, notification := range cacheData.Notifications {
-			if int64(notification.ChatID) == chatResponse.ID {
-				notification.LatestMessage = chatMessages.Messages[len(chatMessages.Messages)-(count+1)].Message
-				notification.LatestSender = chatMessages.Messages[len(chatMessages.Messages)-(count+1)].Sender
-			}
-			updatedCacheNotification.Notifications = append(updatedCacheNotification.Notifications, notification)
-		}
+// errorStatus answers the kind of failure the chat use case sorts the error into,
+// the storage and quota errors come from the blob store and the file store
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, fileStore.ErrQuotaExceeded):
+		return http.StatusInsufficientStorage
+	}
 
-		err = ch.RedisCache.Set(context.Background(), userResponse.Username, updatedCacheNotification, 0)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
+	switch chat.ErrorCode(err) {
+	case entity.ErrCodeNotFound:
+		return http.StatusNotFound
+	case entity.ErrCodeForbidden:
+		return http.StatusForbidden
+	case entity.ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+	case entity.ErrCodeBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
 
-		c.JSON(http.StatusOK, entity.ResponseWithStatus{
-			Status: true,
-		})
-	} else {
-		err := ch.RedisCache.Delete(context.Background(), userResponse.Username)
-		if err != nil {
-			handle.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
-		}
+// parseLimit reads the `limit` query param of message lists
+func parseLimit(c *gin.Context) (int64, error) {
+	if c.Query("limit") == "" {
+		return 50, nil
+	}
 
-		c.JSON(http.StatusOK, entity.ResponseWithStatus{
-			Status: true,
-		})
+	limit, err := strconv.ParseInt(c.Query("limit"), 10, 64)
+	if err != nil || limit <= 0 || limit > 100 {
+		return 0, errors.New("`limit` param must be between 1 and 100")
 	}
+
+	return limit, nil
 }
@@ -0,0 +1,157 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/export"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// ChatRetention
+// @Security		BearerAuth
+// @Summary 		Chat Retention
+// @Description 	This API for getting the days the messages of a chat are kept for, inherited is set when the global retention applies and 0 days keeps them forever
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Success 		200 {object} entity.ChatRetention
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/{id}/retention [GET]
+func (ch *ChatController) ChatRetention(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.ChatRetention(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetChatRetention
+// @Security		BearerAuth
+// @Summary 		Set Chat Retention
+// @Description 	This API for purging the messages of a chat after the given days, 0 falls back to the global retention. Group chats need an admin
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Param 			request body entity.RetentionRequest true "Retention Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/{id}/retention [PUT]
+func (ch *ChatController) SetChatRetention(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.RetentionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.SetChatRetention(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID), request.Days); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// ExportChat
+// @Security		BearerAuth
+// @Summary 		Export Chat
+// @Description 	This API for downloading a zip archive of a chat with its members and messages, for the chat participants and admins
+// @Tags 			chat
+// @Produce 		application/zip
+// @Param 			id path int true "Chat ID"
+// @Param 			format query string false "Archive format: json or html, json by default"
+// @Success 		200 {file} file
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/{id}/export [GET]
+func (ch *ChatController) ExportChat(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	role := cast.ToString(claims["role"])
+
+	header, err := ch.ChatUseCaseI.ChatExport(context.Background(), entity.ExportChatRequest{
+		ChatID: chatID,
+		UserID: cast.ToInt(claims["sub"]),
+		Admin:  role == "admin" || role == "sudo",
+		Format: c.Query("format"),
+	})
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	// the status is sent with the first bytes of the archive, a later failure can only cut the download short
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%d-%s.zip"`, chatID, header.ExportedAt.Format("20060102-150405")))
+	c.Status(http.StatusOK)
+
+	archive, err := export.NewArchive(c.Writer, header)
+	if err == nil {
+		err = ch.ChatUseCaseI.ExportMessages(c.Request.Context(), header, archive.WriteMessages)
+	}
+	if err == nil {
+		err = archive.Close()
+	}
+	if err != nil {
+		log.Println(err)
+		c.Abort()
+	}
+}
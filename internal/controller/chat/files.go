@@ -0,0 +1,194 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/upload"
+	"archv1/internal/pkg/utils"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/cast"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// UploadChatFile
+// @Security		BearerAuth
+// @Summary 		Upload Chat File
+// @Description 	This API for uploading a file to a chat, the returned file id is sent in message attachments
+// @Tags 			chat
+// @Accept 			multipart/form-data
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Param			file formData file true "Upload file"
+// @Success 		200 {object} entity.Attachment
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		413 {object} errors.Error
+// @Failure 		415 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Failure 		507 {object} errors.Error
+// @Router 			/v1/chat/{id}/upload [POST]
+func (ch *ChatController) UploadChatFile(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// the upload is refused before it is read when the user may not post in the chat
+	if err := ch.ChatUseCaseI.CheckChatUpload(c.Request.Context(), cast.ToInt64(claims["sub"]), int64(chatID)); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	header, err := ch.Uploads.FormFile(c.Writer, c.Request, "chat", "file")
+	if err != nil {
+		handle.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
+		return
+	}
+
+	inspection, err := ch.Uploads.Inspect("chat", header)
+	if err != nil {
+		handle.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
+		return
+	}
+
+	file, err := describeFile(header, inspection)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// chat files count against the storage quota of the uploader like the file store uploads
+	if err := ch.FileStore.CheckQuota(c.Request.Context(), cast.ToInt(claims["sub"]), header.Size); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, "invalid file request")
+		return
+	}
+	defer src.Close()
+
+	// chat files share the content stored for the file store, they are only served to the members of the chat
+	blob, err := ch.FileStore.StoreBlob(c.Request.Context(), src, header.Size, file.MimeType)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusInternalServerError, "error happened when save file")
+		return
+	}
+
+	file.Link = uuid.NewString() + strings.ToLower(filepath.Ext(inspection.Name))
+	file.BlobKey = blob.Key
+	file.ChatID = chatID
+	file.UploadedBy = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.UploadChatFile(context.Background(), file)
+	if err != nil {
+		_ = ch.FileStore.ReleaseBlob(context.Background(), blob.Key)
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Attachment)
+}
+
+// DownloadChatFile
+// @Security		BearerAuth
+// @Summary 		Download Chat File
+// @Description 	This API for downloading a file of a chat, only the chat participants have access
+// @Tags 			chat
+// @Produce 		octet-stream
+// @Param 			file_id path int true "File ID"
+// @Success 		200 {file} file
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/files/{file_id} [GET]
+func (ch *ChatController) DownloadChatFile(c *gin.Context) {
+	fileID, err := strconv.Atoi(c.Param("file_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	file, err := ch.ChatUseCaseI.ChatFile(context.Background(), cast.ToInt64(claims["sub"]), int64(fileID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	// the files uploaded before the chat files were stored once per content are kept under their link
+	key := file.BlobKey
+	if key == "" {
+		key = storage.ChatFilesPrefix + filepath.Base(file.Link)
+	}
+
+	blob, info, err := ch.BlobStore.Get(c.Request.Context(), key)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+	defer blob.Close()
+
+	c.DataFromReader(http.StatusOK, info.Size, file.MimeType, blob, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}),
+		"ETag":                info.ETag,
+	})
+}
+
+// describeFile completes the checked upload with its size and the dimensions of an image
+func describeFile(header *multipart.FileHeader, inspection upload.Inspection) (entity.ChatFile, error) {
+	file := entity.ChatFile{
+		Attachment: entity.Attachment{
+			Name:     inspection.Name,
+			Size:     header.Size,
+			MimeType: inspection.MimeType,
+		},
+	}
+
+	if !strings.HasPrefix(file.MimeType, "image/") {
+		return file, nil
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return entity.ChatFile{}, err
+	}
+	defer src.Close()
+
+	imageConfig, _, err := image.DecodeConfig(src)
+	if err == nil {
+		file.Width, file.Height = &imageConfig.Width, &imageConfig.Height
+	}
+
+	return file, nil
+}
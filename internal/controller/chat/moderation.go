@@ -0,0 +1,498 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"net/http"
+	"strconv"
+)
+
+// MuteMember
+// @Security		BearerAuth
+// @Summary 		Mute Member
+// @Description 	This API for muting a group member for duration seconds, 0 mutes until lifted
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			request body entity.RestrictMemberRequest true "Restrict Member Model"
+// @Success 		200 {object} entity.GroupRestriction
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/mutes [POST]
+func (ch *ChatController) MuteMember(c *gin.Context) {
+	ch.restrict(c, entity.RestrictionMute)
+}
+
+// BanMember
+// @Security		BearerAuth
+// @Summary 		Ban Member
+// @Description 	This API for banning a user from a group for duration seconds, 0 bans until lifted. A banned member is removed from the group
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			request body entity.RestrictMemberRequest true "Restrict Member Model"
+// @Success 		200 {object} entity.GroupRestriction
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/bans [POST]
+func (ch *ChatController) BanMember(c *gin.Context) {
+	ch.restrict(c, entity.RestrictionBan)
+}
+
+func (ch *ChatController) restrict(c *gin.Context, kind string) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.RestrictMemberRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.GroupID = groupID
+	request.Kind = kind
+
+	response, err := ch.ChatUseCaseI.RestrictMember(context.Background(), cast.ToInt64(claims["sub"]), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UnmuteMember
+// @Security		BearerAuth
+// @Summary 		Unmute Member
+// @Description 	This API for lifting the mute of a group member
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			user_id path int true "User ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/mutes/{user_id} [DELETE]
+func (ch *ChatController) UnmuteMember(c *gin.Context) {
+	ch.lift(c, entity.RestrictionMute)
+}
+
+// UnbanMember
+// @Security		BearerAuth
+// @Summary 		Unban Member
+// @Description 	This API for lifting the ban of a user, the user has to join the group again
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			user_id path int true "User ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/bans/{user_id} [DELETE]
+func (ch *ChatController) UnbanMember(c *gin.Context) {
+	ch.lift(c, entity.RestrictionBan)
+}
+
+func (ch *ChatController) lift(c *gin.Context, kind string) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.LiftRestriction(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID), int64(userID), kind); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// GroupRestrictions
+// @Security		BearerAuth
+// @Summary 		Group Restrictions
+// @Description 	This API for getting the active mutes and bans of a group
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Success 		200 {object} entity.GroupRestrictionsResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/restrictions [GET]
+func (ch *ChatController) GroupRestrictions(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.GroupRestrictions(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetSlowMode
+// @Security		BearerAuth
+// @Summary 		Set Slow Mode
+// @Description 	This API for letting members below moderator send one message per the given seconds, 0 turns slow mode off
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			request body entity.SlowModeRequest true "Slow Mode Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/slow-mode [PUT]
+func (ch *ChatController) SetSlowMode(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.SlowModeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.SetSlowMode(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID), request.Seconds); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// WordFilters
+// @Security		BearerAuth
+// @Summary 		Word Filters
+// @Description 	This API for getting the word filters of a group
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Success 		200 {object} entity.WordFiltersResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/filters [GET]
+func (ch *ChatController) WordFilters(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.WordFilters(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateWordFilter
+// @Security		BearerAuth
+// @Summary 		Create Word Filter
+// @Description 	This API for adding a banned word or regular expression to a group, action 'block' rejects the message and 'mask' replaces the match with asterisks
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			request body entity.CreateWordFilterRequest true "Create Word Filter Model"
+// @Success 		200 {object} entity.WordFilter
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/filters [POST]
+func (ch *ChatController) CreateWordFilter(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.CreateWordFilterRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.GroupID = groupID
+	request.CreatedBy = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.CreateWordFilter(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteWordFilter
+// @Security		BearerAuth
+// @Summary 		Delete Word Filter
+// @Description 	This API for removing a word filter of a group
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			filter_id path int true "Filter ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/filters/{filter_id} [DELETE]
+func (ch *ChatController) DeleteWordFilter(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filterID, err := strconv.Atoi(c.Param("filter_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.DeleteWordFilter(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID), int64(filterID)); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// ReportMessage
+// @Security		BearerAuth
+// @Summary 		Report Message
+// @Description 	This API for reporting a message of another member in a group chat to the group moderators
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Param 			request body entity.ReportMessageRequest true "Report Message Model"
+// @Success 		200 {object} entity.MessageReport
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/message/{id}/report [POST]
+func (ch *ChatController) ReportMessage(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.ReportMessageRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.MessageID = messageID
+	request.ReportedBy = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.ReportMessage(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MessageReports
+// @Security		BearerAuth
+// @Summary 		Message Reports
+// @Description 	This API for getting the reported messages of a group, open reports by default
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Group ID"
+// @Param 			status query string false "Report status: open, resolved or dismissed"
+// @Success 		200 {object} entity.MessageReportsResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/{id}/reports [GET]
+func (ch *ChatController) MessageReports(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.MessageReports(context.Background(), cast.ToInt64(claims["sub"]), int64(groupID), c.Query("status"))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReviewReport
+// @Security		BearerAuth
+// @Summary 		Review Report
+// @Description 	This API for closing an open message report as resolved or dismissed
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Report ID"
+// @Param 			request body entity.ReviewReportRequest true "Review Report Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/group/reports/{id}/review [POST]
+func (ch *ChatController) ReviewReport(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.ReviewReportRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := ch.ChatUseCaseI.ReviewReport(context.Background(), cast.ToInt64(claims["sub"]), int64(reportID), request.Status); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
@@ -0,0 +1,235 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"net/http"
+	"strconv"
+)
+
+// ForwardMessage
+// @Security		BearerAuth
+// @Summary 		Forward Message
+// @Description 	This API for forwarding a message to another chat, the copy keeps the original author
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			forward body entity.ForwardMessageRequest true "Forward Message Model"
+// @Success 		200 {object} entity.MessageEvent
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/forward [POST]
+func (ch *ChatController) ForwardMessage(c *gin.Context) {
+	var request entity.ForwardMessageRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.Sender = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.ForwardMessage(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReactToMessage
+// @Security		BearerAuth
+// @Summary 		React To Message
+// @Description 	This API for adding an emoji reaction to a message, sending the same emoji again removes it
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Param 			reaction body entity.ReactionRequest true "Reaction Model"
+// @Success 		200 {object} entity.ReactionEvent
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/message/{id}/reactions [POST]
+func (ch *ChatController) ReactToMessage(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.ReactionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.MessageID = messageID
+	request.UserID = cast.ToInt(claims["sub"])
+
+	response, err := ch.ChatUseCaseI.ToggleReaction(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PinMessage
+// @Security		BearerAuth
+// @Summary 		Pin Message
+// @Description 	This API for pinning a message in its chat
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/message/{id}/pin [POST]
+func (ch *ChatController) PinMessage(c *gin.Context) {
+	ch.pin(c, ch.ChatUseCaseI.PinMessage)
+}
+
+// UnpinMessage
+// @Security		BearerAuth
+// @Summary 		Unpin Message
+// @Description 	This API for unpinning a message in its chat
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/message/{id}/pin [DELETE]
+func (ch *ChatController) UnpinMessage(c *gin.Context) {
+	ch.pin(c, ch.ChatUseCaseI.UnpinMessage)
+}
+
+func (ch *ChatController) pin(c *gin.Context, action func(ctx context.Context, userID, messageID int64) error) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := action(context.Background(), cast.ToInt64(claims["sub"]), int64(messageID)); err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// PinnedMessages
+// @Security		BearerAuth
+// @Summary 		Pinned Messages
+// @Description 	This API for getting the pinned messages of a chat, the latest pinned first
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Chat ID"
+// @Success 		200 {object} entity.PinnedMessagesResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/{id}/pins [GET]
+func (ch *ChatController) PinnedMessages(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.PinnedMessages(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MessageHistory
+// @Security		BearerAuth
+// @Summary 		Message History
+// @Description 	This API for getting the previous versions of an edited message
+// @Tags 			chat
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Message ID"
+// @Success 		200 {object} entity.MessageHistoryResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/chat/message/{id}/history [GET]
+func (ch *ChatController) MessageHistory(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, ch.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := ch.ChatUseCaseI.MessageHistory(context.Background(), cast.ToInt64(claims["sub"]), int64(messageID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
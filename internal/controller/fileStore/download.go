@@ -0,0 +1,129 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/pkg/signedurl"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/fileStore"
+	"database/sql"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// CreateDownloadLink
+// @Security 			BearerAuth
+// @Summary 			Create Download Link
+// @Description 		This API for creating a signed, expiring link to the content of a file, the link works without a token
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Param 				link body entity.DownloadLinkRequest true "Link"
+// @Success 			201 {object} entity.DownloadLinkResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/download-link [POST]
+func (f *ControllerFileStore) CreateDownloadLink(c *gin.Context) {
+	fileID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.DownloadLinkRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	role := cast.ToString(claims["role"])
+
+	request.FileID = fileID
+	request.UserID = cast.ToInt(claims["sub"])
+	request.Admin = role == "admin" || role == "sudo"
+	request.IP = c.ClientIP()
+
+	response, err := f.FileUseCase.CreateDownloadLink(c.Request.Context(), request)
+	if err != nil {
+		errors.ErrorResponse(c, downloadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Download
+// @Summary 			Download
+// @Description 		This API for downloading a file through a signed link, Range, If-Range and If-None-Match requests are supported
+// @Tags 				file-storage
+// @Produce 			octet-stream
+// @Param 				token path string true "Link token"
+// @Param 				Range header string false "Byte range"
+// @Success 			200 {file} file
+// @Success 			206 {file} file
+// @Success 			304
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			410 {object} errors.Error
+// @Failure 			416 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/download/{token} [GET]
+func (f *ControllerFileStore) Download(c *gin.Context) {
+	download, content, err := f.FileUseCase.OpenDownload(c.Request.Context(), c.Param("token"), c.ClientIP())
+	if err != nil {
+		errors.ErrorResponse(c, downloadErrorStatus(err), err.Error())
+
+		return
+	}
+	defer content.Close()
+
+	disposition := "attachment"
+	if download.Inline {
+		disposition = "inline"
+	}
+
+	info := content.Info()
+
+	c.Header("Content-Type", download.MimeType)
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": download.Name}))
+	c.Header("ETag", info.ETag)
+	c.Header("Cache-Control", "private")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime, content)
+}
+
+func downloadErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, sql.ErrNoRows), goerrors.Is(err, storage.ErrNotFound), goerrors.Is(err, storage.ErrInvalidKey):
+		return http.StatusNotFound
+	case goerrors.Is(err, fileStore.ErrInvalidExpiry):
+		return http.StatusBadRequest
+	case goerrors.Is(err, fileStore.ErrForbidden), goerrors.Is(err, fileStore.ErrLinkAddress), goerrors.Is(err, signedurl.ErrInvalidToken):
+		return http.StatusForbidden
+	case goerrors.Is(err, signedurl.ErrExpired), goerrors.Is(err, fileStore.ErrLinkUsed):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
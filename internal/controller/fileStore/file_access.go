@@ -0,0 +1,498 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/fileStore"
+	"database/sql"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// GetFolderAccess
+// @Security 			BearerAuth
+// @Summary 			Get Folder Access
+// @Description 		This API for getting the owner of a folder, the roles granted on it and on the folders above it and its share links, for its owners
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Success 			200 {object} entity.ListAccessResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/access [GET]
+func (f *ControllerFileStore) GetFolderAccess(c *gin.Context) {
+	f.listAccess(c, folderTarget)
+}
+
+// GrantFolderAccess
+// @Security 			BearerAuth
+// @Summary 			Grant Folder Access
+// @Description 		This API for giving a user or the members of a chat group the viewer, editor or owner role on a folder and everything below it, granting again replaces the role
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				grant body entity.GrantAccessRequest true "Grant Access Model"
+// @Success 			200 {object} entity.AccessGrant
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/access [PUT]
+func (f *ControllerFileStore) GrantFolderAccess(c *gin.Context) {
+	f.grantAccess(c, folderTarget)
+}
+
+// RevokeFolderAccess
+// @Security 			BearerAuth
+// @Summary 			Revoke Folder Access
+// @Description 		This API for taking back a role granted on a folder
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				grant_id path int true "Grant ID"
+// @Success 			200 {object} entity.ResponseWithStatus
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/access/{grant_id} [DELETE]
+func (f *ControllerFileStore) RevokeFolderAccess(c *gin.Context) {
+	f.revokeAccess(c, folderTarget)
+}
+
+// ShareFolder
+// @Security 			BearerAuth
+// @Summary 			Share Folder
+// @Description 		This API for creating a link that opens a folder and everything below it without a token, with an optional password and expiry
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				link body entity.CreateShareLinkRequest true "Share Link Model"
+// @Success 			201 {object} entity.ShareLink
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/share [POST]
+func (f *ControllerFileStore) ShareFolder(c *gin.Context) {
+	f.createShareLink(c, folderTarget)
+}
+
+// RevokeFolderShare
+// @Security 			BearerAuth
+// @Summary 			Revoke Folder Share
+// @Description 		This API for revoking a share link to a folder
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				link_id path int true "Share Link ID"
+// @Success 			200 {object} entity.ResponseWithStatus
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/share/{link_id} [DELETE]
+func (f *ControllerFileStore) RevokeFolderShare(c *gin.Context) {
+	f.revokeShareLink(c, folderTarget)
+}
+
+// GetFileAccess
+// @Security 			BearerAuth
+// @Summary 			Get File Access
+// @Description 		This API for getting the owner of a file, the roles granted on it and on its folders and its share links, for its owners
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Success 			200 {object} entity.ListAccessResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/access [GET]
+func (f *ControllerFileStore) GetFileAccess(c *gin.Context) {
+	f.listAccess(c, fileTarget)
+}
+
+// GrantFileAccess
+// @Security 			BearerAuth
+// @Summary 			Grant File Access
+// @Description 		This API for giving a user or the members of a chat group the viewer, editor or owner role on a file, granting again replaces the role
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Param 				grant body entity.GrantAccessRequest true "Grant Access Model"
+// @Success 			200 {object} entity.AccessGrant
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/access [PUT]
+func (f *ControllerFileStore) GrantFileAccess(c *gin.Context) {
+	f.grantAccess(c, fileTarget)
+}
+
+// RevokeFileAccess
+// @Security 			BearerAuth
+// @Summary 			Revoke File Access
+// @Description 		This API for taking back a role granted on a file
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Param 				grant_id path int true "Grant ID"
+// @Success 			200 {object} entity.ResponseWithStatus
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/access/{grant_id} [DELETE]
+func (f *ControllerFileStore) RevokeFileAccess(c *gin.Context) {
+	f.revokeAccess(c, fileTarget)
+}
+
+// ShareFile
+// @Security 			BearerAuth
+// @Summary 			Share File
+// @Description 		This API for creating a link that opens a file without a token, with an optional password and expiry
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Param 				link body entity.CreateShareLinkRequest true "Share Link Model"
+// @Success 			201 {object} entity.ShareLink
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/share [POST]
+func (f *ControllerFileStore) ShareFile(c *gin.Context) {
+	f.createShareLink(c, fileTarget)
+}
+
+// RevokeFileShare
+// @Security 			BearerAuth
+// @Summary 			Revoke File Share
+// @Description 		This API for revoking a share link to a file
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "File ID"
+// @Param 				link_id path int true "Share Link ID"
+// @Success 			200 {object} entity.ResponseWithStatus
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/share/{link_id} [DELETE]
+func (f *ControllerFileStore) RevokeFileShare(c *gin.Context) {
+	f.revokeShareLink(c, fileTarget)
+}
+
+// OpenShare
+// @Summary 			Open Share
+// @Description 		This API for opening a share link, it returns the shared file or the shared folder with everything below it. A protected link takes its password in the X-Share-Password header.
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				token path string true "Share token"
+// @Param 				X-Share-Password header string false "Password of the link"
+// @Success 			200 {object} entity.SharedItemResponse
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/share/{token} [GET]
+func (f *ControllerFileStore) OpenShare(c *gin.Context) {
+	response, err := f.FileUseCase.OpenShare(c.Request.Context(), c.Param("token"), c.GetHeader("X-Share-Password"))
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadShare
+// @Summary 			Download Share
+// @Description 		This API for downloading the shared file, or a file below the shared folder given with file_id. A protected link takes its password in the X-Share-Password header.
+// @Tags 				file-storage
+// @Produce 			octet-stream
+// @Param 				token path string true "Share token"
+// @Param 				file_id query int false "File ID, for a shared folder"
+// @Param 				X-Share-Password header string false "Password of the link"
+// @Param 				Range header string false "Byte range"
+// @Success 			200 {file} file
+// @Success 			206 {file} file
+// @Success 			304
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			416 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/share/{token}/content [GET]
+func (f *ControllerFileStore) DownloadShare(c *gin.Context) {
+	var fileID int
+	if value := c.Query("file_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			errors.ErrorResponse(c, http.StatusBadRequest, "invalid file id")
+
+			return
+		}
+
+		fileID = id
+	}
+
+	download, content, err := f.FileUseCase.OpenSharedFile(c.Request.Context(), c.Param("token"), c.GetHeader("X-Share-Password"), fileID)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+	defer content.Close()
+
+	info := content.Info()
+
+	c.Header("Content-Type", download.MimeType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Name}))
+	c.Header("ETag", info.ETag)
+	c.Header("Cache-Control", "private")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime, content)
+}
+
+func (f *ControllerFileStore) listAccess(c *gin.Context, target func(id int) entity.AccessTarget) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.ListAccess(c.Request.Context(), target(id), access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (f *ControllerFileStore) grantAccess(c *gin.Context, target func(id int) entity.AccessTarget) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.GrantAccessRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	request.Target = target(id)
+	request.CreatedBy = access.UserID
+
+	response, err := f.FileUseCase.GrantAccess(c.Request.Context(), request, access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (f *ControllerFileStore) revokeAccess(c *gin.Context, target func(id int) entity.AccessTarget) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	grantID, err := strconv.Atoi(c.Param("grant_id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if err := f.FileUseCase.RevokeAccess(c.Request.Context(), target(id), grantID, access); err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+func (f *ControllerFileStore) createShareLink(c *gin.Context, target func(id int) entity.AccessTarget) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.CreateShareLinkRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	request.Target = target(id)
+	request.CreatedBy = access.UserID
+
+	response, err := f.FileUseCase.CreateShareLink(c.Request.Context(), request, access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (f *ControllerFileStore) revokeShareLink(c *gin.Context, target func(id int) entity.AccessTarget) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	linkID, err := strconv.Atoi(c.Param("link_id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if err := f.FileUseCase.RevokeShareLink(c.Request.Context(), target(id), linkID, access); err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// fileAccess reads who is asking from the token, a request without one sees only what everyone may see
+func (f *ControllerFileStore) fileAccess(c *gin.Context) (entity.FileAccess, error) {
+	if c.GetHeader("Authorization") == "" {
+		return entity.FileAccess{}, nil
+	}
+
+	return f.requiredAccess(c)
+}
+
+// requiredAccess reads who is asking from the token, the admins and the sudo pass every role check
+func (f *ControllerFileStore) requiredAccess(c *gin.Context) (entity.FileAccess, error) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		return entity.FileAccess{}, err
+	}
+
+	role := cast.ToString(claims["role"])
+
+	return entity.FileAccess{
+		UserID: cast.ToInt(claims["sub"]),
+		Admin:  role == "admin" || role == "sudo",
+	}, nil
+}
+
+func folderTarget(id int) entity.AccessTarget {
+	return entity.AccessTarget{FolderID: &id}
+}
+
+func fileTarget(id int) entity.AccessTarget {
+	return entity.AccessTarget{FileID: &id}
+}
+
+func accessErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, sql.ErrNoRows), goerrors.Is(err, fileStore.ErrUnknownGrantee),
+		goerrors.Is(err, fileStore.ErrInvalidShare), goerrors.Is(err, fileStore.ErrNotShared):
+		return http.StatusNotFound
+	case goerrors.Is(err, fileStore.ErrInvalidRole), goerrors.Is(err, fileStore.ErrInvalidGrantee), goerrors.Is(err, fileStore.ErrShareExpiry):
+		return http.StatusBadRequest
+	case goerrors.Is(err, fileStore.ErrForbidden), goerrors.Is(err, fileStore.ErrFolderForbidden), goerrors.Is(err, fileStore.ErrRoleTooLow):
+		return http.StatusForbidden
+	case goerrors.Is(err, fileStore.ErrSharePassword):
+		return http.StatusUnauthorized
+	default:
+		return contentErrorStatus(err)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"archv1/internal/pkg/errors"
 	"archv1/internal/pkg/repo/cache"
 	"archv1/internal/pkg/repo/postgres"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/upload"
 	"archv1/internal/pkg/utils"
 	"archv1/internal/usecase/fileStore"
 	"context"
+	"database/sql"
+	goerrors "errors"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
@@ -22,6 +26,7 @@ type ControllerFileStore struct {
 	Redis       *cache.Redis
 	Enforcer    *casbin.Enforcer
 	FileUseCase fileStore.FilesStoreUseCaseI
+	Uploads     *upload.Validator
 }
 
 func NewFileStoreController(controller ControllerFileStore) *ControllerFileStore {
@@ -31,18 +36,20 @@ func NewFileStoreController(controller ControllerFileStore) *ControllerFileStore
 		Redis:       controller.Redis,
 		Enforcer:    controller.Enforcer,
 		FileUseCase: controller.FileUseCase,
+		Uploads:     controller.Uploads,
 	}
 }
 
 // ListFolder
 // @Summary 			List Folder
-// Description 			This API for getting list folder
+// @Description 		This API for getting the folders the user created or holds a role on, with every folder below them
 // @Tags 				folder-storage
 // @Accept 				json
 // @Produce 			json
 // @Param 				page query int false "Page"
 // @Param 				limit query int false "Limit"
 // @Success 			200 {object} entity.ListFolderResponse
+// @Failure 			401 {object} errors.Error
 // @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/folder/list [GET]
@@ -59,7 +66,14 @@ func (f *ControllerFileStore) ListFolder(c *gin.Context) {
 		Limit: params.Limit,
 	}
 
-	menus, err := f.FileUseCase.ListFolder(context.Background(), filter)
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	menus, err := f.FileUseCase.ListFolder(context.Background(), filter, access)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 
@@ -78,6 +92,8 @@ func (f *ControllerFileStore) ListFolder(c *gin.Context) {
 // @Param 				id path int true "Folder ID"
 // @Success 			200 {object} entity.GetFolderResponse
 // @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
 // @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/folder/{id} [GET]
@@ -91,9 +107,16 @@ func (f *ControllerFileStore) GetFolder(c *gin.Context) {
 		return
 	}
 
-	menuResponse, err := f.FileUseCase.GetFolder(context.Background(), menuIntId)
+	access, err := f.fileAccess(c)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	menuResponse, err := f.FileUseCase.GetFolder(context.Background(), menuIntId, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
 
 		return
 	}
@@ -113,6 +136,7 @@ func (f *ControllerFileStore) GetFolder(c *gin.Context) {
 // @Failure 			400 {object} errors.Error
 // @Failure 			401 {object} errors.Error
 // @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/folder [POST]
 func (f *ControllerFileStore) CreateFolder(c *gin.Context) {
@@ -124,18 +148,18 @@ func (f *ControllerFileStore) CreateFolder(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.CreatedBy = cast.ToInt(claims["sub"])
+	request.CreatedBy = access.UserID
 
-	menuResponse, err := f.FileUseCase.CreateFolder(context.Background(), request)
+	menuResponse, err := f.FileUseCase.CreateFolder(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
 
 		return
 	}
@@ -156,6 +180,7 @@ func (f *ControllerFileStore) CreateFolder(c *gin.Context) {
 // @Failure 			401 {object} errors.Error
 // @Failure 			403 {object} errors.Error
 // @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/folder [PUT]
 func (f *ControllerFileStore) UpdateFolder(c *gin.Context) {
@@ -167,18 +192,18 @@ func (f *ControllerFileStore) UpdateFolder(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.UpdatedBy = cast.ToInt(claims["sub"])
+	request.UpdatedBy = access.UserID
 
-	menuResponse, err := f.FileUseCase.UpdateFolder(context.Background(), request)
+	menuResponse, err := f.FileUseCase.UpdateFolder(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
 
 		return
 	}
@@ -199,6 +224,7 @@ func (f *ControllerFileStore) UpdateFolder(c *gin.Context) {
 // @Failure 			401 {object} errors.Error
 // @Failure 			403 {object} errors.Error
 // @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/folder [PATCH]
 func (f *ControllerFileStore) UpdateFolderColumns(c *gin.Context) {
@@ -210,18 +236,18 @@ func (f *ControllerFileStore) UpdateFolderColumns(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.Fields["updated_by"] = cast.ToString(claims["sub"])
+	request.Fields["updated_by"] = strconv.Itoa(access.UserID)
 
-	menuResponse, err := f.FileUseCase.UpdateFolderColumns(context.Background(), request)
+	menuResponse, err := f.FileUseCase.UpdateFolderColumns(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
 
 		return
 	}
@@ -232,7 +258,7 @@ func (f *ControllerFileStore) UpdateFolderColumns(c *gin.Context) {
 // DeleteFolder
 // @Security 			BearerAuth
 // @Summary 			Delete Folder
-// @Description 		This API for deleting folder with id
+// @Description 		This API for deleting a folder with its subfolders and their files
 // @Tags				folder-storage
 // @Accept 				json
 // @Produce 			json
@@ -254,18 +280,16 @@ func (f *ControllerFileStore) DeleteFolder(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	deletedBy := cast.ToInt(claims["sub"])
-
-	response, err := f.FileUseCase.DeleteFolder(context.Background(), userIntID, deletedBy)
+	response, err := f.FileUseCase.DeleteFolder(context.Background(), userIntID, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
 
 		return
 	}
@@ -275,13 +299,14 @@ func (f *ControllerFileStore) DeleteFolder(c *gin.Context) {
 
 // ListFile
 // @Summary 			List File
-// Description 			This API for getting list file
+// @Description 		This API for getting the files the user may see, their own, the ones they hold a role on and the ones attached to posts and menus
 // @Tags 				file-storage
 // @Accept 				json
 // @Produce 			json
 // @Param 				page query int false "Page"
 // @Param 				limit query int false "Limit"
 // @Success 			200 {object} entity.ListFileResponse
+// @Failure 			401 {object} errors.Error
 // @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/file/list [GET]
@@ -298,7 +323,14 @@ func (f *ControllerFileStore) ListFile(c *gin.Context) {
 		Limit: params.Limit,
 	}
 
-	menus, err := f.FileUseCase.ListFile(context.Background(), filter)
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	menus, err := f.FileUseCase.ListFile(context.Background(), filter, access)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 
@@ -317,6 +349,8 @@ func (f *ControllerFileStore) ListFile(c *gin.Context) {
 // @Param 				id path int true "File ID"
 // @Success 			200 {object} entity.GetFileResponse
 // @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
 // @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/file/{id} [GET]
@@ -330,9 +364,16 @@ func (f *ControllerFileStore) GetFile(c *gin.Context) {
 		return
 	}
 
-	menuResponse, err := f.FileUseCase.GetFile(context.Background(), fileID)
+	access, err := f.fileAccess(c)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	menuResponse, err := f.FileUseCase.GetFile(context.Background(), fileID, access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
 
 		return
 	}
@@ -352,6 +393,7 @@ func (f *ControllerFileStore) GetFile(c *gin.Context) {
 // @Failure 			400 {object} errors.Error
 // @Failure 			401 {object} errors.Error
 // @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
 // @Failure 			500 {object} errors.Error
 // @Router 				/v1/file [POST]
 func (f *ControllerFileStore) CreateFile(c *gin.Context) {
@@ -363,18 +405,18 @@ func (f *ControllerFileStore) CreateFile(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.CreatedBy = cast.ToInt(claims["sub"])
+	request.CreatedBy = access.UserID
 
-	menuResponse, err := f.FileUseCase.CreateFile(context.Background(), request)
+	menuResponse, err := f.FileUseCase.CreateFile(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
 
 		return
 	}
@@ -406,18 +448,18 @@ func (f *ControllerFileStore) UpdateFile(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.UpdatedBy = cast.ToInt(claims["sub"])
+	request.UpdatedBy = access.UserID
 
-	menuResponse, err := f.FileUseCase.UpdateFile(context.Background(), request)
+	menuResponse, err := f.FileUseCase.UpdateFile(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
 
 		return
 	}
@@ -449,18 +491,18 @@ func (f *ControllerFileStore) UpdateFileColumns(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	access, err := f.requiredAccess(c)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 
 		return
 	}
 
-	request.Fields["updated_by"] = cast.ToString(claims["sub"])
+	request.Fields["updated_by"] = strconv.Itoa(access.UserID)
 
-	menuResponse, err := f.FileUseCase.UpdateFileColumns(context.Background(), request)
+	menuResponse, err := f.FileUseCase.UpdateFileColumns(context.Background(), request, access)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
 
 		return
 	}
@@ -493,6 +535,74 @@ func (f *ControllerFileStore) DeleteFile(c *gin.Context) {
 		return
 	}
 
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.DeleteFile(context.Background(), userIntID, access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UploadFile
+// @Security 			BearerAuth
+// @Summary 			Upload File
+// @Description 		This API for uploading a file into a folder, the name, size, sniffed content type and SHA-256 of the upload are recorded
+// @Tags 				file-storage
+// @Accept 				multipart/form-data
+// @Produce 			json
+// @Param				file formData file true "Upload file"
+// @Param 				folder_id formData int false "Folder ID"
+// @Success 			201 {object} entity.CreateFileResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			413 {object} errors.Error
+// @Failure 			415 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Failure 			507 {object} errors.Error
+// @Router 				/v1/file/upload [POST]
+func (f *ControllerFileStore) UploadFile(c *gin.Context) {
+	header, err := f.Uploads.FormFile(c.Writer, c.Request, "file", "file")
+	if err != nil {
+		errors.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
+
+		return
+	}
+
+	inspection, err := f.Uploads.Inspect("file", header)
+	if err != nil {
+		errors.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
+
+		return
+	}
+
+	request := entity.UploadFileRequest{
+		Name:     inspection.Name,
+		Size:     header.Size,
+		MimeType: inspection.MimeType,
+	}
+
+	if folderID := c.PostForm("folder_id"); folderID != "" {
+		id, err := strconv.Atoi(folderID)
+		if err != nil {
+			errors.ErrorResponse(c, http.StatusBadRequest, "invalid folder id")
+
+			return
+		}
+
+		request.FolderID = &id
+	}
+
 	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
 	if err != nil {
 		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
@@ -500,14 +610,76 @@ func (f *ControllerFileStore) DeleteFile(c *gin.Context) {
 		return
 	}
 
-	deletedBy := cast.ToInt(claims["sub"])
+	role := cast.ToString(claims["role"])
+
+	request.CreatedBy = cast.ToInt(claims["sub"])
+	request.Admin = role == "admin" || role == "sudo"
 
-	response, err := f.FileUseCase.DeleteFile(context.Background(), userIntID, deletedBy)
+	src, err := header.Open()
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, http.StatusBadRequest, "invalid file request")
 
 		return
 	}
+	defer src.Close()
 
-	c.JSON(http.StatusOK, response)
+	response, err := f.FileUseCase.UploadFile(c.Request.Context(), request, src)
+	if err != nil {
+		errors.ErrorResponse(c, storageErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// DownloadFile
+// @Security 			BearerAuth
+// @Summary 			Download File
+// @Description 		This API for downloading the content of an uploaded file
+// @Tags 				file-storage
+// @Produce 			octet-stream
+// @Param 				id path int true "File ID"
+// @Success 			200 {file} file
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/content [GET]
+func (f *ControllerFileStore) DownloadFile(c *gin.Context) {
+	fileID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	blob, info, err := f.FileUseCase.OpenFile(c.Request.Context(), fileID, access)
+	if err != nil {
+		errors.ErrorResponse(c, accessErrorStatus(err), err.Error())
+
+		return
+	}
+	defer blob.Close()
+
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, blob, map[string]string{
+		"ETag": info.ETag,
+	})
+}
+
+func contentErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, sql.ErrNoRows), goerrors.Is(err, storage.ErrNotFound), goerrors.Is(err, storage.ErrInvalidKey):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
 }
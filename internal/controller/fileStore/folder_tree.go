@@ -0,0 +1,293 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/usecase/fileStore"
+	"database/sql"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// FolderChildren
+// @Summary 			Folder Children
+// @Description 		This API for getting the folders and files right inside a folder, the folder 0 is the root
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Success 			200 {object} entity.FolderChildrenResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/children [GET]
+func (f *ControllerFileStore) FolderChildren(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var parent *int
+	if folderID != 0 {
+		parent = &folderID
+	}
+
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.FolderChildren(c.Request.Context(), parent, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FolderPath
+// @Summary 			Folder Path
+// @Description 		This API for getting the breadcrumb of a folder, the folders from the root down to the folder
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Success 			200 {object} entity.FolderPathResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/path [GET]
+func (f *ControllerFileStore) FolderPath(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.FolderPath(c.Request.Context(), folderID, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FolderTree
+// @Summary 			Folder Tree
+// @Description 		This API for getting a folder with all its subfolders and files nested in it
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Success 			200 {object} entity.FolderTree
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/tree [GET]
+func (f *ControllerFileStore) FolderTree(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.fileAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.FolderTree(c.Request.Context(), folderID, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MoveFolder
+// @Security 			BearerAuth
+// @Summary 			Move Folder
+// @Description 		This API for moving a folder into another one, a null parent_id moves it to the root and a name renames it
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				folder body entity.MoveFolderRequest true "Move Folder Model"
+// @Success 			200 {object} entity.UpdateFolderResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/move [POST]
+func (f *ControllerFileStore) MoveFolder(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.MoveFolderRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	request.FolderID = folderID
+	request.UpdatedBy = access.UserID
+
+	response, err := f.FileUseCase.MoveFolder(c.Request.Context(), request, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CopyFolder
+// @Security 			BearerAuth
+// @Summary 			Copy Folder
+// @Description 		This API for copying a folder with its subfolders and files into another one, a null parent_id copies it to the root
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Param 				folder body entity.CopyFolderRequest true "Copy Folder Model"
+// @Success 			201 {object} entity.CreateFolderResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Failure 			507 {object} errors.Error
+// @Router 				/v1/folder/{id}/copy [POST]
+func (f *ControllerFileStore) CopyFolder(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.CopyFolderRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	request.FolderID = folderID
+	request.CreatedBy = access.UserID
+
+	response, err := f.FileUseCase.CopyFolder(c.Request.Context(), request, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RestoreFolder
+// @Security 			BearerAuth
+// @Summary 			Restore Folder
+// @Description 		This API for restoring a deleted folder with the subfolders and files deleted together with it
+// @Tags 				folder-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				id path int true "Folder ID"
+// @Success 			200 {object} entity.RestoreFolderResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/folder/{id}/restore [POST]
+func (f *ControllerFileStore) RestoreFolder(c *gin.Context) {
+	folderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.RestoreFolder(c.Request.Context(), folderID, access)
+	if err != nil {
+		errors.ErrorResponse(c, folderErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func folderErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case goerrors.Is(err, fileStore.ErrFolderCycle), goerrors.Is(err, fileStore.ErrParentDeleted):
+		return http.StatusConflict
+	default:
+		return storageErrorStatus(err)
+	}
+}
@@ -0,0 +1,293 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/pkg/upload"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/fileStore"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultChunkSize is the chunk limit when upload_chunk_max_size is not configured
+const defaultChunkSize = 8 << 20
+
+// CreateUpload
+// @Security 			BearerAuth
+// @Summary 			Create Upload
+// @Description 		This API for starting a resumable upload, the chunks are sent with PATCH to the Location of the upload
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				upload body entity.CreateUploadRequest true "Upload"
+// @Success 			201 {object} entity.UploadSession
+// @Header 				201 {string} Location "URL of the upload"
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			413 {object} errors.Error
+// @Failure 			415 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Failure 			507 {object} errors.Error
+// @Router 				/v1/file/uploads [POST]
+func (f *ControllerFileStore) CreateUpload(c *gin.Context) {
+	var request entity.CreateUploadRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	role := cast.ToString(claims["role"])
+
+	request.UserID = cast.ToInt(claims["sub"])
+	request.Admin = role == "admin" || role == "sudo"
+
+	session, err := f.FileUseCase.CreateUpload(c.Request.Context(), request)
+	if err != nil {
+		errors.ErrorResponse(c, uploadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.Header("Location", "/v1/file/uploads/"+session.ID)
+	setUploadHeaders(c, session)
+	c.JSON(http.StatusCreated, session)
+}
+
+// UploadStatus
+// @Security 			BearerAuth
+// @Summary 			Upload Status
+// @Description 		This API for getting how many bytes of a resumable upload were received, a client resumes sending from Upload-Offset
+// @Tags 				file-storage
+// @Param 				id path string true "Upload ID"
+// @Success 			200
+// @Header 				200 {int} Upload-Offset "Bytes received"
+// @Header 				200 {int} Upload-Length "Size of the upload"
+// @Header 				200 {string} Upload-Expires "Expiry of the upload"
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/uploads/{id} [HEAD]
+func (f *ControllerFileStore) UploadStatus(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	session, err := f.FileUseCase.UploadSession(c.Request.Context(), c.Param("id"), cast.ToInt(claims["sub"]))
+	if err != nil {
+		errors.ErrorResponse(c, uploadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+// UploadChunk
+// @Security 			BearerAuth
+// @Summary 			Upload Chunk
+// @Description 		This API for sending the next chunk of a resumable upload, Upload-Offset has to match the bytes received so far
+// @Tags 				file-storage
+// @Accept 				octet-stream
+// @Param 				id path string true "Upload ID"
+// @Param 				Upload-Offset header int true "Offset of the chunk"
+// @Param 				Upload-Checksum header string true "sha256 followed by the base64 SHA-256 of the chunk"
+// @Param 				chunk body string true "Chunk bytes"
+// @Success 			204
+// @Header 				204 {int} Upload-Offset "Bytes received"
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			413 {object} errors.Error
+// @Failure 			422 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/uploads/{id} [PATCH]
+func (f *ControllerFileStore) UploadChunk(c *gin.Context) {
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		errors.ErrorResponse(c, http.StatusBadRequest, "invalid Upload-Offset header")
+
+		return
+	}
+
+	checksum, err := parseChecksum(c.GetHeader("Upload-Checksum"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	maxSize := f.Conf.UploadChunkMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultChunkSize
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if goerrors.As(err, &tooLarge) {
+			errors.ErrorResponse(c, http.StatusRequestEntityTooLarge, "chunk is too large, the limit is "+strconv.FormatInt(maxSize, 10)+" bytes")
+
+			return
+		}
+
+		errors.ErrorResponse(c, http.StatusBadRequest, "invalid chunk")
+
+		return
+	}
+
+	session, err := f.FileUseCase.UploadChunk(c.Request.Context(), entity.UploadChunkRequest{
+		SessionID: c.Param("id"),
+		UserID:    cast.ToInt(claims["sub"]),
+		Offset:    offset,
+		Checksum:  checksum,
+		Data:      data,
+	})
+	if err != nil {
+		if session.ID != "" {
+			setUploadHeaders(c, session)
+		}
+
+		errors.ErrorResponse(c, uploadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteUpload
+// @Security 			BearerAuth
+// @Summary 			Complete Upload
+// @Description 		This API for turning a fully received resumable upload into a file, its content is checked like a form upload
+// @Tags 				file-storage
+// @Produce 			json
+// @Param 				id path string true "Upload ID"
+// @Success 			201 {object} entity.CreateFileResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			413 {object} errors.Error
+// @Failure 			415 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Failure 			507 {object} errors.Error
+// @Router 				/v1/file/uploads/{id}/complete [POST]
+func (f *ControllerFileStore) CompleteUpload(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.CompleteUpload(c.Request.Context(), c.Param("id"), cast.ToInt(claims["sub"]))
+	if err != nil {
+		errors.ErrorResponse(c, uploadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// AbortUpload
+// @Security 			BearerAuth
+// @Summary 			Abort Upload
+// @Description 		This API for dropping a resumable upload together with the chunks it received
+// @Tags 				file-storage
+// @Param 				id path string true "Upload ID"
+// @Success 			204
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			409 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/uploads/{id} [DELETE]
+func (f *ControllerFileStore) AbortUpload(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if err := f.FileUseCase.AbortUpload(c.Request.Context(), c.Param("id"), cast.ToInt(claims["sub"])); err != nil {
+		errors.ErrorResponse(c, uploadErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func setUploadHeaders(c *gin.Context, session entity.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Received, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Size, 10))
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// parseChecksum turns an Upload-Checksum header of the form "sha256 <base64 digest>" into a hex digest
+func parseChecksum(header string) (string, error) {
+	algorithm, digest, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(algorithm, "sha256") {
+		return "", goerrors.New("Upload-Checksum header must be 'sha256 <base64 digest>'")
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(digest))
+	if err != nil || len(sum) != 32 {
+		return "", goerrors.New("invalid sha256 digest in Upload-Checksum header")
+	}
+
+	return hex.EncodeToString(sum), nil
+}
+
+func uploadErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case goerrors.Is(err, fileStore.ErrUploadOffset), goerrors.Is(err, fileStore.ErrUploadIncomplete), goerrors.Is(err, fileStore.ErrUploadBusy):
+		return http.StatusConflict
+	case goerrors.Is(err, fileStore.ErrUploadChecksum):
+		return http.StatusUnprocessableEntity
+	case goerrors.Is(err, fileStore.ErrInvalidChunk), goerrors.Is(err, upload.ErrEmptyFile):
+		return http.StatusBadRequest
+	case goerrors.Is(err, upload.ErrTooLarge), goerrors.Is(err, upload.ErrTypeNotAllowed),
+		goerrors.Is(err, upload.ErrExecutable), goerrors.Is(err, upload.ErrPolyglot):
+		return upload.ErrorStatus(err)
+	default:
+		return storageErrorStatus(err)
+	}
+}
@@ -0,0 +1,151 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/usecase/fileStore"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// MyStorage
+// @Security 			BearerAuth
+// @Summary 			My Storage
+// @Description 		This API for getting the bytes and files the current user keeps in the file store against their quota, a zero quota is no limit
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Success 			200 {object} entity.StorageUsage
+// @Failure 			401 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/me/storage [GET]
+func (f *ControllerFileStore) MyStorage(c *gin.Context) {
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.StorageUsage(c.Request.Context(), access.UserID)
+	if err != nil {
+		errors.ErrorResponse(c, storageErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StorageReport
+// @Security 			BearerAuth
+// @Summary 			Storage Report
+// @Description 		This API for listing the users keeping the most bytes in the file store with the totals of every user, for the admins
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				limit query int false "Users to list, 20 by default and at most 100"
+// @Success 			200 {object} entity.StorageReportResponse
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/storage/report [GET]
+func (f *ControllerFileStore) StorageReport(c *gin.Context) {
+	var limit int
+
+	if value := c.Query("limit"); value != "" {
+		var err error
+
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			errors.ErrorResponse(c, http.StatusBadRequest, "invalid limit")
+
+			return
+		}
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	response, err := f.FileUseCase.StorageReport(c.Request.Context(), limit, access)
+	if err != nil {
+		errors.ErrorResponse(c, storageErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetStorageQuota
+// @Security 			BearerAuth
+// @Summary 			Set Storage Quota
+// @Description 		This API for setting the quota of a user in bytes, 0 is no limit and null falls back to the quota of their role, for the admins
+// @Tags 				file-storage
+// @Accept 				json
+// @Produce 			json
+// @Param 				user_id path int true "User ID"
+// @Param 				quota body entity.SetStorageQuotaRequest true "Storage Quota Model"
+// @Success 			200 {object} entity.StorageUsage
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/storage/users/{user_id}/quota [PUT]
+func (f *ControllerFileStore) SetStorageQuota(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.SetStorageQuotaRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	access, err := f.requiredAccess(c)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	request.UserID = userID
+	request.UpdatedBy = access.UserID
+
+	response, err := f.FileUseCase.SetStorageQuota(c.Request.Context(), request, access)
+	if err != nil {
+		errors.ErrorResponse(c, storageErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func storageErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, fileStore.ErrQuotaExceeded):
+		return http.StatusInsufficientStorage
+	case goerrors.Is(err, fileStore.ErrInvalidQuota):
+		return http.StatusBadRequest
+	case goerrors.Is(err, fileStore.ErrStorageAdmin):
+		return http.StatusForbidden
+	default:
+		return accessErrorStatus(err)
+	}
+}
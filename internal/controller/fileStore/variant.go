@@ -0,0 +1,98 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/errors"
+	"archv1/internal/pkg/imaging"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/fileStore"
+	goerrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// GetFileVariant
+// @Security 			BearerAuth
+// @Summary 			Get File Variant
+// @Description 		This API for getting a resized copy of an image file, upright and without EXIF. A zero or missing side follows the aspect ratio, the image is never enlarged
+// @Tags 				file-storage
+// @Produce 			image/jpeg
+// @Produce 			image/png
+// @Param 				id path int true "File ID"
+// @Param 				w query int false "Width"
+// @Param 				h query int false "Height"
+// @Param 				fit query string false "contain or cover, contain by default"
+// @Param 				format query string false "jpeg or png, by default jpeg for photos and png for PNG and GIF images, webp is not available yet and is answered with 400"
+// @Success 			200 {file} file
+// @Success 			304
+// @Failure 			400 {object} errors.Error
+// @Failure 			401 {object} errors.Error
+// @Failure 			403 {object} errors.Error
+// @Failure 			404 {object} errors.Error
+// @Failure 			415 {object} errors.Error
+// @Failure 			422 {object} errors.Error
+// @Failure 			500 {object} errors.Error
+// @Router 				/v1/file/{id}/variant [GET]
+func (f *ControllerFileStore) GetFileVariant(c *gin.Context) {
+	fileID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.VariantRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, f.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	role := cast.ToString(claims["role"])
+
+	request.FileID = fileID
+	request.UserID = cast.ToInt(claims["sub"])
+	request.Admin = role == "admin" || role == "sudo"
+
+	variant, content, err := f.FileUseCase.OpenVariant(c.Request.Context(), request)
+	if err != nil {
+		errors.ErrorResponse(c, variantErrorStatus(err), err.Error())
+
+		return
+	}
+	defer content.Close()
+
+	info := content.Info()
+
+	c.Header("Content-Type", variant.MimeType)
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": variant.Name}))
+	c.Header("ETag", info.ETag)
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime, content)
+}
+
+func variantErrorStatus(err error) int {
+	switch {
+	case goerrors.Is(err, fileStore.ErrInvalidVariant), goerrors.Is(err, imaging.ErrUnsupportedFormat):
+		return http.StatusBadRequest
+	case goerrors.Is(err, fileStore.ErrNotImage), goerrors.Is(err, imaging.ErrUnsupportedImage):
+		return http.StatusUnsupportedMediaType
+	case goerrors.Is(err, imaging.ErrTooManyPixels):
+		return http.StatusUnprocessableEntity
+	default:
+		return downloadErrorStatus(err)
+	}
+}
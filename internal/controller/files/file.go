@@ -6,64 +6,67 @@ import (
 	"archv1/internal/pkg/errors"
 	"archv1/internal/pkg/repo/cache"
 	"archv1/internal/pkg/repo/postgres"
+	"archv1/internal/pkg/upload"
 	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/fileStore"
 	"archv1/internal/usecase/menu"
 	"archv1/internal/usecase/post"
 	"context"
+	"database/sql"
+	goerrors "errors"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/spf13/cast"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 )
 
 type FileController struct {
-	Conf        *config.Config
-	Postgres    *postgres.DB
-	Redis       *cache.Redis
-	Enforcer    *casbin.Enforcer
-	MenuUseCase menu.MenuUseCaseI
-	PostUseCase post.PostUseCaseI
+	Conf             *config.Config
+	Postgres         *postgres.DB
+	Redis            *cache.Redis
+	Enforcer         *casbin.Enforcer
+	MenuUseCase      menu.MenuUseCaseI
+	PostUseCase      post.PostUseCaseI
+	FileStoreUseCase fileStore.FilesStoreUseCaseI
+	Uploads          *upload.Validator
 }
 
 func NewFileController(controller *FileController) *FileController {
 	return &FileController{
-		Conf:        controller.Conf,
-		Postgres:    controller.Postgres,
-		Redis:       controller.Redis,
-		Enforcer:    controller.Enforcer,
-		MenuUseCase: controller.MenuUseCase,
-		PostUseCase: controller.PostUseCase,
+		Conf:             controller.Conf,
+		Postgres:         controller.Postgres,
+		Redis:            controller.Redis,
+		Enforcer:         controller.Enforcer,
+		MenuUseCase:      controller.MenuUseCase,
+		PostUseCase:      controller.PostUseCase,
+		FileStoreUseCase: controller.FileStoreUseCase,
+		Uploads:          controller.Uploads,
 	}
 }
 
 // UploadFile
 // @Summary     	Upload File
 // @Security 		BearerAuth
-// @Description 	This API for upload a file
+// @Description 	This API for upload a file, the file is recorded in the file store and attached to the post or the menu
 // @Tags  	    	file
 // @Accept      	multipart/form-data
 // @Produce     	json
 // @Param			file formData file true "Upload file"
 // @Param 			category query string true "Category"
 // @Param 			id query string true "Object ID"
+// @Param 			folder_id query int false "Folder ID"
 // @Success     	200 {object} entity.FileUploadResponse
 // @Failure 		400 {object} errors.Error
 // @Failure 		401 {object} errors.Error
 // @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		413 {object} errors.Error
+// @Failure 		415 {object} errors.Error
 // @Failure     	500 {object} errors.Error
+// @Failure 		507 {object} errors.Error
 // @Router 			/v1/upload [POST]
 func (f *FileController) UploadFile(c *gin.Context) {
-	file, err := c.FormFile("file")
-	if err != nil {
-		errors.ErrorResponse(c, http.StatusBadRequest, "invalid file request")
-
-		return
-	}
-
 	category := c.Query("category")
 	id := c.Query("id")
 
@@ -80,22 +83,16 @@ func (f *FileController) UploadFile(c *gin.Context) {
 		return
 	}
 
-	uploadDir := "./internal/files"
-
-	err = os.MkdirAll(uploadDir, os.ModePerm)
+	file, err := f.Uploads.FormFile(c.Writer, c.Request, category, "file")
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusBadRequest, "invalid mkdir request")
+		errors.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
 
 		return
 	}
 
-	uid := uuid.NewString()
-	ext := filepath.Ext(file.Filename)
-
-	savePath := filepath.Join(uploadDir, uid+ext)
-	err = c.SaveUploadedFile(file, savePath)
+	inspection, err := f.Uploads.Inspect(category, file)
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, "error happened when save file")
+		errors.ErrorResponse(c, upload.ErrorStatus(err), err.Error())
 
 		return
 	}
@@ -108,62 +105,74 @@ func (f *FileController) UploadFile(c *gin.Context) {
 	}
 
 	userID := cast.ToInt(claims["sub"])
+	role := cast.ToString(claims["role"])
+
+	request := entity.UploadFileRequest{
+		Name:      inspection.Name,
+		Size:      file.Size,
+		MimeType:  inspection.MimeType,
+		CreatedBy: userID,
+		Admin:     role == "admin" || role == "sudo",
+	}
 
-	filePath := uid + ext
-
-	if category == "menu" {
-		err = f.MenuUseCase.AddFile(context.Background(), filePath, objectID, userID)
+	if folderID := c.Query("folder_id"); folderID != "" {
+		id, err := strconv.Atoi(folderID)
 		if err != nil {
-			errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+			errors.ErrorResponse(c, http.StatusBadRequest, "invalid folder id")
 
 			return
 		}
-	} else {
-		err = f.PostUseCase.AddFile(context.Background(), filePath, objectID, userID)
-		if err != nil {
-			errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 
-			return
-		}
+		request.FolderID = &id
 	}
 
-	c.JSON(http.StatusOK, entity.FileUploadResponse{
-		FileURL: filePath,
-	})
-}
+	src, err := file.Open()
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, "invalid file request")
 
-// GetFile
-// @Summary 		Get File
-// @Security 		BearerAuth
-// @Description 	This API for getting file with url
-// @Tags			file
-// @Accept 			json
-// @Produce 		multipart/form-data
-// @Param 			url query string true "File URL"
-// @Success 		200 {file} form-data "File Download"
-// @Failure 		400 {object} errors.Error
-// @Failure 		401 {object} errors.Error
-// @Failure 		403 {object} errors.Error
-// @Failure 		404 {object} errors.Error
-// @Failure 		500 {object} errors.Error
-// @Router 			/v1/download [GET]
-func (f *FileController) GetFile(c *gin.Context) {
-	baseURL := "./internal/files/"
+		return
+	}
+	defer src.Close()
 
-	fileURL := c.Query("url")
+	stored, err := f.FileStoreUseCase.UploadFile(c.Request.Context(), request, src)
+	if goerrors.Is(err, fileStore.ErrFolderForbidden) || goerrors.Is(err, fileStore.ErrRoleTooLow) {
+		errors.ErrorResponse(c, http.StatusForbidden, err.Error())
 
-	if fileURL == "" {
-		errors.ErrorResponse(c, http.StatusBadRequest, "url is required")
 		return
 	}
+	if goerrors.Is(err, fileStore.ErrQuotaExceeded) {
+		errors.ErrorResponse(c, http.StatusInsufficientStorage, err.Error())
 
-	file, err := os.Open(baseURL + fileURL)
+		return
+	}
 	if err != nil {
-		errors.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.ErrorResponse(c, http.StatusInternalServerError, "error happened when save file")
 
 		return
 	}
-	defer file.Close()
 
-	c.File(baseURL + fileURL)
+	if category == "menu" {
+		err = f.MenuUseCase.AttachFile(context.Background(), objectID, stored.ID, userID)
+	} else {
+		err = f.PostUseCase.AttachFile(context.Background(), objectID, stored.ID, userID)
+	}
+	if err != nil {
+		_ = f.FileStoreUseCase.DiscardFile(context.Background(), stored.ID, userID)
+		errors.ErrorResponse(c, attachErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.FileUploadResponse{
+		FileID:  stored.ID,
+		FileURL: stored.Link,
+	})
+}
+
+func attachErrorStatus(err error) int {
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
 }
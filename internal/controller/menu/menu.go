@@ -9,6 +9,8 @@ import (
 	"archv1/internal/pkg/utils"
 	"archv1/internal/usecase/menu"
 	"context"
+	"database/sql"
+	goerrors "errors"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
@@ -314,3 +316,110 @@ func (m *ControllerMenu) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// AttachFile
+// @Security 		BearerAuth
+// @Summary 		Attach File
+// @Description 	This API for attaching a file of the file store to a menu, a file can be attached to many menus
+// @Tags			menu
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Menu ID"
+// @Param 			request body entity.AttachFileRequest true "Attach File Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/menu/{id}/files [POST]
+func (m *ControllerMenu) AttachFile(c *gin.Context) {
+	menuID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.AttachFileRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, m.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	err = m.MenuUseCase.AttachFile(context.Background(), menuID, request.FileID, cast.ToInt(claims["sub"]))
+	if err != nil {
+		errors.ErrorResponse(c, fileErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// DetachFile
+// @Security 		BearerAuth
+// @Summary 		Detach File
+// @Description 	This API for detaching a file from a menu, the file itself stays in the file store
+// @Tags			menu
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Menu ID"
+// @Param 			file_id path int true "File ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/menu/{id}/files/{file_id} [DELETE]
+func (m *ControllerMenu) DetachFile(c *gin.Context) {
+	menuID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	fileID, err := strconv.Atoi(c.Param("file_id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if _, err := utils.GetTokenClaimsFromHeader(c.Request, m.Conf); err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if err := m.MenuUseCase.DetachFile(context.Background(), menuID, fileID); err != nil {
+		errors.ErrorResponse(c, fileErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+func fileErrorStatus(err error) int {
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
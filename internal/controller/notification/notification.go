@@ -0,0 +1,182 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/chat"
+	"archv1/internal/usecase/notification"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"net/http"
+	"strconv"
+)
+
+type NotificationController struct {
+	Conf                *config.Config
+	NotificationUseCase notification.NotificationUseCaseI
+}
+
+func NewNotificationController(controller *NotificationController) *NotificationController {
+	return &NotificationController{
+		Conf:                controller.Conf,
+		NotificationUseCase: controller.NotificationUseCase,
+	}
+}
+
+// Notifications
+// @Security		BearerAuth
+// @Summary 		Notifications
+// @Description 	This API for getting the notification inbox of the current user, the chats with unread messages
+// @Tags 			notification
+// @Produce 		json
+// @Success 		200 {object} entity.NotificationsResponse
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/notifications [GET]
+func (n *NotificationController) Notifications(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, n.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := n.NotificationUseCase.Notifications(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReadAll
+// @Security		BearerAuth
+// @Summary 		Read All Notifications
+// @Description 	This API for marking every chat in the notification inbox of the current user as read
+// @Tags 			notification
+// @Produce 		json
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/notifications/read [POST]
+func (n *NotificationController) ReadAll(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, n.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = n.NotificationUseCase.MarkAllRead(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// ReadChat
+// @Security		BearerAuth
+// @Summary 		Read Chat Notifications
+// @Description 	This API for marking a chat as read up to its latest message, which clears its notifications
+// @Tags 			notification
+// @Produce 		json
+// @Param 			chat_id path int true "Chat ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/notifications/{chat_id}/read [POST]
+func (n *NotificationController) ReadChat(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("chat_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, n.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = n.NotificationUseCase.MarkRead(context.Background(), cast.ToInt64(claims["sub"]), int64(chatID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// MuteChat
+// @Security		BearerAuth
+// @Summary 		Mute Chat
+// @Description 	This API for muting or unmuting the notifications of a chat, duration is in seconds and 0 mutes until unmuted
+// @Tags 			notification
+// @Accept 			json
+// @Produce 		json
+// @Param 			chat_id path int true "Chat ID"
+// @Param 			request body entity.MuteChatRequest true "Mute Chat Model"
+// @Success 		200 {object} entity.MuteSetting
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/notifications/{chat_id}/mute [PUT]
+func (n *NotificationController) MuteChat(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("chat_id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request entity.MuteChatRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, n.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.ChatID = int64(chatID)
+	request.UserID = cast.ToInt64(claims["sub"])
+
+	response, err := n.NotificationUseCase.MuteChat(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, chat.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, notification.ErrInvalidDuration):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
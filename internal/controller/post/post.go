@@ -9,6 +9,8 @@ import (
 	"archv1/internal/pkg/utils"
 	"archv1/internal/usecase/post"
 	"context"
+	"database/sql"
+	goerrors "errors"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
@@ -275,3 +277,110 @@ func (p *ControllerPost) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// AttachFile
+// @Security 		BearerAuth
+// @Summary 		Attach File
+// @Description 	This API for attaching a file of the file store to a post, a file can be attached to many posts
+// @Tags			post
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Post ID"
+// @Param 			request body entity.AttachFileRequest true "Attach File Model"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/post/{id}/files [POST]
+func (p *ControllerPost) AttachFile(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	var request entity.AttachFileRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	err = p.PostUseCase.AttachFile(context.Background(), postID, request.FileID, cast.ToInt(claims["sub"]))
+	if err != nil {
+		errors.ErrorResponse(c, fileErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// DetachFile
+// @Security 		BearerAuth
+// @Summary 		Detach File
+// @Description 	This API for detaching a file from a post, the file itself stays in the file store
+// @Tags			post
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path int true "Post ID"
+// @Param 			file_id path int true "File ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		403 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/post/{id}/files/{file_id} [DELETE]
+func (p *ControllerPost) DetachFile(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	fileID, err := strconv.Atoi(c.Param("file_id"))
+	if err != nil {
+		errors.ErrorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	if _, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf); err != nil {
+		errors.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if err := p.PostUseCase.DetachFile(context.Background(), postID, fileID); err != nil {
+		errors.ErrorResponse(c, fileErrorStatus(err), err.Error())
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+func fileErrorStatus(err error) int {
+	if goerrors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,396 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	handle "archv1/internal/pkg/errors"
+	"archv1/internal/pkg/utils"
+	"archv1/internal/usecase/push"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"net/http"
+	"strconv"
+)
+
+type PushController struct {
+	Conf        *config.Config
+	PushUseCase push.PushUseCaseI
+}
+
+func NewPushController(controller *PushController) *PushController {
+	return &PushController{
+		Conf:        controller.Conf,
+		PushUseCase: controller.PushUseCase,
+	}
+}
+
+// VAPIDKey
+// @Security		BearerAuth
+// @Summary 		VAPID Key
+// @Description 	This API for getting the application server key to subscribe a browser to Web Push with
+// @Tags 			push
+// @Produce 		json
+// @Success 		200 {object} entity.VAPIDKeyResponse
+// @Failure 		401 {object} errors.Error
+// @Failure 		503 {object} errors.Error
+// @Router 			/v1/push/vapid-key [GET]
+func (p *PushController) VAPIDKey(c *gin.Context) {
+	response, err := p.PushUseCase.VAPIDKey()
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Subscribe
+// @Security		BearerAuth
+// @Summary 		Subscribe Push
+// @Description 	This API for registering the Web Push subscription of a browser, the body is the browser PushSubscription JSON
+// @Tags 			push
+// @Accept 			json
+// @Produce 		json
+// @Param 			request body entity.SubscribePushRequest true "Push Subscription Model"
+// @Success 		200 {object} entity.PushSubscription
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		409 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Failure 		503 {object} errors.Error
+// @Router 			/v1/push/subscriptions [POST]
+func (p *PushController) Subscribe(c *gin.Context) {
+	var request entity.SubscribePushRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.UserID = cast.ToInt64(claims["sub"])
+	request.UserAgent = c.Request.UserAgent()
+
+	response, err := p.PushUseCase.Subscribe(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Subscriptions
+// @Security		BearerAuth
+// @Summary 		Push Subscriptions
+// @Description 	This API for getting the Web Push subscriptions of the current user
+// @Tags 			push
+// @Produce 		json
+// @Success 		200 {object} entity.PushSubscriptionsResponse
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/subscriptions [GET]
+func (p *PushController) Subscriptions(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := p.PushUseCase.Subscriptions(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Unsubscribe
+// @Security		BearerAuth
+// @Summary 		Unsubscribe Push
+// @Description 	This API for removing a Web Push subscription of the current user
+// @Tags 			push
+// @Produce 		json
+// @Param 			id path int true "Subscription ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/subscriptions/{id} [DELETE]
+func (p *PushController) Unsubscribe(c *gin.Context) {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = p.PushUseCase.Unsubscribe(context.Background(), cast.ToInt64(claims["sub"]), int64(subscriptionID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// CreateWebhook
+// @Security		BearerAuth
+// @Summary 		Create Webhook
+// @Description 	This API for registering a webhook called with the messages the current user receives while offline, the signing secret is only returned here
+// @Tags 			push
+// @Accept 			json
+// @Produce 		json
+// @Param 			request body entity.CreateWebhookRequest true "Webhook Model"
+// @Success 		200 {object} entity.CreateWebhookResponse
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/webhooks [POST]
+func (p *PushController) CreateWebhook(c *gin.Context) {
+	var request entity.CreateWebhookRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.UserID = cast.ToInt64(claims["sub"])
+
+	response, err := p.PushUseCase.CreateWebhook(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Webhooks
+// @Security		BearerAuth
+// @Summary 		Webhooks
+// @Description 	This API for getting the webhooks of the current user
+// @Tags 			push
+// @Produce 		json
+// @Success 		200 {object} entity.WebhooksResponse
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/webhooks [GET]
+func (p *PushController) Webhooks(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := p.PushUseCase.Webhooks(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteWebhook
+// @Security		BearerAuth
+// @Summary 		Delete Webhook
+// @Description 	This API for removing a webhook of the current user
+// @Tags 			push
+// @Produce 		json
+// @Param 			id path int true "Webhook ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/webhooks/{id} [DELETE]
+func (p *PushController) DeleteWebhook(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = p.PushUseCase.DeleteWebhook(context.Background(), cast.ToInt64(claims["sub"]), int64(webhookID))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+// QuietHours
+// @Security		BearerAuth
+// @Summary 		Quiet Hours
+// @Description 	This API for getting the quiet hours of the current user
+// @Tags 			push
+// @Produce 		json
+// @Success 		200 {object} entity.QuietHours
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/quiet-hours [GET]
+func (p *PushController) QuietHours(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := p.PushUseCase.QuietHours(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetQuietHours
+// @Security		BearerAuth
+// @Summary 		Set Quiet Hours
+// @Description 	This API for setting the quiet hours of the current user, push deliveries wait for their end
+// @Tags 			push
+// @Accept 			json
+// @Produce 		json
+// @Param 			request body entity.QuietHours true "Quiet Hours Model"
+// @Success 		200 {object} entity.QuietHours
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/quiet-hours [PUT]
+func (p *PushController) SetQuietHours(c *gin.Context) {
+	var request entity.QuietHours
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request.UserID = cast.ToInt64(claims["sub"])
+
+	response, err := p.PushUseCase.SetQuietHours(context.Background(), request)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeadJobs
+// @Security		BearerAuth
+// @Summary 		Failed Deliveries
+// @Description 	This API for getting the push deliveries of the current user which failed every attempt
+// @Tags 			push
+// @Produce 		json
+// @Success 		200 {object} entity.PushJobsResponse
+// @Failure 		401 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/dead-letters [GET]
+func (p *PushController) DeadJobs(c *gin.Context) {
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	response, err := p.PushUseCase.DeadJobs(context.Background(), cast.ToInt64(claims["sub"]))
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RetryJob
+// @Security		BearerAuth
+// @Summary 		Retry Delivery
+// @Description 	This API for queueing a failed push delivery of the current user again
+// @Tags 			push
+// @Produce 		json
+// @Param 			id path int true "Job ID"
+// @Success 		200 {object} entity.ResponseWithStatus
+// @Failure 		400 {object} errors.Error
+// @Failure 		401 {object} errors.Error
+// @Failure 		404 {object} errors.Error
+// @Failure 		500 {object} errors.Error
+// @Router 			/v1/push/dead-letters/{id}/retry [POST]
+func (p *PushController) RetryJob(c *gin.Context) {
+	jobID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := utils.GetTokenClaimsFromHeader(c.Request, p.Conf)
+	if err != nil {
+		handle.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = p.PushUseCase.RetryJob(context.Background(), cast.ToInt64(claims["sub"]), jobID)
+	if err != nil {
+		handle.ErrorResponse(c, errorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.ResponseWithStatus{
+		Status: true,
+	})
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, push.ErrPushDisabled):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, push.ErrSubscriptionTaken):
+		return http.StatusConflict
+	case errors.Is(err, push.ErrInvalidSubscription), errors.Is(err, push.ErrInvalidWebhook),
+		errors.Is(err, push.ErrInvalidQuietHours):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Blob is stored content shared by every file with the same SHA-256, RefCount counts the files holding it.
+// A blob nobody holds is removed once the collection grace period has passed.
+type Blob struct {
+	Key         string
+	Checksum    string
+	Size        int64
+	RefCount    int
+	VerifiedAt  *time.Time
+	CorruptedAt *time.Time
+}
@@ -1,10 +1,13 @@
 package entity
 
+import "time"
+
 type Groups struct {
 	ID          *int    `bun:"id"`
 	Name        string  `bun:"name"`
 	Username    string  `bun:"username"`
 	Description *string `bun:"description"`
+	Visibility  string  `bun:"visibility"`
 	CreatedBy   int     `bun:"created_by"`
 }
 
@@ -21,12 +24,15 @@ type GetGroupResponse struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+	SlowMode    int    `json:"slow_mode"`
 }
 
 type CreateGroupRequest struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
 	CreatedBy   int    `json:"-"`
 }
 
@@ -35,6 +41,7 @@ type CreateGroupResponse struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
 }
 
 type UpdateGroupRequest struct {
@@ -42,6 +49,7 @@ type UpdateGroupRequest struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
 	UpdatedBy   int    `json:"-"`
 }
 
@@ -50,29 +58,143 @@ type UpdateGroupResponse struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
 }
 
 type UpdateGroupColumns struct {
-	GroupID int               `json:"group_id"`
-	Fields  map[string]string `json:"fields"`
+	GroupID   int               `json:"group_id"`
+	Fields    map[string]string `json:"fields"`
+	UpdatedBy int               `json:"-"`
 }
 
 type DeleteGroupResponse struct {
 	Message string `json:"message"`
 }
 
+// Group roles from the most to the least privileged
+const (
+	GroupRoleOwner     = "owner"
+	GroupRoleAdmin     = "admin"
+	GroupRoleModerator = "moderator"
+	GroupRoleMember    = "member"
+)
+
+// Group visibility, public groups can be joined by username
+const (
+	GroupPublic  = "public"
+	GroupPrivate = "private"
+)
+
+// Join request statuses
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+type GroupMember struct {
+	GroupID  int       `json:"group_id"`
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type GroupMembersResponse struct {
+	Members []GroupMember `json:"members"`
+}
+
+type SetGroupRoleRequest struct {
+	GroupID int    `json:"group_id"`
+	UserID  int    `json:"user_id"`
+	Role    string `json:"role"`
+}
+
+type TransferOwnershipRequest struct {
+	GroupID int `json:"group_id"`
+	UserID  int `json:"user_id"`
+}
+
+// CreateInviteRequest creates an invite link valid for ExpiresIn seconds and UsageLimit joins, 0 means no limit
+type CreateInviteRequest struct {
+	ExpiresIn  int64 `json:"expires_in"`
+	UsageLimit int   `json:"usage_limit"`
+	GroupID    int   `json:"-"`
+	CreatedBy  int   `json:"-"`
+}
+
+type GroupInvite struct {
+	ID         int        `json:"id"`
+	GroupID    int        `json:"group_id"`
+	Token      string     `json:"token"`
+	Link       string     `json:"link"`
+	UsageLimit *int       `json:"usage_limit"`
+	UsageCount int        `json:"usage_count"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedBy  int        `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type GroupInvitesResponse struct {
+	Invites []GroupInvite `json:"invites"`
+}
+
+type JoinGroupRequest struct {
+	Username string `json:"username"`
+}
+
+// JoinGroupResponse has the status "joined", or the status of the join request created for a private group
+type JoinGroupResponse struct {
+	GroupID   int    `json:"group_id"`
+	Status    string `json:"status"`
+	RequestID int    `json:"request_id,omitempty"`
+}
+
+type JoinRequest struct {
+	ID         int        `json:"id"`
+	GroupID    int        `json:"group_id"`
+	UserID     int        `json:"user_id"`
+	Username   string     `json:"username"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedBy *int       `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+type JoinRequestsResponse struct {
+	Requests []JoinRequest `json:"requests"`
+}
+
+type ReviewJoinRequest struct {
+	Approve bool `json:"approve"`
+}
+
 type CreatedChatResponse struct {
 	ChatId     int    `json:"chat_id"`
 	ChatType   string `json:"chat_type"`
 	ReceiverID int    `json:"receiver_id"`
 }
 
+type ChatPeer struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// UserChat is a chat in the list of the user's chats. Title is the counterpart's username
+// for a private chat and the group name for a group chat.
+type UserChat struct {
+	ChatID            int          `json:"chat_id"`
+	ChatType          string       `json:"chat_type"`
+	ReceiverID        int          `json:"receiver_id"`
+	Title             string       `json:"title"`
+	Counterpart       *ChatPeer    `json:"counterpart,omitempty"`
+	LastMessage       *ChatMessage `json:"last_message"`
+	LastReadMessageID int          `json:"last_read_message_id"`
+	UnreadCount       int          `json:"unread_count"`
+}
+
 type UserChatsResponse struct {
-	Chats []struct {
-		ChatID     int    `json:"chat_id"`
-		ChatType   string `json:"chat_type"`
-		ReceiverID int    `json:"receiver_id"`
-	}
+	Chats []UserChat `json:"chats"`
 }
 
 type Chat struct {
@@ -81,42 +203,103 @@ type Chat struct {
 	ReceiverID int    `json:"receiver_id"`
 }
 
+// Message kinds, system messages are written by the server only
+const (
+	MessageText   = "text"
+	MessageImage  = "image"
+	MessageFile   = "file"
+	MessageVoice  = "voice"
+	MessageSystem = "system"
+)
+
 type Message struct {
-	ID          int    `json:"id"`
-	ChatId      int    `json:"chat_id"`
-	Content     string `json:"content"`
-	MessageType string `json:"message_type"`
-	Sender      int    `json:"sender"`
+	ID            int             `json:"id"`
+	ChatId        int             `json:"chat_id"`
+	Content       string          `json:"content"`
+	MessageType   string          `json:"message_type"`
+	Sender        int             `json:"sender"`
+	Attachments   []Attachment    `json:"attachments"`
+	ReplyTo       *MessagePreview `json:"reply_to"`
+	ForwardedFrom *ForwardOrigin  `json:"forwarded_from"`
+	CreatedAt     time.Time       `json:"created_at"`
 }
 
+// SendMessageRequest sends a message, Attachments are IDs of files the sender uploaded to the chat
+// and ReplyTo is the ID of a message of the same chat
 type SendMessageRequest struct {
-	ChatID      int    `json:"chat_id"`
-	ChatType    string `json:"chat_type"`
-	Message     string `json:"message"`
-	MessageType string `json:"message_type"`
+	ChatID        int            `json:"chat_id"`
+	ChatType      string         `json:"chat_type"`
+	Message       string         `json:"message"`
+	MessageType   string         `json:"message_type"`
+	Attachments   []int          `json:"attachments"`
+	ReplyTo       int            `json:"reply_to"`
+	ForwardedFrom *ForwardOrigin `json:"-"`
+	Sender        int            `json:"sender"`
+	Receiver      int            `json:"receiver"`
+}
+
+// MessagePreview quotes the message replied to, Deleted is set once the message is removed
+type MessagePreview struct {
+	MessageID   int    `json:"message_id"`
 	Sender      int    `json:"sender"`
-	Receiver    int    `json:"receiver"`
+	Content     string `json:"content"`
+	MessageType string `json:"message_type"`
+	Deleted     bool   `json:"deleted"`
+}
+
+// ForwardOrigin is the original author of a forwarded message, kept across repeated forwards
+type ForwardOrigin struct {
+	UserID    int `json:"user_id"`
+	MessageID int `json:"message_id"`
 }
 
-type SendMessageData struct {
-	Action   string `json:"action"`
-	Property struct {
-		ChatID      int    `json:"chat_id"`
-		ChatType    string `json:"chat_type"`
-		Message     string `json:"message"`
-		MessageType string `json:"message_type"`
-		Sender      int    `json:"sender"`
-		Receiver    int    `json:"receiver"`
-	} `json:"property"`
+type ForwardMessageRequest struct {
+	MessageID int `json:"message_id"`
+	ChatID    int `json:"chat_id"`
+	Sender    int `json:"-"`
+}
+
+// ReactionCount aggregates a reaction of a message, Reacted tells whether the reading user is among them
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+type ReactionRequest struct {
+	MessageID int    `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	UserID    int    `json:"-"`
 }
 
-type UpdateMessageData struct {
-	Action   string `json:"action"`
-	Property struct {
-		ChatID     int    `json:"chat_id"`
-		MessageID  int    `json:"message_id"`
-		NewMessage string `json:"new_message"`
-	} `json:"property"`
+type PinnedMessage struct {
+	Message  ChatMessage `json:"message"`
+	PinnedBy int         `json:"pinned_by"`
+	PinnedAt time.Time   `json:"pinned_at"`
+}
+
+type PinnedMessagesResponse struct {
+	Messages []PinnedMessage `json:"messages"`
+}
+
+type Attachment struct {
+	FileID   int    `json:"file_id"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+	Width    *int   `json:"width,omitempty"`
+	Height   *int   `json:"height,omitempty"`
+}
+
+// ChatFile is a file uploaded to a chat, only participants of the chat can download it
+// ChatFile is a file uploaded to a chat, BlobKey is empty for the files stored under their link before
+// the chat files were stored once per content
+type ChatFile struct {
+	Attachment
+	ChatID     int
+	Link       string
+	BlobKey    string
+	UploadedBy int
 }
 
 type UpdateMessageRequest struct {
@@ -127,37 +310,92 @@ type UpdateMessageRequest struct {
 	Receiver   int    `json:"receiver"`
 }
 
-type DeleteMessageData struct {
-	Action   string `json:"action"`
-	Property struct {
-		ChatID    int    `json:"chat_id"`
-		ChatType  string `json:"chat_type"`
-		MessageID int    `json:"message_id"`
-	} `json:"property"`
-}
-
 type DeleteMessageRequest struct {
 	ChatID    int    `json:"chat_id"`
 	ChatType  string `json:"chat_type"`
 	MessageID int    `json:"message_id"`
 }
 
+type ChatMessage struct {
+	MessageID     int             `json:"message_id"`
+	ChatID        int             `json:"chat_id"`
+	Sender        int             `json:"sender"`
+	Message       string          `json:"content"`
+	MessageType   string          `json:"message_type"`
+	Attachments   []Attachment    `json:"attachments"`
+	ReplyTo       *MessagePreview `json:"reply_to"`
+	ForwardedFrom *ForwardOrigin  `json:"forwarded_from"`
+	Reactions     []ReactionCount `json:"reactions"`
+	Edited        bool            `json:"edited"`
+	Deleted       bool            `json:"deleted"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     *time.Time      `json:"updated_at"`
+}
+
+// Delete modes of a message, a message deleted for everyone stays in the chat history as a tombstone
+const (
+	DeleteForMe       = "me"
+	DeleteForEveryone = "everyone"
+)
+
+// MessageEdit is a previous version of an edited message, EditedAt is when it was replaced
+type MessageEdit struct {
+	Content  string    `json:"content"`
+	EditedBy int       `json:"edited_by"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// MessageHistoryResponse holds the current content of the message and its previous versions, the oldest first
+type MessageHistoryResponse struct {
+	MessageID int           `json:"message_id"`
+	Content   string        `json:"content"`
+	Edits     []MessageEdit `json:"edits"`
+}
+
+// MessageFilter selects a page of chat messages. Before and After page backwards and forwards from
+// a message ID, Around jumps to a message and returns the messages on both sides of it.
+// Without a cursor the latest messages are returned. UserID is the participant reading the chat.
+type MessageFilter struct {
+	ChatID int64
+	UserID int64
+	Before int64
+	After  int64
+	Around int64
+	Limit  int64
+}
+
 type ChatMessagesResponse struct {
-	Messages []struct {
-		MessageID   int    `json:"message_id"`
-		ChatID      int    `json:"chat_id"`
-		Sender      int    `json:"sender"`
-		Message     string `json:"content"`
-		MessageType string `json:"message_type"`
-	} `json:"messages"`
-}
-
-type NotificationsResponse struct {
-	Notifications []struct {
-		ChatID             int    `json:"chat_id"`
-		ChatType           string `json:"chat_type"`
-		LatestSender       int    `json:"latest_sender"`
-		LatestMessage      string `json:"latest_message"`
-		TotalMessagesCount int    `json:"total_messages_count"`
-	} `json:"notifications"`
+	Messages  []ChatMessage `json:"messages"`
+	HasBefore bool          `json:"has_before"`
+	HasAfter  bool          `json:"has_after"`
+}
+
+// SearchMessagesRequest searches the chat with ChatID, or every chat of UserID when ChatID is 0.
+// Results are ordered from the newest message, Before continues from the last returned message.
+type SearchMessagesRequest struct {
+	Query  string
+	ChatID int64
+	UserID int64
+	Before int64
+	Limit  int64
+}
+
+type SearchMessagesResponse struct {
+	Messages []ChatMessage `json:"messages"`
+}
+
+type ChatMember struct {
+	ChatID                 int        `json:"chat_id"`
+	UserID                 int        `json:"user_id"`
+	LastDeliveredMessageID int        `json:"last_delivered_message_id"`
+	LastReadMessageID      int        `json:"last_read_message_id"`
+	ReadAt                 *time.Time `json:"read_at"`
+}
+
+type ChatReceiptsResponse struct {
+	Members []ChatMember `json:"members"`
+}
+
+type MarkReadRequest struct {
+	MessageID int `json:"message_id"`
 }
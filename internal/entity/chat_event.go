@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProtocolVersion is the version of the websocket envelope spoken by the server
+const ProtocolVersion = 1
+
+// Client -> server commands
+const (
+	FrameSendMessage   = "message.send"
+	FrameEditMessage   = "message.edit"
+	FrameDeleteMessage = "message.delete"
+	FrameTyping        = "chat.typing"
+	FrameMarkRead      = "chat.read"
+	FrameSubscribe     = "chat.subscribe"
+	FrameForward       = "message.forward"
+	FrameReact         = "message.react"
+	FramePin           = "message.pin"
+	FrameUnpin         = "message.unpin"
+)
+
+// Server -> client events
+const (
+	FrameAck            = "ack"
+	FrameError          = "error"
+	FrameSession        = "session"
+	FrameMessageNew     = "message.new"
+	FrameMessageUpdated = "message.updated"
+	FrameMessageDeleted = "message.deleted"
+	FrameDelivered      = "message.delivered"
+	FrameMemberJoined   = "group.member_joined"
+	FrameMemberLeft     = "group.member_left"
+	FrameReaction       = "message.reaction"
+	FramePinned         = "message.pinned"
+	FrameUnpinned       = "message.unpinned"
+	FrameMessageHidden  = "message.hidden"
+	FrameRestricted     = "group.member_restricted"
+	FrameReported       = "message.reported"
+)
+
+// Error frame codes
+const (
+	ErrCodeBadRequest  = "bad_request"
+	ErrCodeUnsupported = "unsupported"
+	ErrCodeForbidden   = "forbidden"
+	ErrCodeNotFound    = "not_found"
+	ErrCodeInternal    = "internal"
+	ErrCodeRateLimited = "rate_limited"
+)
+
+// Frame is the envelope of every websocket message in both directions.
+// CorrelationID of ack and error frames holds the ID of the command they answer.
+// Seq numbers the events stored in the user's event log; a client may receive an event twice
+// while resuming and should drop frames whose seq is not greater than the last one it processed.
+type Frame struct {
+	Version       int             `json:"v"`
+	Type          string          `json:"type"`
+	ID            string          `json:"id,omitempty"`
+	Seq           int64           `json:"seq,omitempty"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+}
+
+// SessionEvent is sent when the connection opens. SyncRequired is set when the requested
+// events are no longer retained and the client has to reload its chats through the REST API.
+type SessionEvent struct {
+	Seq          int64 `json:"seq"`
+	Replayed     int   `json:"replayed"`
+	SyncRequired bool  `json:"sync_required"`
+}
+
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DeleteMessageCommand deletes a message, Scope is DeleteForMe or DeleteForEveryone (the default)
+type DeleteMessageCommand struct {
+	MessageID int    `json:"message_id"`
+	Scope     string `json:"scope"`
+}
+
+// PinCommand is the payload of FramePin and FrameUnpin
+type PinCommand struct {
+	MessageID int `json:"message_id"`
+}
+
+// Chat signal actions of FrameTyping
+const (
+	SignalTyping    = "typing"
+	SignalRecording = "recording_voice"
+	SignalStopped   = "stopped"
+)
+
+// TypingCommand is the payload of FrameTyping, Action is one of the chat signal actions (typing by default)
+type TypingCommand struct {
+	ChatID int    `json:"chat_id"`
+	Action string `json:"action"`
+}
+
+type MarkReadCommand struct {
+	ChatID    int `json:"chat_id"`
+	MessageID int `json:"message_id"`
+}
+
+type SubscribeCommand struct {
+	ChatIDs []int `json:"chat_ids"`
+}
+
+type MessageEvent struct {
+	MessageID     int             `json:"message_id"`
+	ChatID        int             `json:"chat_id"`
+	ChatType      string          `json:"chat_type"`
+	Content       string          `json:"content"`
+	MessageType   string          `json:"message_type"`
+	Attachments   []Attachment    `json:"attachments,omitempty"`
+	ReplyTo       *MessagePreview `json:"reply_to,omitempty"`
+	ForwardedFrom *ForwardOrigin  `json:"forwarded_from,omitempty"`
+	Edited        bool            `json:"edited"`
+	Sender        int             `json:"sender"`
+	Receiver      int             `json:"receiver"`
+}
+
+// TypingEvent is sent for FrameTyping to the other members which opened the chat. It is never stored,
+// a signal ends with a stopped event once the user stops, disconnects or does not refresh it within ExpiresIn seconds.
+type TypingEvent struct {
+	ChatID    int    `json:"chat_id"`
+	UserID    int    `json:"user_id"`
+	Action    string `json:"action"`
+	ExpiresIn int    `json:"expires_in,omitempty"`
+}
+
+// ReceiptEvent is sent to the other chat members for FrameDelivered and FrameMarkRead
+type ReceiptEvent struct {
+	ChatID    int `json:"chat_id"`
+	UserID    int `json:"user_id"`
+	MessageID int `json:"message_id"`
+}
+
+// GroupMemberEvent is sent to the group members for FrameMemberJoined and FrameMemberLeft.
+// ActorID is the member who added or removed the user, or the user itself.
+type GroupMemberEvent struct {
+	GroupID int `json:"group_id"`
+	UserID  int `json:"user_id"`
+	ActorID int `json:"actor_id"`
+}
+
+// RestrictionEvent is sent to the group members and the restricted user for FrameRestricted,
+// Lifted is set when the mute or ban is removed before it expires
+type RestrictionEvent struct {
+	GroupID   int        `json:"group_id"`
+	UserID    int        `json:"user_id"`
+	ActorID   int        `json:"actor_id"`
+	Kind      string     `json:"kind"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Lifted    bool       `json:"lifted"`
+}
+
+// ReactionEvent is sent to the chat members for FrameReaction when UserID adds or removes a reaction.
+// Reactions holds the counts after the change, their Reacted flag is not set.
+type ReactionEvent struct {
+	ChatID    int             `json:"chat_id"`
+	MessageID int             `json:"message_id"`
+	UserID    int             `json:"user_id"`
+	Emoji     string          `json:"emoji"`
+	Added     bool            `json:"added"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+// PinEvent is sent to the chat members for FramePinned and FrameUnpinned
+type PinEvent struct {
+	ChatID    int `json:"chat_id"`
+	MessageID int `json:"message_id"`
+	UserID    int `json:"user_id"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// DownloadLinkRequest asks for a signed link to a file, ExpiresIn is in seconds and 0 uses the default lifetime
+type DownloadLinkRequest struct {
+	ExpiresIn int    `json:"expires_in" xml:"expires_in" yaml:"expires_in" toml:"expires_in" form:"expires_in" query:"expires_in"`
+	SingleUse bool   `json:"single_use" xml:"single_use" yaml:"single_use" toml:"single_use" form:"single_use" query:"single_use"`
+	BindIP    bool   `json:"bind_ip" xml:"bind_ip" yaml:"bind_ip" toml:"bind_ip" form:"bind_ip" query:"bind_ip"`
+	Inline    bool   `json:"inline" xml:"inline" yaml:"inline" toml:"inline" form:"inline" query:"inline"`
+	FileID    int    `json:"-"`
+	UserID    int    `json:"-"`
+	Admin     bool   `json:"-"`
+	IP        string `json:"-"`
+}
+
+type DownloadLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SingleUse bool      `json:"single_use"`
+}
+
+// Download is a file opened through a download link
+type Download struct {
+	Name     string
+	MimeType string
+	Inline   bool
+}
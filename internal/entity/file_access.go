@@ -0,0 +1,78 @@
+package entity
+
+import "time"
+
+const (
+	FileRoleViewer = "viewer"
+	FileRoleEditor = "editor"
+	FileRoleOwner  = "owner"
+)
+
+// FileAccess is who reads or changes the file store, a zero UserID is a request without a token and the admins pass every check
+type FileAccess struct {
+	UserID int
+	Admin  bool
+}
+
+// AccessTarget is the folder or the file a grant or a share link is on, exactly one of them is set
+type AccessTarget struct {
+	FolderID *int
+	FileID   *int
+}
+
+// AccessGrant gives a user or the members of a chat group a role on a folder, with everything below it, or on a file
+type AccessGrant struct {
+	ID        int       `json:"id"`
+	FolderID  *int      `json:"folder_id"`
+	FileID    *int      `json:"file_id"`
+	UserID    *int      `json:"user_id"`
+	GroupID   *int      `json:"group_id"`
+	Role      string    `json:"role"`
+	CreatedBy *int      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GrantAccessRequest gives a role to a user or to a chat group, granting again replaces the role
+type GrantAccessRequest struct {
+	UserID    *int         `json:"user_id" xml:"user_id" yaml:"user_id" toml:"user_id" form:"user_id" query:"user_id"`
+	GroupID   *int         `json:"group_id" xml:"group_id" yaml:"group_id" toml:"group_id" form:"group_id" query:"group_id"`
+	Role      string       `json:"role" xml:"role" yaml:"role" toml:"role" form:"role" query:"role"`
+	Target    AccessTarget `json:"-"`
+	CreatedBy int          `json:"-"`
+}
+
+// CreateShareLinkRequest creates a link valid for ExpiresIn seconds, 0 means it does not expire, an empty Password leaves it open
+type CreateShareLinkRequest struct {
+	ExpiresIn int64        `json:"expires_in" xml:"expires_in" yaml:"expires_in" toml:"expires_in" form:"expires_in" query:"expires_in"`
+	Password  string       `json:"password" xml:"password" yaml:"password" toml:"password" form:"password" query:"password"`
+	Target    AccessTarget `json:"-"`
+	CreatedBy int          `json:"-"`
+}
+
+type ShareLink struct {
+	ID           int        `json:"id"`
+	Token        string     `json:"token"`
+	Link         string     `json:"link"`
+	FolderID     *int       `json:"folder_id"`
+	FileID       *int       `json:"file_id"`
+	Protected    bool       `json:"protected"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	CreatedBy    int        `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	PasswordHash string     `json:"-"`
+}
+
+// ListAccessResponse holds the owner of a folder or a file, the grants on it and above it and its usable share links
+type ListAccessResponse struct {
+	Owner     *int           `json:"owner"`
+	Grants    []*AccessGrant `json:"grants"`
+	Inherited []*AccessGrant `json:"inherited"`
+	Links     []*ShareLink   `json:"links"`
+}
+
+// SharedItemResponse is what a share link opens, a file or a folder with everything below it
+type SharedItemResponse struct {
+	File      *GetFileResponse `json:"file"`
+	Folder    *FolderTree      `json:"folder"`
+	ExpiresAt *time.Time       `json:"expires_at"`
+}
@@ -13,6 +13,11 @@ type Files struct {
 	Type      string `json:"type" bun:"type"`
 	Link      string `json:"link" bun:"link"`
 	FolderID  *int   `json:"folder_id" bun:"folder_id"`
+	Name      string `json:"name" bun:"name"`
+	Size      int64  `json:"size" bun:"size"`
+	MimeType  string `json:"mime_type" bun:"mime_type"`
+	Checksum  string `json:"checksum" bun:"checksum"`
+	BlobKey   string `json:"-" bun:"blob_key,nullzero"`
 	CreatedBy *int   `json:"created_by" bun:"created_by"`
 	UpdatedBy *int   `json:"updated_by" bun:"updated_by"`
 }
@@ -47,8 +52,11 @@ type UpdateFolderResponse struct {
 	ParentID *int   `json:"parent_id"`
 }
 
+// DeleteFolderResponse counts the folders and files deleted with the folder, they are restored together
 type DeleteFolderResponse struct {
 	Message string `json:"message"`
+	Folders int64  `json:"folders"`
+	Files   int64  `json:"files"`
 }
 
 type GetFolderResponse struct {
@@ -66,14 +74,34 @@ type CreateFileRequest struct {
 	Type      string `json:"type" xml:"type" yaml:"type" toml:"type" form:"type" query:"type"`
 	Link      string `json:"link" xml:"link" yaml:"link" toml:"link" form:"link" query:"link"`
 	FolderID  *int   `json:"folder_id" xml:"folder_id" yaml:"folder_id" toml:"folder_id" form:"folder_id" query:"folder_id"`
+	Name      string `json:"name" xml:"name" yaml:"name" toml:"name" form:"name" query:"name"`
+	Size      int64  `json:"-" bun:"size"`
+	MimeType  string `json:"-" bun:"mime_type"`
+	Checksum  string `json:"-" bun:"checksum"`
+	BlobKey   string `json:"-" bun:"blob_key"`
 	CreatedBy int    `json:"-" bun:"created_by"`
 }
 
+// UploadFileRequest describes a validated multipart upload stored in the blob store, the link of the created file is its name in the store
+type UploadFileRequest struct {
+	Name      string
+	Size      int64
+	MimeType  string
+	FolderID  *int
+	CreatedBy int
+	Admin     bool
+}
+
 type CreateFileResponse struct {
-	ID       int    `json:"id"`
-	Type     string `json:"type"`
-	Link     string `json:"link"`
-	FolderID *int   `json:"folder_id"`
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Link      string `json:"link"`
+	FolderID  *int   `json:"folder_id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mime_type"`
+	Checksum  string `json:"checksum"`
+	CreatedBy *int   `json:"created_by"`
 }
 
 type UpdateFileRequest struct {
@@ -81,6 +109,7 @@ type UpdateFileRequest struct {
 	Type      string `json:"type" xml:"type" yaml:"type" toml:"type" form:"type" query:"type"`
 	Link      string `json:"link" xml:"link" yaml:"link" toml:"link" form:"link" query:"link"`
 	FolderID  *int   `json:"folder_id" xml:"folder_id" yaml:"folder_id" toml:"folder_id" form:"folder_id" query:"folder_id"`
+	Name      string `json:"name" xml:"name" yaml:"name" toml:"name" form:"name" query:"name"`
 	UpdatedBy int    `json:"-" bun:"updated_by"`
 }
 
@@ -90,24 +119,50 @@ type UpdateFileColumnsRequest struct {
 }
 
 type UpdateFileResponse struct {
-	ID       int    `json:"id"`
-	Type     string `json:"type"`
-	Link     string `json:"link"`
-	FolderID *int   `json:"folder_id"`
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Link      string `json:"link"`
+	FolderID  *int   `json:"folder_id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mime_type"`
+	Checksum  string `json:"checksum"`
+	CreatedBy *int   `json:"created_by"`
 }
 
 type DeleteFileResponse struct {
 	Message string `json:"message"`
 }
 
+// GetFileResponse describes a file, Corrupted is set when the verification found its content changed or missing
 type GetFileResponse struct {
-	ID       int    `json:"id"`
-	Type     string `json:"type"`
-	Link     string `json:"link"`
-	FolderID *int   `json:"folder_id"`
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Link      string `json:"link"`
+	FolderID  *int   `json:"folder_id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mime_type"`
+	Checksum  string `json:"checksum"`
+	Corrupted bool   `json:"corrupted"`
+	BlobKey   string `json:"-"`
+	CreatedBy *int   `json:"created_by"`
 }
 
 type ListFileResponse struct {
 	Files []*GetFileResponse `json:"files"`
 	Total int64              `json:"total"`
 }
+
+// AttachedFile is a file of the store attached to a post or a menu
+type AttachedFile struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Link     string `json:"link"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+type AttachFileRequest struct {
+	FileID int `json:"file_id" xml:"file_id" yaml:"file_id" toml:"file_id" form:"file_id" query:"file_id"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+// VariantRequest asks for a resized copy of an image file, a zero side follows the aspect ratio of the other
+// and with both zero the image keeps its size. Fit is contain or cover and Format jpeg or png, by default
+// photos are JPEG and the images that may be transparent PNG.
+type VariantRequest struct {
+	Width  int    `json:"w" xml:"w" yaml:"w" toml:"w" form:"w" query:"w"`
+	Height int    `json:"h" xml:"h" yaml:"h" toml:"h" form:"h" query:"h"`
+	Fit    string `json:"fit" xml:"fit" yaml:"fit" toml:"fit" form:"fit" query:"fit"`
+	Format string `json:"format" xml:"format" yaml:"format" toml:"format" form:"format" query:"format"`
+	FileID int    `json:"-"`
+	UserID int    `json:"-"`
+	Admin  bool   `json:"-"`
+}
@@ -6,5 +6,6 @@ type FileUploadRequest struct {
 }
 
 type FileUploadResponse struct {
+	FileID  int    `json:"file_id"`
 	FileURL string `json:"file_url"`
 }
@@ -0,0 +1,43 @@
+package entity
+
+// FolderChildrenResponse holds the folders and files right inside a folder
+type FolderChildrenResponse struct {
+	Folders []*GetFolderResponse `json:"folders"`
+	Files   []*GetFileResponse   `json:"files"`
+}
+
+// FolderPathResponse holds the folders from the root down to a folder, the folder itself last
+type FolderPathResponse struct {
+	Path []*GetFolderResponse `json:"path"`
+}
+
+// FolderTree is a folder with everything below it
+type FolderTree struct {
+	ID       *int               `json:"id"`
+	Name     string             `json:"name"`
+	ParentID *int               `json:"parent_id"`
+	Folders  []*FolderTree      `json:"folders"`
+	Files    []*GetFileResponse `json:"files"`
+}
+
+// MoveFolderRequest puts a folder into another one, a nil ParentID moves it to the root and an empty Name keeps its name
+type MoveFolderRequest struct {
+	ParentID  *int   `json:"parent_id" xml:"parent_id" yaml:"parent_id" toml:"parent_id" form:"parent_id" query:"parent_id"`
+	Name      string `json:"name" xml:"name" yaml:"name" toml:"name" form:"name" query:"name"`
+	FolderID  int    `json:"-"`
+	UpdatedBy int    `json:"-"`
+}
+
+// CopyFolderRequest copies a folder with its subfolders and files into another one, the copies share the stored content
+type CopyFolderRequest struct {
+	ParentID  *int   `json:"parent_id" xml:"parent_id" yaml:"parent_id" toml:"parent_id" form:"parent_id" query:"parent_id"`
+	Name      string `json:"name" xml:"name" yaml:"name" toml:"name" form:"name" query:"name"`
+	FolderID  int    `json:"-"`
+	CreatedBy int    `json:"-"`
+}
+
+// RestoreFolderResponse counts the folders and files restored, the ones deleted together with the folder
+type RestoreFolderResponse struct {
+	Folders int64 `json:"folders"`
+	Files   int64 `json:"files"`
+}
@@ -1,20 +1,17 @@
 package entity
 
-import "github.com/lib/pq"
-
 type Menus struct {
-	ID        *int           `json:"id" bun:"id"`
-	Title     string         `json:"title" bun:"title"`
-	Content   string         `json:"content" bun:"content"`
-	IsStatic  bool           `json:"is_static" bun:"is_static"`
-	Sort      int            `json:"sort" bun:"sort"`
-	ParentID  *int           `json:"parent_id" bun:"parent_id"`
-	Status    bool           `json:"status" bun:"status"`
-	Slug      string         `json:"slug" bun:"slug"`
-	Path      string         `json:"path" bun:"path"`
-	Files     pq.StringArray `json:"files" bun:"files"`
-	CreatedBy *int           `json:"created_by" bun:"created_by"`
-	UpdatedBy *int           `json:"updated_by" bun:"updated_by"`
+	ID        *int   `json:"id" bun:"id"`
+	Title     string `json:"title" bun:"title"`
+	Content   string `json:"content" bun:"content"`
+	IsStatic  bool   `json:"is_static" bun:"is_static"`
+	Sort      int    `json:"sort" bun:"sort"`
+	ParentID  *int   `json:"parent_id" bun:"parent_id"`
+	Status    bool   `json:"status" bun:"status"`
+	Slug      string `json:"slug" bun:"slug"`
+	Path      string `json:"path" bun:"path"`
+	CreatedBy *int   `json:"created_by" bun:"created_by"`
+	UpdatedBy *int   `json:"updated_by" bun:"updated_by"`
 }
 
 type CreateMenuRequest struct {
@@ -39,7 +36,7 @@ type CreateMenuResponse struct {
 	Status   bool              `json:"status"`
 	Slug     string            `json:"slug"`
 	Path     string            `json:"path"`
-	Files    []string          `json:"files"`
+	Files    []AttachedFile    `json:"files"`
 }
 
 type UpdateMenuRequest struct {
@@ -65,7 +62,7 @@ type UpdateMenuResponse struct {
 	Status   bool              `json:"status"`
 	Slug     string            `json:"slug"`
 	Path     string            `json:"path"`
-	Files    []string          `json:"files"`
+	Files    []AttachedFile    `json:"files"`
 }
 
 type UpdateMenuColumnsRequest struct {
@@ -87,7 +84,7 @@ type GetMenuResponse struct {
 	Status   bool              `json:"status"`
 	Slug     string            `json:"slug"`
 	Path     string            `json:"path"`
-	Files    []string          `json:"files"`
+	Files    []AttachedFile    `json:"files"`
 }
 
 type ListMenuResponse struct {
@@ -0,0 +1,104 @@
+package entity
+
+import "time"
+
+// Group restrictions, a muted member cannot post and a banned user cannot join the group
+const (
+	RestrictionMute = "mute"
+	RestrictionBan  = "ban"
+)
+
+// Word filter actions, block rejects the message and mask replaces the matched text with asterisks
+const (
+	FilterBlock = "block"
+	FilterMask  = "mask"
+)
+
+// Report statuses
+const (
+	ReportOpen      = "open"
+	ReportResolved  = "resolved"
+	ReportDismissed = "dismissed"
+)
+
+// RestrictMemberRequest mutes or bans the user for Duration seconds, 0 restricts until lifted
+type RestrictMemberRequest struct {
+	UserID   int    `json:"user_id"`
+	Duration int64  `json:"duration"`
+	Reason   string `json:"reason"`
+	GroupID  int    `json:"-"`
+	Kind     string `json:"-"`
+}
+
+type GroupRestriction struct {
+	GroupID   int        `json:"group_id"`
+	UserID    int        `json:"user_id"`
+	Username  string     `json:"username"`
+	Kind      string     `json:"kind"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedBy int        `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type GroupRestrictionsResponse struct {
+	Restrictions []GroupRestriction `json:"restrictions"`
+}
+
+// SlowModeRequest lets every member below moderator send one message per Seconds, 0 turns slow mode off
+type SlowModeRequest struct {
+	Seconds int `json:"seconds"`
+}
+
+// WordFilter matches Pattern as a whole word ignoring case, or as a regular expression when Regex is set
+type WordFilter struct {
+	ID        int       `json:"id"`
+	GroupID   int       `json:"group_id"`
+	Pattern   string    `json:"pattern"`
+	Regex     bool      `json:"regex"`
+	Action    string    `json:"action"`
+	CreatedBy int       `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateWordFilterRequest struct {
+	Pattern   string `json:"pattern"`
+	Regex     bool   `json:"regex"`
+	Action    string `json:"action"`
+	GroupID   int    `json:"-"`
+	CreatedBy int    `json:"-"`
+}
+
+type WordFiltersResponse struct {
+	Filters []WordFilter `json:"filters"`
+}
+
+type ReportMessageRequest struct {
+	Reason     string `json:"reason"`
+	MessageID  int    `json:"-"`
+	ReportedBy int    `json:"-"`
+}
+
+// MessageReport keeps the content of the message as it was reported
+type MessageReport struct {
+	ID         int        `json:"id"`
+	GroupID    int        `json:"group_id"`
+	ChatID     int        `json:"chat_id"`
+	MessageID  int        `json:"message_id"`
+	Content    string     `json:"content"`
+	ReportedBy int        `json:"reported_by"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	ReviewedBy *int       `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type MessageReportsResponse struct {
+	Reports []MessageReport `json:"reports"`
+}
+
+// ReviewReportRequest closes a report with the status "resolved" or "dismissed"
+type ReviewReportRequest struct {
+	Status string `json:"status"`
+}
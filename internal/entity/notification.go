@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// Notification is the inbox entry of one chat, TotalMessagesCount counts the unread messages
+type Notification struct {
+	ChatID             int        `json:"chat_id"`
+	ChatType           string     `json:"chat_type"`
+	LatestMessageID    int        `json:"latest_message_id"`
+	LatestSender       int        `json:"latest_sender"`
+	LatestMessage      string     `json:"latest_message"`
+	TotalMessagesCount int        `json:"total_messages_count"`
+	Muted              bool       `json:"muted"`
+	MutedUntil         *time.Time `json:"muted_until"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// NotificationsResponse lists the chats with unread messages, the latest first.
+// UnreadCount sums the unread messages of the chats which are not muted.
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count"`
+}
+
+// MuteChatRequest mutes or unmutes the notifications of a chat, Duration is in seconds and 0 mutes until unmuted
+type MuteChatRequest struct {
+	Muted    bool  `json:"muted"`
+	Duration int64 `json:"duration"`
+	ChatID   int64 `json:"-"`
+	UserID   int64 `json:"-"`
+}
+
+// MuteSetting is the mute state of a chat for a user
+type MuteSetting struct {
+	ChatID     int        `json:"chat_id"`
+	Muted      bool       `json:"muted"`
+	MutedUntil *time.Time `json:"muted_until"`
+}
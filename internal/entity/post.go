@@ -1,18 +1,15 @@
 package entity
 
-import "github.com/lib/pq"
-
 type Posts struct {
-	ID           *int           `json:"id" bun:"id"`
-	Title        string         `json:"title" bun:"title"`
-	Content      string         `json:"content" bun:"content"`
-	ShortContent string         `json:"short_content" bun:"short_content"`
-	Slug         string         `json:"slug" bun:"slug"`
-	Status       bool           `json:"status" bun:"status"`
-	UserID       int            `json:"user_id" bun:"user_id"`
-	Files        pq.StringArray `json:"files" bun:"files"`
-	CreatedBy    *int           `json:"created_by" bun:"created_by"`
-	UpdatedBy    *int           `json:"updated_by" bun:"updated_by"`
+	ID           *int   `json:"id" bun:"id"`
+	Title        string `json:"title" bun:"title"`
+	Content      string `json:"content" bun:"content"`
+	ShortContent string `json:"short_content" bun:"short_content"`
+	Slug         string `json:"slug" bun:"slug"`
+	Status       bool   `json:"status" bun:"status"`
+	UserID       int    `json:"user_id" bun:"user_id"`
+	CreatedBy    *int   `json:"created_by" bun:"created_by"`
+	UpdatedBy    *int   `json:"updated_by" bun:"updated_by"`
 }
 
 type CreatePostRequest struct {
@@ -33,7 +30,7 @@ type CreatePostResponse struct {
 	Slug         string            `json:"slug"`
 	Status       bool              `json:"status"`
 	UserID       int               `json:"user_id"`
-	Files        []string          `json:"files"`
+	Files        []AttachedFile    `json:"files"`
 }
 
 type UpdatePostRequest struct {
@@ -44,7 +41,6 @@ type UpdatePostRequest struct {
 	Slug         string            `json:"slug" xml:"slug" yaml:"slug" toml:"slug" query:"slug" form:"slug"`
 	Status       bool              `json:"status" xml:"status" yaml:"status" toml:"status" query:"status" form:"status"`
 	UserID       int               `json:"user_id" xml:"user_id" yaml:"user_id" toml:"user_id" query:"user_id" form:"user_id"`
-	Files        []string          `json:"files" xml:"files" yaml:"files" toml:"files" query:"files" form:"files"`
 	UpdatedBy    int               `json:"-" bun:"updated_by"`
 }
 
@@ -56,7 +52,7 @@ type UpdatePostResponse struct {
 	Slug         string            `json:"slug"`
 	Status       bool              `json:"status"`
 	UserID       int               `json:"user_id"`
-	Files        []string          `json:"files"`
+	Files        []AttachedFile    `json:"files"`
 }
 
 type UpdatePostColumnsRequest struct {
@@ -76,7 +72,7 @@ type GetPostResponse struct {
 	Slug         string            `json:"slug"`
 	Status       bool              `json:"status"`
 	UserID       int               `json:"user_id"`
-	Files        []string          `json:"files"`
+	Files        []AttachedFile    `json:"files"`
 }
 
 type ListPostResponse struct {
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Push delivery channels
+const (
+	PushWebPush = "webpush"
+	PushWebhook = "webhook"
+)
+
+// Push job statuses, delivered jobs are removed. A dead job failed every attempt or was refused
+// by the receiver and is kept until the user retries it.
+const (
+	PushPending = "pending"
+	PushDead    = "dead"
+)
+
+// PushKeys are the client keys of a Web Push subscription, base64url encoded as the browser reports them
+type PushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// SubscribePushRequest has the shape of the browser PushSubscription JSON
+type SubscribePushRequest struct {
+	Endpoint  string   `json:"endpoint"`
+	Keys      PushKeys `json:"keys"`
+	UserID    int64    `json:"-"`
+	UserAgent string   `json:"-"`
+}
+
+type PushSubscription struct {
+	ID        int       `json:"id"`
+	Endpoint  string    `json:"endpoint"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PushSubscriptionsResponse struct {
+	Subscriptions []PushSubscription `json:"subscriptions"`
+}
+
+type VAPIDKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string `json:"url"`
+	UserID int64  `json:"-"`
+}
+
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhookResponse returns the signing secret of the webhook, it is not shown again
+type CreateWebhookResponse struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// QuietHours holds back push deliveries of the user between Start and End, "HH:MM" in the Timezone.
+// A window with Start after End spans midnight.
+type QuietHours struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	UserID   int64  `json:"-"`
+}
+
+// PushPayload is the body of a Web Push message and the data of a webhook call
+type PushPayload struct {
+	Event     string    `json:"event"`
+	ChatID    int       `json:"chat_id"`
+	ChatType  string    `json:"chat_type"`
+	MessageID int       `json:"message_id"`
+	Sender    int       `json:"sender"`
+	Content   string    `json:"content"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+type PushJob struct {
+	ID            int64           `json:"id"`
+	Channel       string          `json:"channel"`
+	TargetID      int             `json:"target_id"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type PushJobsResponse struct {
+	Jobs []PushJob `json:"jobs"`
+}
+
+// PushDelivery is a claimed job with its target. Active is false once the subscription or webhook is removed.
+type PushDelivery struct {
+	PushJob
+	UserID        int
+	Active        bool
+	Endpoint      string
+	Keys          PushKeys
+	WebhookURL    string
+	WebhookSecret string
+	QuietHours    *QuietHours
+}
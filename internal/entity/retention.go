@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Export formats of a chat archive
+const (
+	ExportJSON = "json"
+	ExportHTML = "html"
+)
+
+// RetentionRequest keeps the messages of a chat for Days, 0 falls back to the global retention
+type RetentionRequest struct {
+	Days int `json:"days"`
+}
+
+// ChatRetention is the retention applied to a chat, Inherited is set when the global retention applies.
+// Days 0 keeps the messages forever.
+type ChatRetention struct {
+	ChatID    int        `json:"chat_id"`
+	Days      int        `json:"days"`
+	Inherited bool       `json:"inherited"`
+	UpdatedBy *int       `json:"updated_by"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ExportChatRequest struct {
+	ChatID int
+	UserID int
+	Admin  bool
+	Format string
+}
+
+type ExportMember struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ChatExport is the header of a chat archive, the messages are streamed after it
+type ChatExport struct {
+	Chat       Chat           `json:"chat"`
+	Format     string         `json:"-"`
+	ExportedBy int            `json:"exported_by"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Members    []ExportMember `json:"members"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// StorageUsage is what a user keeps in the file store, every file counts with its full size even when its
+// content is shared with other files. A zero Quota is no limit and leaves Remaining empty.
+type StorageUsage struct {
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	UsedBytes int64     `json:"used_bytes"`
+	FileCount int       `json:"file_count"`
+	Quota     int64     `json:"quota_bytes"`
+	Remaining *int64    `json:"remaining_bytes"`
+	UserQuota *int64    `json:"user_quota_bytes"`
+	OverQuota bool      `json:"over_quota"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StorageReportResponse lists the users keeping the most bytes, the totals count every user
+type StorageReportResponse struct {
+	Users      []*StorageUsage `json:"users"`
+	TotalBytes int64           `json:"total_bytes"`
+	TotalFiles int             `json:"total_files"`
+}
+
+// SetStorageQuotaRequest sets the quota of a user in bytes, 0 is no limit and null falls back to the quota of the role
+type SetStorageQuotaRequest struct {
+	Quota     *int64 `json:"quota_bytes" xml:"quota_bytes" yaml:"quota_bytes" toml:"quota_bytes" form:"quota_bytes" query:"quota_bytes"`
+	UserID    int    `json:"-"`
+	UpdatedBy int    `json:"-"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	UploadOpen       = "open"
+	UploadCompleting = "completing"
+)
+
+type CreateUploadRequest struct {
+	Name     string `json:"name" xml:"name" yaml:"name" toml:"name" form:"name" query:"name"`
+	Size     int64  `json:"size" xml:"size" yaml:"size" toml:"size" form:"size" query:"size"`
+	FolderID *int   `json:"folder_id" xml:"folder_id" yaml:"folder_id" toml:"folder_id" form:"folder_id" query:"folder_id"`
+	UserID   int    `json:"-"`
+	Admin    bool   `json:"-"`
+}
+
+// UploadSession is a resumable upload, Received bytes of Size have been stored as chunks
+type UploadSession struct {
+	ID        string    `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	Received  int64     `json:"received"`
+	FolderID  *int      `json:"folder_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UploadChunk is a stored part of a resumable upload starting at Position
+type UploadChunk struct {
+	SessionID string
+	Position  int64
+	Size      int64
+	Checksum  string
+	BlobKey   string
+}
+
+// UploadChunkRequest is the body of a chunk, Checksum is the hex SHA-256 of Data
+type UploadChunkRequest struct {
+	SessionID string
+	UserID    int
+	Offset    int64
+	Checksum  string
+	Data      []byte
+}
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"archive/zip"
+	"archv1/internal/entity"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Archive streams a chat export into a zip holding a single JSON or HTML document,
+// the messages are written page by page so the chat never has to fit in memory
+type Archive struct {
+	zip       *zip.Writer
+	file      io.Writer
+	format    string
+	usernames map[int]string
+	written   int
+}
+
+var htmlHeader = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Chat {{.Chat.ID}}</title>
+<style>
+body { font-family: sans-serif; max-width: 800px; margin: 0 auto; }
+.message { border-bottom: 1px solid #ddd; padding: 8px 0; }
+.meta { color: #666; font-size: 12px; }
+.content { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Chat {{.Chat.ID}} ({{.Chat.ChatType}})</h1>
+<p class="meta">Exported at {{.ExportedAt.Format "2006-01-02 15:04:05 UTC"}} by user {{.ExportedBy}}</p>
+<h2>Members</h2>
+<ul>
+{{range .Members}}<li>{{.Username}} (#{{.UserID}})</li>
+{{end}}</ul>
+<h2>Messages</h2>
+`))
+
+var htmlMessage = template.Must(template.New("message").Parse(`<div class="message" id="m{{.MessageID}}">
+<div class="meta">{{.Sender}} &middot; {{.CreatedAt}}{{if .Edited}} &middot; edited{{end}}{{if .ReplyTo}} &middot; reply to <a href="#m{{.ReplyTo}}">#{{.ReplyTo}}</a>{{end}}</div>
+{{if .Deleted}}<div class="content"><em>deleted</em></div>{{else}}<div class="content">{{.Content}}</div>{{end}}
+{{range .Attachments}}<div class="meta">attachment: {{.}}</div>
+{{end}}</div>
+`))
+
+const htmlFooter = "</body>\n</html>\n"
+
+// NewArchive starts the zip on w and writes the chat, the export time and the members
+func NewArchive(w io.Writer, export entity.ChatExport) (*Archive, error) {
+	archive := &Archive{
+		zip:       zip.NewWriter(w),
+		format:    export.Format,
+		usernames: make(map[int]string, len(export.Members)),
+	}
+
+	for _, member := range export.Members {
+		archive.usernames[member.UserID] = member.Username
+	}
+
+	file, err := archive.zip.CreateHeader(&zip.FileHeader{
+		Name:     fmt.Sprintf("chat-%d.%s", export.Chat.ID, export.Format),
+		Method:   zip.Deflate,
+		Modified: export.ExportedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archive.file = file
+
+	if export.Format == entity.ExportHTML {
+		return archive, htmlHeader.Execute(file, export)
+	}
+
+	header, err := json.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+
+	// the messages array is appended to the header object and closed by Close
+	if _, err := file.Write(header[:len(header)-1]); err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(file, `,"messages":[`)
+
+	return archive, err
+}
+
+// WriteMessages appends a page of messages in ascending order
+func (a *Archive) WriteMessages(messages []entity.ChatMessage) error {
+	for _, message := range messages {
+		var err error
+		if a.format == entity.ExportHTML {
+			err = a.writeHTML(message)
+		} else {
+			err = a.writeJSON(message)
+		}
+		if err != nil {
+			return err
+		}
+
+		a.written++
+	}
+
+	return nil
+}
+
+// Close ends the document and the zip, it does not close the underlying writer
+func (a *Archive) Close() error {
+	footer := "]}\n"
+	if a.format == entity.ExportHTML {
+		footer = htmlFooter
+	}
+
+	if _, err := io.WriteString(a.file, footer); err != nil {
+		return err
+	}
+
+	return a.zip.Close()
+}
+
+func (a *Archive) writeJSON(message entity.ChatMessage) error {
+	if a.written > 0 {
+		if _, err := io.WriteString(a.file, ","); err != nil {
+			return err
+		}
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.file.Write(body)
+
+	return err
+}
+
+func (a *Archive) writeHTML(message entity.ChatMessage) error {
+	view := struct {
+		MessageID   int
+		Sender      string
+		CreatedAt   string
+		Content     string
+		Edited      bool
+		Deleted     bool
+		ReplyTo     int
+		Attachments []string
+	}{
+		MessageID: message.MessageID,
+		Sender:    a.username(message.Sender),
+		CreatedAt: message.CreatedAt.UTC().Format(time.RFC3339),
+		Content:   message.Message,
+		Edited:    message.Edited,
+		Deleted:   message.Deleted,
+	}
+
+	if message.ReplyTo != nil {
+		view.ReplyTo = message.ReplyTo.MessageID
+	}
+
+	for _, attachment := range message.Attachments {
+		view.Attachments = append(view.Attachments, attachment.Name)
+	}
+
+	return htmlMessage.Execute(a.file, view)
+}
+
+// username names the sender, a user who left the chat is shown by ID
+func (a *Archive) username(userID int) string {
+	if username, ok := a.usernames[userID]; ok {
+		return username
+	}
+
+	return "user #" + strconv.Itoa(userID)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS chat_members (
+    id SERIAL PRIMARY KEY,
+    chat_id INT NOT NULL,
+    user_id INT NOT NULL,
+    last_delivered_message_id INT NOT NULL DEFAULT 0,
+    last_read_message_id INT NOT NULL DEFAULT 0,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    UNIQUE (chat_id, user_id),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS messages_chat_id_id_idx ON messages (chat_id, id);
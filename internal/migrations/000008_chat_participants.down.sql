@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS chat_direct_key_idx;
+
+ALTER TABLE chat DROP COLUMN IF EXISTS direct_key;
+
+DROP TABLE IF EXISTS chat_participants;
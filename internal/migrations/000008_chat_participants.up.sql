@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS chat_participants (
+    id SERIAL PRIMARY KEY,
+    chat_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    UNIQUE (chat_id, user_id),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS chat_participants_user_id_idx ON chat_participants (user_id);
+
+ALTER TABLE chat ADD COLUMN IF NOT EXISTS direct_key VARCHAR;
+
+INSERT INTO chat_participants (chat_id, user_id)
+SELECT id, receiver_id FROM chat WHERE chat_type = 'private'
+UNION
+SELECT id, created_by FROM chat WHERE chat_type = 'private' AND created_by IS NOT NULL
+UNION
+SELECT m.chat_id, m.sender FROM messages AS m INNER JOIN chat AS c ON c.id = m.chat_id WHERE c.chat_type = 'private'
+ON CONFLICT DO NOTHING;
+
+UPDATE chat SET direct_key = keys.direct_key
+FROM (
+    SELECT DISTINCT ON (candidates.direct_key) candidates.chat_id, candidates.direct_key FROM (
+        SELECT chat_id, 'private:' || MIN(user_id) || ':' || MAX(user_id) AS direct_key
+        FROM chat_participants GROUP BY chat_id HAVING COUNT(*) = 2
+        UNION ALL
+        SELECT id, 'group:' || receiver_id FROM chat WHERE chat_type = 'group'
+    ) AS candidates
+    INNER JOIN chat AS c ON c.id = candidates.chat_id AND c.deleted_at IS NULL
+    ORDER BY candidates.direct_key, candidates.chat_id
+) AS keys
+WHERE chat.id = keys.chat_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS chat_direct_key_idx ON chat (direct_key) WHERE deleted_at IS NULL;
+
+INSERT INTO group_users (group_id, user_id, created_by)
+SELECT g.id, g.created_by, g.created_by FROM groups AS g
+WHERE g.created_by IS NOT NULL AND NOT EXISTS (
+    SELECT 1 FROM group_users AS gu WHERE gu.group_id = g.id AND gu.user_id = g.created_by
+);
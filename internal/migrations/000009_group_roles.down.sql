@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS group_users_owner_idx;
+
+ALTER TABLE group_users DROP CONSTRAINT IF EXISTS group_users_role_check;
+
+ALTER TABLE group_users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,13 @@
+ALTER TABLE group_users ADD COLUMN IF NOT EXISTS role VARCHAR NOT NULL DEFAULT 'member';
+
+ALTER TABLE group_users ADD CONSTRAINT group_users_role_check CHECK (role IN ('owner', 'admin', 'moderator', 'member'));
+
+UPDATE group_users SET role = 'owner'
+WHERE id IN (
+    SELECT MIN(gu.id) FROM group_users AS gu
+    INNER JOIN groups AS g ON g.id = gu.group_id AND g.created_by = gu.user_id
+    WHERE gu.deleted_at IS NULL
+    GROUP BY gu.group_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS group_users_owner_idx ON group_users (group_id) WHERE role = 'owner' AND deleted_at IS NULL;
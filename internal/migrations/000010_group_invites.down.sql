@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS group_join_requests;
+
+DROP TABLE IF EXISTS group_invites;
+
+DROP INDEX IF EXISTS groups_username_idx;
+
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_visibility_check;
+
+ALTER TABLE groups DROP COLUMN IF EXISTS visibility;
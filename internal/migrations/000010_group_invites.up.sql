@@ -0,0 +1,49 @@
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS visibility VARCHAR NOT NULL DEFAULT 'private';
+
+ALTER TABLE groups ADD CONSTRAINT groups_visibility_check CHECK (visibility IN ('public', 'private'));
+
+-- usernames differing only in case would fail the unique index, the oldest group keeps the username
+-- and the others get their id appended to it
+UPDATE groups AS g
+SET username = g.username || '_' || g.id
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY id) AS position
+    FROM groups
+    WHERE deleted_at IS NULL
+) AS duplicates
+WHERE duplicates.id = g.id AND duplicates.position > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS groups_username_idx ON groups (LOWER(username)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS group_invites (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL,
+    token VARCHAR NOT NULL UNIQUE,
+    usage_limit INT,
+    usage_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by INT NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_by INT,
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (revoked_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS group_invites_group_id_idx ON group_invites (group_id);
+
+CREATE TABLE IF NOT EXISTS group_join_requests (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL,
+    user_id INT NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP,
+    reviewed_by INT,
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (reviewed_by) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS group_join_requests_pending_idx ON group_join_requests (group_id, user_id) WHERE status = 'pending';
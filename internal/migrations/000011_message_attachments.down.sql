@@ -0,0 +1,14 @@
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+
+ALTER TABLE messages ALTER COLUMN message_type DROP DEFAULT;
+
+DROP TABLE IF EXISTS message_attachments;
+
+DROP INDEX IF EXISTS files_chat_id_idx;
+
+ALTER TABLE files DROP COLUMN IF EXISTS chat_id;
+ALTER TABLE files DROP COLUMN IF EXISTS height;
+ALTER TABLE files DROP COLUMN IF EXISTS width;
+ALTER TABLE files DROP COLUMN IF EXISTS mime_type;
+ALTER TABLE files DROP COLUMN IF EXISTS size;
+ALTER TABLE files DROP COLUMN IF EXISTS name;
@@ -0,0 +1,25 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS name VARCHAR;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS size BIGINT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS mime_type VARCHAR;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS height INT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS chat_id INT REFERENCES chat(id);
+
+CREATE INDEX IF NOT EXISTS files_chat_id_idx ON files (chat_id) WHERE chat_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS message_attachments (
+    message_id INT NOT NULL,
+    file_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, file_id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (file_id) REFERENCES files(id)
+);
+
+CREATE INDEX IF NOT EXISTS message_attachments_file_id_idx ON message_attachments (file_id);
+
+UPDATE messages SET message_type = 'text' WHERE message_type NOT IN ('text', 'image', 'file', 'voice', 'system');
+
+ALTER TABLE messages ALTER COLUMN message_type SET DEFAULT 'text';
+
+ALTER TABLE messages ADD CONSTRAINT messages_message_type_check CHECK (message_type IN ('text', 'image', 'file', 'voice', 'system'));
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS pinned_messages;
+
+DROP TABLE IF EXISTS message_reactions;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS forwarded_from_message;
+ALTER TABLE messages DROP COLUMN IF EXISTS forwarded_from_user;
+ALTER TABLE messages DROP COLUMN IF EXISTS reply_to_id;
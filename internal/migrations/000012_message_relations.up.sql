@@ -0,0 +1,24 @@
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS reply_to_id INT REFERENCES messages(id);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forwarded_from_user INT REFERENCES users(id);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forwarded_from_message INT REFERENCES messages(id);
+
+CREATE TABLE IF NOT EXISTS message_reactions (
+    message_id INT NOT NULL,
+    user_id INT NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, user_id, emoji),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS pinned_messages (
+    chat_id INT NOT NULL,
+    message_id INT NOT NULL,
+    pinned_by INT NOT NULL,
+    pinned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chat_id, message_id),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (pinned_by) REFERENCES users(id)
+);
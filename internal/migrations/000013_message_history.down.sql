@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS hidden_messages;
+
+DROP TABLE IF EXISTS message_edits;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS message_edits (
+    id SERIAL PRIMARY KEY,
+    message_id INT NOT NULL,
+    content TEXT NOT NULL,
+    edited_by INT NOT NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (edited_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS message_edits_message_id_idx ON message_edits (message_id);
+
+CREATE TABLE IF NOT EXISTS hidden_messages (
+    message_id INT NOT NULL,
+    user_id INT NOT NULL,
+    hidden_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, message_id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
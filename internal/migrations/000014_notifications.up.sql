@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    user_id INT NOT NULL,
+    chat_id INT NOT NULL,
+    unread_count INT NOT NULL DEFAULT 0 CHECK (unread_count >= 0),
+    latest_message_id INT,
+    latest_sender INT,
+    latest_message TEXT NOT NULL DEFAULT '',
+    muted BOOLEAN NOT NULL DEFAULT FALSE,
+    muted_until TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, chat_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (latest_message_id) REFERENCES messages(id),
+    FOREIGN KEY (latest_sender) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id, updated_at DESC) WHERE unread_count > 0;
+
+INSERT INTO notifications (user_id, chat_id, unread_count, latest_message_id, latest_sender, latest_message, updated_at)
+SELECT p.user_id, p.chat_id, unread.count, latest.id, latest.sender, latest.content, latest.created_at
+FROM (
+    SELECT cp.chat_id, cp.user_id
+    FROM chat_participants AS cp
+    INNER JOIN chat AS c ON c.id = cp.chat_id
+    WHERE cp.deleted_at IS NULL AND c.deleted_at IS NULL AND c.chat_type = 'private'
+    UNION
+    SELECT c.id, gu.user_id
+    FROM chat AS c
+    INNER JOIN group_users AS gu ON gu.group_id = c.receiver_id AND gu.deleted_at IS NULL
+    WHERE c.deleted_at IS NULL AND c.chat_type = 'group'
+) AS p
+LEFT JOIN chat_members AS cm ON cm.chat_id = p.chat_id AND cm.user_id = p.user_id
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS count FROM messages AS m
+    WHERE m.chat_id = p.chat_id AND m.deleted_at IS NULL AND m.sender <> p.user_id
+    AND m.id > COALESCE(cm.last_read_message_id, 0)
+) AS unread
+CROSS JOIN LATERAL (
+    SELECT m.id, m.sender, m.content, m.created_at FROM messages AS m
+    WHERE m.chat_id = p.chat_id AND m.deleted_at IS NULL
+    ORDER BY m.id DESC
+    LIMIT 1
+) AS latest
+WHERE unread.count > 0
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS push_jobs;
+DROP TABLE IF EXISTS quiet_hours;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS push_subscriptions;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh VARCHAR(128) NOT NULL,
+    auth VARCHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS push_subscriptions_user_id_idx ON push_subscriptions (user_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON webhooks (user_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS quiet_hours (
+    user_id INT PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS push_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    channel VARCHAR(16) NOT NULL CHECK (channel IN ('webpush', 'webhook')),
+    subscription_id INT,
+    webhook_id INT,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (subscription_id) REFERENCES push_subscriptions(id),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id),
+    CHECK ((channel = 'webpush') = (subscription_id IS NOT NULL) AND (channel = 'webhook') = (webhook_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS push_jobs_due_idx ON push_jobs (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS push_jobs_dead_idx ON push_jobs (user_id, updated_at DESC) WHERE status = 'dead';
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS messages_chat_id_sender_idx;
+
+DROP TABLE IF EXISTS message_reports;
+
+DROP TABLE IF EXISTS group_word_filters;
+
+DROP TABLE IF EXISTS group_restrictions;
+
+ALTER TABLE groups DROP COLUMN IF EXISTS slow_mode;
@@ -0,0 +1,53 @@
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS slow_mode INT NOT NULL DEFAULT 0 CHECK (slow_mode >= 0);
+
+CREATE TABLE IF NOT EXISTS group_restrictions (
+    group_id INT NOT NULL,
+    user_id INT NOT NULL,
+    kind VARCHAR(8) NOT NULL CHECK (kind IN ('mute', 'ban')),
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id, kind),
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS group_word_filters (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL,
+    pattern VARCHAR(200) NOT NULL,
+    is_regex BOOLEAN NOT NULL DEFAULT FALSE,
+    action VARCHAR(8) NOT NULL CHECK (action IN ('block', 'mask')),
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS group_word_filters_group_id_idx ON group_word_filters (group_id);
+
+CREATE TABLE IF NOT EXISTS message_reports (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL,
+    chat_id INT NOT NULL,
+    message_id INT NOT NULL,
+    content TEXT NOT NULL,
+    reported_by INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    reviewed_by INT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (message_id, reported_by),
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (reported_by) REFERENCES users(id),
+    FOREIGN KEY (reviewed_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS message_reports_group_id_idx ON message_reports (group_id, status, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS messages_chat_id_sender_idx ON messages (chat_id, sender, created_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_created_at_idx;
+
+DROP TABLE IF EXISTS chat_retention;
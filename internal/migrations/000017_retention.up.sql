@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS chat_retention (
+    chat_id INT PRIMARY KEY,
+    days INT NOT NULL CHECK (days > 0),
+    updated_by INT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (chat_id) REFERENCES chat(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS messages_created_at_idx ON messages (created_at);
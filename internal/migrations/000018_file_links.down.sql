@@ -0,0 +1,32 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS files VARCHAR[];
+
+ALTER TABLE menus ADD COLUMN IF NOT EXISTS files VARCHAR[];
+
+UPDATE posts p
+SET files = (
+    SELECT array_agg(f.link ORDER BY pf.created_at, f.id)
+    FROM post_files pf
+    JOIN files f ON f.id = pf.file_id
+    WHERE pf.post_id = p.id
+);
+
+UPDATE menus m
+SET files = (
+    SELECT array_agg(f.link ORDER BY mf.created_at, f.id)
+    FROM menu_files mf
+    JOIN files f ON f.id = mf.file_id
+    WHERE mf.menu_id = m.id
+);
+
+DROP TABLE IF EXISTS menu_files;
+
+DROP TABLE IF EXISTS post_files;
+
+ALTER TABLE files
+    DROP COLUMN IF EXISTS checksum,
+    ALTER COLUMN mime_type DROP NOT NULL,
+    ALTER COLUMN mime_type DROP DEFAULT,
+    ALTER COLUMN size DROP NOT NULL,
+    ALTER COLUMN size DROP DEFAULT,
+    ALTER COLUMN name DROP NOT NULL,
+    ALTER COLUMN name DROP DEFAULT;
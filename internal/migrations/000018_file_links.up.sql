@@ -0,0 +1,65 @@
+-- name, size and mime_type came nullable with the attachments, the file rows now always carry them
+UPDATE files
+SET name = COALESCE(name, link),
+    size = COALESCE(size, 0),
+    mime_type = COALESCE(mime_type, '')
+WHERE name IS NULL OR size IS NULL OR mime_type IS NULL;
+
+ALTER TABLE files
+    ALTER COLUMN name SET DEFAULT '',
+    ALTER COLUMN name SET NOT NULL,
+    ALTER COLUMN size SET DEFAULT 0,
+    ALTER COLUMN size SET NOT NULL,
+    ALTER COLUMN mime_type SET DEFAULT '',
+    ALTER COLUMN mime_type SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS checksum VARCHAR NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS post_files (
+    post_id INT NOT NULL,
+    file_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by INT,
+    PRIMARY KEY (post_id, file_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id),
+    FOREIGN KEY (file_id) REFERENCES files(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS post_files_file_id_idx ON post_files (file_id);
+
+CREATE TABLE IF NOT EXISTS menu_files (
+    menu_id INT NOT NULL,
+    file_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by INT,
+    PRIMARY KEY (menu_id, file_id),
+    FOREIGN KEY (menu_id) REFERENCES menus(id),
+    FOREIGN KEY (file_id) REFERENCES files(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS menu_files_file_id_idx ON menu_files (file_id);
+
+-- the arrays hold the names /v1/upload saved the files under, they become the links of file rows
+INSERT INTO files (type, link, name, size, mime_type, created_by)
+SELECT DISTINCT ON (uploaded.link) 'file', uploaded.link, uploaded.link, 0, 'application/octet-stream', uploaded.created_by
+FROM (
+    SELECT unnest(files) AS link, created_by FROM posts
+    UNION ALL
+    SELECT unnest(files) AS link, created_by FROM menus
+) uploaded
+WHERE NOT EXISTS (SELECT 1 FROM files f WHERE f.link = uploaded.link);
+
+INSERT INTO post_files (post_id, file_id, created_by)
+SELECT p.id, (SELECT MIN(f.id) FROM files f WHERE f.link = uploaded.link), p.created_by
+FROM posts p, unnest(p.files) AS uploaded(link)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO menu_files (menu_id, file_id, created_by)
+SELECT m.id, (SELECT MIN(f.id) FROM files f WHERE f.link = uploaded.link), m.created_by
+FROM menus m, unnest(m.files) AS uploaded(link)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS files;
+
+ALTER TABLE menus DROP COLUMN IF EXISTS files;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id UUID PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    received BIGINT NOT NULL DEFAULT 0 CHECK (received <= size),
+    folder_id INT,
+    status VARCHAR NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (folder_id) REFERENCES folders(id)
+);
+
+CREATE INDEX IF NOT EXISTS upload_sessions_expires_at_idx ON upload_sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS upload_chunks (
+    session_id UUID NOT NULL,
+    position BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    checksum VARCHAR NOT NULL,
+    blob_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, position),
+    FOREIGN KEY (session_id) REFERENCES upload_sessions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS used_download_links (
+    nonce VARCHAR PRIMARY KEY,
+    file_id INT NOT NULL,
+    used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS used_download_links_expires_at_idx ON used_download_links (expires_at);
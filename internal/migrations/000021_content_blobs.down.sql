@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS files_blob_key_idx;
+
+ALTER TABLE files DROP COLUMN IF EXISTS blob_key;
+
+DROP TABLE IF EXISTS blobs;
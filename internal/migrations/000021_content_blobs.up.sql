@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS blobs (
+    key TEXT PRIMARY KEY,
+    checksum VARCHAR NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    ref_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMP,
+    verified_at TIMESTAMP,
+    corrupted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS blobs_released_at_idx ON blobs (released_at) WHERE ref_count = 0;
+
+CREATE INDEX IF NOT EXISTS blobs_verified_at_idx ON blobs (verified_at NULLS FIRST) WHERE ref_count > 0;
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS blob_key TEXT;
+
+CREATE INDEX IF NOT EXISTS files_blob_key_idx ON files (blob_key) WHERE blob_key IS NOT NULL;
+
+-- the files uploaded before keep their own copy under files/, outside links and chat files have no blob
+UPDATE files SET blob_key = 'files/' || link
+WHERE blob_key IS NULL AND chat_id IS NULL AND link <> '' AND link NOT LIKE '%/%';
+
+INSERT INTO blobs (key, checksum, size, ref_count)
+SELECT blob_key, MAX(COALESCE(checksum, '')), MAX(COALESCE(size, 0)), COUNT(*)
+FROM files
+WHERE blob_key IS NOT NULL
+GROUP BY blob_key
+ON CONFLICT DO NOTHING;
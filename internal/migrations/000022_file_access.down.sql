@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS share_links;
+
+DROP INDEX IF EXISTS folders_parent_id_idx;
+
+DROP TABLE IF EXISTS file_acl;
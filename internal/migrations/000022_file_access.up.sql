@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS file_acl (
+    id SERIAL PRIMARY KEY,
+    folder_id INT,
+    file_id INT,
+    user_id INT,
+    group_id INT,
+    role VARCHAR NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by INT,
+    CHECK ((folder_id IS NULL) <> (file_id IS NULL)),
+    CHECK ((user_id IS NULL) <> (group_id IS NULL)),
+    FOREIGN KEY (folder_id) REFERENCES folders(id),
+    FOREIGN KEY (file_id) REFERENCES files(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (group_id) REFERENCES groups(id),
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS file_acl_grantee_idx ON file_acl (
+    (COALESCE(folder_id, 0)), (COALESCE(file_id, 0)), (COALESCE(user_id, 0)), (COALESCE(group_id, 0))
+);
+
+CREATE INDEX IF NOT EXISTS file_acl_user_id_idx ON file_acl (user_id) WHERE user_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS file_acl_group_id_idx ON file_acl (group_id) WHERE group_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS folders_parent_id_idx ON folders (parent_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id SERIAL PRIMARY KEY,
+    token VARCHAR NOT NULL UNIQUE,
+    folder_id INT,
+    file_id INT,
+    password_hash VARCHAR,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by INT NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_by INT,
+    CHECK ((folder_id IS NULL) <> (file_id IS NULL)),
+    FOREIGN KEY (folder_id) REFERENCES folders(id),
+    FOREIGN KEY (file_id) REFERENCES files(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (revoked_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS share_links_folder_id_idx ON share_links (folder_id) WHERE folder_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS share_links_file_id_idx ON share_links (file_id) WHERE file_id IS NOT NULL;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS user_storage (
+    user_id INT PRIMARY KEY,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    file_count INT NOT NULL DEFAULT 0,
+    quota_bytes BIGINT CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_by INT,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (updated_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS user_storage_used_bytes_idx ON user_storage (used_bytes DESC);
+
+-- the files kept so far are counted once, from here on every change to the files updates the usage with it
+INSERT INTO user_storage (user_id, used_bytes, file_count)
+SELECT created_by, COALESCE(SUM(size), 0), COUNT(*)
+FROM files
+WHERE created_by IS NOT NULL AND deleted_at IS NULL
+GROUP BY created_by
+ON CONFLICT (user_id) DO UPDATE SET used_bytes = EXCLUDED.used_bytes, file_count = EXCLUDED.file_count, updated_at = NOW();
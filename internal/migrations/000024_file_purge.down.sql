@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS files_purge_idx;
+
+ALTER TABLE files DROP COLUMN IF EXISTS purged_at;
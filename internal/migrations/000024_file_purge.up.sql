@@ -0,0 +1,3 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS files_purge_idx ON files (deleted_at) WHERE deleted_at IS NOT NULL AND purged_at IS NULL;
@@ -19,6 +19,71 @@ type Config struct {
 	RedisDB   int    `yaml:"redis_db"`
 	RedisPWD  string `yaml:"redis_pwd"`
 
+	EventLogSize int64  `yaml:"event_log_size"`
+	EventLogTTL  string `yaml:"event_log_ttl"`
+
+	DeleteForEveryoneWindow string `yaml:"delete_for_everyone_window"`
+
+	RetentionDays      int    `yaml:"retention_days"`
+	RetentionInterval  string `yaml:"retention_interval"`
+	RetentionBatchSize int    `yaml:"retention_batch_size"`
+
+	// VAPIDPublicKey and VAPIDPrivateKey are read from VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY when those are set,
+	// the private key is not kept in the file and web push is off while it is empty. PushAllowHTTP accepts plain
+	// http targets and PushAllowPrivate loopback, private and link-local ones, both are for development only.
+	VAPIDPublicKey   string `yaml:"vapid_public_key"`
+	VAPIDPrivateKey  string `yaml:"vapid_private_key"`
+	VAPIDSubject     string `yaml:"vapid_subject"`
+	PushTTL          string `yaml:"push_ttl"`
+	PushPollInterval string `yaml:"push_poll_interval"`
+	PushBatchSize    int    `yaml:"push_batch_size"`
+	PushMaxAttempts  int    `yaml:"push_max_attempts"`
+	PushTimeout      string `yaml:"push_timeout"`
+	PushAllowHTTP    bool   `yaml:"push_allow_http"`
+	PushAllowPrivate bool   `yaml:"push_allow_private"`
+
+	StorageBackend   string `yaml:"storage_backend"`
+	StorageLocalDir  string `yaml:"storage_local_dir"`
+	StorageLegacyDir string `yaml:"storage_legacy_dir"`
+	S3Endpoint       string `yaml:"s3_endpoint"`
+	S3Region         string `yaml:"s3_region"`
+	S3Bucket         string `yaml:"s3_bucket"`
+	S3AccessKey      string `yaml:"s3_access_key"`
+	S3SecretKey      string `yaml:"s3_secret_key"`
+	S3PathStyle      bool   `yaml:"s3_path_style"`
+
+	// Uploads holds the rules of the post, menu, avatar, chat and file store uploads
+	Uploads             map[string]UploadRule `yaml:"uploads"`
+	UploadMaxNameLength int                   `yaml:"upload_max_name_length"`
+	UploadChunkMaxSize  int64                 `yaml:"upload_chunk_max_size"`
+	UploadSessionTTL    string                `yaml:"upload_session_ttl"`
+	UploadSweepInterval string                `yaml:"upload_sweep_interval"`
+
+	// DownloadSecret signs the download links, the JWT secret is used when it is empty
+	DownloadSecret     string `yaml:"download_secret"`
+	DownloadLinkTTL    string `yaml:"download_link_ttl"`
+	DownloadLinkMaxTTL string `yaml:"download_link_max_ttl"`
+
+	// ImagePresets are the variants made right after an image is uploaded, other sizes are made on their first request
+	ImagePresets     []ImagePreset `yaml:"image_presets"`
+	ImageMaxSide     int           `yaml:"image_max_side"`
+	ImageMaxPixels   int64         `yaml:"image_max_pixels"`
+	ImageJPEGQuality int           `yaml:"image_jpeg_quality"`
+	ImageWorkers     int           `yaml:"image_workers"`
+
+	// BlobVerifyAge is how often every stored blob is hashed again, BlobGCGrace how long a blob nobody holds is kept
+	// and FileTrashRetention how long the files of a deleted folder keep their content so the folder can be restored
+	BlobVerifyInterval string `yaml:"blob_verify_interval"`
+	BlobVerifyAge      string `yaml:"blob_verify_age"`
+	BlobVerifyBatch    int    `yaml:"blob_verify_batch"`
+	BlobGCGrace        string `yaml:"blob_gc_grace"`
+	FileTrashRetention string `yaml:"file_trash_retention"`
+
+	// StorageQuotas are the bytes the users of a role may keep in the file store, StorageDefaultQuota applies to the
+	// other roles. A quota set on the user wins over both and 0 is no limit.
+	StorageQuotas       map[string]int64 `yaml:"storage_quotas"`
+	StorageDefaultQuota int64            `yaml:"storage_default_quota"`
+
 	HttpHost   string `yaml:"http_host"`
 	HttpPort   string `yaml:"http_port"`
 	CtxTimeout string `yaml:"ctx_timeout"`
@@ -32,6 +97,20 @@ type Config struct {
 	JWTSecret  string `yaml:"jwt_secret"`
 }
 
+// UploadRule limits the size of an upload and the sniffed content types it may have, "image/*" allows every image
+type UploadRule struct {
+	MaxSize      int64    `yaml:"max_size"`
+	AllowedTypes []string `yaml:"allowed_types"`
+}
+
+// ImagePreset is a variant size of the uploaded images, a zero side follows the aspect ratio
+type ImagePreset struct {
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	Fit    string `yaml:"fit"`
+	Format string `yaml:"format"`
+}
+
 func NewConfig() *Config {
 	c := &Config{}
 	yamlFile, err := os.ReadFile("./internal/pkg/config/config.yaml")
@@ -44,5 +123,13 @@ func NewConfig() *Config {
 		log.Fatalf("Unmarshal: %v", err)
 	}
 
+	if key := os.Getenv("VAPID_PUBLIC_KEY"); key != "" {
+		c.VAPIDPublicKey = key
+	}
+
+	if key := os.Getenv("VAPID_PRIVATE_KEY"); key != "" {
+		c.VAPIDPrivateKey = key
+	}
+
 	return c
 }
@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Fit modes of Resize, FitContain keeps the whole image inside the box and FitCover fills the box, cropping the overflow
+const (
+	FitContain = "contain"
+	FitCover   = "cover"
+)
+
+// Output formats of Encode, WebP is not among them since the standard library has no encoder for it
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+var (
+	ErrUnsupportedImage  = errors.New("the image format is not supported")
+	ErrUnsupportedFormat = errors.New("the output format is not supported, use jpeg or png")
+	ErrTooManyPixels     = errors.New("the image has too many pixels to process")
+)
+
+// Decode reads a JPEG, PNG or GIF image, rotating it upright by its EXIF orientation.
+// The dimensions are checked before decoding so a small file can not expand into a huge bitmap.
+// The metadata of the source is not carried over, so an encoded result holds no EXIF.
+func Decode(data []byte, maxPixels int64) (*image.NRGBA, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	if maxPixels > 0 && int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	var img image.Image
+	switch format {
+	case "jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case "png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, "", ErrUnsupportedImage
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	nrgba := toNRGBA(img)
+	if format == "jpeg" {
+		nrgba = orient(nrgba, exifOrientation(data))
+	}
+
+	return nrgba, format, nil
+}
+
+// Encode writes the image as JPEG or PNG. JPEG has no transparency, transparent pixels are put on white.
+func Encode(w io.Writer, img *image.NRGBA, format string, quality int) error {
+	switch format {
+	case FormatJPEG:
+		if quality <= 0 || quality > 100 {
+			quality = jpeg.DefaultQuality
+		}
+
+		return jpeg.Encode(w, flatten(img), &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+
+		return encoder.Encode(w, img)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// OutputFormat is the format a variant of a decoded image is encoded in when none is asked for,
+// PNG for the sources that may be transparent and JPEG for photos
+func OutputFormat(source string) string {
+	if source == "png" || source == "gif" {
+		return FormatPNG
+	}
+
+	return FormatJPEG
+}
+
+// MimeType is the content type of an output format
+func MimeType(format string) string {
+	if format == FormatPNG {
+		return "image/png"
+	}
+
+	return "image/jpeg"
+}
+
+// Extension is the file extension of an output format
+func Extension(format string) string {
+	if format == FormatPNG {
+		return ".png"
+	}
+
+	return ".jpg"
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+
+	return nrgba
+}
+
+func flatten(img *image.NRGBA) image.Image {
+	if img.Opaque() {
+		return img
+	}
+
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+
+	return flat
+}
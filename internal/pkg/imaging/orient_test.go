@@ -0,0 +1,113 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// labeled returns an image whose pixels carry the letters of the rows in their red channel
+func labeled(rows ...string) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x := range row {
+			img.SetNRGBA(x, y, color.NRGBA{R: row[x], A: 255})
+		}
+	}
+
+	return img
+}
+
+// labels reads the letters back from the red channel of the image
+func labels(img *image.NRGBA) []string {
+	rows := make([]string, 0, img.Rect.Dy())
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := make([]byte, 0, img.Rect.Dx())
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			row = append(row, img.NRGBAAt(x, y).R)
+		}
+
+		rows = append(rows, string(row))
+	}
+
+	return rows
+}
+
+func TestOrient(t *testing.T) {
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{orientation: 1, want: []string{"ABC", "DEF"}},
+		{orientation: 2, want: []string{"CBA", "FED"}},
+		{orientation: 3, want: []string{"FED", "CBA"}},
+		{orientation: 4, want: []string{"DEF", "ABC"}},
+		{orientation: 5, want: []string{"AD", "BE", "CF"}},
+		{orientation: 6, want: []string{"DA", "EB", "FC"}},
+		{orientation: 7, want: []string{"FC", "EB", "DA"}},
+		{orientation: 8, want: []string{"CF", "BE", "AD"}},
+		{orientation: 9, want: []string{"ABC", "DEF"}},
+	}
+
+	for _, tt := range tests {
+		got := labels(orient(labeled("ABC", "DEF"), tt.orientation))
+		if len(got) != len(tt.want) {
+			t.Errorf("orientation %d = %q, want %q", tt.orientation, got, tt.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("orientation %d = %q, want %q", tt.orientation, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// exifJPEG returns the start of a JPEG holding an EXIF segment with the orientation in the given byte order
+func exifJPEG(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(segment)+2))
+
+	return append(append(data, segment...), 0xFF, 0xDA)
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "intel byte order", data: exifJPEG(binary.LittleEndian, 6), want: 6},
+		{name: "motorola byte order", data: exifJPEG(binary.BigEndian, 8), want: 8},
+		{name: "an orientation out of range", data: exifJPEG(binary.LittleEndian, 12), want: 1},
+		{name: "no EXIF", data: []byte{0xFF, 0xD8, 0xFF, 0xDA}, want: 1},
+		{name: "not a JPEG", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "a cut segment", data: exifJPEG(binary.LittleEndian, 6)[:20], want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := exifOrientation(tt.data); got != tt.want {
+			t.Errorf("%s: exifOrientation = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
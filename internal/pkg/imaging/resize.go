@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"image"
+)
+
+// Resize scales the image into a width x height box, a zero side follows the aspect ratio of the other.
+// Images are never enlarged. Downscaling averages every source pixel that falls into a target pixel,
+// which keeps thin lines and text readable in thumbnails.
+func Resize(img *image.NRGBA, width, height int, fit string) *image.NRGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	switch {
+	case width <= 0 && height <= 0:
+		return img
+	case width <= 0:
+		width = max(1, w*height/h)
+	case height <= 0:
+		height = max(1, h*width/w)
+	}
+
+	src := img
+	if fit == FitCover {
+		// crop the source to the aspect ratio of the box, keeping the center
+		cw, ch := w, w*height/width
+		if ch > h {
+			cw, ch = h*width/height, h
+		}
+
+		x0, y0 := (w-cw)/2, (h-ch)/2
+		src = img.SubImage(image.Rect(x0, y0, x0+cw, y0+ch)).(*image.NRGBA)
+		width, height = min(width, cw), min(height, ch)
+	} else {
+		// shrink the box to the aspect ratio of the image
+		if w*height > h*width {
+			height = max(1, h*width/w)
+		} else {
+			width = max(1, w*height/h)
+		}
+
+		width, height = min(width, w), min(height, h)
+	}
+
+	if width == src.Rect.Dx() && height == src.Rect.Dy() {
+		return toNRGBA(src)
+	}
+
+	return boxResize(src, width, height)
+}
+
+// boxResize averages the premultiplied colors of the source pixels covered by each target pixel
+func boxResize(src *image.NRGBA, width, height int) *image.NRGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	ox, oy := src.Rect.Min.X, src.Rect.Min.Y
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(ox+x0, oy+sy)
+				for sx := x0; sx < x1; sx++ {
+					pa := uint64(src.Pix[i+3])
+					r += uint64(src.Pix[i]) * pa
+					g += uint64(src.Pix[i+1]) * pa
+					b += uint64(src.Pix[i+2]) * pa
+					a += pa
+					n++
+					i += 4
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[j] = uint8(r / a)
+				dst.Pix[j+1] = uint8(g / a)
+				dst.Pix[j+2] = uint8(b / a)
+				dst.Pix[j+3] = uint8(a / n)
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,56 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name                  string
+		w, h                  int
+		width, height         int
+		fit                   string
+		wantWidth, wantHeight int
+	}{
+		{name: "contain in a square", w: 400, h: 200, width: 100, height: 100, fit: FitContain, wantWidth: 100, wantHeight: 50},
+		{name: "only the width", w: 400, h: 200, width: 100, wantWidth: 100, wantHeight: 50},
+		{name: "only the height", w: 400, h: 200, height: 50, wantWidth: 100, wantHeight: 50},
+		{name: "contain never enlarges", w: 400, h: 200, width: 800, height: 800, fit: FitContain, wantWidth: 400, wantHeight: 200},
+		{name: "no box", w: 400, h: 200, wantWidth: 400, wantHeight: 200},
+		{name: "cover a square", w: 400, h: 200, width: 100, height: 100, fit: FitCover, wantWidth: 100, wantHeight: 100},
+		{name: "cover a tall box", w: 400, h: 200, width: 50, height: 100, fit: FitCover, wantWidth: 50, wantHeight: 100},
+		{name: "cover never enlarges", w: 400, h: 200, width: 300, height: 300, fit: FitCover, wantWidth: 200, wantHeight: 200},
+		{name: "a side never drops to zero", w: 1000, h: 1, width: 10, height: 10, fit: FitContain, wantWidth: 10, wantHeight: 1},
+	}
+
+	for _, tt := range tests {
+		got := Resize(image.NewNRGBA(image.Rect(0, 0, tt.w, tt.h)), tt.width, tt.height, tt.fit)
+		if got.Rect.Dx() != tt.wantWidth || got.Rect.Dy() != tt.wantHeight {
+			t.Errorf("%s: Resize = %dx%d, want %dx%d", tt.name, got.Rect.Dx(), got.Rect.Dy(), tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+func TestResizeAveragesThePixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{A: 255})
+
+	if got := Resize(img, 1, 1, FitContain).NRGBAAt(0, 0); got != (color.NRGBA{R: 127, G: 127, B: 127, A: 255}) {
+		t.Errorf("white next to black = %v, want gray", got)
+	}
+
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{})
+
+	if got := Resize(img, 1, 1, FitContain).NRGBAAt(0, 0); got != (color.NRGBA{R: 255, A: 127}) {
+		t.Errorf("red next to a transparent pixel = %v, want half transparent red", got)
+	}
+
+	cropped := Resize(labeled("ABCD", "EFGH"), 1, 1, FitCover)
+	if labels(cropped)[0] != string(byte(('B'+'C'+'F'+'G')/4)) {
+		t.Errorf("cover did not keep the center of the image: %q", labels(cropped))
+	}
+}
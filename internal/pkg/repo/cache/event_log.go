@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// EventLog keeps the latest websocket events of every user in a sorted set scored by sequence number
+type EventLog struct {
+	redis *Redis
+	size  int64
+	ttl   time.Duration
+}
+
+func NewEventLog(r *Redis, cfg *config.Config) *EventLog {
+	ttl, err := time.ParseDuration(cfg.EventLogTTL)
+	if err != nil {
+		ttl = 72 * time.Hour
+	}
+
+	size := cfg.EventLogSize
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &EventLog{
+		redis: r,
+		size:  size,
+		ttl:   ttl,
+	}
+}
+
+func eventsKey(userID int) string {
+	return fmt.Sprintf("events:%d", userID)
+}
+
+func eventsSeqKey(userID int) string {
+	return fmt.Sprintf("events:%d:seq", userID)
+}
+
+// Append assigns the next sequence number of the user to the frame and stores it
+func (l *EventLog) Append(ctx context.Context, userID int, frame entity.Frame) (entity.Frame, error) {
+	seq, err := l.redis.Cache.Incr(ctx, eventsSeqKey(userID)).Result()
+	if err != nil {
+		return entity.Frame{}, err
+	}
+
+	frame.Seq = seq
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return entity.Frame{}, err
+	}
+
+	_, err = l.redis.Cache.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, eventsKey(userID), redis.Z{Score: float64(seq), Member: data})
+		pipe.ZRemRangeByRank(ctx, eventsKey(userID), 0, -(l.size + 1))
+		pipe.Expire(ctx, eventsKey(userID), l.ttl)
+		pipe.Expire(ctx, eventsSeqKey(userID), l.ttl)
+		return nil
+	})
+	if err != nil {
+		return entity.Frame{}, err
+	}
+
+	return frame, nil
+}
+
+// Since returns the retained events of the user after seq, the latest sequence number
+// and whether the returned events cover everything that happened after seq
+func (l *EventLog) Since(ctx context.Context, userID int, seq int64) ([]entity.Frame, int64, bool, error) {
+	latest, err := l.Latest(ctx, userID)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	if seq >= latest {
+		return nil, latest, coversSince(nil, seq, latest), nil
+	}
+
+	values, err := l.redis.Cache.ZRangeByScore(ctx, eventsKey(userID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(seq, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	frames, err := decodeFrames(values)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	return frames, latest, coversSince(frames, seq, latest), nil
+}
+
+func decodeFrames(values []string) ([]entity.Frame, error) {
+	frames := make([]entity.Frame, 0, len(values))
+	for _, value := range values {
+		var frame entity.Frame
+		if err := json.Unmarshal([]byte(value), &frame); err != nil {
+			return nil, err
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+// coversSince reports whether the frames, ordered by sequence number, are every event from seq+1 up to latest.
+// The oldest ones are gone once the log is trimmed, and an event whose number was taken but that could not be
+// stored leaves a gap.
+func coversSince(frames []entity.Frame, seq, latest int64) bool {
+	if len(frames) == 0 {
+		return seq == latest
+	}
+
+	for i, frame := range frames {
+		if frame.Seq != seq+int64(i)+1 {
+			return false
+		}
+	}
+
+	return frames[len(frames)-1].Seq >= latest
+}
+
+// Latest returns the sequence number of the last event stored for the user
+func (l *EventLog) Latest(ctx context.Context, userID int) (int64, error) {
+	latest, err := l.redis.Cache.Get(ctx, eventsSeqKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return latest, err
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"archv1/internal/entity"
+	"testing"
+)
+
+func frames(seqs ...int64) []entity.Frame {
+	out := make([]entity.Frame, 0, len(seqs))
+	for _, seq := range seqs {
+		out = append(out, entity.Frame{Seq: seq})
+	}
+
+	return out
+}
+
+func TestCoversSince(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []entity.Frame
+		seq    int64
+		latest int64
+		want   bool
+	}{
+		{name: "up to date", seq: 5, latest: 5, want: true},
+		{name: "nothing happened yet", seq: 0, latest: 0, want: true},
+		{name: "ahead of the log", seq: 9, latest: 5, want: false},
+		{name: "everything retained", frames: frames(4, 5, 6), seq: 3, latest: 6, want: true},
+		{name: "appended while reading", frames: frames(4, 5, 6, 7), seq: 3, latest: 6, want: true},
+		{name: "oldest trimmed", frames: frames(5, 6), seq: 3, latest: 6, want: false},
+		{name: "log expired", seq: 3, latest: 6, want: false},
+		{name: "gap in the middle", frames: frames(4, 6), seq: 3, latest: 6, want: false},
+		{name: "newest not stored", frames: frames(4, 5), seq: 3, latest: 6, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := coversSince(tt.frames, tt.seq, tt.latest); got != tt.want {
+			t.Errorf("%s: coversSince = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeFrames(t *testing.T) {
+	got, err := decodeFrames([]string{`{"seq":4}`, `{"seq":5}`})
+	if err != nil {
+		t.Fatalf("decodeFrames: %v", err)
+	}
+
+	if len(got) != 2 || got[0].Seq != 4 || got[1].Seq != 5 {
+		t.Fatalf("decodeFrames = %+v, want the frames 4 and 5", got)
+	}
+
+	if _, err := decodeFrames([]string{`{"seq":4}`, `not json`}); err == nil {
+		t.Fatalf("decodeFrames took a broken frame")
+	}
+}
@@ -0,0 +1,94 @@
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("the download link is invalid")
+	ErrExpired      = errors.New("the download link has expired")
+)
+
+// Claims are what a download link grants, they are signed as a whole so none can be changed
+type Claims struct {
+	FileID    int    `json:"f"`
+	ExpiresAt int64  `json:"e"`
+	Nonce     string `json:"n"`
+	SingleUse bool   `json:"o,omitempty"`
+	// IP binds the link to the address it was created for when it is not empty
+	IP     string `json:"ip,omitempty"`
+	Inline bool   `json:"i,omitempty"`
+}
+
+// Signer issues and verifies download tokens of the form base64url(claims) "." base64url(HMAC-SHA256(claims))
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{
+		key: []byte(secret),
+	}
+}
+
+// Sign fills in a random nonce and returns the token of the claims
+func (s *Signer) Sign(claims Claims) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	claims.Nonce = base64.RawURLEncoding.EncodeToString(nonce)
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Verify checks the signature and the expiry of a token and returns its claims
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Nonce == "" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpired
+	}
+
+	return claims, nil
+}
+
+func (s *Signer) mac(data string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
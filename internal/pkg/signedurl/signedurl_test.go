@@ -0,0 +1,116 @@
+package signedurl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyReturnsTheSignedClaims(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(Claims{FileID: 7, ExpiresAt: now.Add(time.Minute).Unix(), SingleUse: true, IP: "203.0.113.9"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	claims, err := signer.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if claims.FileID != 7 || !claims.SingleUse || claims.IP != "203.0.113.9" || claims.Nonce == "" {
+		t.Fatalf("Verify = %+v, want the signed claims with a nonce", claims)
+	}
+
+	again, err := signer.Sign(Claims{FileID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if again == token {
+		t.Errorf("two links for the same file got the same token")
+	}
+}
+
+func TestVerifyRefusesExpiredTokens(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(Claims{FileID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if _, err := signer.Verify(token, now.Add(time.Minute-time.Second)); err != nil {
+		t.Fatalf("a second before the expiry: %v", err)
+	}
+
+	for _, at := range []time.Time{now.Add(time.Minute), now.Add(time.Hour)} {
+		if _, err := signer.Verify(token, at); !errors.Is(err, ErrExpired) {
+			t.Errorf("Verify at %s = %v, want ErrExpired", at.Sub(now), err)
+		}
+	}
+}
+
+func TestVerifyRefusesTamperedTokens(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(Claims{FileID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	// the same claims pointing at another file and living longer, signed with the original signature
+	payload, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("decoding the claims: %v", err)
+	}
+
+	claims.FileID = 8
+	claims.ExpiresAt = now.Add(time.Hour).Unix()
+
+	changed, _ := json.Marshal(claims)
+
+	forged, err := NewSigner("other").Sign(Claims{FileID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	tests := map[string]string{
+		"changed claims":   base64.RawURLEncoding.EncodeToString(changed) + "." + signature,
+		"changed mac":      encoded + "." + string(flipped),
+		"other secret":     forged,
+		"no signature":     encoded,
+		"empty signature":  encoded + ".",
+		"not base64":       encoded + ".!!",
+		"empty token":      "",
+		"signed non-claim": unsigned(signer, "[]"),
+		"no nonce":         unsigned(signer, `{"f":7,"e":1700000060}`),
+	}
+
+	for name, token := range tests {
+		if _, err := signer.Verify(token, now); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+// unsigned signs a payload as it is, without the nonce Sign fills in
+func unsigned(s *Signer, payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
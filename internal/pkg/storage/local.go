@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// tempPrefix marks the partial files of uploads in progress, List skips them
+const tempPrefix = ".upload-"
+
+// LocalStore keeps the blobs as files below a root directory. The files of UploadsPrefix stay below
+// legacyRoot when it is set, where /v1/upload saved them before the store was configurable.
+type LocalStore struct {
+	root       string
+	legacyRoot string
+}
+
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{
+		root: root,
+	}
+}
+
+// Put writes to a temporary file first, so readers never see a partial blob
+func (l *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (BlobInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return BlobInfo{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), tempPrefix+"*")
+	if err != nil {
+		return BlobInfo{}, err
+	}
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size >= 0 && written != size {
+		err = fmt.Errorf("wrote %d of %d bytes: %w", written, size, io.ErrUnexpectedEOF)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return BlobInfo{}, err
+	}
+
+	return l.Stat(ctx, key)
+}
+
+func (l *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, BlobInfo{}, notFound(err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, BlobInfo{}, err
+	}
+
+	if stat.IsDir() {
+		_ = file.Close()
+		return nil, BlobInfo{}, ErrNotFound
+	}
+
+	return file, localInfo(key, stat), nil
+}
+
+func (l *LocalStore) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, BlobInfo, error) {
+	body, info, err := l.Get(ctx, key)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	file := body.(*os.File)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, BlobInfo{}, err
+	}
+
+	return limitedBody{Reader: io.LimitReader(file, length), Closer: file}, info, nil
+}
+
+func (l *LocalStore) Stat(_ context.Context, key string) (BlobInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+
+	stat, err := os.Stat(name)
+	if err != nil {
+		return BlobInfo{}, notFound(err)
+	}
+
+	if stat.IsDir() {
+		return BlobInfo{}, ErrNotFound
+	}
+
+	return localInfo(key, stat), nil
+}
+
+func (l *LocalStore) Move(_ context.Context, src, dst string) error {
+	from, err := l.path(src)
+	if err != nil {
+		return err
+	}
+
+	to, err := l.path(dst)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(from, to); err != nil {
+		return notFound(err)
+	}
+
+	return nil
+}
+
+func (l *LocalStore) Delete(_ context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *LocalStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	// walk only the directory holding the prefix
+	dir := path.Dir(prefix)
+	if strings.HasSuffix(prefix, "/") {
+		dir = strings.TrimSuffix(prefix, "/")
+	}
+
+	root := l.rootOf(prefix)
+	start := root
+	if dir != "." && dir != "" {
+		if _, err := cleanKey(dir); err != nil {
+			return nil, err
+		}
+
+		start = filepath.Join(root, filepath.FromSlash(dir))
+	}
+
+	var blobs []BlobInfo
+	err := filepath.WalkDir(start, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		blobs = append(blobs, localInfo(key, stat))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
+func (l *LocalStore) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(l.rootOf(key), filepath.FromSlash(key)), nil
+}
+
+// rootOf returns the directory the key is stored below
+func (l *LocalStore) rootOf(key string) string {
+	if l.legacyRoot != "" && strings.HasPrefix(key, UploadsPrefix) {
+		return l.legacyRoot
+	}
+
+	return l.root
+}
+
+// localInfo describes a file, the content type is guessed from the extension
+// and the ETag changes with the size and the modification time
+func localInfo(key string, stat fs.FileInfo) BlobInfo {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return BlobInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: contentType,
+		ETag:        fmt.Sprintf(`"%x-%x"`, stat.ModTime().UnixNano(), stat.Size()),
+		ModTime:     stat.ModTime(),
+	}
+}
+
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
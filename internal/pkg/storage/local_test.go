@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"archv1/internal/pkg/config"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreKeepsLegacyUploadsInPlace(t *testing.T) {
+	root, legacy := t.TempDir(), t.TempDir()
+	ctx := context.Background()
+
+	store, err := NewBlobStore(&config.Config{StorageLocalDir: root, StorageLegacyDir: legacy})
+	if err != nil {
+		t.Fatalf("NewBlobStore: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: UploadsPrefix + "photo.png", want: filepath.Join(legacy, "files", "photo.png")},
+		{key: ContentKey(strings.Repeat("ab", 32)), want: filepath.Join(root, "blobs", "ab", strings.Repeat("ab", 32))},
+		{key: ChatFilesPrefix + "voice.ogg", want: filepath.Join(root, "chat_files", "voice.ogg")},
+		{key: "filesystem/notes.txt", want: filepath.Join(root, "filesystem", "notes.txt")},
+	}
+
+	for _, tt := range tests {
+		if _, err := store.Put(ctx, tt.key, strings.NewReader("content"), 7, ""); err != nil {
+			t.Fatalf("Put %s: %v", tt.key, err)
+		}
+
+		if _, err := os.Stat(tt.want); err != nil {
+			t.Errorf("%s is not stored at %s: %v", tt.key, tt.want, err)
+		}
+	}
+
+	listed, err := store.List(ctx, UploadsPrefix)
+	if err != nil || len(listed) != 1 || listed[0].Key != UploadsPrefix+"photo.png" {
+		t.Errorf("List of the legacy uploads = %+v, %v, want files/photo.png", listed, err)
+	}
+
+	if err := store.Move(ctx, UploadsPrefix+"photo.png", VariantsPrefix+"photo.png"); err != nil {
+		t.Fatalf("Move out of the legacy uploads: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "variants", "photo.png")); err != nil {
+		t.Errorf("the moved file is not below the root: %v", err)
+	}
+}
@@ -0,0 +1,429 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayload is the SHA-256 of an empty body
+	emptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Options configures an S3 compatible store such as AWS S3 or MinIO
+type S3Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket as endpoint/bucket instead of bucket.endpoint, MinIO needs it
+	PathStyle bool
+}
+
+// S3Store keeps the blobs in an S3 bucket, the requests are signed with AWS Signature Version 4
+type S3Store struct {
+	client  *http.Client
+	options S3Options
+	base    *url.URL
+	now     func() time.Time
+}
+
+// S3Error is a failed S3 request with the error code of the response body
+type S3Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+func NewS3Store(client *http.Client, options S3Options) (*S3Store, error) {
+	if options.Endpoint == "" || options.Bucket == "" {
+		return nil, errors.New("s3 storage needs an endpoint and a bucket")
+	}
+
+	base, err := url.Parse(options.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", options.Endpoint)
+	}
+
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+
+	return &S3Store{
+		client:  client,
+		options: options,
+		base:    base,
+		now:     time.Now,
+	}, nil
+}
+
+// Put streams the body without hashing it, so size has to be known up front
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (BlobInfo, error) {
+	if size < 0 {
+		return BlobInfo{}, errors.New("s3 storage needs the size of the upload")
+	}
+
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, io.NopCloser(r), size, header)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	return BlobInfo{
+		Key:         key,
+		Size:        size,
+		ContentType: contentType,
+		ETag:        resp.Header.Get("ETag"),
+		ModTime:     s.now().UTC(),
+	}, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, 0, nil)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	return resp.Body, s3Info(key, resp), nil
+}
+
+func (s *S3Store) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, BlobInfo, error) {
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, 0, header)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	// a server that ignores the range sends the whole object
+	if resp.StatusCode != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			_ = resp.Body.Close()
+			return nil, BlobInfo{}, err
+		}
+	}
+
+	return limitedBody{Reader: io.LimitReader(resp.Body, length), Closer: resp.Body}, s3Info(key, resp), nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, 0, nil)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	return s3Info(key, resp), nil
+}
+
+// Move copies the object on the server side and deletes the source, S3 has no rename
+func (s *S3Store) Move(ctx context.Context, src, dst string) error {
+	source, err := cleanKey(src)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", uriEncode("/"+s.options.Bucket+"/"+source, false))
+
+	resp, err := s.do(ctx, http.MethodPut, dst, nil, nil, 0, header)
+	if err != nil {
+		return err
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return err
+	}
+
+	return s.Delete(ctx, src)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, 0, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// List pages through ListObjectsV2 until the listing is complete
+func (s *S3Store) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	var (
+		blobs []BlobInfo
+		token string
+	)
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			IsTruncated           bool
+			NextContinuationToken string
+			Contents              []struct {
+				Key          string
+				LastModified time.Time
+				ETag         string
+				Size         int64
+			}
+		}
+
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			blobs = append(blobs, BlobInfo{
+				Key:     object.Key,
+				Size:    object.Size,
+				ETag:    object.ETag,
+				ModTime: object.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return blobs, nil
+		}
+
+		token = result.NextContinuationToken
+	}
+}
+
+// do signs and sends a request for key, an empty key addresses the bucket.
+// A 404 becomes ErrNotFound and any other failure an *S3Error.
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body io.ReadCloser, size int64, header http.Header) (*http.Response, error) {
+	if key != "" {
+		var err error
+		if key, err = cleanKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key, query).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	payload := emptyPayload
+	if body != nil {
+		req.Body = body
+		req.ContentLength = size
+		payload = unsignedPayload
+	}
+
+	s.sign(req, payload, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	failure := &S3Error{Status: resp.StatusCode, Code: http.StatusText(resp.StatusCode)}
+	var parsed struct {
+		Code    string
+		Message string
+	}
+	if xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&parsed) == nil && parsed.Code != "" {
+		failure.Code = parsed.Code
+		failure.Message = parsed.Message
+	}
+
+	return nil, failure
+}
+
+func (s *S3Store) objectURL(key string, query url.Values) *url.URL {
+	u := *s.base
+	prefix := strings.TrimSuffix(u.Path, "/")
+
+	if s.options.PathStyle {
+		prefix += "/" + s.options.Bucket
+	} else {
+		u.Host = s.options.Bucket + "." + u.Host
+	}
+
+	u.Path = prefix + "/" + key
+	u.RawPath = uriEncode(prefix, false) + "/" + uriEncode(key, false)
+	u.RawQuery = canonicalQuery(query)
+
+	return &u
+}
+
+// sign adds the AWS Signature Version 4 authorization, signing the host, the range and the x-amz-* headers
+func (s *S3Store) sign(req *http.Request, payload string, now time.Time) {
+	now = now.UTC()
+	stamp := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payload,
+	}, "\n")
+
+	scope := day + "/" + s.options.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", stamp, scope, hashHex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), day)
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.options.AccessKey, scope, signedHeaders, signature))
+}
+
+func s3Info(key string, resp *http.Response) BlobInfo {
+	info := BlobInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        resp.Header.Get("ETag"),
+	}
+
+	if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+
+	// a ranged response carries the size of the whole object in "bytes first-last/size"
+	if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+		if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+			info.Size = size
+		}
+	}
+
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modified
+	}
+
+	return info
+}
+
+// canonicalQuery sorts and encodes the query the way the signature expects
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode escapes everything except the unreserved characters, slashes are kept unless encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// limitedBody closes the blob behind a reader that stops at the end of a range
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// Seeker reads a blob as an io.ReadSeeker, so it can be served with http.ServeContent.
+// Every seek that moves away from the current position reopens the blob at the new offset
+// with GetRange, a range request therefore fetches only the bytes it needs.
+type Seeker struct {
+	ctx    context.Context
+	store  BlobStore
+	info   BlobInfo
+	offset int64
+	body   io.ReadCloser
+}
+
+// OpenSeeker looks the blob up and returns a Seeker positioned at its start
+func OpenSeeker(ctx context.Context, store BlobStore, key string) (*Seeker, error) {
+	info, err := store.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Seeker{
+		ctx:   ctx,
+		store: store,
+		info:  info,
+	}, nil
+}
+
+// Info describes the blob behind the Seeker
+func (s *Seeker) Info() BlobInfo {
+	return s.info
+}
+
+func (s *Seeker) Read(p []byte) (int, error) {
+	if s.offset >= s.info.Size {
+		return 0, io.EOF
+	}
+
+	if s.body == nil {
+		body, _, err := s.store.GetRange(s.ctx, s.info.Key, s.offset, s.info.Size-s.offset)
+		if err != nil {
+			return 0, err
+		}
+
+		s.body = body
+	}
+
+	n, err := s.body.Read(p)
+	s.offset += int64(n)
+
+	return n, err
+}
+
+func (s *Seeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.info.Size
+	}
+
+	if offset < 0 {
+		return 0, errors.New("seek before the start of the blob")
+	}
+
+	if offset != s.offset {
+		_ = s.Close()
+		s.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (s *Seeker) Close() error {
+	if s.body == nil {
+		return nil
+	}
+
+	err := s.body.Close()
+	s.body = nil
+
+	return err
+}
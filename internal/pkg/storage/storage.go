@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"archv1/internal/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Key prefixes of the uploads. Menu, post and chat files are stored once per content under BlobsPrefix, the ones
+// uploaded before that under UploadsPrefix and the chat files under ChatFilesPrefix. The chunks of unfinished resumable
+// uploads live under ChunksPrefix, the uploads being hashed under StagingPrefix and the resized copies of images under VariantsPrefix.
+const (
+	UploadsPrefix   = "files/"
+	BlobsPrefix     = "blobs/"
+	StagingPrefix   = "blobs/staging/"
+	ChatFilesPrefix = "chat_files/"
+	ChunksPrefix    = "uploads/"
+	VariantsPrefix  = "variants/"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file path")
+)
+
+// BlobInfo describes a stored blob, ETag is quoted as in the HTTP header
+type BlobInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+// BlobStore keeps the uploaded files. Keys are slash separated paths relative to the store,
+// a key with an empty or ".." segment is rejected with ErrInvalidKey.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing a previous blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (BlobInfo, error)
+	// Get opens the blob for reading, the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// GetRange opens length bytes of the blob starting at offset, BlobInfo.Size is the size of the whole blob
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, BlobInfo, error)
+	Stat(ctx context.Context, key string) (BlobInfo, error)
+	// Move renames the blob at src to dst, replacing a blob stored under dst
+	Move(ctx context.Context, src, dst string) error
+	// Delete removes the blob, deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the blobs whose keys start with prefix
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+}
+
+// NewBlobStore returns the backend selected by cfg.StorageBackend, the local disk by default
+func NewBlobStore(cfg *config.Config) (BlobStore, error) {
+	switch cfg.StorageBackend {
+	case "", "local":
+		root := cfg.StorageLocalDir
+		if root == "" {
+			root = "./data"
+		}
+
+		store := NewLocalStore(root)
+		store.legacyRoot = cfg.StorageLegacyDir
+
+		return store, nil
+	case "s3":
+		return NewS3Store(&http.Client{}, S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
+
+// ContentKey is the key of a blob addressed by the hex SHA-256 of its content,
+// the first two digits make a directory so no directory holds too many blobs
+func ContentKey(checksum string) string {
+	return BlobsPrefix + checksum[:2] + "/" + checksum
+}
+
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+
+	return path.Clean(key), nil
+}
@@ -0,0 +1,78 @@
+package upload
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// mediaExtensions are the extensions a media file may keep, the first one replaces any other
+var mediaExtensions = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg", ".jpe", ".jfif"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"image/bmp":       {".bmp"},
+	"image/x-icon":    {".ico"},
+	"video/mp4":       {".mp4", ".m4v", ".m4a"},
+	"video/webm":      {".webm"},
+	"video/avi":       {".avi"},
+	"audio/mpeg":      {".mp3"},
+	"audio/wave":      {".wav"},
+	"audio/aiff":      {".aiff", ".aif"},
+	"audio/midi":      {".mid", ".midi"},
+	"application/pdf": {".pdf"},
+}
+
+// SanitizeFilename keeps the base name of the upload with letters, digits and a few punctuation
+// marks, drops control and bidi characters and shortens it to maxLength bytes. The extension of
+// a media file is replaced when it does not belong to the sniffed type.
+func SanitizeFilename(name, mimeType string, maxLength int) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == utf8.RuneError, unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			continue
+		case unicode.IsLetter(r), unicode.IsDigit(r), strings.ContainsRune(" .-_()", r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	name = strings.Trim(b.String(), " .")
+	ext := strings.ToLower(path.Ext(name))
+	base := strings.TrimRight(name[:len(name)-len(ext)], " .")
+
+	if extensions, ok := mediaExtensions[mimeType]; ok && !contains(extensions, ext) {
+		ext = extensions[0]
+	}
+
+	if len(ext) > maxLength/2 {
+		ext = ""
+	}
+
+	if base == "" {
+		base = "file"
+	}
+
+	for len(base)+len(ext) > maxLength {
+		_, size := utf8.DecodeLastRuneInString(base)
+		base = base[:len(base)-size]
+	}
+
+	return base + ext
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,263 @@
+package upload
+
+import (
+	"archv1/internal/pkg/config"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ScanWindow is how much of the start and of the end of a file is sniffed and searched for embedded content
+const ScanWindow = 64 << 10
+
+// formOverhead leaves room for the multipart boundaries and the other form fields
+const formOverhead = 1 << 20
+
+var (
+	ErrInvalidFile     = errors.New("invalid file request")
+	ErrEmptyFile       = errors.New("file is empty")
+	ErrTooLarge        = errors.New("file is too large")
+	ErrUnknownCategory = errors.New("unknown upload category")
+	ErrTypeNotAllowed  = errors.New("file type is not allowed")
+	ErrExecutable      = errors.New("executable files are not allowed")
+	ErrPolyglot        = errors.New("file holds content of another type")
+)
+
+// Inspection is what the validator found out about an accepted upload
+type Inspection struct {
+	// Name is the sanitized filename, its extension follows the sniffed type of media files
+	Name     string
+	MimeType string
+}
+
+// Validator checks uploads against the per category rules of config.Config.Uploads
+type Validator struct {
+	rules         map[string]config.UploadRule
+	maxNameLength int
+}
+
+func NewValidator(cfg *config.Config) *Validator {
+	maxNameLength := cfg.UploadMaxNameLength
+	if maxNameLength <= 0 {
+		maxNameLength = 100
+	}
+
+	return &Validator{
+		rules:         cfg.Uploads,
+		maxNameLength: maxNameLength,
+	}
+}
+
+// FormFile reads the file field of a multipart request, the body is cut off once it
+// exceeds the size limit of the category so an oversized upload is never buffered
+func (v *Validator) FormFile(w http.ResponseWriter, r *http.Request, category, field string) (*multipart.FileHeader, error) {
+	rule, ok := v.rules[category]
+	if !ok {
+		return nil, ErrUnknownCategory
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, rule.MaxSize+formOverhead)
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, fmt.Errorf("%w, the limit is %d bytes", ErrTooLarge, rule.MaxSize)
+		}
+
+		return nil, ErrInvalidFile
+	}
+
+	_ = file.Close()
+
+	return header, nil
+}
+
+// Check applies the size limit and the denied extensions of the category before any byte is read
+func (v *Validator) Check(category, name string, size int64) error {
+	rule, ok := v.rules[category]
+	if !ok {
+		return ErrUnknownCategory
+	}
+
+	if size <= 0 {
+		return ErrEmptyFile
+	}
+
+	if size > rule.MaxSize {
+		return fmt.Errorf("%w, the limit is %d bytes", ErrTooLarge, rule.MaxSize)
+	}
+
+	if deniedExtensions[strings.ToLower(path.Ext(name))] {
+		return ErrExecutable
+	}
+
+	return nil
+}
+
+// Inspect sniffs the content type of the upload from its bytes and checks it against the
+// rule of the category, executables and files hiding markup or an archive are rejected
+func (v *Validator) Inspect(category string, header *multipart.FileHeader) (Inspection, error) {
+	if err := v.Check(category, header.Filename, header.Size); err != nil {
+		return Inspection{}, err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return Inspection{}, ErrInvalidFile
+	}
+	defer file.Close()
+
+	head, err := readAt(file, 0, ScanWindow)
+	if err != nil {
+		return Inspection{}, err
+	}
+
+	tail := head
+	if header.Size > ScanWindow {
+		if tail, err = readAt(file, header.Size-ScanWindow, ScanWindow); err != nil {
+			return Inspection{}, err
+		}
+	}
+
+	return v.InspectContent(category, header.Filename, header.Size, head, tail)
+}
+
+// InspectContent is Inspect for a file that is not at hand as a whole,
+// head and tail are its first and last ScanWindow bytes
+func (v *Validator) InspectContent(category, name string, size int64, head, tail []byte) (Inspection, error) {
+	if err := v.Check(category, name, size); err != nil {
+		return Inspection{}, err
+	}
+
+	if isExecutable(head) {
+		return Inspection{}, ErrExecutable
+	}
+
+	mimeType := strings.SplitN(http.DetectContentType(head), ";", 2)[0]
+	if !allowed(v.rules[category].AllowedTypes, mimeType) {
+		return Inspection{}, fmt.Errorf("%w: %s", ErrTypeNotAllowed, mimeType)
+	}
+
+	if isPolyglot(mimeType, head, tail) {
+		return Inspection{}, ErrPolyglot
+	}
+
+	return Inspection{
+		Name:     SanitizeFilename(name, mimeType, v.maxNameLength),
+		MimeType: mimeType,
+	}, nil
+}
+
+// ErrorStatus is the HTTP status of a validation error
+func ErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrTypeNotAllowed), errors.Is(err, ErrExecutable), errors.Is(err, ErrPolyglot):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func readAt(file io.ReaderAt, offset, size int64) ([]byte, error) {
+	buf := make([]byte, size)
+
+	n, err := file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// allowed matches the type against the allow-list, "image/*" allows every image
+func allowed(allowedTypes []string, mimeType string) bool {
+	for _, allowedType := range allowedTypes {
+		if allowedType == mimeType || allowedType == "*/*" {
+			return true
+		}
+
+		if strings.HasSuffix(allowedType, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(allowedType, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var executableSignatures = [][]byte{
+	[]byte("MZ"),             // Windows PE and DOS
+	[]byte("\x7fELF"),        // Linux and BSD
+	{0xfe, 0xed, 0xfa, 0xce}, // Mach-O 32 bit
+	{0xfe, 0xed, 0xfa, 0xcf}, // Mach-O 64 bit
+	{0xce, 0xfa, 0xed, 0xfe}, // Mach-O 32 bit, little endian
+	{0xcf, 0xfa, 0xed, 0xfe}, // Mach-O 64 bit, little endian
+	{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal and Java classes
+	[]byte("\x00asm"),        // WebAssembly
+	[]byte("dex\n"),          // Android
+	[]byte("#!"),             // scripts
+}
+
+// deniedExtensions are run by the OS or a browser no matter what their bytes look like
+var deniedExtensions = map[string]bool{
+	".exe": true, ".com": true, ".scr": true, ".msi": true, ".dll": true, ".bat": true, ".cmd": true,
+	".ps1": true, ".vbs": true, ".js": true, ".jar": true, ".apk": true, ".sh": true, ".lnk": true,
+	".hta": true, ".html": true, ".htm": true, ".svg": true, ".php": true,
+}
+
+func isExecutable(head []byte) bool {
+	for _, signature := range executableSignatures {
+		if bytes.HasPrefix(head, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markupMarkers turn a media file into a page when a browser sniffs it
+var markupMarkers = [][]byte{
+	[]byte("<script"),
+	[]byte("<html"),
+	[]byte("<iframe"),
+	[]byte("<svg"),
+	[]byte("<?php"),
+	[]byte("javascript:"),
+}
+
+// isPolyglot looks for markup in media files and for a zip archive inside a file that is not one,
+// e.g. a GIF with a jar appended
+func isPolyglot(mimeType string, head, tail []byte) bool {
+	if mimeType != "application/zip" {
+		if bytes.Contains(tail, []byte("PK\x05\x06")) || bytes.Contains(head[min(len(head), 4):], []byte("PK\x03\x04")) {
+			return true
+		}
+	}
+
+	if !isMedia(mimeType) {
+		return false
+	}
+
+	for _, window := range [][]byte{head, tail} {
+		lower := bytes.ToLower(window)
+		for _, marker := range markupMarkers {
+			if bytes.Contains(lower, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isMedia(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "image/") || strings.HasPrefix(mimeType, "audio/") ||
+		strings.HasPrefix(mimeType, "video/") || mimeType == "application/pdf"
+}
@@ -0,0 +1,157 @@
+package upload
+
+import (
+	"archv1/internal/pkg/config"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+
+func newTestValidator() *Validator {
+	return NewValidator(&config.Config{
+		Uploads: map[string]config.UploadRule{
+			"image": {MaxSize: 1 << 20, AllowedTypes: []string{"image/*"}},
+			"file":  {MaxSize: 1 << 20, AllowedTypes: []string{"*/*"}},
+			"zip":   {MaxSize: 1 << 20, AllowedTypes: []string{"application/zip"}},
+		},
+	})
+}
+
+// inspect runs InspectContent on a file held in memory
+func inspect(v *Validator, category, name string, content []byte) (Inspection, error) {
+	head := content[:min(len(content), ScanWindow)]
+	tail := content[max(len(content)-ScanWindow, 0):]
+
+	return v.InspectContent(category, name, int64(len(content)), head, tail)
+}
+
+func TestInspectAcceptsPlainFiles(t *testing.T) {
+	v := newTestValidator()
+
+	got, err := inspect(v, "image", "photo.png", append(pngHeader, make([]byte, 100)...))
+	if err != nil {
+		t.Fatalf("a png: %v", err)
+	}
+
+	if got.MimeType != "image/png" || got.Name != "photo.png" {
+		t.Fatalf("a png = %+v, want photo.png as image/png", got)
+	}
+
+	zip := append([]byte("PK\x03\x04"), make([]byte, 100)...)
+	zip = append(zip, []byte("PK\x05\x06")...)
+	if _, err := inspect(v, "zip", "archive.zip", zip); err != nil {
+		t.Fatalf("a zip: %v", err)
+	}
+}
+
+func TestInspectRejectsExecutables(t *testing.T) {
+	v := newTestValidator()
+
+	tests := map[string][]byte{
+		"windows":     append([]byte("MZ\x90\x00"), make([]byte, 64)...),
+		"linux":       append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 64)...),
+		"mach-o":      append([]byte{0xcf, 0xfa, 0xed, 0xfe}, make([]byte, 64)...),
+		"java class":  append([]byte{0xca, 0xfe, 0xba, 0xbe}, make([]byte, 64)...),
+		"webassembly": append([]byte("\x00asm\x01\x00\x00\x00"), make([]byte, 64)...),
+		"script":      []byte("#!/bin/sh\nrm -rf /\n"),
+	}
+
+	for name, content := range tests {
+		if _, err := inspect(v, "file", "notes.txt", content); !errors.Is(err, ErrExecutable) {
+			t.Errorf("%s: InspectContent = %v, want ErrExecutable", name, err)
+		}
+	}
+
+	for _, name := range []string{"setup.exe", "run.BAT", "page.html", "icon.svg", "app.js"} {
+		if _, err := inspect(v, "file", name, []byte("plain text")); !errors.Is(err, ErrExecutable) {
+			t.Errorf("%s: InspectContent = %v, want ErrExecutable", name, err)
+		}
+	}
+}
+
+func TestInspectRejectsPolyglots(t *testing.T) {
+	v := newTestValidator()
+
+	padding := bytes.Repeat([]byte{0}, 2*ScanWindow)
+
+	tests := map[string][]byte{
+		"script in an image":          append(append([]byte{}, pngHeader...), []byte("<script>alert(1)</script>")...),
+		"markup at the end":           append(append(append([]byte{}, pngHeader...), padding...), []byte("<HTML><body>")...),
+		"zip appended to an image":    append(append(append([]byte{}, pngHeader...), padding...), []byte("PK\x05\x06\x00\x00")...),
+		"zip entry inside an image":   append(append([]byte{}, pngHeader...), []byte("PK\x03\x04")...),
+		"javascript link in an image": append(append([]byte{}, pngHeader...), []byte("javascript:alert(1)")...),
+	}
+
+	for name, content := range tests {
+		if _, err := inspect(v, "image", "photo.png", content); !errors.Is(err, ErrPolyglot) {
+			t.Errorf("%s: InspectContent = %v, want ErrPolyglot", name, err)
+		}
+	}
+
+	if _, err := inspect(v, "file", "notes.txt", []byte("see <script> in the docs")); err != nil {
+		t.Errorf("markup in a text file is not a polyglot: %v", err)
+	}
+}
+
+func TestInspectAppliesTheRule(t *testing.T) {
+	v := newTestValidator()
+
+	if _, err := inspect(v, "image", "notes.txt", []byte("plain text")); !errors.Is(err, ErrTypeNotAllowed) {
+		t.Errorf("text as an image: %v, want ErrTypeNotAllowed", err)
+	}
+
+	if _, err := inspect(v, "image", "photo.png", append(pngHeader, make([]byte, 1<<20)...)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("an oversized image: %v, want ErrTooLarge", err)
+	}
+
+	if _, err := inspect(v, "image", "photo.png", nil); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("an empty file: %v, want ErrEmptyFile", err)
+	}
+
+	if _, err := inspect(v, "video", "clip.mp4", pngHeader); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("an unknown category: %v, want ErrUnknownCategory", err)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name      string
+		mimeType  string
+		maxLength int
+		want      string
+	}{
+		{name: "report.pdf", mimeType: "application/pdf", maxLength: 100, want: "report.pdf"},
+		{name: "../../etc/passwd", mimeType: "text/plain", maxLength: 100, want: "passwd"},
+		{name: `C:\Users\me\photo.JPG`, mimeType: "image/jpeg", maxLength: 100, want: "photo.jpg"},
+		{name: "photo.php", mimeType: "image/png", maxLength: 100, want: "photo.png"},
+		{name: "invoice\u202egnp.exe", mimeType: "text/plain", maxLength: 100, want: "invoicegnp.exe"},
+		{name: "a<b>|c.txt", mimeType: "text/plain", maxLength: 100, want: "a_b__c.txt"},
+		{name: " ...", mimeType: "text/plain", maxLength: 100, want: "file"},
+		{name: "abcdefghij.png", mimeType: "image/png", maxLength: 8, want: "abcd.png"},
+		{name: "ab.verylongextension", mimeType: "text/plain", maxLength: 10, want: "ab"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeFilename(tt.name, tt.mimeType, tt.maxLength); got != tt.want {
+			t.Errorf("SanitizeFilename(%q, %q, %d) = %q, want %q", tt.name, tt.mimeType, tt.maxLength, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeFilenameCutsWholeCharacters(t *testing.T) {
+	name := strings.Repeat("ж", 80) + ".png"
+
+	got := SanitizeFilename(name, "image/png", 101)
+
+	if len(got) > 101 || !utf8.ValidString(got) || !strings.HasSuffix(got, ".png") {
+		t.Fatalf("SanitizeFilename = %q (%d bytes), want at most 101 bytes of valid UTF-8 ending in .png", got, len(got))
+	}
+
+	if len(got) != 100 {
+		t.Fatalf("SanitizeFilename kept %d bytes, want 100 since a two byte letter does not fit the last byte", len(got))
+	}
+}
@@ -0,0 +1,61 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook posts payloads to the URLs registered by users. Every call is signed with the webhook secret,
+// X-Webhook-Signature is "sha256=" and the hex HMAC-SHA256 of the timestamp, a dot and the body.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook(client *http.Client) *Webhook {
+	return &Webhook{
+		client: client,
+	}
+}
+
+// Send posts the payload, jobID is sent as X-Webhook-ID so receivers can drop repeated deliveries
+func (h *Webhook) Send(ctx context.Context, url, secret string, jobID int64, payload []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-ID", strconv.FormatInt(jobID, 10))
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+Sign(secret, timestamp, payload))
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	return checkStatus(response)
+}
+
+// Sign returns the hex signature of a webhook call, receivers compute it the same way to verify a call
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,294 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recordSize is the aes128gcm record size, a push message is a single record of at most 4096 bytes
+const recordSize = 4096
+
+// maxPayload leaves room in the record for the padding delimiter and the authentication tag
+const maxPayload = recordSize - 1 - 16
+
+var (
+	ErrGone          = errors.New("push subscription expired")
+	ErrInvalidTarget = errors.New("invalid push target")
+)
+
+// StatusError is an unexpected answer of a push service or webhook receiver
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("receiver answered %d", e.Status)
+	}
+
+	return fmt.Sprintf("receiver answered %d: %s", e.Status, e.Body)
+}
+
+// Temporary reports whether a retry may succeed
+func (e *StatusError) Temporary() bool {
+	return e.Status == http.StatusRequestTimeout || e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+// WebPush sends RFC 8030 push messages, encrypted as RFC 8291 aes128gcm and authorized with RFC 8292 VAPID
+type WebPush struct {
+	client     *http.Client
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	ttl        time.Duration
+}
+
+// NewWebPush takes the VAPID key pair base64url encoded, the private key as its 32 byte scalar
+func NewWebPush(client *http.Client, publicKey, privateKey, subject string, ttl time.Duration) (*WebPush, error) {
+	scalar, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(scalar)
+	if err != nil {
+		return nil, err
+	}
+
+	public := key.PublicKey().Bytes()
+	if publicKey != base64.RawURLEncoding.EncodeToString(public) {
+		return nil, errors.New("the VAPID public key does not match the private key")
+	}
+
+	curve := elliptic.P256()
+	signingKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(scalar),
+	}
+
+	return &WebPush{
+		client:     client,
+		privateKey: signingKey,
+		publicKey:  publicKey,
+		subject:    subject,
+		ttl:        ttl,
+	}, nil
+}
+
+// Send encrypts the payload for the subscription and posts it to the push service
+func (w *WebPush) Send(ctx context.Context, endpoint string, keys entity.PushKeys, payload []byte) error {
+	body, err := encrypt(payload, keys)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := w.authorization(endpoint)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", strconv.Itoa(int(w.ttl.Seconds())))
+	request.Header.Set("Urgency", "normal")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone {
+		return ErrGone
+	}
+
+	return checkStatus(response)
+}
+
+// authorization signs the VAPID token for the origin of the push service
+func (w *WebPush) authorization(endpoint string) (string, error) {
+	origin, err := url.Parse(endpoint)
+	if err != nil || origin.Host == "" {
+		return "", fmt.Errorf("%w: malformed endpoint", ErrInvalidTarget)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": origin.Scheme + "://" + origin.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": w.subject,
+	})
+
+	signed, err := token.SignedString(w.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return "vapid t=" + signed + ", k=" + w.publicKey, nil
+}
+
+// encrypt builds the aes128gcm body: the header with a random salt and the ephemeral public key,
+// then the payload encrypted with keys derived from the ECDH secret and the subscription auth secret
+func encrypt(payload []byte, keys entity.PushKeys) ([]byte, error) {
+	if len(payload) > maxPayload {
+		return nil, fmt.Errorf("%w: payload of %d bytes is too large", ErrInvalidTarget, len(payload))
+	}
+
+	if !ValidKeys(keys) {
+		return nil, fmt.Errorf("%w: malformed subscription keys", ErrInvalidTarget)
+	}
+
+	clientPublic, _ := decodeKey(keys.P256dh)
+	authSecret, _ := decodeKey(keys.Auth)
+
+	curve := ecdh.P256()
+
+	clientKey, err := curve.NewPublicKey(clientPublic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+
+	serverKey, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := serverKey.ECDH(clientKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+
+	serverPublic := serverKey.PublicKey().Bytes()
+
+	keyInfo := append([]byte("WebPush: info\x00"), clientPublic...)
+	keyInfo = append(keyInfo, serverPublic...)
+
+	ikm, err := derive(secret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	contentKey, err := derive(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := derive(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, 16+4+1+len(serverPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	plaintext := append(append([]byte{}, payload...), 2)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func derive(secret, salt, info []byte, size int) ([]byte, error) {
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// decodeKey decodes base64url keys, browsers may report them padded
+func decodeKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
+
+// ValidKeys checks the subscription keys are an uncompressed P-256 point and a 16 byte auth secret
+func ValidKeys(keys entity.PushKeys) bool {
+	public, err := decodeKey(keys.P256dh)
+	if err != nil || len(public) != 65 || public[0] != 4 {
+		return false
+	}
+
+	auth, err := decodeKey(keys.Auth)
+
+	return err == nil && len(auth) == 16
+}
+
+// ValidURL checks a push endpoint or webhook URL, plain http is only accepted when allowHTTP is set
+// and loopback, private and link-local hosts only when allowPrivate is
+func ValidURL(raw string, allowHTTP, allowPrivate bool) bool {
+	target, err := url.Parse(raw)
+	if err != nil || target.Host == "" || target.User != nil {
+		return false
+	}
+
+	if !allowPrivate && !publicHost(target.Hostname()) {
+		return false
+	}
+
+	return target.Scheme == "https" || (allowHTTP && target.Scheme == "http")
+}
+
+// checkStatus turns an answer other than 2xx into a StatusError
+func checkStatus(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+	return &StatusError{
+		Status: response.StatusCode,
+		Body:   strings.TrimSpace(string(body)),
+	}
+}
@@ -0,0 +1,143 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) *ecdh.PrivateKey {
+	t.Helper()
+
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating a key: %v", err)
+	}
+
+	return key
+}
+
+func testWebPush(t *testing.T, client *http.Client) (*WebPush, string) {
+	t.Helper()
+
+	key := testKey(t)
+	publicKey := base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+
+	webPush, err := NewWebPush(client, publicKey, base64.RawURLEncoding.EncodeToString(key.Bytes()), "mailto:admin@localhost", time.Hour)
+	if err != nil {
+		t.Fatalf("NewWebPush: %v", err)
+	}
+
+	return webPush, publicKey
+}
+
+func testSubscription(t *testing.T) entity.PushKeys {
+	t.Helper()
+
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		t.Fatalf("generating the auth secret: %v", err)
+	}
+
+	return entity.PushKeys{
+		P256dh: base64.RawURLEncoding.EncodeToString(testKey(t).PublicKey().Bytes()),
+		Auth:   base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+func TestNewWebPushRejectsMismatchedKeys(t *testing.T) {
+	publicKey := base64.RawURLEncoding.EncodeToString(testKey(t).PublicKey().Bytes())
+	privateKey := base64.RawURLEncoding.EncodeToString(testKey(t).Bytes())
+
+	if _, err := NewWebPush(http.DefaultClient, publicKey, privateKey, "mailto:admin@localhost", time.Hour); err == nil {
+		t.Fatal("a public key of another pair was accepted")
+	}
+}
+
+func TestWebPushSendEncryptsAndAuthorizes(t *testing.T) {
+	var publicKey string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Encoding"); got != "aes128gcm" {
+			t.Errorf("Content-Encoding = %q, want aes128gcm", got)
+		}
+
+		if got := r.Header.Get("TTL"); got != "3600" {
+			t.Errorf("TTL = %q, want 3600", got)
+		}
+
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "vapid t=") || !strings.HasSuffix(authorization, ", k="+publicKey) {
+			t.Errorf("Authorization = %q, want a vapid token with the public key", authorization)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading the body: %v", err)
+		}
+
+		// the aes128gcm header is the salt, the record size, the key id length and the key id
+		if len(body) < 86 || binary.BigEndian.Uint32(body[16:20]) != recordSize || body[20] != 65 {
+			t.Errorf("the body does not start with an aes128gcm header")
+		}
+
+		if strings.Contains(string(body), "hello") {
+			t.Errorf("the payload was sent in clear")
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	webPush, key := testWebPush(t, server.Client())
+	publicKey = key
+
+	if err := webPush.Send(context.Background(), server.URL+"/push/1", testSubscription(t), []byte(`{"content":"hello"}`)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+}
+
+func TestWorkerRemovesExpiredSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	webPush, _ := testWebPush(t, server.Client())
+
+	jobs := newJobRecorder()
+	worker := &Worker{
+		jobs:        jobs,
+		webPush:     webPush,
+		maxAttempts: 3,
+	}
+
+	delivery := entity.PushDelivery{
+		PushJob: entity.PushJob{
+			ID:       1,
+			Channel:  entity.PushWebPush,
+			TargetID: 9,
+			Payload:  []byte(`{}`),
+		},
+		Active:   true,
+		Endpoint: server.URL,
+		Keys:     testSubscription(t),
+	}
+
+	if err := worker.deliver(context.Background(), delivery); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	if len(jobs.removed) != 1 || jobs.removed[0] != 9 || jobs.outcomes[1] != "failed" {
+		t.Fatalf("removed %v and job 1 is %s, want subscription 9 removed and the job failed", jobs.removed, jobs.outcomes[1])
+	}
+}
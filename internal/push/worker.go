@@ -0,0 +1,214 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	"archv1/internal/service/push"
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Worker delivers the queued push jobs. Failed deliveries are retried with an exponential backoff
+// until the attempts run out, then the job is kept as a dead letter.
+type Worker struct {
+	jobs        push.PushServiceI
+	webPush     *WebPush
+	webhook     *Webhook
+	interval    time.Duration
+	lease       time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewWorker(jobs push.PushServiceI, cfg *config.Config) (*Worker, error) {
+	timeout, err := time.ParseDuration(cfg.PushTimeout)
+	if err != nil {
+		timeout = 10 * time.Second
+	}
+
+	interval, err := time.ParseDuration(cfg.PushPollInterval)
+	if err != nil {
+		interval = 5 * time.Second
+	}
+
+	ttl, err := time.ParseDuration(cfg.PushTTL)
+	if err != nil {
+		ttl = 24 * time.Hour
+	}
+
+	worker := &Worker{
+		jobs:        jobs,
+		webhook:     NewWebhook(NewClient(timeout, cfg.PushAllowPrivate)),
+		interval:    interval,
+		lease:       2*timeout + time.Minute,
+		batchSize:   cfg.PushBatchSize,
+		maxAttempts: cfg.PushMaxAttempts,
+	}
+
+	if worker.batchSize <= 0 {
+		worker.batchSize = 50
+	}
+
+	if worker.maxAttempts <= 0 {
+		worker.maxAttempts = 6
+	}
+
+	if cfg.VAPIDPrivateKey != "" {
+		worker.webPush, err = NewWebPush(NewClient(timeout, cfg.PushAllowPrivate), cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey, cfg.VAPIDSubject, ttl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return worker, nil
+}
+
+// Run polls the due jobs until the context ends
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		for w.deliverBatch(ctx) == w.batchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch claims a batch of due jobs and delivers them concurrently, it returns the size of the batch
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	deliveries, err := w.jobs.ClaimJobs(ctx, w.batchSize, w.lease)
+	if err != nil {
+		log.Println(err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery entity.PushDelivery) {
+			defer wg.Done()
+
+			if err := w.deliver(ctx, delivery); err != nil {
+				log.Println(err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery entity.PushDelivery) error {
+	if !delivery.Active {
+		return w.jobs.FailJob(ctx, delivery.ID, "the "+delivery.Channel+" target was removed")
+	}
+
+	if delay := quietDelay(delivery.QuietHours, time.Now()); delay > 0 {
+		return w.jobs.DeferJob(ctx, delivery.ID, delay)
+	}
+
+	err := w.send(ctx, delivery)
+	switch {
+	case err == nil:
+		return w.jobs.CompleteJob(ctx, delivery.ID)
+	case errors.Is(err, ErrGone):
+		if err := w.jobs.RemoveSubscription(ctx, int64(delivery.TargetID)); err != nil {
+			return err
+		}
+
+		return w.jobs.FailJob(ctx, delivery.ID, err.Error())
+	case !retryable(err) || delivery.Attempts+1 >= w.maxAttempts:
+		return w.jobs.FailJob(ctx, delivery.ID, err.Error())
+	default:
+		return w.jobs.RetryJob(ctx, delivery.ID, backoff(delivery.Attempts+1), err.Error())
+	}
+}
+
+func (w *Worker) send(ctx context.Context, delivery entity.PushDelivery) error {
+	if delivery.Channel == entity.PushWebhook {
+		return w.webhook.Send(ctx, delivery.WebhookURL, delivery.WebhookSecret, delivery.ID, delivery.Payload)
+	}
+
+	if w.webPush == nil {
+		return errors.New("web push is not configured")
+	}
+
+	return w.webPush.Send(ctx, delivery.Endpoint, delivery.Keys, delivery.Payload)
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, ErrInvalidTarget) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+
+	return true
+}
+
+// backoff doubles the delay after every failed attempt from 30 seconds up to an hour, with a little jitter
+// so the retries of a receiver which was down do not all arrive at once
+func backoff(attempt int) time.Duration {
+	delay := time.Hour
+	if attempt < 8 {
+		delay = 30 * time.Second << (attempt - 1)
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(delay/5)))
+}
+
+// quietDelay returns how long a delivery waits for the end of the quiet hours, 0 outside of them
+func quietDelay(quietHours *entity.QuietHours, now time.Time) time.Duration {
+	if quietHours == nil || !quietHours.Enabled {
+		return 0
+	}
+
+	start, err := time.Parse("15:04", quietHours.Start)
+	if err != nil {
+		return 0
+	}
+
+	end, err := time.Parse("15:04", quietHours.End)
+	if err != nil {
+		return 0
+	}
+
+	location, err := time.LoadLocation(quietHours.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	clock := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+
+	from, to, current := clock(start), clock(end), clock(local)
+
+	quiet := from <= current && current < to
+	if from > to {
+		quiet = current >= from || current < to
+	}
+
+	if from == to || !quiet {
+		return 0
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, location)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+
+	return until.Sub(local)
+}
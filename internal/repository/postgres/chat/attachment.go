@@ -0,0 +1,177 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+)
+
+// CreateChatFile stores the metadata of a file uploaded to the chat and adds it to the storage usage of the uploader
+func (ch *RepoChat) CreateChatFile(ctx context.Context, file entity.ChatFile) (entity.ChatFile, error) {
+	query := `
+	WITH created AS (
+		INSERT INTO files (type, link, name, size, mime_type, width, height, chat_id, created_by, blob_key)
+		VALUES ('chat', ?0, ?1, ?2, ?3, ?4, ?5, ?6, ?7, NULLIF(?8, ''))
+		RETURNING id, created_by, size
+	),
+	usage AS (
+		INSERT INTO user_storage (user_id, used_bytes, file_count)
+		SELECT created_by, size, 1 FROM created
+		ON CONFLICT (user_id) DO UPDATE SET
+			used_bytes = user_storage.used_bytes + EXCLUDED.used_bytes,
+			file_count = user_storage.file_count + 1,
+			updated_at = NOW()
+	)
+	SELECT id FROM created`
+
+	err := ch.DB.QueryRowContext(ctx, query,
+		file.Link,
+		file.Name,
+		file.Size,
+		file.MimeType,
+		file.Width,
+		file.Height,
+		file.ChatID,
+		file.UploadedBy,
+		file.BlobKey,
+	).Scan(&file.FileID)
+	if err != nil {
+		return entity.ChatFile{}, err
+	}
+
+	return file, nil
+}
+
+func (ch *RepoChat) GetChatFile(ctx context.Context, fileID int64) (entity.ChatFile, error) {
+	query := `
+	SELECT id, name, size, mime_type, width, height, chat_id, link, COALESCE(blob_key, ''), created_by
+	FROM files
+	WHERE id = ?0 AND chat_id IS NOT NULL AND deleted_at IS NULL`
+
+	var (
+		width, height sql.NullInt64
+		file          entity.ChatFile
+	)
+
+	err := ch.DB.QueryRowContext(ctx, query, fileID).Scan(
+		&file.FileID,
+		&file.Name,
+		&file.Size,
+		&file.MimeType,
+		&width,
+		&height,
+		&file.ChatID,
+		&file.Link,
+		&file.BlobKey,
+		&file.UploadedBy,
+	)
+	if err != nil {
+		return entity.ChatFile{}, err
+	}
+
+	file.Width, file.Height = nullInt(width), nullInt(height)
+
+	return file, nil
+}
+
+func addAttachments(ctx context.Context, tx bun.Tx, messageID int, fileIDs []int) error {
+	query := `INSERT INTO message_attachments (message_id, file_id, position) VALUES (?0, ?1, ?2)`
+
+	for position, fileID := range fileIDs {
+		if _, err := tx.ExecContext(ctx, query, messageID, fileID, position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// messageAttachments returns the attachments of the messages in the order they were sent
+func (ch *RepoChat) messageAttachments(ctx context.Context, db bun.IDB, messageIDs []int) (map[int][]entity.Attachment, error) {
+	attachments := make(map[int][]entity.Attachment)
+	if len(messageIDs) == 0 {
+		return attachments, nil
+	}
+
+	query := `
+	SELECT a.message_id, f.id, f.name, f.size, f.mime_type, f.width, f.height
+	FROM message_attachments AS a
+	INNER JOIN files AS f ON f.id = a.file_id
+	WHERE a.message_id IN (?0)
+	ORDER BY a.message_id, a.position`
+
+	rows, err := db.QueryContext(ctx, query, bun.In(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	for rows.Next() {
+		var (
+			messageID     int
+			width, height sql.NullInt64
+			attachment    entity.Attachment
+		)
+
+		err := rows.Scan(
+			&messageID,
+			&attachment.FileID,
+			&attachment.Name,
+			&attachment.Size,
+			&attachment.MimeType,
+			&width,
+			&height,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attachment.Width, attachment.Height = nullInt(width), nullInt(height)
+
+		attachments[messageID] = append(attachments[messageID], attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// withAttachments fills in the attachments of the listed messages which are not deleted
+func (ch *RepoChat) withAttachments(ctx context.Context, messages []entity.ChatMessage) error {
+	messageIDs := make([]int, 0, len(messages))
+	for _, message := range messages {
+		if !message.Deleted {
+			messageIDs = append(messageIDs, message.MessageID)
+		}
+	}
+
+	attachments, err := ch.messageAttachments(ctx, ch.DB, messageIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Attachments = attachments[messages[i].MessageID]
+	}
+
+	return nil
+}
+
+func nullInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	result := int(value.Int64)
+
+	return &result
+}
@@ -5,7 +5,10 @@ import (
 	"archv1/internal/pkg/repo/postgres"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/uptrace/bun"
+	"math"
 	"time"
 )
 
@@ -25,7 +28,9 @@ func (ch *RepoChat) UserGroups(ctx context.Context, userID int64) ([]entity.GetG
 		g.id,
 		g.name,
 		g.username,
-		g.description
+		g.description,
+		g.visibility,
+		g.slow_mode
 	FROM
 	    group_users AS gu
 	INNER JOIN
@@ -60,6 +65,8 @@ func (ch *RepoChat) UserGroups(ctx context.Context, userID int64) ([]entity.GetG
 			&group.Name,
 			&group.Username,
 			&nullDescription,
+			&group.Visibility,
+			&group.SlowMode,
 		)
 
 		if err != nil {
@@ -142,7 +149,9 @@ func (ch *RepoChat) GetGroup(ctx context.Context, groupID int64) (entity.GetGrou
 		id,
 		name,
 		username,
-		description
+		description,
+		visibility,
+		slow_mode
 	FROM
 	    groups
 	WHERE
@@ -158,6 +167,8 @@ func (ch *RepoChat) GetGroup(ctx context.Context, groupID int64) (entity.GetGrou
 		&result.Name,
 		&result.Username,
 		&nullDescription,
+		&result.Visibility,
+		&result.SlowMode,
 	)
 	if err != nil {
 		return entity.GetGroupResponse{}, err
@@ -182,10 +193,11 @@ func (ch *RepoChat) CreateGroup(ctx context.Context, group entity.CreateGroupReq
 				Name:        group.Name,
 				Username:    group.Username,
 				Description: &group.Description,
+				Visibility:  group.Visibility,
 				CreatedBy:   group.CreatedBy,
 			}).
-		Returning("id, name, username, description").
-		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription)
+		Returning("id, name, username, description, visibility").
+		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription, &response.Visibility)
 
 	if err != nil {
 		return entity.CreateGroupResponse{}, err
@@ -195,6 +207,10 @@ func (ch *RepoChat) CreateGroup(ctx context.Context, group entity.CreateGroupReq
 		response.Description = nullDescription.String
 	}
 
+	if err := ch.addOwner(ctx, int64(group.CreatedBy), int64(response.GroupID)); err != nil {
+		return entity.CreateGroupResponse{}, err
+	}
+
 	return response, nil
 }
 
@@ -208,11 +224,12 @@ func (ch *RepoChat) UpdateGroup(ctx context.Context, group entity.UpdateGroupReq
 		Set("name = ?", group.Name).
 		Set("description = ?", group.Description).
 		Set("username = ?", group.Username).
+		Set("visibility = ?", group.Visibility).
 		Set("updated_at = ?", time.Now()).
 		Set("updated_by = ?", group.UpdatedBy).
 		Where("deleted_at IS NULL AND id = ?", group.GroupID).
-		Returning("id, name, username, description").
-		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription)
+		Returning("id, name, username, description, visibility").
+		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription, &response.Visibility)
 
 	if err != nil {
 		return entity.UpdateGroupResponse{}, err
@@ -240,14 +257,16 @@ func (ch *RepoChat) UpdateGroupColumns(ctx context.Context, fields entity.Update
 			updater.Set(key+" = ?", value)
 		} else if key == "description" {
 			updater.Set(key+" = ?", value)
+		} else if key == "visibility" {
+			updater.Set(key+" = ?", value)
 		} else if key == "updated_by" {
 			updater.Set(key+" = ?", value)
 		}
 	}
 
 	err := updater.Where("deleted_at IS NULL AND id = ?", fields.GroupID).
-		Returning("id, name, username, description").
-		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription)
+		Returning("id, name, username, description, visibility").
+		Scan(ctx, &response.GroupID, &response.Name, &response.Username, &nullDescription, &response.Visibility)
 
 	if err != nil {
 		return entity.UpdateGroupResponse{}, err
@@ -286,56 +305,47 @@ func (ch *RepoChat) DeleteGroup(ctx context.Context, groupID, deletedBy int64) (
 	}, nil
 }
 
+// AddUserToGroup adds the user to the group as a member, restoring a previously removed membership
 func (ch *RepoChat) AddUserToGroup(ctx context.Context, userID, groupID int64) error {
-	checkQuery := fmt.Sprintf(`SELECT COUNT(*) FROM group_users WHERE group_id = '%d' AND user_id = '%d'`, groupID, userID)
+	checkQuery := `SELECT COUNT(*) FROM group_users WHERE group_id = ?0 AND user_id = ?1`
 
 	var count int
-	if err := ch.DB.QueryRowContext(ctx, checkQuery).Scan(&count); err != nil {
+	if err := ch.DB.QueryRowContext(ctx, checkQuery, groupID, userID).Scan(&count); err != nil {
 		return err
 	}
 
-	if count == 0 {
-		query := fmt.Sprintf(`INSERT INTO group_users (group_id, user_id) VALUES ('%d', '%d')`, groupID, userID)
+	if count != 0 {
+		// active members keep their role
+		query := `UPDATE group_users SET deleted_at = NULL, role = 'member', updated_at = NOW() WHERE group_id = ?0 AND user_id = ?1 AND deleted_at IS NOT NULL`
 
-		result, err := ch.DB.ExecContext(ctx, query)
-		if err != nil {
-			return err
-		}
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return err
-		}
+		_, err := ch.DB.ExecContext(ctx, query, groupID, userID)
 
-		if rows == 0 {
-			return sql.ErrNoRows
-		}
+		return err
+	}
 
-		return nil
-	} else {
-		query := fmt.Sprintf(`UPDATE group_users SET deleted_at = NULL WHERE group_id = '%d' AND user_id = '%d'`, groupID, userID)
+	query := `INSERT INTO group_users (group_id, user_id, role) VALUES (?0, ?1, 'member')`
 
-		result, err := ch.DB.ExecContext(ctx, query)
-		if err != nil {
-			return err
-		}
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return err
-		}
+	result, err := ch.DB.ExecContext(ctx, query, groupID, userID)
+	if err != nil {
+		return err
+	}
 
-		if rows == 0 {
-			return sql.ErrNoRows
-		}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
 
-		return nil
+	if rows == 0 {
+		return sql.ErrNoRows
 	}
 
+	return nil
 }
 
 func (ch *RepoChat) RemoveUserFromGroup(ctx context.Context, userID, groupID int64) error {
-	query := fmt.Sprintf(`UPDATE group_users  SET deleted_at = NOW() WHERE group_id = '%d' AND user_id = '%d' AND deleted_at IS NULL`, groupID, userID)
+	query := `UPDATE group_users SET deleted_at = NOW() WHERE group_id = ?0 AND user_id = ?1 AND deleted_at IS NULL`
 
-	result, err := ch.DB.ExecContext(ctx, query)
+	result, err := ch.DB.ExecContext(ctx, query, groupID, userID)
 	if err != nil {
 		return err
 	}
@@ -352,10 +362,10 @@ func (ch *RepoChat) RemoveUserFromGroup(ctx context.Context, userID, groupID int
 	return nil
 }
 
-func (ch *RepoChat) addAdmin(ctx context.Context, adminID, groupID int64) error {
-	query := fmt.Sprintf(`INSERT INTO group_users (group_id, user_id) VALUES ('%d', '%d')`, groupID, adminID)
+func (ch *RepoChat) addOwner(ctx context.Context, ownerID, groupID int64) error {
+	query := `INSERT INTO group_users (group_id, user_id, role, created_by) VALUES (?0, ?1, 'owner', ?1)`
 
-	result, err := ch.DB.ExecContext(ctx, query)
+	result, err := ch.DB.ExecContext(ctx, query, groupID, ownerID)
 	if err != nil {
 		return err
 	}
@@ -372,31 +382,181 @@ func (ch *RepoChat) addAdmin(ctx context.Context, adminID, groupID int64) error
 	return nil
 }
 
-func (ch *RepoChat) CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error) {
-	query := fmt.Sprintf(`INSERT INTO chat (receiver_id, chat_type) VALUES ('%d', '%s') RETURNING id, receiver_id, chat_type`, receiverID, chatType)
-
-	var response entity.CreatedChatResponse
-	err := ch.DB.QueryRowContext(ctx, query).Scan(
-		&response.ChatId,
-		&response.ReceiverID,
-		&response.ChatType,
+// GroupMember returns the membership of the user in the group or sql.ErrNoRows when the user is not in it
+func (ch *RepoChat) GroupMember(ctx context.Context, groupID, userID int64) (entity.GroupMember, error) {
+	query := `
+	SELECT gu.group_id, gu.user_id, u.username, gu.role, gu.created_at
+	FROM group_users AS gu
+	INNER JOIN groups AS g ON g.id = gu.group_id
+	INNER JOIN users AS u ON u.id = gu.user_id
+	WHERE gu.deleted_at IS NULL AND g.deleted_at IS NULL AND gu.group_id = ?0 AND gu.user_id = ?1`
+
+	var member entity.GroupMember
+
+	err := ch.DB.QueryRowContext(ctx, query, groupID, userID).Scan(
+		&member.GroupID,
+		&member.UserID,
+		&member.Username,
+		&member.Role,
+		&member.JoinedAt,
 	)
 	if err != nil {
-		return entity.CreatedChatResponse{}, err
+		return entity.GroupMember{}, err
 	}
 
-	if chatType == "group" {
-		err := ch.addAdmin(ctx, creator, int64(response.ChatId))
+	return member, nil
+}
+
+func (ch *RepoChat) GroupMembers(ctx context.Context, groupID int64) ([]entity.GroupMember, error) {
+	query := `
+	SELECT gu.group_id, gu.user_id, u.username, gu.role, gu.created_at
+	FROM group_users AS gu
+	INNER JOIN users AS u ON u.id = gu.user_id
+	WHERE gu.deleted_at IS NULL AND u.deleted_at IS NULL AND gu.group_id = ?0
+	ORDER BY CASE gu.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 WHEN 'moderator' THEN 2 ELSE 3 END, gu.created_at`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var response []entity.GroupMember
+	for rows.Next() {
+		var member entity.GroupMember
+
+		err = rows.Scan(
+			&member.GroupID,
+			&member.UserID,
+			&member.Username,
+			&member.Role,
+			&member.JoinedAt,
+		)
 		if err != nil {
-			return entity.CreatedChatResponse{}, err
+			return nil, err
 		}
+
+		response = append(response, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (ch *RepoChat) SetGroupRole(ctx context.Context, groupID, userID int64, role string) error {
+	query := `UPDATE group_users SET role = ?0, updated_at = NOW() WHERE group_id = ?1 AND user_id = ?2 AND deleted_at IS NULL`
+
+	result, err := ch.DB.ExecContext(ctx, query, role, groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// TransferGroupOwnership makes the user the owner of the group and the previous owner an admin
+func (ch *RepoChat) TransferGroupOwnership(ctx context.Context, groupID, ownerID, userID int64) error {
+	return ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, change := range []struct {
+			userID int64
+			role   string
+		}{
+			{ownerID, entity.GroupRoleAdmin},
+			{userID, entity.GroupRoleOwner},
+		} {
+			query := `UPDATE group_users SET role = ?0, updated_at = NOW() WHERE group_id = ?1 AND user_id = ?2 AND deleted_at IS NULL`
+
+			result, err := tx.ExecContext(ctx, query, change.role, groupID, change.userID)
+			if err != nil {
+				return err
+			}
+
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			if rows == 0 {
+				return sql.ErrNoRows
+			}
+		}
+
+		return nil
+	})
+}
+
+// CreateChat returns the private chat between creator and receiverID or the chat of the receiverID group,
+// creating it on the first call. Both users of a private chat are recorded as its participants.
+func (ch *RepoChat) CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error) {
+	directKey := fmt.Sprintf("group:%d", receiverID)
+	if chatType == "private" {
+		directKey = fmt.Sprintf("private:%d:%d", min(creator, receiverID), max(creator, receiverID))
+	}
+
+	var response entity.CreatedChatResponse
+
+	err := ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		insertQuery := `
+		INSERT INTO chat (chat_type, receiver_id, created_by, direct_key) VALUES (?0, ?1, ?2, ?3)
+		ON CONFLICT (direct_key) WHERE deleted_at IS NULL DO NOTHING
+		RETURNING id, receiver_id, chat_type`
+
+		err := tx.QueryRowContext(ctx, insertQuery, chatType, receiverID, creator, directKey).Scan(
+			&response.ChatId,
+			&response.ReceiverID,
+			&response.ChatType,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			selectQuery := `SELECT id, receiver_id, chat_type FROM chat WHERE direct_key = ?0 AND deleted_at IS NULL`
+
+			err = tx.QueryRowContext(ctx, selectQuery, directKey).Scan(
+				&response.ChatId,
+				&response.ReceiverID,
+				&response.ChatType,
+			)
+		}
+		if err != nil {
+			return err
+		}
+
+		if chatType != "private" {
+			return nil
+		}
+
+		participantsQuery := `
+		INSERT INTO chat_participants (chat_id, user_id) VALUES (?0, ?1), (?0, ?2)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET deleted_at = NULL`
+
+		_, err = tx.ExecContext(ctx, participantsQuery, response.ChatId, creator, receiverID)
+
+		return err
+	})
+	if err != nil {
+		return entity.CreatedChatResponse{}, err
 	}
 
 	return response, nil
 }
 
 func (ch *RepoChat) DeleteChat(ctx context.Context, chatID int64) error {
-	query := `UPDATE chat SET deleted_at = NOW() WHERE id = $1`
+	query := `UPDATE chat SET deleted_at = NOW() WHERE id = ?0`
 
 	result, err := ch.DB.ExecContext(ctx, query, chatID)
 	if err != nil {
@@ -415,8 +575,67 @@ func (ch *RepoChat) DeleteChat(ctx context.Context, chatID int64) error {
 	return nil
 }
 
+// participantsQuery selects chat_id and user_id of every participant of active chats:
+// the users recorded for private chats and the users of the group for group chats
+const participantsQuery = `
+	SELECT p.chat_id, p.user_id
+	FROM chat_participants AS p
+	INNER JOIN chat AS c ON c.id = p.chat_id
+	WHERE p.deleted_at IS NULL AND c.deleted_at IS NULL AND c.chat_type = 'private'
+	UNION
+	SELECT c.id, gu.user_id
+	FROM chat AS c
+	INNER JOIN group_users AS gu ON gu.group_id = c.receiver_id AND gu.deleted_at IS NULL
+	WHERE c.deleted_at IS NULL AND c.chat_type = 'group'`
+
+// UserChats returns every chat the user participates in, the most recently active first
 func (ch *RepoChat) UserChats(ctx context.Context, userID int64) (entity.UserChatsResponse, error) {
-	query := `SELECT id, chat_type, receiver_id FROM chat WHERE receiver_id = $1 AND deleted_at IS NULL`
+	query := fmt.Sprintf(`
+	WITH participants AS (%[1]s)
+	SELECT
+		c.id,
+		c.chat_type,
+		c.receiver_id,
+		peer.id,
+		peer.username,
+		g.name,
+		latest.id,
+		latest.content,
+		latest.sender,
+		latest.message_type,
+		latest.created_at,
+		latest.updated_at,
+		COALESCE(cm.last_read_message_id, 0),
+		(
+			SELECT COUNT(*) FROM messages AS m
+			WHERE m.chat_id = c.id AND m.deleted_at IS NULL AND m.sender <> ?0
+			AND m.id > COALESCE(cm.last_read_message_id, 0) AND %[2]s
+		)
+	FROM
+		participants AS me
+	INNER JOIN
+		chat AS c ON c.id = me.chat_id
+	LEFT JOIN LATERAL (
+		SELECT u.id, u.username FROM participants AS other
+		INNER JOIN users AS u ON u.id = other.user_id
+		WHERE c.chat_type = 'private' AND other.chat_id = c.id AND other.user_id <> ?0
+		LIMIT 1
+	) AS peer ON TRUE
+	LEFT JOIN
+		groups AS g ON c.chat_type = 'group' AND g.id = c.receiver_id
+	LEFT JOIN LATERAL (
+		SELECT m.id, m.content, m.sender, m.message_type, m.created_at, m.updated_at FROM messages AS m
+		WHERE m.chat_id = c.id AND m.deleted_at IS NULL AND %[2]s
+		ORDER BY m.id DESC
+		LIMIT 1
+	) AS latest ON TRUE
+	LEFT JOIN
+		chat_members AS cm ON cm.chat_id = c.id AND cm.user_id = ?0
+	WHERE
+		me.user_id = ?0
+	ORDER BY
+		COALESCE(latest.created_at, c.created_at) DESC
+	`, participantsQuery, notHidden(0))
 
 	var response entity.UserChatsResponse
 
@@ -432,21 +651,66 @@ func (ch *RepoChat) UserChats(ctx context.Context, userID int64) (entity.UserCha
 	}(rows)
 
 	for rows.Next() {
-		var chat struct {
-			ChatID     int    `json:"chat_id"`
-			ChatType   string `json:"chat_type"`
-			ReceiverID int    `json:"receiver_id"`
-		}
+		var (
+			peerID        sql.NullInt64
+			peerUsername  sql.NullString
+			groupName     sql.NullString
+			lastID        sql.NullInt64
+			lastContent   sql.NullString
+			lastSender    sql.NullInt64
+			lastType      sql.NullString
+			lastCreatedAt sql.NullTime
+			lastUpdatedAt sql.NullTime
+			chat          entity.UserChat
+		)
 
 		err = rows.Scan(
 			&chat.ChatID,
 			&chat.ChatType,
 			&chat.ReceiverID,
+			&peerID,
+			&peerUsername,
+			&groupName,
+			&lastID,
+			&lastContent,
+			&lastSender,
+			&lastType,
+			&lastCreatedAt,
+			&lastUpdatedAt,
+			&chat.LastReadMessageID,
+			&chat.UnreadCount,
 		)
 		if err != nil {
 			return entity.UserChatsResponse{}, err
 		}
 
+		if peerID.Valid {
+			chat.Counterpart = &entity.ChatPeer{
+				ID:       int(peerID.Int64),
+				Username: peerUsername.String,
+			}
+			chat.Title = peerUsername.String
+		}
+
+		if groupName.Valid {
+			chat.Title = groupName.String
+		}
+
+		if lastID.Valid {
+			chat.LastMessage = &entity.ChatMessage{
+				MessageID:   int(lastID.Int64),
+				ChatID:      chat.ChatID,
+				Sender:      int(lastSender.Int64),
+				Message:     lastContent.String,
+				MessageType: lastType.String,
+				CreatedAt:   lastCreatedAt.Time,
+			}
+
+			if lastUpdatedAt.Valid {
+				chat.LastMessage.UpdatedAt = &lastUpdatedAt.Time
+			}
+		}
+
 		response.Chats = append(response.Chats, chat)
 	}
 
@@ -457,61 +721,161 @@ func (ch *RepoChat) UserChats(ctx context.Context, userID int64) (entity.UserCha
 	return response, nil
 }
 
-func (ch *RepoChat) SendMessage(ctx context.Context, message entity.SendMessageRequest) error {
+// IsParticipant reports whether the user participates in the chat
+func (ch *RepoChat) IsParticipant(ctx context.Context, chatID, userID int64) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM (%s) AS p WHERE p.chat_id = ?0 AND p.user_id = ?1)`, participantsQuery)
+
+	var exists bool
+	if err := ch.DB.QueryRowContext(ctx, query, chatID, userID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (ch *RepoChat) ChatParticipants(ctx context.Context, chatID int64) ([]entity.GetUserResponse, error) {
 	query := fmt.Sprintf(`
-	INSERT INTO messages (chat_id, content, message_type, sender) VALUES ('%d', '%s', '%s', '%d')`,
-		message.ChatID,
-		message.Message,
-		message.MessageType,
-		message.Sender,
-	)
+	SELECT u.id, u.username, u.role, u.status
+	FROM (%s) AS p
+	INNER JOIN users AS u ON u.id = p.user_id
+	WHERE p.chat_id = ?0 AND u.deleted_at IS NULL
+	ORDER BY u.id`, participantsQuery)
 
-	result, err := ch.DB.ExecContext(ctx, query)
+	rows, err := ch.DB.QueryContext(ctx, query, chatID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	var response []entity.GetUserResponse
+	for rows.Next() {
+		var user entity.GetUserResponse
+
+		err = rows.Scan(
+			&user.Id,
+			&user.Username,
+			&user.Role,
+			&user.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, user)
 	}
 
-	if rows == 0 {
-		return sql.ErrNoRows
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return response, nil
 }
 
-func (ch *RepoChat) UpdateMessage(ctx context.Context, message entity.UpdateMessageRequest) error {
-	query := fmt.Sprintf(`
-	UPDATE messages SET  chat_id = '%d', content = '%s' WHERE id = '%d' AND deleted_at IS NULL`,
-		message.ChatID,
-		message.NewMessage,
-		message.MessageID,
+// SendMessage stores the message together with its attachments
+func (ch *RepoChat) SendMessage(ctx context.Context, message entity.SendMessageRequest) (entity.Message, error) {
+	query := `
+	INSERT INTO messages (chat_id, content, message_type, sender, reply_to_id, forwarded_from_user, forwarded_from_message)
+	VALUES (?0, ?1, ?2, ?3, ?4, ?5, ?6)
+	RETURNING id, chat_id, content, message_type, sender, created_at`
+
+	var (
+		forwardedUser    sql.NullInt64
+		forwardedMessage sql.NullInt64
+		response         entity.Message
 	)
 
-	result, err := ch.DB.ExecContext(ctx, query)
-	if err != nil {
-		return err
+	if message.ForwardedFrom != nil {
+		forwardedUser = nullID(message.ForwardedFrom.UserID)
+		forwardedMessage = nullID(message.ForwardedFrom.MessageID)
+		response.ForwardedFrom = message.ForwardedFrom
 	}
 
-	rows, err := result.RowsAffected()
+	err := ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.QueryRowContext(ctx, query,
+			message.ChatID,
+			message.Message,
+			message.MessageType,
+			message.Sender,
+			nullID(message.ReplyTo),
+			forwardedUser,
+			forwardedMessage,
+		).Scan(&response.ID, &response.ChatId, &response.Content, &response.MessageType, &response.Sender, &response.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		if message.ReplyTo != 0 {
+			previews, err := messagePreviews(ctx, tx, []int{message.ReplyTo})
+			if err != nil {
+				return err
+			}
+
+			if preview, ok := previews[message.ReplyTo]; ok {
+				response.ReplyTo = &preview
+			}
+		}
+
+		if err := addAttachments(ctx, tx, response.ID, message.Attachments); err != nil {
+			return err
+		}
+
+		attachments, err := ch.messageAttachments(ctx, tx, []int{response.ID})
+		if err != nil {
+			return err
+		}
+
+		response.Attachments = attachments[response.ID]
+
+		return nil
+	})
 	if err != nil {
-		return err
+		return entity.Message{}, err
 	}
 
-	if rows == 0 {
-		return sql.ErrNoRows
-	}
+	return response, nil
+}
 
-	return nil
+// UpdateMessage replaces the content of the message and keeps the previous content in its edit history
+func (ch *RepoChat) UpdateMessage(ctx context.Context, message entity.UpdateMessageRequest) error {
+	history := `
+	INSERT INTO message_edits (message_id, content, edited_by)
+	SELECT id, content, ?1 FROM messages WHERE id = ?0 AND deleted_at IS NULL`
+
+	query := `UPDATE messages SET content = ?0, updated_at = NOW(), updated_by = ?1 WHERE id = ?2 AND deleted_at IS NULL`
+
+	return ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, history, message.MessageID, message.Sender); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, query, message.NewMessage, message.Sender, message.MessageID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		return nil
+	})
 }
 
-func (ch *RepoChat) DeleteMessage(ctx context.Context, messageID int64) error {
-	query := fmt.Sprintf(`UPDATE messages SET  deleted_at = NOW() WHERE id = '%d' AND deleted_at IS NULL`, messageID)
+// DeleteMessage tombstones the message for every participant
+func (ch *RepoChat) DeleteMessage(ctx context.Context, messageID, deletedBy int64) error {
+	query := `UPDATE messages SET deleted_at = NOW(), deleted_by = ?1 WHERE id = ?0 AND deleted_at IS NULL`
 
-	result, err := ch.DB.ExecContext(ctx, query)
+	result, err := ch.DB.ExecContext(ctx, query, messageID, deletedBy)
 	if err != nil {
 		return err
 	}
@@ -528,15 +892,26 @@ func (ch *RepoChat) DeleteMessage(ctx context.Context, messageID int64) error {
 	return nil
 }
 
-func (ch *RepoChat) GetChatMessages(ctx context.Context, chatID int64) (entity.ChatMessagesResponse, error) {
-	query := fmt.Sprintf(
-		`SELECT id, chat_id, content, sender, message_type FROM messages WHERE chat_id = '%d' AND deleted_at IS NULL;`,
-		chatID)
+// HideMessage deletes the message for the user only
+func (ch *RepoChat) HideMessage(ctx context.Context, messageID, userID int64) error {
+	query := `
+	INSERT INTO hidden_messages (message_id, user_id) VALUES (?0, ?1)
+	ON CONFLICT (user_id, message_id) DO NOTHING`
 
-	rows, err := ch.DB.QueryContext(ctx, query)
+	_, err := ch.DB.ExecContext(ctx, query, messageID, userID)
+
+	return err
+}
+
+// MessageEdits returns the previous versions of the message, the oldest first
+func (ch *RepoChat) MessageEdits(ctx context.Context, messageID int64) ([]entity.MessageEdit, error) {
+	query := `SELECT content, edited_by, edited_at FROM message_edits WHERE message_id = ?0 ORDER BY id`
+
+	rows, err := ch.DB.QueryContext(ctx, query, messageID)
 	if err != nil {
-		return entity.ChatMessagesResponse{}, err
+		return nil, err
 	}
+
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
 		if err != nil {
@@ -544,37 +919,237 @@ func (ch *RepoChat) GetChatMessages(ctx context.Context, chatID int64) (entity.C
 		}
 	}(rows)
 
-	var response entity.ChatMessagesResponse
+	var edits []entity.MessageEdit
 	for rows.Next() {
-		var message struct {
-			MessageID   int    `json:"message_id"`
-			ChatID      int    `json:"chat_id"`
-			Sender      int    `json:"sender"`
-			Message     string `json:"content"`
-			MessageType string `json:"message_type"`
+		var edit entity.MessageEdit
+		if err := rows.Scan(&edit.Content, &edit.EditedBy, &edit.EditedAt); err != nil {
+			return nil, err
 		}
 
-		err = rows.Scan(
-			&message.MessageID,
-			&message.ChatID,
-			&message.Message,
-			&message.Sender,
-			&message.MessageType,
-		)
+		edits = append(edits, edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return edits, nil
+}
+
+func (ch *RepoChat) GetChatMessages(ctx context.Context, filter entity.MessageFilter) (entity.ChatMessagesResponse, error) {
+	var (
+		response entity.ChatMessagesResponse
+		err      error
+	)
+
+	switch {
+	case filter.Around != 0:
+		var older, newer []entity.ChatMessage
+
+		older, response.HasBefore, err = ch.pageMessages(ctx, filter, filter.Around, filter.Limit/2, true)
 		if err != nil {
 			return entity.ChatMessagesResponse{}, err
 		}
 
-		response.Messages = append(response.Messages, message)
+		newer, response.HasAfter, err = ch.pageMessages(ctx, filter, filter.Around-1, filter.Limit-filter.Limit/2, false)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
+
+		response.Messages = append(older, newer...)
+	case filter.After != 0:
+		response.Messages, response.HasAfter, err = ch.pageMessages(ctx, filter, filter.After, filter.Limit, false)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
+
+		response.HasBefore, err = ch.hasMessages(ctx, filter, "m.id <= ?1", filter.After)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
+	case filter.Before != 0:
+		response.Messages, response.HasBefore, err = ch.pageMessages(ctx, filter, filter.Before, filter.Limit, true)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
+
+		response.HasAfter, err = ch.hasMessages(ctx, filter, "m.id >= ?1", filter.Before)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
+	default:
+		response.Messages, response.HasBefore, err = ch.pageMessages(ctx, filter, math.MaxInt64, filter.Limit, true)
+		if err != nil {
+			return entity.ChatMessagesResponse{}, err
+		}
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := ch.fillMessages(ctx, filter.UserID, response.Messages); err != nil {
 		return entity.ChatMessagesResponse{}, err
 	}
 
 	return response, nil
 }
 
+// pageMessages returns up to limit messages of the chat older or newer than messageID in ascending order
+// and whether more messages exist beyond them. Messages deleted for everyone are returned as tombstones,
+// messages the reader deleted for themselves are left out.
+func (ch *RepoChat) pageMessages(ctx context.Context, filter entity.MessageFilter, messageID, limit int64, older bool) ([]entity.ChatMessage, bool, error) {
+	condition, order := "m.id > ?1", "ASC"
+	if older {
+		condition, order = "m.id < ?1", "DESC"
+	}
+
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM messages AS m
+	WHERE m.chat_id = ?0 AND %s AND %s
+	ORDER BY m.id %s
+	LIMIT ?2`, messageColumns, notHidden(3), condition, order)
+
+	rows, err := ch.DB.QueryContext(ctx, query, filter.ChatID, messageID, limit+1, filter.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := int64(len(messages)) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	if older {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return messages, hasMore, nil
+}
+
+func (ch *RepoChat) hasMessages(ctx context.Context, filter entity.MessageFilter, condition string, messageID int64) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM messages AS m WHERE m.chat_id = ?0 AND %s AND %s)`, notHidden(2), condition)
+
+	var exists bool
+	if err := ch.DB.QueryRowContext(ctx, query, filter.ChatID, messageID, filter.UserID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (ch *RepoChat) SearchMessages(ctx context.Context, request entity.SearchMessagesRequest) (entity.SearchMessagesResponse, error) {
+	chatQuery := `m.chat_id = ?1`
+	chatArg := request.ChatID
+
+	if request.ChatID == 0 {
+		chatQuery = fmt.Sprintf(`m.chat_id IN (SELECT p.chat_id FROM (%s) AS p WHERE p.user_id = ?1)`, participantsQuery)
+		chatArg = request.UserID
+	}
+
+	before := request.Before
+	if before == 0 {
+		before = math.MaxInt64
+	}
+
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM messages AS m
+	WHERE m.deleted_at IS NULL AND m.search_vector @@ plainto_tsquery('simple', ?0) AND m.id < ?2 AND %s AND %s
+	ORDER BY m.id DESC
+	LIMIT ?3`, messageColumns, chatQuery, notHidden(4))
+
+	rows, err := ch.DB.QueryContext(ctx, query, request.Query, chatArg, before, request.Limit, request.UserID)
+	if err != nil {
+		return entity.SearchMessagesResponse{}, err
+	}
+
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return entity.SearchMessagesResponse{}, err
+	}
+
+	if err := ch.fillMessages(ctx, request.UserID, messages); err != nil {
+		return entity.SearchMessagesResponse{}, err
+	}
+
+	return entity.SearchMessagesResponse{
+		Messages: messages,
+	}, nil
+}
+
+func scanMessages(rows *sql.Rows) ([]entity.ChatMessage, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var messages []entity.ChatMessage
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// scanMessage scans messageColumns followed by the extra destinations. The quoted reply holds
+// only its message ID until the messages are filled.
+func scanMessage(rows *sql.Rows, extra ...interface{}) (entity.ChatMessage, error) {
+	var (
+		replyTo          sql.NullInt64
+		forwardedUser    sql.NullInt64
+		forwardedMessage sql.NullInt64
+		updatedAt        sql.NullTime
+		message          entity.ChatMessage
+	)
+
+	dest := append([]interface{}{
+		&message.MessageID,
+		&message.ChatID,
+		&message.Message,
+		&message.Sender,
+		&message.MessageType,
+		&replyTo,
+		&forwardedUser,
+		&forwardedMessage,
+		&message.Deleted,
+		&message.CreatedAt,
+		&updatedAt,
+	}, extra...)
+
+	if err := rows.Scan(dest...); err != nil {
+		return entity.ChatMessage{}, err
+	}
+
+	if replyTo.Valid {
+		message.ReplyTo = &entity.MessagePreview{MessageID: int(replyTo.Int64)}
+	}
+
+	message.ForwardedFrom = forwardOrigin(forwardedUser, forwardedMessage)
+
+	if updatedAt.Valid {
+		message.UpdatedAt = &updatedAt.Time
+		message.Edited = !message.Deleted
+	}
+
+	return message, nil
+}
+
 func (ch *RepoChat) GetChat(ctx context.Context, chatID int64) (entity.Chat, error) {
 	query := fmt.Sprintf(`
 	SELECT id, chat_type, receiver_id FROM chat	WHERE id = '%d' AND deleted_at IS NULL
@@ -592,19 +1167,132 @@ func (ch *RepoChat) GetChat(ctx context.Context, chatID int64) (entity.Chat, err
 	return response, nil
 }
 
+// GetMessage returns the message with its attachments, the quoted reply holds only its message ID
 func (ch *RepoChat) GetMessage(ctx context.Context, messageID int64) (entity.Message, error) {
 	query := fmt.Sprintf(`
-	SELECT id, chat_id, content, message_type, sender FROM messages	WHERE id = '%d' AND deleted_at IS NULL
+	SELECT id, chat_id, content, message_type, sender, reply_to_id, forwarded_from_user, forwarded_from_message, created_at
+	FROM messages	WHERE id = '%d' AND deleted_at IS NULL
 	`, messageID)
 
-	var response entity.Message
+	var (
+		replyTo          sql.NullInt64
+		forwardedUser    sql.NullInt64
+		forwardedMessage sql.NullInt64
+		response         entity.Message
+	)
 
 	row := ch.DB.QueryRowContext(ctx, query)
 
-	err := row.Scan(&response.ID, &response.ChatId, &response.Content, &response.MessageType, &response.Sender)
+	err := row.Scan(
+		&response.ID,
+		&response.ChatId,
+		&response.Content,
+		&response.MessageType,
+		&response.Sender,
+		&replyTo,
+		&forwardedUser,
+		&forwardedMessage,
+		&response.CreatedAt,
+	)
+	if err != nil {
+		return entity.Message{}, err
+	}
+
+	if replyTo.Valid {
+		response.ReplyTo = &entity.MessagePreview{MessageID: int(replyTo.Int64)}
+	}
+
+	response.ForwardedFrom = forwardOrigin(forwardedUser, forwardedMessage)
+
+	attachments, err := ch.messageAttachments(ctx, ch.DB, []int{response.ID})
 	if err != nil {
 		return entity.Message{}, err
 	}
 
+	response.Attachments = attachments[response.ID]
+
+	return response, nil
+}
+
+func (ch *RepoChat) MarkDelivered(ctx context.Context, userID, chatID, messageID int64) error {
+	query := `
+	INSERT INTO chat_members (chat_id, user_id, last_delivered_message_id) VALUES (?0, ?1, ?2)
+	ON CONFLICT (chat_id, user_id) DO UPDATE SET
+		last_delivered_message_id = GREATEST(chat_members.last_delivered_message_id, EXCLUDED.last_delivered_message_id),
+		updated_at = NOW()`
+
+	_, err := ch.DB.ExecContext(ctx, query, chatID, userID, messageID)
+
+	return err
+}
+
+// MarkRead moves the read cursor of the user forward to messageID, or to the latest message of the chat
+// when messageID is 0, and returns the message ID the cursor points to
+func (ch *RepoChat) MarkRead(ctx context.Context, userID, chatID, messageID int64) (int64, error) {
+	query := `
+	INSERT INTO chat_members (chat_id, user_id, last_delivered_message_id, last_read_message_id, read_at)
+	SELECT ?0, ?1, target.id, target.id, NOW() FROM (
+		SELECT COALESCE(NULLIF(?2::INT, 0), (SELECT MAX(id) FROM messages WHERE chat_id = ?0 AND deleted_at IS NULL), 0) AS id
+	) AS target
+	ON CONFLICT (chat_id, user_id) DO UPDATE SET
+		last_delivered_message_id = GREATEST(chat_members.last_delivered_message_id, EXCLUDED.last_delivered_message_id),
+		last_read_message_id = GREATEST(chat_members.last_read_message_id, EXCLUDED.last_read_message_id),
+		read_at = NOW(),
+		updated_at = NOW()
+	RETURNING last_read_message_id`
+
+	var lastRead int64
+	if err := ch.DB.QueryRowContext(ctx, query, chatID, userID, messageID).Scan(&lastRead); err != nil {
+		return 0, err
+	}
+
+	return lastRead, nil
+}
+
+func (ch *RepoChat) ChatMembers(ctx context.Context, chatID int64) ([]entity.ChatMember, error) {
+	query := `
+	SELECT chat_id, user_id, last_delivered_message_id, last_read_message_id, read_at
+	FROM chat_members WHERE chat_id = ?0 ORDER BY user_id`
+
+	rows, err := ch.DB.QueryContext(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var response []entity.ChatMember
+	for rows.Next() {
+		var (
+			readAt sql.NullTime
+			member entity.ChatMember
+		)
+
+		err = rows.Scan(
+			&member.ChatID,
+			&member.UserID,
+			&member.LastDeliveredMessageID,
+			&member.LastReadMessageID,
+			&readAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if readAt.Valid {
+			member.ReadAt = &readAt.Time
+		}
+
+		response = append(response, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return response, nil
 }
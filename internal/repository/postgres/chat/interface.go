@@ -15,13 +15,60 @@ type ChatRepository interface {
 	DeleteGroup(ctx context.Context, groupID, deletedBy int64) (entity.DeleteGroupResponse, error)
 	AddUserToGroup(ctx context.Context, userID, groupID int64) error
 	RemoveUserFromGroup(ctx context.Context, userID, groupID int64) error
+	GroupMember(ctx context.Context, groupID, userID int64) (entity.GroupMember, error)
+	GroupMembers(ctx context.Context, groupID int64) ([]entity.GroupMember, error)
+	SetGroupRole(ctx context.Context, groupID, userID int64, role string) error
+	TransferGroupOwnership(ctx context.Context, groupID, ownerID, userID int64) error
+	GetGroupByUsername(ctx context.Context, username string) (entity.GetGroupResponse, error)
+	CreateInvite(ctx context.Context, request entity.CreateInviteRequest, token string) (entity.GroupInvite, error)
+	GroupInvites(ctx context.Context, groupID int64) ([]entity.GroupInvite, error)
+	GetInvite(ctx context.Context, token string) (entity.GroupInvite, error)
+	UseInvite(ctx context.Context, token string) error
+	RevokeInvite(ctx context.Context, groupID, inviteID, revokedBy int64) error
+	CreateJoinRequest(ctx context.Context, groupID, userID int64) (entity.JoinRequest, error)
+	GetJoinRequest(ctx context.Context, requestID int64) (entity.JoinRequest, error)
+	JoinRequests(ctx context.Context, groupID int64) ([]entity.JoinRequest, error)
+	ReviewJoinRequest(ctx context.Context, requestID, reviewedBy int64, status string) error
+	RestrictMember(ctx context.Context, request entity.RestrictMemberRequest, createdBy int64) (entity.GroupRestriction, error)
+	LiftRestriction(ctx context.Context, groupID, userID int64, kind string) error
+	ActiveRestriction(ctx context.Context, groupID, userID int64, kind string) (entity.GroupRestriction, error)
+	GroupRestrictions(ctx context.Context, groupID int64) ([]entity.GroupRestriction, error)
+	SetSlowMode(ctx context.Context, groupID, updatedBy int64, seconds int) error
+	SlowModeWait(ctx context.Context, groupID, chatID, userID int64) (int, error)
+	WordFilters(ctx context.Context, groupID int64) ([]entity.WordFilter, error)
+	CreateWordFilter(ctx context.Context, request entity.CreateWordFilterRequest) (entity.WordFilter, error)
+	DeleteWordFilter(ctx context.Context, groupID, filterID int64) error
+	ReportMessage(ctx context.Context, report entity.MessageReport) (entity.MessageReport, error)
+	MessageReports(ctx context.Context, groupID int64, status string) ([]entity.MessageReport, error)
+	GetMessageReport(ctx context.Context, reportID int64) (entity.MessageReport, error)
+	ReviewReport(ctx context.Context, reportID, reviewedBy int64, status string) error
 	CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error)
 	DeleteChat(ctx context.Context, chatID int64) error
 	UserChats(ctx context.Context, userID int64) (entity.UserChatsResponse, error)
-	SendMessage(ctx context.Context, message entity.SendMessageRequest) error
+	IsParticipant(ctx context.Context, chatID, userID int64) (bool, error)
+	ChatParticipants(ctx context.Context, chatID int64) ([]entity.GetUserResponse, error)
+	CreateChatFile(ctx context.Context, file entity.ChatFile) (entity.ChatFile, error)
+	GetChatFile(ctx context.Context, fileID int64) (entity.ChatFile, error)
+	FileChats(ctx context.Context, fileID int64) ([]int64, error)
+	SendMessage(ctx context.Context, message entity.SendMessageRequest) (entity.Message, error)
 	UpdateMessage(ctx context.Context, message entity.UpdateMessageRequest) error
-	DeleteMessage(ctx context.Context, messageID int64) error
-	GetChatMessages(ctx context.Context, chatID int64) (entity.ChatMessagesResponse, error)
+	DeleteMessage(ctx context.Context, messageID, deletedBy int64) error
+	HideMessage(ctx context.Context, messageID, userID int64) error
+	MessageEdits(ctx context.Context, messageID int64) ([]entity.MessageEdit, error)
+	GetChatMessages(ctx context.Context, filter entity.MessageFilter) (entity.ChatMessagesResponse, error)
+	ChatRetention(ctx context.Context, chatID int64) (entity.ChatRetention, error)
+	SetChatRetention(ctx context.Context, chatID, updatedBy int64, days int) error
+	PurgeMessages(ctx context.Context, defaultDays, limit int) (int, error)
+	ExportMessages(ctx context.Context, filter entity.MessageFilter) ([]entity.ChatMessage, error)
+	SearchMessages(ctx context.Context, request entity.SearchMessagesRequest) (entity.SearchMessagesResponse, error)
 	GetChat(ctx context.Context, chatID int64) (entity.Chat, error)
 	GetMessage(ctx context.Context, messageID int64) (entity.Message, error)
+	MessageReactions(ctx context.Context, messageID, userID int64) ([]entity.ReactionCount, error)
+	ToggleReaction(ctx context.Context, messageID, userID int64, emoji string) (bool, error)
+	PinMessage(ctx context.Context, chatID, messageID, pinnedBy int64) error
+	UnpinMessage(ctx context.Context, chatID, messageID int64) error
+	PinnedMessages(ctx context.Context, chatID, userID int64) ([]entity.PinnedMessage, error)
+	MarkDelivered(ctx context.Context, userID, chatID, messageID int64) error
+	MarkRead(ctx context.Context, userID, chatID, messageID int64) (int64, error)
+	ChatMembers(ctx context.Context, chatID int64) ([]entity.ChatMember, error)
 }
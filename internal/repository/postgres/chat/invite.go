@@ -0,0 +1,329 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"time"
+)
+
+func (ch *RepoChat) GetGroupByUsername(ctx context.Context, username string) (entity.GetGroupResponse, error) {
+	query := `
+	SELECT id, name, username, description, visibility, slow_mode
+	FROM groups
+	WHERE deleted_at IS NULL AND LOWER(username) = LOWER(?0)`
+
+	var (
+		nullDescription sql.NullString
+		result          entity.GetGroupResponse
+	)
+
+	err := ch.DB.QueryRowContext(ctx, query, username).Scan(
+		&result.GroupId,
+		&result.Name,
+		&result.Username,
+		&nullDescription,
+		&result.Visibility,
+		&result.SlowMode,
+	)
+	if err != nil {
+		return entity.GetGroupResponse{}, err
+	}
+
+	if nullDescription.Valid {
+		result.Description = nullDescription.String
+	}
+
+	return result, nil
+}
+
+func (ch *RepoChat) CreateInvite(ctx context.Context, request entity.CreateInviteRequest, token string) (entity.GroupInvite, error) {
+	var (
+		usageLimit sql.NullInt64
+		expiresAt  sql.NullTime
+	)
+
+	if request.UsageLimit > 0 {
+		usageLimit = sql.NullInt64{Int64: int64(request.UsageLimit), Valid: true}
+	}
+
+	if request.ExpiresIn > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().Add(time.Duration(request.ExpiresIn) * time.Second), Valid: true}
+	}
+
+	query := `
+	INSERT INTO group_invites (group_id, token, usage_limit, expires_at, created_by) VALUES (?0, ?1, ?2, ?3, ?4)
+	RETURNING id, group_id, token, usage_limit, usage_count, expires_at, created_by, created_at`
+
+	rows, err := ch.DB.QueryContext(ctx, query, request.GroupID, token, usageLimit, expiresAt, request.CreatedBy)
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	invites, err := scanInvites(rows)
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	if len(invites) == 0 {
+		return entity.GroupInvite{}, sql.ErrNoRows
+	}
+
+	return invites[0], nil
+}
+
+// GroupInvites returns the invites of the group which can still be used
+func (ch *RepoChat) GroupInvites(ctx context.Context, groupID int64) ([]entity.GroupInvite, error) {
+	query := `
+	SELECT id, group_id, token, usage_limit, usage_count, expires_at, created_by, created_at
+	FROM group_invites
+	WHERE group_id = ?0 AND revoked_at IS NULL
+	AND (expires_at IS NULL OR expires_at > NOW())
+	AND (usage_limit IS NULL OR usage_count < usage_limit)
+	ORDER BY id DESC`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanInvites(rows)
+}
+
+func (ch *RepoChat) GetInvite(ctx context.Context, token string) (entity.GroupInvite, error) {
+	query := `
+	SELECT id, group_id, token, usage_limit, usage_count, expires_at, created_by, created_at
+	FROM group_invites
+	WHERE token = ?0 AND revoked_at IS NULL`
+
+	rows, err := ch.DB.QueryContext(ctx, query, token)
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	invites, err := scanInvites(rows)
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	if len(invites) == 0 {
+		return entity.GroupInvite{}, sql.ErrNoRows
+	}
+
+	return invites[0], nil
+}
+
+// UseInvite counts a join through the invite, it returns sql.ErrNoRows when the invite is revoked,
+// expired or used up
+func (ch *RepoChat) UseInvite(ctx context.Context, token string) error {
+	query := `
+	UPDATE group_invites SET usage_count = usage_count + 1
+	WHERE token = ?0 AND revoked_at IS NULL
+	AND (expires_at IS NULL OR expires_at > NOW())
+	AND (usage_limit IS NULL OR usage_count < usage_limit)`
+
+	result, err := ch.DB.ExecContext(ctx, query, token)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (ch *RepoChat) RevokeInvite(ctx context.Context, groupID, inviteID, revokedBy int64) error {
+	query := `UPDATE group_invites SET revoked_at = NOW(), revoked_by = ?0 WHERE id = ?1 AND group_id = ?2 AND revoked_at IS NULL`
+
+	result, err := ch.DB.ExecContext(ctx, query, revokedBy, inviteID, groupID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanInvites(rows *sql.Rows) ([]entity.GroupInvite, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var invites []entity.GroupInvite
+	for rows.Next() {
+		var (
+			usageLimit sql.NullInt64
+			expiresAt  sql.NullTime
+			invite     entity.GroupInvite
+		)
+
+		err := rows.Scan(
+			&invite.ID,
+			&invite.GroupID,
+			&invite.Token,
+			&usageLimit,
+			&invite.UsageCount,
+			&expiresAt,
+			&invite.CreatedBy,
+			&invite.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if usageLimit.Valid {
+			limit := int(usageLimit.Int64)
+			invite.UsageLimit = &limit
+		}
+
+		if expiresAt.Valid {
+			invite.ExpiresAt = &expiresAt.Time
+		}
+
+		invites = append(invites, invite)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// CreateJoinRequest returns the pending request of the user to join the group, creating it when there is none
+func (ch *RepoChat) CreateJoinRequest(ctx context.Context, groupID, userID int64) (entity.JoinRequest, error) {
+	query := `
+	INSERT INTO group_join_requests (group_id, user_id) VALUES (?0, ?1)
+	ON CONFLICT (group_id, user_id) WHERE status = 'pending' DO UPDATE SET status = EXCLUDED.status
+	RETURNING id`
+
+	var requestID int64
+	if err := ch.DB.QueryRowContext(ctx, query, groupID, userID).Scan(&requestID); err != nil {
+		return entity.JoinRequest{}, err
+	}
+
+	return ch.GetJoinRequest(ctx, requestID)
+}
+
+func (ch *RepoChat) GetJoinRequest(ctx context.Context, requestID int64) (entity.JoinRequest, error) {
+	rows, err := ch.DB.QueryContext(ctx, joinRequestsQuery+` WHERE r.id = ?0`, requestID)
+	if err != nil {
+		return entity.JoinRequest{}, err
+	}
+
+	requests, err := scanJoinRequests(rows)
+	if err != nil {
+		return entity.JoinRequest{}, err
+	}
+
+	if len(requests) == 0 {
+		return entity.JoinRequest{}, sql.ErrNoRows
+	}
+
+	return requests[0], nil
+}
+
+// JoinRequests returns the pending join requests of the group, the oldest first
+func (ch *RepoChat) JoinRequests(ctx context.Context, groupID int64) ([]entity.JoinRequest, error) {
+	rows, err := ch.DB.QueryContext(ctx, joinRequestsQuery+` WHERE r.group_id = ?0 AND r.status = 'pending' ORDER BY r.id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanJoinRequests(rows)
+}
+
+// ReviewJoinRequest approves or rejects a pending join request
+func (ch *RepoChat) ReviewJoinRequest(ctx context.Context, requestID, reviewedBy int64, status string) error {
+	query := `
+	UPDATE group_join_requests SET status = ?0, reviewed_by = ?1, reviewed_at = NOW()
+	WHERE id = ?2 AND status = 'pending'`
+
+	result, err := ch.DB.ExecContext(ctx, query, status, reviewedBy, requestID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const joinRequestsQuery = `
+	SELECT r.id, r.group_id, r.user_id, u.username, r.status, r.created_at, r.reviewed_by, r.reviewed_at
+	FROM group_join_requests AS r
+	INNER JOIN users AS u ON u.id = r.user_id`
+
+func scanJoinRequests(rows *sql.Rows) ([]entity.JoinRequest, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var requests []entity.JoinRequest
+	for rows.Next() {
+		var (
+			reviewedBy sql.NullInt64
+			reviewedAt sql.NullTime
+			request    entity.JoinRequest
+		)
+
+		err := rows.Scan(
+			&request.ID,
+			&request.GroupID,
+			&request.UserID,
+			&request.Username,
+			&request.Status,
+			&request.CreatedAt,
+			&reviewedBy,
+			&reviewedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if reviewedBy.Valid {
+			reviewer := int(reviewedBy.Int64)
+			request.ReviewedBy = &reviewer
+		}
+
+		if reviewedAt.Valid {
+			request.ReviewedAt = &reviewedAt.Time
+		}
+
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,413 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+const restrictionColumns = `r.group_id, r.user_id, u.username, r.kind, r.reason, r.expires_at, r.created_by, r.created_at`
+
+const reportColumns = `id, group_id, chat_id, message_id, content, reported_by, reason, status, reviewed_by, reviewed_at, created_at`
+
+// RestrictMember mutes or bans the user, a restriction given again replaces the previous one
+func (ch *RepoChat) RestrictMember(ctx context.Context, request entity.RestrictMemberRequest, createdBy int64) (entity.GroupRestriction, error) {
+	query := `
+	WITH r AS (
+		INSERT INTO group_restrictions (group_id, user_id, kind, reason, expires_at, created_by)
+		VALUES (?0, ?1, ?2, ?3, CASE WHEN ?4 > 0 THEN NOW() + MAKE_INTERVAL(secs => ?4) END, ?5)
+		ON CONFLICT (group_id, user_id, kind) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			expires_at = EXCLUDED.expires_at,
+			created_by = EXCLUDED.created_by,
+			created_at = NOW()
+		RETURNING *
+	)
+	SELECT ` + restrictionColumns + `
+	FROM r
+	INNER JOIN users AS u ON u.id = r.user_id`
+
+	rows, err := ch.DB.QueryContext(ctx, query, request.GroupID, request.UserID, request.Kind, request.Reason, request.Duration, createdBy)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	restrictions, err := scanRestrictions(rows)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	if len(restrictions) == 0 {
+		return entity.GroupRestriction{}, sql.ErrNoRows
+	}
+
+	return restrictions[0], nil
+}
+
+// LiftRestriction removes an active mute or ban of the user
+func (ch *RepoChat) LiftRestriction(ctx context.Context, groupID, userID int64, kind string) error {
+	query := `
+	DELETE FROM group_restrictions
+	WHERE group_id = ?0 AND user_id = ?1 AND kind = ?2 AND (expires_at IS NULL OR expires_at > NOW())`
+
+	result, err := ch.DB.ExecContext(ctx, query, groupID, userID, kind)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ActiveRestriction returns the mute or ban of the user which has not expired yet
+func (ch *RepoChat) ActiveRestriction(ctx context.Context, groupID, userID int64, kind string) (entity.GroupRestriction, error) {
+	query := `
+	SELECT ` + restrictionColumns + `
+	FROM group_restrictions AS r
+	INNER JOIN users AS u ON u.id = r.user_id
+	WHERE r.group_id = ?0 AND r.user_id = ?1 AND r.kind = ?2 AND (r.expires_at IS NULL OR r.expires_at > NOW())`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID, userID, kind)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	restrictions, err := scanRestrictions(rows)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	if len(restrictions) == 0 {
+		return entity.GroupRestriction{}, sql.ErrNoRows
+	}
+
+	return restrictions[0], nil
+}
+
+// GroupRestrictions returns the active mutes and bans of the group
+func (ch *RepoChat) GroupRestrictions(ctx context.Context, groupID int64) ([]entity.GroupRestriction, error) {
+	query := `
+	SELECT ` + restrictionColumns + `
+	FROM group_restrictions AS r
+	INNER JOIN users AS u ON u.id = r.user_id
+	WHERE r.group_id = ?0 AND (r.expires_at IS NULL OR r.expires_at > NOW())
+	ORDER BY r.created_at DESC`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRestrictions(rows)
+}
+
+func (ch *RepoChat) SetSlowMode(ctx context.Context, groupID, updatedBy int64, seconds int) error {
+	query := `UPDATE groups SET slow_mode = ?1, updated_at = NOW(), updated_by = ?2 WHERE id = ?0 AND deleted_at IS NULL`
+
+	result, err := ch.DB.ExecContext(ctx, query, groupID, seconds, updatedBy)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SlowModeWait returns the seconds the user has to wait before posting in the group chat again, 0 when it may post
+func (ch *RepoChat) SlowModeWait(ctx context.Context, groupID, chatID, userID int64) (int, error) {
+	query := `
+	SELECT COALESCE(CEIL(EXTRACT(EPOCH FROM MAX(m.created_at) + MAKE_INTERVAL(secs => g.slow_mode) - NOW())), 0)
+	FROM groups AS g
+	LEFT JOIN messages AS m ON m.chat_id = ?1 AND m.sender = ?2
+	WHERE g.id = ?0 AND g.slow_mode > 0
+	GROUP BY g.slow_mode`
+
+	var wait float64
+
+	err := ch.DB.QueryRowContext(ctx, query, groupID, chatID, userID).Scan(&wait)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if wait < 0 {
+		return 0, nil
+	}
+
+	return int(wait), nil
+}
+
+func (ch *RepoChat) WordFilters(ctx context.Context, groupID int64) ([]entity.WordFilter, error) {
+	query := `
+	SELECT id, group_id, pattern, is_regex, action, created_by, created_at
+	FROM group_word_filters
+	WHERE group_id = ?0
+	ORDER BY id`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWordFilters(rows)
+}
+
+func (ch *RepoChat) CreateWordFilter(ctx context.Context, request entity.CreateWordFilterRequest) (entity.WordFilter, error) {
+	query := `
+	INSERT INTO group_word_filters (group_id, pattern, is_regex, action, created_by) VALUES (?0, ?1, ?2, ?3, ?4)
+	RETURNING id, group_id, pattern, is_regex, action, created_by, created_at`
+
+	rows, err := ch.DB.QueryContext(ctx, query, request.GroupID, request.Pattern, request.Regex, request.Action, request.CreatedBy)
+	if err != nil {
+		return entity.WordFilter{}, err
+	}
+
+	filters, err := scanWordFilters(rows)
+	if err != nil {
+		return entity.WordFilter{}, err
+	}
+
+	if len(filters) == 0 {
+		return entity.WordFilter{}, sql.ErrNoRows
+	}
+
+	return filters[0], nil
+}
+
+func (ch *RepoChat) DeleteWordFilter(ctx context.Context, groupID, filterID int64) error {
+	result, err := ch.DB.ExecContext(ctx, `DELETE FROM group_word_filters WHERE id = ?0 AND group_id = ?1`, filterID, groupID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ReportMessage stores the report, a user reporting the same message again only updates the reason
+func (ch *RepoChat) ReportMessage(ctx context.Context, report entity.MessageReport) (entity.MessageReport, error) {
+	query := `
+	INSERT INTO message_reports (group_id, chat_id, message_id, content, reported_by, reason)
+	VALUES (?0, ?1, ?2, ?3, ?4, ?5)
+	ON CONFLICT (message_id, reported_by) DO UPDATE SET reason = EXCLUDED.reason
+	RETURNING ` + reportColumns
+
+	rows, err := ch.DB.QueryContext(ctx, query, report.GroupID, report.ChatID, report.MessageID, report.Content, report.ReportedBy, report.Reason)
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	reports, err := scanReports(rows)
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	if len(reports) == 0 {
+		return entity.MessageReport{}, sql.ErrNoRows
+	}
+
+	return reports[0], nil
+}
+
+// MessageReports returns the reports of the group with the status, the newest first
+func (ch *RepoChat) MessageReports(ctx context.Context, groupID int64, status string) ([]entity.MessageReport, error) {
+	query := `
+	SELECT ` + reportColumns + `
+	FROM message_reports
+	WHERE group_id = ?0 AND status = ?1
+	ORDER BY created_at DESC
+	LIMIT 200`
+
+	rows, err := ch.DB.QueryContext(ctx, query, groupID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReports(rows)
+}
+
+func (ch *RepoChat) GetMessageReport(ctx context.Context, reportID int64) (entity.MessageReport, error) {
+	rows, err := ch.DB.QueryContext(ctx, `SELECT `+reportColumns+` FROM message_reports WHERE id = ?0`, reportID)
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	reports, err := scanReports(rows)
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	if len(reports) == 0 {
+		return entity.MessageReport{}, sql.ErrNoRows
+	}
+
+	return reports[0], nil
+}
+
+// ReviewReport closes an open report
+func (ch *RepoChat) ReviewReport(ctx context.Context, reportID, reviewedBy int64, status string) error {
+	query := `
+	UPDATE message_reports SET status = ?2, reviewed_by = ?1, reviewed_at = NOW()
+	WHERE id = ?0 AND status = 'open'`
+
+	result, err := ch.DB.ExecContext(ctx, query, reportID, reviewedBy, status)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanRestrictions(rows *sql.Rows) ([]entity.GroupRestriction, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var restrictions []entity.GroupRestriction
+	for rows.Next() {
+		var (
+			expiresAt   sql.NullTime
+			restriction entity.GroupRestriction
+		)
+
+		err := rows.Scan(
+			&restriction.GroupID,
+			&restriction.UserID,
+			&restriction.Username,
+			&restriction.Kind,
+			&restriction.Reason,
+			&expiresAt,
+			&restriction.CreatedBy,
+			&restriction.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if expiresAt.Valid {
+			restriction.ExpiresAt = &expiresAt.Time
+		}
+
+		restrictions = append(restrictions, restriction)
+	}
+
+	return restrictions, rows.Err()
+}
+
+func scanWordFilters(rows *sql.Rows) ([]entity.WordFilter, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var filters []entity.WordFilter
+	for rows.Next() {
+		var filter entity.WordFilter
+
+		err := rows.Scan(
+			&filter.ID,
+			&filter.GroupID,
+			&filter.Pattern,
+			&filter.Regex,
+			&filter.Action,
+			&filter.CreatedBy,
+			&filter.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, rows.Err()
+}
+
+func scanReports(rows *sql.Rows) ([]entity.MessageReport, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var reports []entity.MessageReport
+	for rows.Next() {
+		var (
+			reviewedBy sql.NullInt64
+			reviewedAt sql.NullTime
+			report     entity.MessageReport
+		)
+
+		err := rows.Scan(
+			&report.ID,
+			&report.GroupID,
+			&report.ChatID,
+			&report.MessageID,
+			&report.Content,
+			&report.ReportedBy,
+			&report.Reason,
+			&report.Status,
+			&reviewedBy,
+			&reviewedAt,
+			&report.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if reviewedBy.Valid {
+			id := int(reviewedBy.Int64)
+			report.ReviewedBy = &id
+		}
+
+		if reviewedAt.Valid {
+			report.ReviewedAt = &reviewedAt.Time
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
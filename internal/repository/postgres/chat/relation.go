@@ -0,0 +1,338 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/uptrace/bun"
+)
+
+// messageColumns are the columns read by scanMessages, the messages table is aliased as m.
+// The content of a message deleted for everyone is not read.
+const messageColumns = `m.id, m.chat_id, CASE WHEN m.deleted_at IS NULL THEN m.content ELSE '' END, m.sender,
+	m.message_type, m.reply_to_id, m.forwarded_from_user, m.forwarded_from_message, m.deleted_at IS NOT NULL,
+	m.created_at, m.updated_at`
+
+// notHidden is the condition leaving out the messages deleted for the user at the placeholder ?arg
+func notHidden(arg int) string {
+	return fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM hidden_messages AS h WHERE h.message_id = m.id AND h.user_id = ?%d)`, arg)
+}
+
+// fillMessages adds the attachments, the quoted replies and the reactions to the listed messages,
+// userID is the user reading them
+func (ch *RepoChat) fillMessages(ctx context.Context, userID int64, messages []entity.ChatMessage) error {
+	if err := ch.withAttachments(ctx, messages); err != nil {
+		return err
+	}
+
+	var messageIDs, replyIDs []int
+	for _, message := range messages {
+		if message.Deleted {
+			continue
+		}
+
+		messageIDs = append(messageIDs, message.MessageID)
+
+		if message.ReplyTo != nil {
+			replyIDs = append(replyIDs, message.ReplyTo.MessageID)
+		}
+	}
+
+	previews, err := messagePreviews(ctx, ch.DB, replyIDs)
+	if err != nil {
+		return err
+	}
+
+	reactions, err := ch.messageReactions(ctx, messageIDs, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		if messages[i].ReplyTo != nil {
+			if preview, ok := previews[messages[i].ReplyTo.MessageID]; ok {
+				messages[i].ReplyTo = &preview
+			}
+		}
+
+		messages[i].Reactions = reactions[messages[i].MessageID]
+	}
+
+	return nil
+}
+
+// messagePreviews quotes the messages replied to, a removed message is quoted without its content
+func messagePreviews(ctx context.Context, db bun.IDB, messageIDs []int) (map[int]entity.MessagePreview, error) {
+	previews := make(map[int]entity.MessagePreview)
+	if len(messageIDs) == 0 {
+		return previews, nil
+	}
+
+	query := `
+	SELECT id, sender, CASE WHEN deleted_at IS NULL THEN LEFT(content, 100) ELSE '' END, message_type, deleted_at IS NOT NULL
+	FROM messages
+	WHERE id IN (?0)`
+
+	rows, err := db.QueryContext(ctx, query, bun.In(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	for rows.Next() {
+		var preview entity.MessagePreview
+
+		err := rows.Scan(&preview.MessageID, &preview.Sender, &preview.Content, &preview.MessageType, &preview.Deleted)
+		if err != nil {
+			return nil, err
+		}
+
+		previews[preview.MessageID] = preview
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return previews, nil
+}
+
+// messageReactions counts the reactions of the messages in the order they were first given
+func (ch *RepoChat) messageReactions(ctx context.Context, messageIDs []int, userID int64) (map[int][]entity.ReactionCount, error) {
+	reactions := make(map[int][]entity.ReactionCount)
+	if len(messageIDs) == 0 {
+		return reactions, nil
+	}
+
+	query := `
+	SELECT message_id, emoji, COUNT(*), BOOL_OR(user_id = ?1)
+	FROM message_reactions
+	WHERE message_id IN (?0)
+	GROUP BY message_id, emoji
+	ORDER BY message_id, MIN(created_at)`
+
+	rows, err := ch.DB.QueryContext(ctx, query, bun.In(messageIDs), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	for rows.Next() {
+		var (
+			messageID int
+			reaction  entity.ReactionCount
+		)
+
+		if err := rows.Scan(&messageID, &reaction.Emoji, &reaction.Count, &reaction.Reacted); err != nil {
+			return nil, err
+		}
+
+		reactions[messageID] = append(reactions[messageID], reaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+// MessageReactions returns the reaction counts of the message, Reacted is set for the reactions of userID
+func (ch *RepoChat) MessageReactions(ctx context.Context, messageID, userID int64) ([]entity.ReactionCount, error) {
+	reactions, err := ch.messageReactions(ctx, []int{int(messageID)}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reactions[int(messageID)], nil
+}
+
+// ToggleReaction removes the reaction of the user from the message or adds it when the user has not
+// reacted with the emoji yet, it reports whether the reaction was added
+func (ch *RepoChat) ToggleReaction(ctx context.Context, messageID, userID int64, emoji string) (bool, error) {
+	var added bool
+
+	err := ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM message_reactions WHERE message_id = ?0 AND user_id = ?1 AND emoji = ?2`,
+			messageID, userID, emoji)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rows > 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `
+		INSERT INTO message_reactions (message_id, user_id, emoji) VALUES (?0, ?1, ?2)
+		ON CONFLICT (message_id, user_id, emoji) DO NOTHING`, messageID, userID, emoji)
+		if err != nil {
+			return err
+		}
+
+		added = true
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (ch *RepoChat) PinMessage(ctx context.Context, chatID, messageID, pinnedBy int64) error {
+	query := `
+	INSERT INTO pinned_messages (chat_id, message_id, pinned_by) VALUES (?0, ?1, ?2)
+	ON CONFLICT (chat_id, message_id) DO NOTHING`
+
+	_, err := ch.DB.ExecContext(ctx, query, chatID, messageID, pinnedBy)
+
+	return err
+}
+
+func (ch *RepoChat) UnpinMessage(ctx context.Context, chatID, messageID int64) error {
+	result, err := ch.DB.ExecContext(ctx, `DELETE FROM pinned_messages WHERE chat_id = ?0 AND message_id = ?1`, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PinnedMessages returns the pinned messages of the chat which are not removed, the latest pinned first
+func (ch *RepoChat) PinnedMessages(ctx context.Context, chatID, userID int64) ([]entity.PinnedMessage, error) {
+	query := `
+	SELECT ` + messageColumns + `, p.pinned_by, p.pinned_at
+	FROM pinned_messages AS p
+	INNER JOIN messages AS m ON m.id = p.message_id AND m.deleted_at IS NULL
+	WHERE p.chat_id = ?0 AND ` + notHidden(1) + `
+	ORDER BY p.pinned_at DESC`
+
+	rows, err := ch.DB.QueryContext(ctx, query, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		messages []entity.ChatMessage
+		pins     []entity.PinnedMessage
+	)
+
+	err = func() error {
+		defer func(rows *sql.Rows) {
+			err := rows.Close()
+			if err != nil {
+				_ = err
+			}
+		}(rows)
+
+		for rows.Next() {
+			var pin entity.PinnedMessage
+
+			message, err := scanMessage(rows, &pin.PinnedBy, &pin.PinnedAt)
+			if err != nil {
+				return err
+			}
+
+			messages = append(messages, message)
+			pins = append(pins, pin)
+		}
+
+		return rows.Err()
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.fillMessages(ctx, userID, messages); err != nil {
+		return nil, err
+	}
+
+	for i := range pins {
+		pins[i].Message = messages[i]
+	}
+
+	return pins, nil
+}
+
+// FileChats returns the chat a file was uploaded to and the chats it was forwarded to
+func (ch *RepoChat) FileChats(ctx context.Context, fileID int64) ([]int64, error) {
+	query := `
+	SELECT chat_id FROM files WHERE id = ?0 AND chat_id IS NOT NULL
+	UNION
+	SELECT m.chat_id FROM message_attachments AS a
+	INNER JOIN messages AS m ON m.id = a.message_id AND m.deleted_at IS NULL
+	WHERE a.file_id = ?0`
+
+	rows, err := ch.DB.QueryContext(ctx, query, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return chatIDs, nil
+}
+
+func nullID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// forwardOrigin reads the forwarded_from columns of a message
+func forwardOrigin(userID, messageID sql.NullInt64) *entity.ForwardOrigin {
+	if !userID.Valid {
+		return nil
+	}
+
+	return &entity.ForwardOrigin{
+		UserID:    int(userID.Int64),
+		MessageID: int(messageID.Int64),
+	}
+}
@@ -0,0 +1,161 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/repository/postgres/fileStore"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+)
+
+func (ch *RepoChat) ChatRetention(ctx context.Context, chatID int64) (entity.ChatRetention, error) {
+	query := `SELECT chat_id, days, updated_by, updated_at FROM chat_retention WHERE chat_id = ?0`
+
+	var (
+		updatedBy int
+		updatedAt sql.NullTime
+		retention entity.ChatRetention
+	)
+
+	err := ch.DB.QueryRowContext(ctx, query, chatID).Scan(&retention.ChatID, &retention.Days, &updatedBy, &updatedAt)
+	if err != nil {
+		return entity.ChatRetention{}, err
+	}
+
+	retention.UpdatedBy = &updatedBy
+	if updatedAt.Valid {
+		retention.UpdatedAt = &updatedAt.Time
+	}
+
+	return retention, nil
+}
+
+// SetChatRetention overrides the global retention of the chat, 0 days removes the override
+func (ch *RepoChat) SetChatRetention(ctx context.Context, chatID, updatedBy int64, days int) error {
+	if days == 0 {
+		_, err := ch.DB.ExecContext(ctx, `DELETE FROM chat_retention WHERE chat_id = ?0`, chatID)
+		return err
+	}
+
+	query := `
+	INSERT INTO chat_retention (chat_id, days, updated_by) VALUES (?0, ?1, ?2)
+	ON CONFLICT (chat_id) DO UPDATE SET days = EXCLUDED.days, updated_by = EXCLUDED.updated_by, updated_at = NOW()`
+
+	_, err := ch.DB.ExecContext(ctx, query, chatID, days, updatedBy)
+
+	return err
+}
+
+// PurgeMessages deletes up to limit messages older than the retention of their chat together with
+// the rows referring to them, defaultDays applies to chats without their own retention and 0 keeps
+// those forever. Replies and forwards of a purged message lose the reference and keep their content.
+// Files uploaded to the chat go with the last message attaching them, they leave the usage of their
+// uploader and release their blobs as a deleted file does.
+func (ch *RepoChat) PurgeMessages(ctx context.Context, defaultDays, limit int) (int, error) {
+	var purged int
+
+	err := ch.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		query := `
+		SELECT m.id
+		FROM messages AS m
+		LEFT JOIN chat_retention AS r ON r.chat_id = m.chat_id
+		WHERE COALESCE(r.days, ?0) > 0 AND m.created_at < NOW() - MAKE_INTERVAL(days => COALESCE(r.days, ?0))
+		ORDER BY m.id
+		LIMIT ?1
+		FOR UPDATE OF m SKIP LOCKED`
+
+		rows, err := tx.QueryContext(ctx, query, defaultDays, limit)
+		if err != nil {
+			return err
+		}
+
+		defer func(rows *sql.Rows) {
+			err := rows.Close()
+			if err != nil {
+				_ = err
+			}
+		}(rows)
+
+		var messageIDs []int64
+		for rows.Next() {
+			var messageID int64
+			if err := rows.Scan(&messageID); err != nil {
+				return err
+			}
+
+			messageIDs = append(messageIDs, messageID)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(messageIDs) == 0 {
+			return nil
+		}
+
+		filesQuery := `
+		WITH target AS (
+			SELECT f.id, f.blob_key
+			FROM files AS f
+			WHERE f.chat_id IS NOT NULL AND f.deleted_at IS NULL
+				AND f.id IN (SELECT file_id FROM message_attachments WHERE message_id IN (?0))
+				AND NOT EXISTS (SELECT 1 FROM message_attachments AS a WHERE a.file_id = f.id AND a.message_id NOT IN (?0))
+			FOR UPDATE OF f
+		),
+		deleted_files AS (
+			UPDATE files f SET deleted_at = NOW(), purged_at = NOW(), blob_key = NULL
+			FROM target t
+			WHERE f.id = t.id
+			RETURNING f.id, f.created_by, f.size, t.blob_key
+		),
+		usage AS (` + fileStore.UsageQuery("deleted_files", -1) + `),
+		released AS (` + fileStore.ReleaseQuery("deleted_files") + `)
+		SELECT COUNT(*) FROM deleted_files`
+
+		if _, err := tx.ExecContext(ctx, filesQuery, bun.In(messageIDs)); err != nil {
+			return err
+		}
+
+		for _, statement := range []string{
+			`UPDATE messages SET reply_to_id = NULL WHERE reply_to_id IN (?0)`,
+			`UPDATE messages SET forwarded_from_message = NULL WHERE forwarded_from_message IN (?0)`,
+			`UPDATE notifications SET latest_message_id = NULL, latest_message = '' WHERE latest_message_id IN (?0)`,
+			`DELETE FROM message_attachments WHERE message_id IN (?0)`,
+			`DELETE FROM message_reactions WHERE message_id IN (?0)`,
+			`DELETE FROM pinned_messages WHERE message_id IN (?0)`,
+			`DELETE FROM message_edits WHERE message_id IN (?0)`,
+			`DELETE FROM hidden_messages WHERE message_id IN (?0)`,
+			`DELETE FROM message_reports WHERE message_id IN (?0)`,
+			`DELETE FROM messages WHERE id IN (?0)`,
+		} {
+			if _, err := tx.ExecContext(ctx, statement, bun.In(messageIDs)); err != nil {
+				return err
+			}
+		}
+
+		purged = len(messageIDs)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// ExportMessages returns up to filter.Limit messages of the chat after filter.After in ascending order,
+// as the reader filter.UserID sees them
+func (ch *RepoChat) ExportMessages(ctx context.Context, filter entity.MessageFilter) ([]entity.ChatMessage, error) {
+	messages, _, err := ch.pageMessages(ctx, filter, filter.After, filter.Limit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.fillMessages(ctx, filter.UserID, messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,202 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// AcquireBlob takes a reference to the blob, recording it when it is new.
+// It waits while the collector holds the blob, so a blob with a reference is never removed.
+func (r *Repo) AcquireBlob(ctx context.Context, blob entity.Blob) (entity.Blob, error) {
+	insertQuery := `
+	INSERT INTO blobs (key, checksum, size, ref_count)
+	VALUES (?0, ?1, ?2, 1)
+	ON CONFLICT (key) DO UPDATE SET ref_count = blobs.ref_count + 1, released_at = NULL
+	RETURNING key, checksum, size, ref_count, verified_at, corrupted_at
+	`
+
+	var response entity.Blob
+	err := r.DB.QueryRowContext(ctx, insertQuery, blob.Key, blob.Checksum, blob.Size).Scan(
+		&response.Key,
+		&response.Checksum,
+		&response.Size,
+		&response.RefCount,
+		&response.VerifiedAt,
+		&response.CorruptedAt,
+	)
+	if err != nil {
+		return entity.Blob{}, err
+	}
+
+	return response, nil
+}
+
+// ReleaseBlob drops a reference to the blob, the last one starts the grace period of the collector
+func (r *Repo) ReleaseBlob(ctx context.Context, key string) error {
+	_, err := r.DB.ExecContext(ctx, releaseBlobQuery, key)
+
+	return err
+}
+
+// PurgeDeletedFiles drops the content of up to limit files deleted before deletedBefore, their blobs lose the
+// references the files held. A purged file stays deleted when its folder is restored.
+func (r *Repo) PurgeDeletedFiles(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	purgeQuery := `
+	WITH expired AS (
+		SELECT id, blob_key
+		FROM files
+		WHERE deleted_at < ?0 AND purged_at IS NULL
+		ORDER BY deleted_at
+		LIMIT ?1
+		FOR UPDATE SKIP LOCKED
+	),
+	purged AS (
+		UPDATE files f SET purged_at = NOW(), blob_key = NULL
+		FROM expired e
+		WHERE f.id = e.id
+		RETURNING e.blob_key
+	),
+	released AS (` + ReleaseQuery("purged") + `)
+	SELECT COUNT(*) FROM purged
+	`
+
+	var purged int
+	if err := r.DB.QueryRowContext(ctx, purgeQuery, deletedBefore, limit).Scan(&purged); err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// ReleasedBlobs returns the keys of the blobs nobody held since before releasedBefore
+func (r *Repo) ReleasedBlobs(ctx context.Context, releasedBefore time.Time, limit int) ([]string, error) {
+	selectQuery := `
+	SELECT key
+	FROM blobs
+	WHERE ref_count = 0 AND released_at < ?0
+	ORDER BY released_at
+	LIMIT ?1
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, releasedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// DropBlob forgets a blob nobody holds after remove deleted its content. The row stays locked meanwhile,
+// an upload of the same content waits and stores it again. It returns false when the blob was taken again.
+func (r *Repo) DropBlob(ctx context.Context, key string, remove func(ctx context.Context) error) (bool, error) {
+	var dropped bool
+
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var locked string
+		err := tx.QueryRowContext(ctx, `SELECT key FROM blobs WHERE key = ?0 AND ref_count = 0 FOR UPDATE`, key).Scan(&locked)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := remove(ctx); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM blobs WHERE key = ?0`, key); err != nil {
+			return err
+		}
+
+		dropped = true
+
+		return nil
+	})
+
+	return dropped, err
+}
+
+// BlobsToVerify returns the held blobs not verified since verifiedBefore, the never verified first
+func (r *Repo) BlobsToVerify(ctx context.Context, verifiedBefore time.Time, limit int) ([]entity.Blob, error) {
+	selectQuery := `
+	SELECT key, checksum, size, ref_count, verified_at, corrupted_at
+	FROM blobs
+	WHERE ref_count > 0 AND (verified_at IS NULL OR verified_at < ?0)
+	ORDER BY verified_at NULLS FIRST
+	LIMIT ?1
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, verifiedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blobs []entity.Blob
+	for rows.Next() {
+		var blob entity.Blob
+		err = rows.Scan(&blob.Key, &blob.Checksum, &blob.Size, &blob.RefCount, &blob.VerifiedAt, &blob.CorruptedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		blobs = append(blobs, blob)
+	}
+
+	return blobs, rows.Err()
+}
+
+// SetBlobVerified records the outcome of a verification. A blob stored before the checksums were
+// kept takes the checksum it was read with, a corrupted blob keeps the time it was first found so.
+func (r *Repo) SetBlobVerified(ctx context.Context, key, checksum string, intact bool) error {
+	updateQuery := `
+	UPDATE blobs
+	SET verified_at = NOW(),
+		checksum = CASE WHEN checksum = '' THEN ?1 ELSE checksum END,
+		corrupted_at = CASE WHEN ?2 THEN NULL ELSE COALESCE(corrupted_at, NOW()) END
+	WHERE key = ?0
+	`
+
+	_, err := r.DB.ExecContext(ctx, updateQuery, key, checksum, intact)
+
+	return err
+}
+
+// ReleaseQuery drops the references the rows of changed, with a blob_key column, held on their blobs
+func ReleaseQuery(changed string) string {
+	return fmt.Sprintf(`
+	UPDATE blobs b
+	SET ref_count = GREATEST(b.ref_count - r.refs, 0),
+		released_at = CASE WHEN b.ref_count <= r.refs THEN NOW() ELSE b.released_at END
+	FROM (
+		SELECT blob_key, COUNT(*) AS refs
+		FROM %s
+		WHERE blob_key IS NOT NULL
+		GROUP BY blob_key
+	) r
+	WHERE b.key = r.blob_key
+	`, changed)
+}
+
+const releaseBlobQuery = `
+UPDATE blobs
+SET ref_count = ref_count - 1, released_at = CASE WHEN ref_count = 1 THEN NOW() ELSE released_at END
+WHERE key = ?0 AND ref_count > 0
+`
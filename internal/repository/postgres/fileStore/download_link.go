@@ -0,0 +1,53 @@
+package fileStore
+
+import (
+	"context"
+	"time"
+)
+
+// FileAttached reports whether the file is attached to a post or a menu
+func (r *Repo) FileAttached(ctx context.Context, fileID int) (bool, error) {
+	selectQuery := `
+	SELECT
+		EXISTS (SELECT 1 FROM post_files WHERE file_id = ?0)
+		OR EXISTS (SELECT 1 FROM menu_files WHERE file_id = ?0)
+	`
+
+	var attached bool
+	err := r.DB.QueryRowContext(ctx, selectQuery, fileID).Scan(&attached)
+
+	return attached, err
+}
+
+// UseDownloadLink marks a single use link as used, it returns false when it was used before
+func (r *Repo) UseDownloadLink(ctx context.Context, nonce string, fileID int, expiresAt time.Time) (bool, error) {
+	insertQuery := `
+	INSERT INTO used_download_links (nonce, file_id, expires_at)
+	VALUES (?0, ?1, ?2)
+	ON CONFLICT (nonce) DO NOTHING
+	`
+
+	result, err := r.DB.ExecContext(ctx, insertQuery, nonce, fileID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// PurgeDownloadLinks forgets the used links that have expired anyway
+func (r *Repo) PurgeDownloadLinks(ctx context.Context) (int, error) {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM used_download_links WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+
+	return int(rowsAffected), err
+}
@@ -0,0 +1,371 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+)
+
+// memberGrant matches the grants of the user ?1 and of the chat groups they are in
+const memberGrant = `(acl.user_id = ?1 OR acl.group_id IN (
+		SELECT group_id FROM group_users WHERE user_id = ?1 AND deleted_at IS NULL
+	))`
+
+// roleRank orders the roles of file_acl acl so the strongest one wins
+const roleRank = `CASE acl.role WHEN 'owner' THEN 3 WHEN 'editor' THEN 2 ELSE 1 END`
+
+// rankRole turns the strongest rank back into a role, no rank is no role
+const rankRole = `CASE MAX(rank) WHEN 3 THEN 'owner' WHEN 2 THEN 'editor' WHEN 1 THEN 'viewer' ELSE '' END`
+
+// visibleQuery selects the folders the user ?1 created or holds a role on with every folder below them,
+// the user's own folders count as owned
+const visibleQuery = `
+	visible AS (
+		SELECT f.id, 0 AS depth
+		FROM folders f
+		WHERE f.deleted_at IS NULL AND (f.created_by = ?1 OR EXISTS (
+			SELECT 1 FROM file_acl acl WHERE acl.folder_id = f.id AND ` + memberGrant + `
+		))
+		UNION
+		SELECT f.id, v.depth + 1
+		FROM folders f
+		JOIN visible v ON f.parent_id = v.id
+		WHERE f.deleted_at IS NULL AND v.depth < ?2
+	)`
+
+// visibleFolder tells whether the folder f is visible to the user ?1, every folder is to the admins ?3
+const visibleFolder = `(?3 OR f.id IN (SELECT id FROM visible))`
+
+// visibleFile tells whether the file f is visible to the user ?1, the files attached to posts and menus are public
+// and every file is visible to the admins ?3
+const visibleFile = `(?3 OR f.created_by = ?1
+		OR f.folder_id IN (SELECT id FROM visible)
+		OR EXISTS (SELECT 1 FROM file_acl acl WHERE acl.file_id = f.id AND ` + memberGrant + `)
+		OR EXISTS (SELECT 1 FROM post_files WHERE file_id = f.id)
+		OR EXISTS (SELECT 1 FROM menu_files WHERE file_id = f.id))`
+
+// ancestorsQuery selects the folder ?0 with every folder above it, nearest first
+var ancestorsQuery = ancestorsOf("deleted_at IS NULL")
+
+// ancestorsOf selects the folder ?0, when it matches the condition, with every live folder above it, nearest first
+func ancestorsOf(condition string) string {
+	return `
+	ancestors AS (
+		SELECT id, parent_id, created_by, 0 AS depth FROM folders WHERE id = ?0 AND ` + condition + `
+		UNION ALL
+		SELECT f.id, f.parent_id, f.created_by, a.depth + 1
+		FROM folders f
+		JOIN ancestors a ON f.id = a.parent_id
+		WHERE f.deleted_at IS NULL AND a.depth < ?2
+	)`
+}
+
+// FolderRole returns the strongest role the user holds on the folder through the folders above it,
+// an empty role when none and sql.ErrNoRows when the folder does not exist
+func (r *Repo) FolderRole(ctx context.Context, folderID, userID int) (string, error) {
+	return r.folderRole(ctx, ancestorsQuery, folderID, userID)
+}
+
+// DeletedFolderRole is FolderRole for a deleted folder, the role it was held with before the folder was deleted
+func (r *Repo) DeletedFolderRole(ctx context.Context, folderID, userID int) (string, error) {
+	return r.folderRole(ctx, ancestorsOf("deleted_at IS NOT NULL"), folderID, userID)
+}
+
+func (r *Repo) folderRole(ctx context.Context, ancestors string, folderID, userID int) (string, error) {
+	selectQuery := `
+	WITH RECURSIVE ` + ancestors + `
+	SELECT (SELECT COUNT(*) FROM ancestors WHERE depth = 0), ` + rankRole + ` FROM (
+		SELECT 3 AS rank FROM ancestors WHERE created_by = ?1
+		UNION ALL
+		SELECT ` + roleRank + ` FROM file_acl acl JOIN ancestors a ON acl.folder_id = a.id WHERE ` + memberGrant + `
+	) AS grants
+	`
+
+	var (
+		found int
+		role  string
+	)
+
+	err := r.DB.QueryRowContext(ctx, selectQuery, folderID, userID, maxFolderDepth).Scan(&found, &role)
+	if err != nil {
+		return "", err
+	}
+
+	if found == 0 {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+// FileRole returns the strongest role the user holds on the file, on its own or through its folders
+func (r *Repo) FileRole(ctx context.Context, fileID, userID int) (string, error) {
+	selectQuery := `
+	WITH RECURSIVE ` + ancestorsQuery + `
+	SELECT ` + rankRole + ` FROM (
+		SELECT 3 AS rank FROM files WHERE id = ?3 AND created_by = ?1
+		UNION ALL
+		SELECT 3 FROM ancestors WHERE created_by = ?1
+		UNION ALL
+		SELECT ` + roleRank + ` FROM file_acl acl WHERE acl.file_id = ?3 AND ` + memberGrant + `
+		UNION ALL
+		SELECT ` + roleRank + ` FROM file_acl acl JOIN ancestors a ON acl.folder_id = a.id WHERE ` + memberGrant + `
+	) AS grants
+	`
+
+	var folderID *int
+	if err := r.DB.QueryRowContext(ctx, `SELECT folder_id FROM files WHERE id = ?0 AND deleted_at IS NULL`, fileID).Scan(&folderID); err != nil {
+		return "", err
+	}
+
+	var role string
+	err := r.DB.QueryRowContext(ctx, selectQuery, folderID, userID, maxFolderDepth, fileID).Scan(&role)
+
+	return role, err
+}
+
+// AccessGrants returns the creator of the folder or the file, the grants on it and the ones inherited from the folders above it
+func (r *Repo) AccessGrants(ctx context.Context, target entity.AccessTarget) (entity.ListAccessResponse, error) {
+	var response entity.ListAccessResponse
+
+	ownerQuery := `
+	SELECT COALESCE((SELECT created_by FROM folders WHERE id = ?0), (SELECT created_by FROM files WHERE id = ?1))
+	`
+
+	if err := r.DB.QueryRowContext(ctx, ownerQuery, target.FolderID, target.FileID).Scan(&response.Owner); err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	grantQuery := `
+	SELECT id, folder_id, file_id, user_id, group_id, role, created_by, created_at
+	FROM file_acl
+	WHERE folder_id IS NOT DISTINCT FROM ?0 AND file_id IS NOT DISTINCT FROM ?1
+	ORDER BY id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, grantQuery, target.FolderID, target.FileID)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	response.Grants, err = scanGrants(rows)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	inheritedQuery := `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id, 0 AS depth FROM folders
+		WHERE id = COALESCE((SELECT parent_id FROM folders WHERE id = ?0), (SELECT folder_id FROM files WHERE id = ?1))
+		AND deleted_at IS NULL
+		UNION ALL
+		SELECT f.id, f.parent_id, a.depth + 1
+		FROM folders f
+		JOIN ancestors a ON f.id = a.parent_id
+		WHERE f.deleted_at IS NULL AND a.depth < ?2
+	)
+	SELECT acl.id, acl.folder_id, acl.file_id, acl.user_id, acl.group_id, acl.role, acl.created_by, acl.created_at
+	FROM file_acl acl
+	JOIN ancestors a ON acl.folder_id = a.id
+	ORDER BY a.depth, acl.id
+	`
+
+	rows, err = r.DB.QueryContext(ctx, inheritedQuery, target.FolderID, target.FileID, maxFolderDepth)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	response.Inherited, err = scanGrants(rows)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	return response, nil
+}
+
+// GrantAccess gives the role to the user or the chat group, replacing the role they held,
+// sql.ErrNoRows when the user or the group does not exist
+func (r *Repo) GrantAccess(ctx context.Context, request entity.GrantAccessRequest) (entity.AccessGrant, error) {
+	insertQuery := `
+	INSERT INTO file_acl (folder_id, file_id, user_id, group_id, role, created_by)
+	SELECT ?0, ?1, ?2, ?3, ?4, ?5
+	WHERE EXISTS (SELECT 1 FROM users WHERE id = ?2 AND deleted_at IS NULL)
+	OR EXISTS (SELECT 1 FROM groups WHERE id = ?3 AND deleted_at IS NULL)
+	ON CONFLICT ((COALESCE(folder_id, 0)), (COALESCE(file_id, 0)), (COALESCE(user_id, 0)), (COALESCE(group_id, 0)))
+	DO UPDATE SET role = EXCLUDED.role, created_by = EXCLUDED.created_by, created_at = NOW()
+	RETURNING id, folder_id, file_id, user_id, group_id, role, created_by, created_at
+	`
+
+	var grant entity.AccessGrant
+
+	err := r.DB.QueryRowContext(ctx, insertQuery, request.Target.FolderID, request.Target.FileID,
+		request.UserID, request.GroupID, request.Role, request.CreatedBy).Scan(
+		&grant.ID,
+		&grant.FolderID,
+		&grant.FileID,
+		&grant.UserID,
+		&grant.GroupID,
+		&grant.Role,
+		&grant.CreatedBy,
+		&grant.CreatedAt,
+	)
+	if err != nil {
+		return entity.AccessGrant{}, err
+	}
+
+	return grant, nil
+}
+
+// RevokeAccess removes a grant from the folder or the file, sql.ErrNoRows when it is not on it
+func (r *Repo) RevokeAccess(ctx context.Context, target entity.AccessTarget, grantID int) error {
+	deleteQuery := `
+	DELETE FROM file_acl
+	WHERE id = ?0 AND folder_id IS NOT DISTINCT FROM ?1 AND file_id IS NOT DISTINCT FROM ?2
+	`
+
+	result, err := r.DB.ExecContext(ctx, deleteQuery, grantID, target.FolderID, target.FileID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateShareLink stores a share link to the folder or the file
+func (r *Repo) CreateShareLink(ctx context.Context, link entity.ShareLink) (entity.ShareLink, error) {
+	insertQuery := `
+	INSERT INTO share_links (token, folder_id, file_id, password_hash, expires_at, created_by)
+	VALUES (?0, ?1, ?2, NULLIF(?3, ''), ?4, ?5)
+	RETURNING ` + shareLinkColumns
+
+	row := r.DB.QueryRowContext(ctx, insertQuery, link.Token, link.FolderID, link.FileID,
+		link.PasswordHash, link.ExpiresAt, link.CreatedBy)
+
+	return scanShareLink(row)
+}
+
+// ShareLinks returns the share links to the folder or the file that are neither revoked nor expired
+func (r *Repo) ShareLinks(ctx context.Context, target entity.AccessTarget) ([]*entity.ShareLink, error) {
+	selectQuery := `
+	SELECT ` + shareLinkColumns + `
+	FROM share_links
+	WHERE folder_id IS NOT DISTINCT FROM ?0 AND file_id IS NOT DISTINCT FROM ?1
+	AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	ORDER BY id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, target.FolderID, target.FileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*entity.ShareLink
+
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, &link)
+	}
+
+	return links, rows.Err()
+}
+
+// GetShareLink returns the share link with the token if it is neither revoked nor expired, sql.ErrNoRows otherwise
+func (r *Repo) GetShareLink(ctx context.Context, token string) (entity.ShareLink, error) {
+	selectQuery := `
+	SELECT ` + shareLinkColumns + `
+	FROM share_links
+	WHERE token = ?0 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`
+
+	return scanShareLink(r.DB.QueryRowContext(ctx, selectQuery, token))
+}
+
+// RevokeShareLink revokes a share link to the folder or the file, sql.ErrNoRows when it is not on it
+func (r *Repo) RevokeShareLink(ctx context.Context, target entity.AccessTarget, linkID, revokedBy int) error {
+	updateQuery := `
+	UPDATE share_links SET revoked_at = NOW(), revoked_by = ?3
+	WHERE id = ?0 AND folder_id IS NOT DISTINCT FROM ?1 AND file_id IS NOT DISTINCT FROM ?2 AND revoked_at IS NULL
+	`
+
+	result, err := r.DB.ExecContext(ctx, updateQuery, linkID, target.FolderID, target.FileID, revokedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// shareLinkColumns are the columns of ShareLink scanned by scanShareLink
+const shareLinkColumns = `id, token, folder_id, file_id, COALESCE(password_hash, ''), expires_at, created_by, created_at`
+
+func scanShareLink(row interface{ Scan(dest ...any) error }) (entity.ShareLink, error) {
+	var link entity.ShareLink
+
+	err := row.Scan(
+		&link.ID,
+		&link.Token,
+		&link.FolderID,
+		&link.FileID,
+		&link.PasswordHash,
+		&link.ExpiresAt,
+		&link.CreatedBy,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return entity.ShareLink{}, err
+	}
+
+	link.Protected = link.PasswordHash != ""
+
+	return link, nil
+}
+
+func scanGrants(rows *sql.Rows) ([]*entity.AccessGrant, error) {
+	defer rows.Close()
+
+	var grants []*entity.AccessGrant
+
+	for rows.Next() {
+		var grant entity.AccessGrant
+		err := rows.Scan(
+			&grant.ID,
+			&grant.FolderID,
+			&grant.FileID,
+			&grant.UserID,
+			&grant.GroupID,
+			&grant.Role,
+			&grant.CreatedBy,
+			&grant.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		grants = append(grants, &grant)
+	}
+
+	return grants, rows.Err()
+}
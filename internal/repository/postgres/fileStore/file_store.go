@@ -4,8 +4,10 @@ import (
 	"archv1/internal/entity"
 	"archv1/internal/pkg/repo/postgres"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/uptrace/bun"
 )
 
 type Repo struct {
@@ -18,22 +20,24 @@ func NewFileStoreRepo(DB *postgres.DB) FilesStoreRepository {
 	}
 }
 
-func (r *Repo) ListFolder(ctx context.Context, filter entity.Filter) (entity.ListFolderResponse, error) {
+// ListFolder lists the folders visible to the user, the ones they created or hold a role on with every folder below them
+func (r *Repo) ListFolder(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFolderResponse, error) {
 	offset := filter.Limit * (filter.Page - 1)
 	var response entity.ListFolderResponse
 
 	selectQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
 	SELECT 
-		id, 
-		name, 
-		parent_id
+		f.id, 
+		f.name, 
+		f.parent_id
 	FROM
-	    folders
+	    folders f
 	`
 
-	filterQuery := fmt.Sprintf(` WHERE deleted_at IS NULL LIMIT %d OFFSET %d`, filter.Limit, offset)
+	filterQuery := fmt.Sprintf(` WHERE f.deleted_at IS NULL AND `+visibleFolder+` ORDER BY f.id LIMIT %d OFFSET %d`, filter.Limit, offset)
 
-	rows, err := r.DB.QueryContext(ctx, selectQuery+filterQuery)
+	rows, err := r.DB.QueryContext(ctx, selectQuery+filterQuery, nil, access.UserID, maxFolderDepth, access.Admin)
 	if err != nil {
 		return entity.ListFolderResponse{}, err
 	}
@@ -57,9 +61,11 @@ func (r *Repo) ListFolder(ctx context.Context, filter entity.Filter) (entity.Lis
 		return entity.ListFolderResponse{}, err
 	}
 
-	countQuery := `SELECT COUNT(*) FROM folders WHERE deleted_at IS NULL`
+	countQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
+	SELECT COUNT(*) FROM folders f WHERE f.deleted_at IS NULL AND ` + visibleFolder
 
-	if err := r.DB.QueryRowContext(ctx, countQuery).Scan(&response.Total); err != nil {
+	if err := r.DB.QueryRowContext(ctx, countQuery, nil, access.UserID, maxFolderDepth, access.Admin).Scan(&response.Total); err != nil {
 		return entity.ListFolderResponse{}, err
 	}
 
@@ -112,96 +118,98 @@ func (r *Repo) CreateFolder(ctx context.Context, folder entity.CreateFolderReque
 }
 
 func (r *Repo) UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest) (entity.UpdateFolderResponse, error) {
-	var response entity.UpdateFolderResponse
-
-	err := r.DB.NewUpdate().
-		Table("folders").
-		Set("name = ?", folder.Name).
-		Set("parent_id = ?", folder.ParentID).
-		Set("updated_by = ?", folder.UpdatedBy).
-		Set("updated_at = NOW()").
-		Where("deleted_at IS NULL AND id = ?", folder.ID).
-		Returning("id, name, parent_id").
-		Scan(ctx, &response.ID, &response.Name, &response.ParentID)
-
-	if err != nil {
-		return entity.UpdateFolderResponse{}, err
-	}
-
-	return response, nil
+	return r.moveFolder(ctx, folder.ID, folder.ParentID, func(update *bun.UpdateQuery) *bun.UpdateQuery {
+		return update.
+			Set("name = ?", folder.Name).
+			Set("parent_id = ?", folder.ParentID).
+			Set("updated_by = ?", folder.UpdatedBy)
+	})
 }
 
 func (r *Repo) UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest) (entity.UpdateFolderResponse, error) {
-	var response entity.UpdateFolderResponse
-
-	updater := r.DB.NewUpdate().
-		Table("folders")
-
-	for key, value := range fields.Fields {
-		if key == "name" {
-			updater.Set(key+" = ?", value)
-		} else if key == "parent_id" {
-			updater.Set(key+" = ?", value)
-		} else if key == "updated_by" {
-			updater.Set(key+" = ?", value)
-		}
+	var parentID any
+	if value, ok := fields.Fields["parent_id"]; ok {
+		parentID = value
 	}
 
-	err := updater.Set("updated_at = NOW()").
-		Where("deleted_at IS NULL AND id = ?", fields.FolderID).
-		Returning("id, name, parent_id").
-		Scan(ctx, &response.ID, &response.Name, &response.ParentID)
-
-	if err != nil {
-		return entity.UpdateFolderResponse{}, err
-	}
+	return r.moveFolder(ctx, fields.FolderID, parentID, func(update *bun.UpdateQuery) *bun.UpdateQuery {
+		for key, value := range fields.Fields {
+			if key == "name" {
+				update.Set(key+" = ?", value)
+			} else if key == "parent_id" {
+				update.Set(key+" = ?", value)
+			} else if key == "updated_by" {
+				update.Set(key+" = ?", value)
+			}
+		}
 
-	return response, nil
+		return update
+	})
 }
 
+// DeleteFolder soft deletes the folder with its subfolders and their files at the same time,
+// so RestoreFolder can tell them from the ones deleted before
 func (r *Repo) DeleteFolder(ctx context.Context, folderID, deletedBy int) (entity.DeleteFolderResponse, error) {
-	result, err := r.DB.NewUpdate().
-		Table("folders").
-		Set("deleted_at = NOW()").
-		Set("deleted_by = ?", deletedBy).
-		Where("deleted_at IS NULL AND id = ?", folderID).
-		Exec(ctx)
+	deleteQuery := `
+	WITH RECURSIVE ` + subtreeQuery + `,
+	deleted_files AS (
+		UPDATE files SET deleted_at = NOW(), deleted_by = ?1
+		WHERE folder_id IN (SELECT id FROM subtree) AND deleted_at IS NULL
+		RETURNING id, created_by, size
+	),
+	usage AS (` + UsageQuery("deleted_files", -1) + `),
+	deleted_folders AS (
+		UPDATE folders SET deleted_at = NOW(), deleted_by = ?1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id
+	)
+	SELECT (SELECT COUNT(*) FROM deleted_folders), (SELECT COUNT(*) FROM deleted_files)
+	`
 
-	if err != nil {
-		return entity.DeleteFolderResponse{}, err
+	response := entity.DeleteFolderResponse{
+		Message: "success",
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	err := r.DB.QueryRowContext(ctx, deleteQuery, folderID, deletedBy, maxFolderDepth).Scan(&response.Folders, &response.Files)
 	if err != nil {
 		return entity.DeleteFolderResponse{}, err
 	}
 
-	if rowsAffected == 0 {
-		return entity.DeleteFolderResponse{}, errors.New("no rows affected")
+	if response.Folders == 0 {
+		return entity.DeleteFolderResponse{}, sql.ErrNoRows
 	}
 
-	return entity.DeleteFolderResponse{
-		Message: "success",
-	}, nil
+	return response, nil
 }
 
-func (r *Repo) ListFile(ctx context.Context, filter entity.Filter) (entity.ListFileResponse, error) {
+// ListFile lists the files visible to the user, their own, the ones they hold a role on, directly or through
+// a folder, and the ones attached to posts and menus
+func (r *Repo) ListFile(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFileResponse, error) {
 	offset := filter.Limit * (filter.Page - 1)
 	var response entity.ListFileResponse
 
 	selectQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
 	SELECT 
-		id, 
-		type, 
-		link,
-		folder_id
+		f.id, 
+		f.type, 
+		f.link,
+		f.folder_id,
+		f.name,
+		f.size,
+		f.mime_type,
+		f.checksum,
+		b.corrupted_at IS NOT NULL,
+		COALESCE(f.blob_key, ''),
+		f.created_by
 	FROM
-	    files
+	    files f
+	LEFT JOIN blobs b ON b.key = f.blob_key
 	`
 
-	filterQuery := fmt.Sprintf(` WHERE deleted_at IS NULL LIMIT %d OFFSET %d`, filter.Limit, offset)
+	filterQuery := fmt.Sprintf(` WHERE f.deleted_at IS NULL AND `+visibleFile+` ORDER BY f.id LIMIT %d OFFSET %d`, filter.Limit, offset)
 
-	rows, err := r.DB.QueryContext(ctx, selectQuery+filterQuery)
+	rows, err := r.DB.QueryContext(ctx, selectQuery+filterQuery, nil, access.UserID, maxFolderDepth, access.Admin)
 	if err != nil {
 		return entity.ListFileResponse{}, err
 	}
@@ -214,6 +222,13 @@ func (r *Repo) ListFile(ctx context.Context, filter entity.Filter) (entity.ListF
 			&file.Type,
 			&file.Link,
 			&file.FolderID,
+			&file.Name,
+			&file.Size,
+			&file.MimeType,
+			&file.Checksum,
+			&file.Corrupted,
+			&file.BlobKey,
+			&file.CreatedBy,
 		)
 		if err != nil {
 			return entity.ListFileResponse{}, err
@@ -226,9 +241,11 @@ func (r *Repo) ListFile(ctx context.Context, filter entity.Filter) (entity.ListF
 		return entity.ListFileResponse{}, err
 	}
 
-	countQuery := `SELECT COUNT(*) FROM files WHERE deleted_at IS NULL`
+	countQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
+	SELECT COUNT(*) FROM files f WHERE f.deleted_at IS NULL AND ` + visibleFile
 
-	if err := r.DB.QueryRowContext(ctx, countQuery).Scan(&response.Total); err != nil {
+	if err := r.DB.QueryRowContext(ctx, countQuery, nil, access.UserID, maxFolderDepth, access.Admin).Scan(&response.Total); err != nil {
 		return entity.ListFileResponse{}, err
 	}
 
@@ -240,21 +257,36 @@ func (r *Repo) GetFile(ctx context.Context, fileID int) (entity.GetFileResponse,
 
 	selectQuery := `
 	SELECT
-		id,
-		type,
-		link,
-		folder_id
+		f.id,
+		f.type,
+		f.link,
+		f.folder_id,
+		f.name,
+		f.size,
+		f.mime_type,
+		f.checksum,
+		b.corrupted_at IS NOT NULL,
+		COALESCE(f.blob_key, ''),
+		f.created_by
 	FROM
-	    files
+	    files f
+	LEFT JOIN blobs b ON b.key = f.blob_key
 	`
 
-	filterQuery := fmt.Sprintf(" WHERE id = %d AND deleted_at IS NULL", fileID)
+	filterQuery := fmt.Sprintf(" WHERE f.id = %d AND f.deleted_at IS NULL", fileID)
 
 	err := r.DB.QueryRowContext(ctx, selectQuery+filterQuery).Scan(
 		&response.ID,
 		&response.Type,
 		&response.Link,
 		&response.FolderID,
+		&response.Name,
+		&response.Size,
+		&response.MimeType,
+		&response.Checksum,
+		&response.Corrupted,
+		&response.BlobKey,
+		&response.CreatedBy,
 	)
 	if err != nil {
 		return entity.GetFileResponse{}, err
@@ -263,19 +295,46 @@ func (r *Repo) GetFile(ctx context.Context, fileID int) (entity.GetFileResponse,
 	return response, nil
 }
 
-func (r *Repo) CreateFile(ctx context.Context, file entity.CreateFileRequest) (entity.CreateFileResponse, error) {
+// CreateFile records the file and adds it to the usage of its creator. The usage is locked while check
+// looks at it, so files created at the same time by the same user are checked one after the other.
+func (r *Repo) CreateFile(ctx context.Context, file entity.CreateFileRequest, check func(usage entity.StorageUsage) error) (entity.CreateFileResponse, error) {
 	var response entity.CreateFileResponse
 
-	err := r.DB.NewInsert().
-		Model(&entity.Files{
-			Type:      file.Type,
-			Link:      file.Link,
-			FolderID:  file.FolderID,
-			CreatedBy: &file.CreatedBy,
-		}).
-		Returning("id, type, link, folder_id").
-		Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID)
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if check != nil {
+			usage, err := lockStorageUsage(ctx, tx, file.CreatedBy)
+			if err != nil {
+				return err
+			}
 
+			if err := check(usage); err != nil {
+				return err
+			}
+		}
+
+		err := tx.NewInsert().
+			Model(&entity.Files{
+				Type:      file.Type,
+				Link:      file.Link,
+				FolderID:  file.FolderID,
+				Name:      file.Name,
+				Size:      file.Size,
+				MimeType:  file.MimeType,
+				Checksum:  file.Checksum,
+				BlobKey:   file.BlobKey,
+				CreatedBy: &file.CreatedBy,
+			}).
+			Returning("id, type, link, folder_id, name, size, mime_type, checksum, created_by").
+			Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID,
+				&response.Name, &response.Size, &response.MimeType, &response.Checksum, &response.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, UsageQuery("(SELECT ?0::INT AS created_by, ?1::BIGINT AS size) created", 1), file.CreatedBy, file.Size)
+
+		return err
+	})
 	if err != nil {
 		return entity.CreateFileResponse{}, err
 	}
@@ -291,11 +350,13 @@ func (r *Repo) UpdateFile(ctx context.Context, file entity.UpdateFileRequest) (e
 		Set("type = ?", file.Type).
 		Set("link = ?", file.Link).
 		Set("folder_id = ?", file.FolderID).
+		Set("name = ?", file.Name).
 		Set("updated_by = ?", file.UpdatedBy).
 		Set("updated_at = NOW()").
 		Where("deleted_at IS NULL AND id = ?", file.ID).
-		Returning("id, type, link, folder_id").
-		Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID)
+		Returning("id, type, link, folder_id, name, size, mime_type, checksum, created_by").
+		Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID,
+			&response.Name, &response.Size, &response.MimeType, &response.Checksum, &response.CreatedBy)
 
 	if err != nil {
 		return entity.UpdateFileResponse{}, err
@@ -315,6 +376,8 @@ func (r *Repo) UpdateFileColumns(ctx context.Context, fields entity.UpdateFileCo
 			updater.Set(key+" = ?", value)
 		} else if key == "link" {
 			updater.Set(key+" = ?", value)
+		} else if key == "name" {
+			updater.Set(key+" = ?", value)
 		} else if key == "parent_id" {
 			updater.Set(key+" = ?", value)
 		} else if key == "updated_by" {
@@ -324,8 +387,9 @@ func (r *Repo) UpdateFileColumns(ctx context.Context, fields entity.UpdateFileCo
 
 	err := updater.Set("updated_at = NOW()").
 		Where("deleted_at IS NULL AND id = ?", fields.FileID).
-		Returning("id, type, link, folder_id").
-		Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID)
+		Returning("id, type, link, folder_id, name, size, mime_type, checksum, created_by").
+		Scan(ctx, &response.ID, &response.Type, &response.Link, &response.FolderID,
+			&response.Name, &response.Size, &response.MimeType, &response.Checksum, &response.CreatedBy)
 
 	if err != nil {
 		return entity.UpdateFileResponse{}, err
@@ -334,24 +398,29 @@ func (r *Repo) UpdateFileColumns(ctx context.Context, fields entity.UpdateFileCo
 	return response, nil
 }
 
+// DeleteFile soft deletes the file and takes it off the usage of its creator
 func (r *Repo) DeleteFile(ctx context.Context, fileID, deletedBy int) (entity.DeleteFileResponse, error) {
-	result, err := r.DB.NewUpdate().
-		Table("files").
-		Set("deleted_at = NOW()").
-		Set("deleted_by = ?", deletedBy).
-		Where("deleted_at IS NULL AND id = ?", fileID).
-		Exec(ctx)
-
-	if err != nil {
-		return entity.DeleteFileResponse{}, err
-	}
+	deleteQuery := `
+	WITH target AS (
+		SELECT id, blob_key FROM files WHERE deleted_at IS NULL AND id = ?0 FOR UPDATE
+	),
+	deleted_files AS (
+		UPDATE files f SET deleted_at = NOW(), deleted_by = ?1, purged_at = NOW(), blob_key = NULL
+		FROM target t
+		WHERE f.id = t.id
+		RETURNING f.id, f.created_by, f.size, t.blob_key
+	),
+	usage AS (` + UsageQuery("deleted_files", -1) + `),
+	released AS (` + ReleaseQuery("deleted_files") + `)
+	SELECT COUNT(*) FROM deleted_files
+	`
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	var deleted int
+	if err := r.DB.QueryRowContext(ctx, deleteQuery, fileID, deletedBy).Scan(&deleted); err != nil {
 		return entity.DeleteFileResponse{}, err
 	}
 
-	if rowsAffected == 0 {
+	if deleted == 0 {
 		return entity.DeleteFileResponse{}, errors.New("no rows affected")
 	}
 
@@ -0,0 +1,400 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+)
+
+// maxFolderDepth stops the recursive queries on folders that were nested in a loop before moves were checked
+const maxFolderDepth = 256
+
+// folderMoveLock is the advisory lock that serializes the moves of folders, see moveFolder
+const folderMoveLock = 4807
+
+// notBelowItself holds for the folder ?1 unless the new parent ?0 is the folder or one of the folders below it
+var notBelowItself = `NOT EXISTS (WITH RECURSIVE ` + ancestorsQuery + ` SELECT 1 FROM ancestors WHERE ancestors.id = ?1)`
+
+// subtreeQuery selects the folder ?0 with every folder below it that is not deleted, nearest first
+const subtreeQuery = `
+	subtree AS (
+		SELECT id, name, parent_id, 0 AS depth FROM folders WHERE id = ?0 AND deleted_at IS NULL
+		UNION ALL
+		SELECT f.id, f.name, f.parent_id, s.depth + 1
+		FROM folders f
+		JOIN subtree s ON f.parent_id = s.id
+		WHERE f.deleted_at IS NULL AND s.depth < ?2
+	)`
+
+// fileColumns are the columns of GetFileResponse, selected from files f joined with blobs b
+const fileColumns = `
+		f.id,
+		f.type,
+		f.link,
+		f.folder_id,
+		f.name,
+		f.size,
+		f.mime_type,
+		f.checksum,
+		b.corrupted_at IS NOT NULL,
+		COALESCE(f.blob_key, ''),
+		f.created_by`
+
+// FolderChildren returns the folders and files right inside the folder that are visible to the user, a nil folder is the root
+func (r *Repo) FolderChildren(ctx context.Context, folderID *int, access entity.FileAccess) (entity.FolderChildrenResponse, error) {
+	var response entity.FolderChildrenResponse
+
+	folderQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
+	SELECT f.id, f.name, f.parent_id
+	FROM folders f
+	WHERE f.parent_id IS NOT DISTINCT FROM ?0 AND f.deleted_at IS NULL AND ` + visibleFolder + `
+	ORDER BY f.name, f.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, folderQuery, folderID, access.UserID, maxFolderDepth, access.Admin)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	response.Folders, err = scanFolders(rows)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	fileQuery := `
+	WITH RECURSIVE ` + visibleQuery + `
+	SELECT ` + fileColumns + `
+	FROM files f
+	LEFT JOIN blobs b ON b.key = f.blob_key
+	WHERE f.folder_id IS NOT DISTINCT FROM ?0 AND f.chat_id IS NULL AND f.deleted_at IS NULL AND ` + visibleFile + `
+	ORDER BY f.name, f.id
+	`
+
+	rows, err = r.DB.QueryContext(ctx, fileQuery, folderID, access.UserID, maxFolderDepth, access.Admin)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	response.Files, err = scanFiles(rows)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	return response, nil
+}
+
+// FolderPath returns the folders from the root down to the folder, sql.ErrNoRows when it does not exist
+func (r *Repo) FolderPath(ctx context.Context, folderID int) ([]*entity.GetFolderResponse, error) {
+	selectQuery := `
+	WITH RECURSIVE path AS (
+		SELECT id, name, parent_id, 0 AS depth FROM folders WHERE id = ?0 AND deleted_at IS NULL
+		UNION ALL
+		SELECT f.id, f.name, f.parent_id, p.depth + 1
+		FROM folders f
+		JOIN path p ON f.id = p.parent_id
+		WHERE f.deleted_at IS NULL AND p.depth < ?1
+	)
+	SELECT id, name, parent_id FROM path ORDER BY depth DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, folderID, maxFolderDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := scanFolders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(path) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return path, nil
+}
+
+// FolderSubtree returns the folder with every folder below it, nearest first, and the files in them
+func (r *Repo) FolderSubtree(ctx context.Context, folderID int) (entity.FolderChildrenResponse, error) {
+	var response entity.FolderChildrenResponse
+
+	folderQuery := `
+	WITH RECURSIVE ` + subtreeQuery + `
+	SELECT id, name, parent_id FROM subtree ORDER BY depth, name, id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, folderQuery, folderID, nil, maxFolderDepth)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	response.Folders, err = scanFolders(rows)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	if len(response.Folders) == 0 {
+		return entity.FolderChildrenResponse{}, sql.ErrNoRows
+	}
+
+	folderIDs := make([]int, 0, len(response.Folders))
+	for _, folder := range response.Folders {
+		folderIDs = append(folderIDs, *folder.ID)
+	}
+
+	fileQuery := `
+	SELECT ` + fileColumns + `
+	FROM files f
+	LEFT JOIN blobs b ON b.key = f.blob_key
+	WHERE f.folder_id IN (?0) AND f.deleted_at IS NULL
+	ORDER BY f.name, f.id
+	`
+
+	rows, err = r.DB.QueryContext(ctx, fileQuery, bun.In(folderIDs))
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	response.Files, err = scanFiles(rows)
+	if err != nil {
+		return entity.FolderChildrenResponse{}, err
+	}
+
+	return response, nil
+}
+
+// MoveFolder puts the folder into another one or the root, renaming it when a name is given
+func (r *Repo) MoveFolder(ctx context.Context, request entity.MoveFolderRequest) (entity.UpdateFolderResponse, error) {
+	return r.moveFolder(ctx, request.FolderID, request.ParentID, func(update *bun.UpdateQuery) *bun.UpdateQuery {
+		return update.
+			Set("parent_id = ?", request.ParentID).
+			Set("name = COALESCE(NULLIF(?, ''), name)", request.Name).
+			Set("updated_by = ?", request.UpdatedBy)
+	})
+}
+
+// moveFolder runs the update of the folder unless it would put the folder under itself, sql.ErrNoRows tells the
+// folder is gone or the parent is below it. The moves hold folderMoveLock until they commit, so two moves can not
+// pass the check against each other and nest two folders in a loop.
+func (r *Repo) moveFolder(ctx context.Context, folderID, parentID any, set func(update *bun.UpdateQuery) *bun.UpdateQuery) (entity.UpdateFolderResponse, error) {
+	var response entity.UpdateFolderResponse
+
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(?0)`, folderMoveLock); err != nil {
+			return err
+		}
+
+		return set(tx.NewUpdate().Table("folders")).
+			Set("updated_at = NOW()").
+			Where("deleted_at IS NULL AND id = ?", folderID).
+			Where(notBelowItself, parentID, folderID, maxFolderDepth).
+			Returning("id, name, parent_id").
+			Scan(ctx, &response.ID, &response.Name, &response.ParentID)
+	})
+	if err != nil {
+		return entity.UpdateFolderResponse{}, err
+	}
+
+	return response, nil
+}
+
+// CopyFolder copies the folder with its subfolders and files. The copied files hold the same blobs,
+// so the references of the blobs grow by the number of copies, and count in full for the user copying them.
+func (r *Repo) CopyFolder(ctx context.Context, request entity.CopyFolderRequest) (entity.CreateFolderResponse, error) {
+	var response entity.CreateFolderResponse
+
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		rows, err := tx.QueryContext(ctx, `WITH RECURSIVE `+subtreeQuery+` SELECT id, name, parent_id FROM subtree ORDER BY depth`,
+			request.FolderID, nil, maxFolderDepth)
+		if err != nil {
+			return err
+		}
+
+		folders, err := scanFolders(rows)
+		if err != nil {
+			return err
+		}
+
+		if len(folders) == 0 {
+			return sql.ErrNoRows
+		}
+
+		insertFolderQuery := `
+		INSERT INTO folders (name, parent_id, created_by)
+		VALUES (?0, ?1, ?2)
+		RETURNING id
+		`
+
+		insertFilesQuery := `
+		INSERT INTO files (type, link, folder_id, name, size, mime_type, checksum, blob_key, created_by)
+		SELECT type, link, ?1, name, size, mime_type, checksum, blob_key, ?2
+		FROM files
+		WHERE folder_id = ?0 AND deleted_at IS NULL
+		`
+
+		// the parents are copied before their children, so the copy of every parent is known
+		copies := make(map[int]int, len(folders))
+		for i, folder := range folders {
+			name, parentID := folder.Name, request.ParentID
+			if i == 0 {
+				if request.Name != "" {
+					name = request.Name
+				}
+			} else {
+				copyID := copies[*folder.ParentID]
+				parentID = &copyID
+			}
+
+			var copyID int
+			if err := tx.QueryRowContext(ctx, insertFolderQuery, name, parentID, request.CreatedBy).Scan(&copyID); err != nil {
+				return err
+			}
+
+			copies[*folder.ID] = copyID
+
+			if _, err := tx.ExecContext(ctx, insertFilesQuery, *folder.ID, copyID, request.CreatedBy); err != nil {
+				return err
+			}
+
+			if i == 0 {
+				response.ID = &copyID
+				response.Name = name
+				response.ParentID = parentID
+			}
+		}
+
+		copyIDs := make([]int, 0, len(copies))
+		for _, copyID := range copies {
+			copyIDs = append(copyIDs, copyID)
+		}
+
+		refQuery := `
+		UPDATE blobs b
+		SET ref_count = b.ref_count + c.copies, released_at = NULL
+		FROM (
+			SELECT blob_key, COUNT(*) AS copies
+			FROM files
+			WHERE folder_id IN (?0) AND blob_key IS NOT NULL
+			GROUP BY blob_key
+		) c
+		WHERE b.key = c.blob_key
+		`
+
+		if _, err := tx.ExecContext(ctx, refQuery, bun.In(copyIDs)); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, UsageQuery("(SELECT created_by, size FROM files WHERE folder_id IN (?0)) copied", 1), bun.In(copyIDs))
+
+		return err
+	})
+	if err != nil {
+		return entity.CreateFolderResponse{}, err
+	}
+
+	return response, nil
+}
+
+// DeletedFolder returns a deleted folder, sql.ErrNoRows when it does not exist or is not deleted
+func (r *Repo) DeletedFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error) {
+	var response entity.GetFolderResponse
+
+	selectQuery := `SELECT id, name, parent_id FROM folders WHERE id = ?0 AND deleted_at IS NOT NULL`
+
+	err := r.DB.QueryRowContext(ctx, selectQuery, folderID).Scan(&response.ID, &response.Name, &response.ParentID)
+	if err != nil {
+		return entity.GetFolderResponse{}, err
+	}
+
+	return response, nil
+}
+
+// RestoreFolder restores the deleted folder with the subfolders and files deleted together with it,
+// the ones deleted on their own before and the ones purged since stay deleted
+func (r *Repo) RestoreFolder(ctx context.Context, folderID, restoredBy int) (entity.RestoreFolderResponse, error) {
+	restoreQuery := `
+	WITH RECURSIVE root AS (
+		SELECT deleted_at FROM folders WHERE id = ?0 AND deleted_at IS NOT NULL
+	),
+	subtree AS (
+		SELECT id, 0 AS depth FROM folders WHERE id = ?0 AND deleted_at IS NOT NULL
+		UNION ALL
+		SELECT f.id, s.depth + 1
+		FROM folders f
+		JOIN subtree s ON f.parent_id = s.id
+		WHERE f.deleted_at = (SELECT deleted_at FROM root) AND s.depth < ?2
+	),
+	restored_files AS (
+		UPDATE files SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW(), updated_by = ?1
+		WHERE folder_id IN (SELECT id FROM subtree) AND deleted_at = (SELECT deleted_at FROM root) AND purged_at IS NULL
+		RETURNING id, created_by, size
+	),
+	usage AS (` + UsageQuery("restored_files", 1) + `),
+	restored_folders AS (
+		UPDATE folders SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW(), updated_by = ?1
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING id
+	)
+	SELECT (SELECT COUNT(*) FROM restored_folders), (SELECT COUNT(*) FROM restored_files)
+	`
+
+	var response entity.RestoreFolderResponse
+
+	err := r.DB.QueryRowContext(ctx, restoreQuery, folderID, restoredBy, maxFolderDepth).Scan(&response.Folders, &response.Files)
+	if err != nil {
+		return entity.RestoreFolderResponse{}, err
+	}
+
+	if response.Folders == 0 {
+		return entity.RestoreFolderResponse{}, sql.ErrNoRows
+	}
+
+	return response, nil
+}
+
+func scanFolders(rows *sql.Rows) ([]*entity.GetFolderResponse, error) {
+	defer rows.Close()
+
+	var folders []*entity.GetFolderResponse
+	for rows.Next() {
+		var folder entity.GetFolderResponse
+		if err := rows.Scan(&folder.ID, &folder.Name, &folder.ParentID); err != nil {
+			return nil, err
+		}
+
+		folders = append(folders, &folder)
+	}
+
+	return folders, rows.Err()
+}
+
+func scanFiles(rows *sql.Rows) ([]*entity.GetFileResponse, error) {
+	defer rows.Close()
+
+	var files []*entity.GetFileResponse
+	for rows.Next() {
+		var file entity.GetFileResponse
+		err := rows.Scan(
+			&file.ID,
+			&file.Type,
+			&file.Link,
+			&file.FolderID,
+			&file.Name,
+			&file.Size,
+			&file.MimeType,
+			&file.Checksum,
+			&file.Corrupted,
+			&file.BlobKey,
+			&file.CreatedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, &file)
+	}
+
+	return files, rows.Err()
+}
@@ -0,0 +1,54 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestMoveFolderRefusesLoops(t *testing.T) {
+	r := newTestRepo(t)
+	ctx := context.Background()
+
+	user := testUser(t, r, "user")
+
+	top := testFolder(t, r, "top", nil, user)
+	mid := testFolder(t, r, "mid", &top, user)
+	leaf := testFolder(t, r, "leaf", &mid, user)
+
+	loops := []struct {
+		name   string
+		parent int
+	}{
+		{name: "into itself", parent: top},
+		{name: "into its child", parent: mid},
+		{name: "into a folder further below", parent: leaf},
+	}
+
+	for _, tt := range loops {
+		if _, err := r.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: top, ParentID: &tt.parent, UpdatedBy: user}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("MoveFolder %s = %v, want sql.ErrNoRows", tt.name, err)
+		}
+
+		_, err := r.UpdateFolderColumns(ctx, entity.UpdateFolderColumnsRequest{
+			FolderID: top,
+			Fields:   map[string]string{"parent_id": strconv.Itoa(tt.parent)},
+		})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateFolderColumns %s = %v, want sql.ErrNoRows", tt.name, err)
+		}
+	}
+
+	moved, err := r.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: leaf, UpdatedBy: user})
+	if err != nil || moved.ParentID != nil {
+		t.Fatalf("MoveFolder to the root = %+v, %v", moved, err)
+	}
+
+	moved, err = r.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: top, ParentID: &leaf, UpdatedBy: user})
+	if err != nil || moved.ParentID == nil || *moved.ParentID != leaf {
+		t.Fatalf("MoveFolder into a folder no longer below = %+v, %v", moved, err)
+	}
+}
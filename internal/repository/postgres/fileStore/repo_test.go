@@ -0,0 +1,220 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/repo/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newTestRepo migrates a schema of its own in the database TEST_DATABASE_URL points at and drops it when the
+// test ends, the tests are skipped without the variable
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("file_store_test_%d", time.Now().UnixNano())
+
+	admin := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
+	t.Cleanup(func() { _ = admin.Close() })
+
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("creating the schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_, _ = admin.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	sqlDB := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithDSN(dsn),
+		pgdriver.WithConnParams(map[string]interface{}{"search_path": schema}),
+	))
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	migrations, err := filepath.Glob("../../../migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("finding the migrations: %v", err)
+	}
+
+	sort.Strings(migrations)
+
+	for _, migration := range migrations {
+		body, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("reading %s: %v", migration, err)
+		}
+
+		if _, err := sqlDB.ExecContext(ctx, string(body)); err != nil {
+			t.Fatalf("running %s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return &Repo{
+		DB: &postgres.DB{DB: bun.NewDB(sqlDB, pgdialect.New())},
+	}
+}
+
+func insertID(t *testing.T, r *Repo, query string, args ...any) int {
+	t.Helper()
+
+	var id int
+	if err := r.DB.QueryRowContext(context.Background(), query+" RETURNING id", args...).Scan(&id); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+
+	return id
+}
+
+func testUser(t *testing.T, r *Repo, name string) int {
+	t.Helper()
+
+	return insertID(t, r, "INSERT INTO users (username, role) VALUES (?0, 'user')", name)
+}
+
+func testFolder(t *testing.T, r *Repo, name string, parentID *int, createdBy int) int {
+	t.Helper()
+
+	folder, err := r.CreateFolder(context.Background(), entity.CreateFolderRequest{Name: name, ParentID: parentID, CreatedBy: createdBy})
+	if err != nil {
+		t.Fatalf("creating the folder %s: %v", name, err)
+	}
+
+	return *folder.ID
+}
+
+func testFile(t *testing.T, r *Repo, name string, folderID *int, size int64, createdBy int) int {
+	t.Helper()
+
+	file, err := r.CreateFile(context.Background(), entity.CreateFileRequest{
+		Type:      "document",
+		Link:      name,
+		FolderID:  folderID,
+		Name:      name,
+		Size:      size,
+		CreatedBy: createdBy,
+	}, nil)
+	if err != nil {
+		t.Fatalf("creating the file %s: %v", name, err)
+	}
+
+	return file.ID
+}
+
+func grant(t *testing.T, r *Repo, target entity.AccessTarget, userID, groupID *int, role string, createdBy int) {
+	t.Helper()
+
+	_, err := r.GrantAccess(context.Background(), entity.GrantAccessRequest{
+		UserID:    userID,
+		GroupID:   groupID,
+		Role:      role,
+		Target:    target,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		t.Fatalf("granting %s: %v", role, err)
+	}
+}
+
+func TestRolesAreInheritedDownTheFolders(t *testing.T) {
+	r := newTestRepo(t)
+	ctx := context.Background()
+
+	owner := testUser(t, r, "owner")
+	editor := testUser(t, r, "editor")
+	member := testUser(t, r, "member")
+	stranger := testUser(t, r, "stranger")
+
+	group := insertID(t, r, "INSERT INTO groups (name, username, created_by) VALUES ('team', 'team', ?0)", owner)
+	membership := insertID(t, r, "INSERT INTO group_users (group_id, user_id) VALUES (?0, ?1)", group, member)
+
+	root := testFolder(t, r, "root", nil, owner)
+	mid := testFolder(t, r, "mid", &root, owner)
+	leaf := testFolder(t, r, "leaf", &mid, owner)
+	file := testFile(t, r, "report.pdf", &leaf, 10, owner)
+
+	grant(t, r, entity.AccessTarget{FolderID: &mid}, &editor, nil, entity.FileRoleEditor, owner)
+	grant(t, r, entity.AccessTarget{FolderID: &root}, nil, &group, entity.FileRoleViewer, owner)
+	grant(t, r, entity.AccessTarget{FolderID: &leaf}, &member, nil, entity.FileRoleEditor, owner)
+	grant(t, r, entity.AccessTarget{FileID: &file}, &stranger, nil, entity.FileRoleViewer, owner)
+
+	folderRoles := []struct {
+		name   string
+		folder int
+		user   int
+		want   string
+	}{
+		{name: "the creator owns the folder", folder: root, user: owner, want: entity.FileRoleOwner},
+		{name: "the creator owns the folders below", folder: leaf, user: owner, want: entity.FileRoleOwner},
+		{name: "a grant does not reach up", folder: root, user: editor, want: ""},
+		{name: "a grant holds on its folder", folder: mid, user: editor, want: entity.FileRoleEditor},
+		{name: "a grant reaches down", folder: leaf, user: editor, want: entity.FileRoleEditor},
+		{name: "a group grant holds for its members", folder: root, user: member, want: entity.FileRoleViewer},
+		{name: "a group grant reaches down", folder: mid, user: member, want: entity.FileRoleViewer},
+		{name: "the strongest role wins", folder: leaf, user: member, want: entity.FileRoleEditor},
+		{name: "a file grant gives no folder role", folder: leaf, user: stranger, want: ""},
+	}
+
+	for _, tt := range folderRoles {
+		role, err := r.FolderRole(ctx, tt.folder, tt.user)
+		if err != nil || role != tt.want {
+			t.Errorf("%s: FolderRole = %q, %v, want %q", tt.name, role, err, tt.want)
+		}
+	}
+
+	fileRoles := map[int]string{
+		owner:    entity.FileRoleOwner,
+		editor:   entity.FileRoleEditor,
+		member:   entity.FileRoleEditor,
+		stranger: entity.FileRoleViewer,
+	}
+
+	for user, want := range fileRoles {
+		if role, err := r.FileRole(ctx, file, user); err != nil || role != want {
+			t.Errorf("FileRole of user %d = %q, %v, want %q", user, role, err, want)
+		}
+	}
+
+	if _, err := r.FolderRole(ctx, leaf+100, owner); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FolderRole of a missing folder = %v, want sql.ErrNoRows", err)
+	}
+
+	if _, err := r.DB.ExecContext(ctx, "UPDATE group_users SET deleted_at = NOW() WHERE id = ?0", membership); err != nil {
+		t.Fatalf("leaving the group: %v", err)
+	}
+
+	if role, err := r.FolderRole(ctx, mid, member); err != nil || role != "" {
+		t.Errorf("FolderRole after leaving the group = %q, %v, want no role", role, err)
+	}
+
+	if _, err := r.DeleteFolder(ctx, mid, owner); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+
+	if _, err := r.FolderRole(ctx, mid, editor); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FolderRole of a deleted folder = %v, want sql.ErrNoRows", err)
+	}
+
+	if role, err := r.DeletedFolderRole(ctx, mid, editor); err != nil || role != entity.FileRoleEditor {
+		t.Errorf("DeletedFolderRole = %q, %v, want the editor role held before the delete", role, err)
+	}
+
+	if _, err := r.DeletedFolderRole(ctx, root, owner); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("DeletedFolderRole of a live folder = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,152 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/uptrace/bun"
+)
+
+const storageUsageColumns = `
+	u.id,
+	COALESCE(u.username, ''),
+	COALESCE(u.role, ''),
+	COALESCE(s.used_bytes, 0),
+	COALESCE(s.file_count, 0),
+	s.quota_bytes,
+	COALESCE(s.updated_at, u.created_at)
+`
+
+// UsageQuery adds the files of changed, rows with created_by and size, sign times to the usage of their creators
+func UsageQuery(changed string, sign int) string {
+	return fmt.Sprintf(`
+	INSERT INTO user_storage (user_id, used_bytes, file_count)
+	SELECT created_by, %[2]d * COALESCE(SUM(size), 0), %[2]d * COUNT(*)
+	FROM %[1]s
+	WHERE created_by IS NOT NULL
+	GROUP BY created_by
+	ON CONFLICT (user_id) DO UPDATE SET
+		used_bytes = user_storage.used_bytes + EXCLUDED.used_bytes,
+		file_count = user_storage.file_count + EXCLUDED.file_count,
+		updated_at = NOW()
+	`, changed, sign)
+}
+
+// StorageUsage returns what the user keeps in the file store with the quota set on them, sql.ErrNoRows when the user does not exist
+func (r *Repo) StorageUsage(ctx context.Context, userID int) (entity.StorageUsage, error) {
+	selectQuery := `
+	SELECT ` + storageUsageColumns + `
+	FROM users u
+	LEFT JOIN user_storage s ON s.user_id = u.id
+	WHERE u.id = ?0 AND u.deleted_at IS NULL
+	`
+
+	return scanStorageUsage(r.DB.QueryRowContext(ctx, selectQuery, userID))
+}
+
+// StorageReport returns the limit users keeping the most bytes and the totals of every user
+func (r *Repo) StorageReport(ctx context.Context, limit int) (entity.StorageReportResponse, error) {
+	response := entity.StorageReportResponse{
+		Users: []*entity.StorageUsage{},
+	}
+
+	totalQuery := `SELECT COALESCE(SUM(used_bytes), 0), COALESCE(SUM(file_count), 0) FROM user_storage`
+
+	if err := r.DB.QueryRowContext(ctx, totalQuery).Scan(&response.TotalBytes, &response.TotalFiles); err != nil {
+		return entity.StorageReportResponse{}, err
+	}
+
+	selectQuery := `
+	SELECT ` + storageUsageColumns + `
+	FROM user_storage s
+	JOIN users u ON u.id = s.user_id
+	WHERE u.deleted_at IS NULL AND s.used_bytes > 0
+	ORDER BY s.used_bytes DESC, u.id
+	LIMIT ?0
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, limit)
+	if err != nil {
+		return entity.StorageReportResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		usage, err := scanStorageUsage(rows)
+		if err != nil {
+			return entity.StorageReportResponse{}, err
+		}
+
+		response.Users = append(response.Users, &usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.StorageReportResponse{}, err
+	}
+
+	return response, nil
+}
+
+// SetStorageQuota sets the quota of the user, a nil quota drops it. sql.ErrNoRows when the user does not exist.
+func (r *Repo) SetStorageQuota(ctx context.Context, request entity.SetStorageQuotaRequest) error {
+	upsertQuery := `
+	INSERT INTO user_storage (user_id, quota_bytes, updated_by)
+	SELECT id, ?1, ?2
+	FROM users
+	WHERE id = ?0 AND deleted_at IS NULL
+	ON CONFLICT (user_id) DO UPDATE SET quota_bytes = EXCLUDED.quota_bytes, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+	`
+
+	result, err := r.DB.ExecContext(ctx, upsertQuery, request.UserID, request.Quota, request.UpdatedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// lockStorageUsage returns the usage of the user and holds it until the transaction ends
+func lockStorageUsage(ctx context.Context, tx bun.Tx, userID int) (entity.StorageUsage, error) {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO user_storage (user_id) VALUES (?0) ON CONFLICT DO NOTHING`, userID); err != nil {
+		return entity.StorageUsage{}, err
+	}
+
+	selectQuery := `
+	SELECT ` + storageUsageColumns + `
+	FROM user_storage s
+	JOIN users u ON u.id = s.user_id
+	WHERE s.user_id = ?0
+	FOR UPDATE OF s
+	`
+
+	return scanStorageUsage(tx.QueryRowContext(ctx, selectQuery, userID))
+}
+
+func scanStorageUsage(row interface{ Scan(dest ...any) error }) (entity.StorageUsage, error) {
+	var usage entity.StorageUsage
+
+	err := row.Scan(
+		&usage.UserID,
+		&usage.Username,
+		&usage.Role,
+		&usage.UsedBytes,
+		&usage.FileCount,
+		&usage.UserQuota,
+		&usage.UpdatedAt,
+	)
+	if err != nil {
+		return entity.StorageUsage{}, err
+	}
+
+	return usage, nil
+}
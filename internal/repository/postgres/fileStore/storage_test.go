@@ -0,0 +1,105 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStorageUsageFollowsTheFiles(t *testing.T) {
+	r := newTestRepo(t)
+	ctx := context.Background()
+
+	user := testUser(t, r, "user")
+
+	usage := func(wantBytes int64, wantFiles int) {
+		t.Helper()
+
+		got, err := r.StorageUsage(ctx, user)
+		if err != nil {
+			t.Fatalf("StorageUsage: %v", err)
+		}
+
+		if got.UsedBytes != wantBytes || got.FileCount != wantFiles {
+			t.Fatalf("the user keeps %d bytes in %d files, want %d bytes in %d files", got.UsedBytes, got.FileCount, wantBytes, wantFiles)
+		}
+	}
+
+	usage(0, 0)
+
+	folder := testFolder(t, r, "photos", nil, user)
+	sub := testFolder(t, r, "2024", &folder, user)
+
+	testFile(t, r, "a.jpg", &folder, 100, user)
+	single := testFile(t, r, "b.jpg", &sub, 50, user)
+	loose := testFile(t, r, "c.txt", nil, 25, user)
+	usage(175, 3)
+
+	refused := errors.New("over quota")
+
+	var checked entity.StorageUsage
+	_, err := r.CreateFile(ctx, entity.CreateFileRequest{Type: "document", Link: "d.txt", Name: "d.txt", Size: 1000, CreatedBy: user},
+		func(usage entity.StorageUsage) error {
+			checked = usage
+			return refused
+		})
+	if !errors.Is(err, refused) {
+		t.Fatalf("CreateFile with a failing check = %v, want its error", err)
+	}
+
+	if checked.UsedBytes != 175 {
+		t.Errorf("the check saw %d bytes, want the 175 kept", checked.UsedBytes)
+	}
+
+	usage(175, 3)
+
+	if _, err := r.DeleteFile(ctx, single, user); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+
+	usage(125, 2)
+
+	if _, err := r.DeleteFolder(ctx, folder, user); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+
+	usage(25, 1)
+
+	restored, err := r.RestoreFolder(ctx, folder, user)
+	if err != nil {
+		t.Fatalf("RestoreFolder: %v", err)
+	}
+
+	if restored.Folders != 2 || restored.Files != 1 {
+		t.Errorf("RestoreFolder brought back %d folders and %d files, want 2 and the file deleted with the folder", restored.Folders, restored.Files)
+	}
+
+	usage(125, 2)
+
+	if _, err := r.DeleteFolder(ctx, folder, user); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+
+	if _, err := r.PurgeDeletedFiles(ctx, time.Now().Add(24*time.Hour), 100); err != nil {
+		t.Fatalf("PurgeDeletedFiles: %v", err)
+	}
+
+	restored, err = r.RestoreFolder(ctx, folder, user)
+	if err != nil {
+		t.Fatalf("RestoreFolder: %v", err)
+	}
+
+	if restored.Files != 0 {
+		t.Errorf("RestoreFolder brought back %d purged files", restored.Files)
+	}
+
+	usage(25, 1)
+
+	if _, err := r.DeleteFile(ctx, loose, user); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+
+	usage(0, 0)
+}
@@ -0,0 +1,232 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+func (r *Repo) CreateUploadSession(ctx context.Context, session entity.UploadSession) (entity.UploadSession, error) {
+	insertQuery := `
+	INSERT INTO upload_sessions (id, user_id, name, size, folder_id, expires_at)
+	VALUES (?0, ?1, ?2, ?3, ?4, ?5)
+	RETURNING id, user_id, name, size, received, folder_id, status, created_at, expires_at
+	`
+
+	rows, err := r.DB.QueryContext(ctx, insertQuery,
+		session.ID, session.UserID, session.Name, session.Size, session.FolderID, session.ExpiresAt)
+	if err != nil {
+		return entity.UploadSession{}, err
+	}
+	defer rows.Close()
+
+	return scanUploadSession(rows)
+}
+
+func (r *Repo) GetUploadSession(ctx context.Context, sessionID string) (entity.UploadSession, error) {
+	selectQuery := `
+	SELECT id, user_id, name, size, received, folder_id, status, created_at, expires_at
+	FROM upload_sessions
+	WHERE id = ?0 AND expires_at > NOW()
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, sessionID)
+	if err != nil {
+		return entity.UploadSession{}, err
+	}
+	defer rows.Close()
+
+	return scanUploadSession(rows)
+}
+
+// AddUploadChunk records a stored chunk and moves the session forward, it fails with
+// sql.ErrNoRows when the session is not open or has moved past the position of the chunk
+func (r *Repo) AddUploadChunk(ctx context.Context, chunk entity.UploadChunk, expiresAt time.Time) (entity.UploadSession, error) {
+	var session entity.UploadSession
+
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		updateQuery := `
+		UPDATE upload_sessions
+		SET received = received + ?2, expires_at = ?3
+		WHERE id = ?0 AND received = ?1 AND status = 'open' AND expires_at > NOW()
+		RETURNING id, user_id, name, size, received, folder_id, status, created_at, expires_at
+		`
+
+		rows, err := tx.QueryContext(ctx, updateQuery, chunk.SessionID, chunk.Position, chunk.Size, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		session, err = scanUploadSession(rows)
+		_ = rows.Close()
+		if err != nil {
+			return err
+		}
+
+		insertQuery := `
+		INSERT INTO upload_chunks (session_id, position, size, checksum, blob_key)
+		VALUES (?0, ?1, ?2, ?3, ?4)
+		`
+
+		_, err = tx.ExecContext(ctx, insertQuery, chunk.SessionID, chunk.Position, chunk.Size, chunk.Checksum, chunk.BlobKey)
+
+		return err
+	})
+	if err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	return session, nil
+}
+
+func (r *Repo) UploadChunks(ctx context.Context, sessionID string) ([]entity.UploadChunk, error) {
+	selectQuery := `
+	SELECT session_id, position, size, checksum, blob_key
+	FROM upload_chunks
+	WHERE session_id = ?0
+	ORDER BY position
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []entity.UploadChunk
+	for rows.Next() {
+		var chunk entity.UploadChunk
+		err = rows.Scan(&chunk.SessionID, &chunk.Position, &chunk.Size, &chunk.Checksum, &chunk.BlobKey)
+		if err != nil {
+			return nil, err
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return chunks, nil
+}
+
+// SetUploadStatus moves the session from one status to another, sql.ErrNoRows means it was not in the first
+func (r *Repo) SetUploadStatus(ctx context.Context, sessionID, from, to string) error {
+	result, err := r.DB.ExecContext(ctx, `UPDATE upload_sessions SET status = ?2 WHERE id = ?0 AND status = ?1`, sessionID, from, to)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteUploadSession removes the session with its chunks, the chunk blobs are left to the caller
+func (r *Repo) DeleteUploadSession(ctx context.Context, sessionID string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM upload_sessions WHERE id = ?0`, sessionID)
+
+	return err
+}
+
+func (r *Repo) ExpiredUploadSessions(ctx context.Context, limit int) ([]entity.UploadSession, error) {
+	selectQuery := `
+	SELECT id, user_id, name, size, received, folder_id, status, created_at, expires_at
+	FROM upload_sessions
+	WHERE expires_at <= NOW()
+	ORDER BY expires_at
+	LIMIT ?0
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []entity.UploadSession
+	for rows.Next() {
+		session, err := scanUploadSessionRow(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// ExistingUploadSessions returns which of the sessions still exist
+func (r *Repo) ExistingUploadSessions(ctx context.Context, sessionIDs []string) ([]string, error) {
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT id FROM upload_sessions WHERE id::text IN (?0)`, bun.In(sessionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+
+		existing = append(existing, sessionID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// scanUploadSession reads a single session, sql.ErrNoRows when there is none
+func scanUploadSession(rows *sql.Rows) (entity.UploadSession, error) {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return entity.UploadSession{}, err
+		}
+
+		return entity.UploadSession{}, sql.ErrNoRows
+	}
+
+	return scanUploadSessionRow(rows)
+}
+
+func scanUploadSessionRow(rows *sql.Rows) (entity.UploadSession, error) {
+	var session entity.UploadSession
+
+	err := rows.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Name,
+		&session.Size,
+		&session.Received,
+		&session.FolderID,
+		&session.Status,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+
+	return session, err
+}
@@ -8,8 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/lib/pq"
 	_ "github.com/lib/pq"
+	"github.com/uptrace/bun"
 )
 
 type Repo struct {
@@ -173,8 +173,7 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 	    parent_id, 
 	    slug, 
 	    path,
-	    status,
-	    files
+	    status
 	FROM menus`, lang, lang)
 
 	whereQuery := ` WHERE deleted_at IS NULL AND status = TRUE`
@@ -191,7 +190,6 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 		var (
 			title   sql.NullString
 			content sql.NullString
-			files   pq.StringArray
 			menu    entity.GetMenuResponse
 		)
 		err := rows.Scan(
@@ -204,7 +202,6 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 			&menu.Slug,
 			&menu.Path,
 			&menu.Status,
-			&files,
 		)
 		if err != nil {
 			return entity.ListMenuResponse{}, err
@@ -217,8 +214,6 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 			menu.Content = map[string]string{lang: content.String}
 		}
 
-		menu.Files = files
-
 		response.Menus = append(response.Menus, &menu)
 	}
 
@@ -226,6 +221,20 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 		return entity.ListMenuResponse{}, err
 	}
 
+	menuIDs := make([]int, 0, len(response.Menus))
+	for _, menu := range response.Menus {
+		menuIDs = append(menuIDs, menu.ID)
+	}
+
+	files, err := r.attachedFiles(ctx, menuIDs)
+	if err != nil {
+		return entity.ListMenuResponse{}, err
+	}
+
+	for _, menu := range response.Menus {
+		menu.Files = files[menu.ID]
+	}
+
 	totalQuery := `SELECT COUNT(*) FROM menus WHERE deleted_at IS NULL AND status = TRUE`
 	if err := r.DB.QueryRowContext(ctx, totalQuery).Scan(&response.Total); err != nil {
 		return entity.ListMenuResponse{}, err
@@ -251,13 +260,11 @@ func (r *Repo) GetByID(ctx context.Context, menuID int, lang string) (entity.Get
 	    parent_id, 
 	    slug, 
 	    path,
-	    status,
-	    files
+	    status
 	FROM menus`, lang, lang)
 
 	whereQuery := ` WHERE deleted_at IS NULL AND status = TRUE AND id = ?`
 
-	var files pq.StringArray
 	err := r.DB.QueryRowContext(ctx, selectQuery+whereQuery, menuID).Scan(
 		&response.ID,
 		&title,
@@ -268,13 +275,17 @@ func (r *Repo) GetByID(ctx context.Context, menuID int, lang string) (entity.Get
 		&response.Slug,
 		&response.Path,
 		&response.Status,
-		&files,
 	)
 	if err != nil {
 		return entity.GetMenuResponse{}, err
 	}
 
-	response.Files = files
+	files, err := r.attachedFiles(ctx, []int{response.ID})
+	if err != nil {
+		return entity.GetMenuResponse{}, err
+	}
+
+	response.Files = files[response.ID]
 
 	if content.Valid {
 		response.Content = map[string]string{lang: content.String}
@@ -476,16 +487,35 @@ func (r *Repo) Delete(ctx context.Context, menuID, deletedBy int) (entity.Delete
 	}, nil
 }
 
-func (r *Repo) AddFile(ctx context.Context, fileURL string, menuID, updatedBy int) error {
-	updateQuery := fmt.Sprintf(`
-	UPDATE menus 
-	SET 
-	    files = array_append(files, '%v'),
-	    updated_by = '%d'
-	WHERE deleted_at IS NULL AND status = TRUE AND id = '%d'
-	`, fileURL, updatedBy, menuID)
+// AttachFile links a stored file to the menu, attaching it again is not an error
+func (r *Repo) AttachFile(ctx context.Context, menuID, fileID, attachedBy int) error {
+	insertQuery := `
+	INSERT INTO menu_files (menu_id, file_id, created_by)
+	SELECT m.id, f.id, ?2
+	FROM menus m, files f
+	WHERE m.id = ?0 AND m.deleted_at IS NULL AND f.id = ?1 AND f.deleted_at IS NULL
+	ON CONFLICT (menu_id, file_id) DO UPDATE SET menu_id = EXCLUDED.menu_id
+	`
+
+	result, err := r.DB.ExecContext(ctx, insertQuery, menuID, fileID, attachedBy)
+	if err != nil {
+		return err
+	}
 
-	result, err := r.DB.ExecContext(ctx, updateQuery)
+	rowEffects, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowEffects == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *Repo) DetachFile(ctx context.Context, menuID, fileID int) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM menu_files WHERE menu_id = ?0 AND file_id = ?1`, menuID, fileID)
 	if err != nil {
 		return err
 	}
@@ -496,8 +526,55 @@ func (r *Repo) AddFile(ctx context.Context, fileURL string, menuID, updatedBy in
 	}
 
 	if rowEffects == 0 {
-		return errors.New("menu not found for append file")
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
+
+// attachedFiles loads the files of the menus in attach order, deleted files are left out
+func (r *Repo) attachedFiles(ctx context.Context, menuIDs []int) (map[int][]entity.AttachedFile, error) {
+	files := make(map[int][]entity.AttachedFile, len(menuIDs))
+	if len(menuIDs) == 0 {
+		return files, nil
+	}
+
+	selectQuery := `
+	SELECT
+		mf.menu_id,
+		f.id,
+		f.name,
+		f.link,
+		f.mime_type,
+		f.size
+	FROM menu_files mf
+	JOIN files f ON f.id = mf.file_id AND f.deleted_at IS NULL
+	WHERE mf.menu_id IN (?0)
+	ORDER BY mf.created_at, f.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, bun.In(menuIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			menuID int
+			file   entity.AttachedFile
+		)
+		err = rows.Scan(&menuID, &file.ID, &file.Name, &file.Link, &file.MimeType, &file.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		files[menuID] = append(files[menuID], file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,15 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"context"
+)
+
+type NotificationRepository interface {
+	Notifications(ctx context.Context, userID int64) (entity.NotificationsResponse, error)
+	AddMessage(ctx context.Context, userIDs []int, message entity.MessageEvent) error
+	UpdateMessage(ctx context.Context, messageID int64, content string) error
+	RemoveMessage(ctx context.Context, chatID, messageID int64) error
+	MarkRead(ctx context.Context, userID, chatID, lastRead int64) error
+	MuteChat(ctx context.Context, request entity.MuteChatRequest) (entity.MuteSetting, error)
+}
@@ -0,0 +1,204 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/repo/postgres"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type RepoNotification struct {
+	DB *postgres.DB
+}
+
+func NewNotificationRepo(DB *postgres.DB) NotificationRepository {
+	return &RepoNotification{
+		DB: DB,
+	}
+}
+
+// mutedCondition tells whether the notifications of the row n are muted now
+const mutedCondition = `(n.muted AND (n.muted_until IS NULL OR n.muted_until > NOW()))`
+
+// Notifications returns the inbox of the user, the chats with unread messages
+func (r *RepoNotification) Notifications(ctx context.Context, userID int64) (entity.NotificationsResponse, error) {
+	query := `
+	SELECT
+		n.chat_id,
+		c.chat_type,
+		COALESCE(n.latest_message_id, 0),
+		COALESCE(n.latest_sender, 0),
+		n.latest_message,
+		n.unread_count,
+		` + mutedCondition + `,
+		n.muted_until,
+		n.updated_at
+	FROM notifications AS n
+	INNER JOIN chat AS c ON c.id = n.chat_id AND c.deleted_at IS NULL
+	WHERE n.user_id = ?0 AND n.unread_count > 0
+	ORDER BY n.updated_at DESC`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return entity.NotificationsResponse{}, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var response entity.NotificationsResponse
+	for rows.Next() {
+		var (
+			mutedUntil   sql.NullTime
+			notification entity.Notification
+		)
+
+		err := rows.Scan(
+			&notification.ChatID,
+			&notification.ChatType,
+			&notification.LatestMessageID,
+			&notification.LatestSender,
+			&notification.LatestMessage,
+			&notification.TotalMessagesCount,
+			&notification.Muted,
+			&mutedUntil,
+			&notification.UpdatedAt,
+		)
+		if err != nil {
+			return entity.NotificationsResponse{}, err
+		}
+
+		if notification.Muted && mutedUntil.Valid {
+			notification.MutedUntil = &mutedUntil.Time
+		}
+
+		if !notification.Muted {
+			response.UnreadCount += notification.TotalMessagesCount
+		}
+
+		response.Notifications = append(response.Notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.NotificationsResponse{}, err
+	}
+
+	return response, nil
+}
+
+// AddMessage counts the message as unread for the users and makes it the latest message of their chat entry
+func (r *RepoNotification) AddMessage(ctx context.Context, userIDs []int, message entity.MessageEvent) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO notifications (user_id, chat_id, unread_count, latest_message_id, latest_sender, latest_message)
+	SELECT u.id, ?1, 1, ?2, ?3, ?4 FROM UNNEST(ARRAY[?0]::INT[]) AS u(id)
+	ON CONFLICT (user_id, chat_id) DO UPDATE SET
+		unread_count = notifications.unread_count + 1,
+		latest_message_id = EXCLUDED.latest_message_id,
+		latest_sender = EXCLUDED.latest_sender,
+		latest_message = EXCLUDED.latest_message,
+		updated_at = NOW()`
+
+	_, err := r.DB.ExecContext(ctx, query, bun.In(userIDs), message.ChatID, message.MessageID, message.Sender, message.Content)
+
+	return err
+}
+
+// UpdateMessage refreshes the inbox entries showing the edited message
+func (r *RepoNotification) UpdateMessage(ctx context.Context, messageID int64, content string) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE notifications SET latest_message = ?1 WHERE latest_message_id = ?0`, messageID, content)
+
+	return err
+}
+
+// RemoveMessage uncounts a message deleted for everyone from the users who had not read it,
+// and replaces it in the entries showing it with the latest remaining message of the chat
+func (r *RepoNotification) RemoveMessage(ctx context.Context, chatID, messageID int64) error {
+	uncount := `
+	UPDATE notifications AS n SET unread_count = n.unread_count - 1
+	FROM messages AS m
+	WHERE m.id = ?1 AND n.chat_id = ?0 AND n.unread_count > 0 AND n.user_id <> m.sender
+	AND NOT EXISTS (
+		SELECT 1 FROM chat_members AS cm
+		WHERE cm.chat_id = n.chat_id AND cm.user_id = n.user_id AND cm.last_read_message_id >= m.id
+	)`
+
+	replace := `
+	UPDATE notifications AS n SET
+		latest_message_id = latest.id,
+		latest_sender = latest.sender,
+		latest_message = COALESCE(latest.content, '')
+	FROM (SELECT 1) AS one
+	LEFT JOIN LATERAL (
+		SELECT m.id, m.sender, m.content FROM messages AS m
+		WHERE m.chat_id = ?0 AND m.deleted_at IS NULL
+		ORDER BY m.id DESC
+		LIMIT 1
+	) AS latest ON TRUE
+	WHERE n.chat_id = ?0 AND n.latest_message_id = ?1`
+
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, uncount, chatID, messageID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, replace, chatID, messageID)
+
+		return err
+	})
+}
+
+// MarkRead recounts the unread messages of the chat for the user after the read cursor moved to lastRead
+func (r *RepoNotification) MarkRead(ctx context.Context, userID, chatID, lastRead int64) error {
+	query := `
+	UPDATE notifications SET unread_count = (
+		SELECT COUNT(*) FROM messages AS m
+		WHERE m.chat_id = ?1 AND m.id > ?2 AND m.sender <> ?0 AND m.deleted_at IS NULL
+	)
+	WHERE user_id = ?0 AND chat_id = ?1`
+
+	_, err := r.DB.ExecContext(ctx, query, userID, chatID, lastRead)
+
+	return err
+}
+
+func (r *RepoNotification) MuteChat(ctx context.Context, request entity.MuteChatRequest) (entity.MuteSetting, error) {
+	var mutedUntil sql.NullTime
+	if request.Muted && request.Duration > 0 {
+		mutedUntil = sql.NullTime{Time: time.Now().Add(time.Duration(request.Duration) * time.Second), Valid: true}
+	}
+
+	query := `
+	INSERT INTO notifications (user_id, chat_id, muted, muted_until) VALUES (?0, ?1, ?2, ?3)
+	ON CONFLICT (user_id, chat_id) DO UPDATE SET muted = EXCLUDED.muted, muted_until = EXCLUDED.muted_until
+	RETURNING chat_id, muted, muted_until`
+
+	var (
+		scannedUntil sql.NullTime
+		response     entity.MuteSetting
+	)
+
+	err := r.DB.QueryRowContext(ctx, query, request.UserID, request.ChatID, request.Muted, mutedUntil).Scan(
+		&response.ChatID,
+		&response.Muted,
+		&scannedUntil,
+	)
+	if err != nil {
+		return entity.MuteSetting{}, err
+	}
+
+	if scannedUntil.Valid {
+		response.MutedUntil = &scannedUntil.Time
+	}
+
+	return response, nil
+}
@@ -12,5 +12,6 @@ type PostRepository interface {
 	Update(ctx context.Context, post entity.UpdatePostRequest) (entity.UpdatePostResponse, error)
 	UpdateColumns(ctx context.Context, post entity.UpdatePostColumnsRequest) (entity.UpdatePostResponse, error)
 	Delete(ctx context.Context, postID, deletedBy int) (entity.DeletePostResponse, error)
-	AddFile(ctx context.Context, fileURL string, postID, updatedBy int) error
+	AttachFile(ctx context.Context, postID, fileID, attachedBy int) error
+	DetachFile(ctx context.Context, postID, fileID int) error
 }
@@ -8,7 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/lib/pq"
+	"github.com/uptrace/bun"
 )
 
 type Repo struct {
@@ -33,8 +33,7 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 		short_content ->> '%s',
 		slug,
 		status,
-		user_id,
-		files
+		user_id
 	FROM
 	    posts
 	`, lang, lang, lang)
@@ -54,7 +53,6 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 			title        sql.NullString
 			content      sql.NullString
 			shortContent sql.NullString
-			files        pq.StringArray
 			post         entity.GetPostResponse
 		)
 		err = rows.Scan(
@@ -65,7 +63,6 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 			&post.Slug,
 			&post.Status,
 			&post.UserID,
-			&files,
 		)
 		if err != nil {
 			return entity.ListPostResponse{}, err
@@ -81,14 +78,26 @@ func (r *Repo) List(ctx context.Context, filter entity.Filter, lang string) (ent
 			post.ShortContent = map[string]string{lang: shortContent.String}
 		}
 
-		post.Files = files
-
 		response.Posts = append(response.Posts, &post)
 	}
 	if err := rows.Err(); err != nil {
 		return entity.ListPostResponse{}, err
 	}
 
+	postIDs := make([]int, 0, len(response.Posts))
+	for _, post := range response.Posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	files, err := r.attachedFiles(ctx, postIDs)
+	if err != nil {
+		return entity.ListPostResponse{}, err
+	}
+
+	for _, post := range response.Posts {
+		post.Files = files[post.ID]
+	}
+
 	totalQuery := `SELECT count(*) FROM posts WHERE deleted_at IS NULL AND status = TRUE`
 	if err := r.DB.QueryRowContext(ctx, totalQuery).Scan(&response.Total); err != nil {
 		return entity.ListPostResponse{}, err
@@ -102,7 +111,6 @@ func (r *Repo) GetByID(ctx context.Context, postID int, lang string) (entity.Get
 		title        sql.NullString
 		content      sql.NullString
 		shortContent sql.NullString
-		files        pq.StringArray
 		response     entity.GetPostResponse
 	)
 
@@ -114,8 +122,7 @@ func (r *Repo) GetByID(ctx context.Context, postID int, lang string) (entity.Get
 		short_content ->> '%s',
 		slug,
 		status,
-		user_id,
-		files
+		user_id
 	FROM
 	    posts
 	`, lang, lang, lang)
@@ -130,13 +137,17 @@ func (r *Repo) GetByID(ctx context.Context, postID int, lang string) (entity.Get
 		&response.Slug,
 		&response.Status,
 		&response.UserID,
-		&files,
 	)
 	if err != nil {
 		return entity.GetPostResponse{}, err
 	}
 
-	response.Files = files
+	files, err := r.attachedFiles(ctx, []int{response.ID})
+	if err != nil {
+		return entity.GetPostResponse{}, err
+	}
+
+	response.Files = files[response.ID]
 
 	if title.Valid {
 		response.Title = map[string]string{lang: title.String}
@@ -215,7 +226,6 @@ func (r *Repo) Update(ctx context.Context, post entity.UpdatePostRequest) (entit
 		title        []byte
 		content      []byte
 		shortContent []byte
-		files        pq.StringArray
 		response     entity.UpdatePostResponse
 	)
 
@@ -230,7 +240,7 @@ func (r *Repo) Update(ctx context.Context, post entity.UpdatePostRequest) (entit
 		Set("updated_by = ?", post.UpdatedBy).
 		Set("updated_at = NOW()").
 		Where("deleted_at IS NULL AND status = TRUE AND id = ?", post.ID).
-		Returning("id, title, content, short_content, slug, status, user_id, files").
+		Returning("id, title, content, short_content, slug, status, user_id").
 		Scan(ctx,
 			&response.ID,
 			&title,
@@ -239,7 +249,6 @@ func (r *Repo) Update(ctx context.Context, post entity.UpdatePostRequest) (entit
 			&response.Slug,
 			&response.Status,
 			&response.UserID,
-			&files,
 		)
 
 	if err != nil {
@@ -256,7 +265,12 @@ func (r *Repo) Update(ctx context.Context, post entity.UpdatePostRequest) (entit
 		return entity.UpdatePostResponse{}, err
 	}
 
-	response.Files = files
+	files, err := r.attachedFiles(ctx, []int{response.ID})
+	if err != nil {
+		return entity.UpdatePostResponse{}, err
+	}
+
+	response.Files = files[response.ID]
 
 	return response, nil
 }
@@ -266,7 +280,6 @@ func (r *Repo) UpdateColumns(ctx context.Context, fields entity.UpdatePostColumn
 		title        string
 		content      string
 		shortContent string
-		files        pq.StringArray
 		response     entity.UpdatePostResponse
 	)
 
@@ -285,8 +298,6 @@ func (r *Repo) UpdateColumns(ctx context.Context, fields entity.UpdatePostColumn
 			updater.Set(key+" = ?", value)
 		} else if key == "user_id" {
 			updater.Set(key+" = ?", value)
-		} else if key == "files" {
-			updater.Set(key+" = ?", value)
 		} else if key == "updated_by" {
 			updater.Set(key+" = ?", value)
 		}
@@ -295,7 +306,7 @@ func (r *Repo) UpdateColumns(ctx context.Context, fields entity.UpdatePostColumn
 	updater.Set("updated_at = NOW()")
 
 	err := updater.Where("deleted_at IS NULL AND status = TRUE AND id = ?", fields.ID).
-		Returning("id, title, content, short_content, slug, status, user_id, files").
+		Returning("id, title, content, short_content, slug, status, user_id").
 		Scan(ctx,
 			&response.ID,
 			&title,
@@ -304,7 +315,6 @@ func (r *Repo) UpdateColumns(ctx context.Context, fields entity.UpdatePostColumn
 			&response.Slug,
 			&response.Status,
 			&response.UserID,
-			&files,
 		)
 
 	if err != nil {
@@ -321,7 +331,12 @@ func (r *Repo) UpdateColumns(ctx context.Context, fields entity.UpdatePostColumn
 		return entity.UpdatePostResponse{}, err
 	}
 
-	response.Files = files
+	files, err := r.attachedFiles(ctx, []int{response.ID})
+	if err != nil {
+		return entity.UpdatePostResponse{}, err
+	}
+
+	response.Files = files[response.ID]
 
 	return response, nil
 }
@@ -352,16 +367,35 @@ func (r *Repo) Delete(ctx context.Context, postID, deletedBy int) (entity.Delete
 	}, nil
 }
 
-func (r *Repo) AddFile(ctx context.Context, fileURL string, postID, updatedBy int) error {
-	updateQuery := fmt.Sprintf(`
-	UPDATE posts 
-	SET 
-	    files = array_append(files, '%v'),
-	    updated_by = '%d'
-	WHERE deleted_at IS NULL AND status = TRUE AND id = '%d'
-	`, fileURL, updatedBy, postID)
+// AttachFile links a stored file to the post, attaching it again is not an error
+func (r *Repo) AttachFile(ctx context.Context, postID, fileID, attachedBy int) error {
+	insertQuery := `
+	INSERT INTO post_files (post_id, file_id, created_by)
+	SELECT p.id, f.id, ?2
+	FROM posts p, files f
+	WHERE p.id = ?0 AND p.deleted_at IS NULL AND f.id = ?1 AND f.deleted_at IS NULL
+	ON CONFLICT (post_id, file_id) DO UPDATE SET post_id = EXCLUDED.post_id
+	`
+
+	result, err := r.DB.ExecContext(ctx, insertQuery, postID, fileID, attachedBy)
+	if err != nil {
+		return err
+	}
+
+	rowEffects, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowEffects == 0 {
+		return sql.ErrNoRows
+	}
 
-	result, err := r.DB.ExecContext(ctx, updateQuery)
+	return nil
+}
+
+func (r *Repo) DetachFile(ctx context.Context, postID, fileID int) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM post_files WHERE post_id = ?0 AND file_id = ?1`, postID, fileID)
 	if err != nil {
 		return err
 	}
@@ -372,8 +406,55 @@ func (r *Repo) AddFile(ctx context.Context, fileURL string, postID, updatedBy in
 	}
 
 	if rowEffects == 0 {
-		return errors.New("post not found for append file")
+		return sql.ErrNoRows
 	}
 
 	return nil
 }
+
+// attachedFiles loads the files of the posts in attach order, deleted files are left out
+func (r *Repo) attachedFiles(ctx context.Context, postIDs []int) (map[int][]entity.AttachedFile, error) {
+	files := make(map[int][]entity.AttachedFile, len(postIDs))
+	if len(postIDs) == 0 {
+		return files, nil
+	}
+
+	selectQuery := `
+	SELECT
+		pf.post_id,
+		f.id,
+		f.name,
+		f.link,
+		f.mime_type,
+		f.size
+	FROM post_files pf
+	JOIN files f ON f.id = pf.file_id AND f.deleted_at IS NULL
+	WHERE pf.post_id IN (?0)
+	ORDER BY pf.created_at, f.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, selectQuery, bun.In(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			postID int
+			file   entity.AttachedFile
+		)
+		err = rows.Scan(&postID, &file.ID, &file.Name, &file.Link, &file.MimeType, &file.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		files[postID] = append(files[postID], file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,450 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/repo/postgres"
+	"context"
+	"database/sql"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type RepoPush struct {
+	DB *postgres.DB
+}
+
+func NewPushRepo(DB *postgres.DB) PushRepository {
+	return &RepoPush{
+		DB: DB,
+	}
+}
+
+// Subscribe stores the subscription of a browser, an endpoint subscribed again takes the new keys. An endpoint
+// another user holds is only taken over once that user unsubscribed, otherwise sql.ErrNoRows is returned.
+func (r *RepoPush) Subscribe(ctx context.Context, request entity.SubscribePushRequest) (entity.PushSubscription, error) {
+	query := `
+	INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent) VALUES (?0, ?1, ?2, ?3, ?4)
+	ON CONFLICT (endpoint) DO UPDATE SET
+		user_id = EXCLUDED.user_id,
+		p256dh = EXCLUDED.p256dh,
+		auth = EXCLUDED.auth,
+		user_agent = EXCLUDED.user_agent,
+		created_at = NOW(),
+		deleted_at = NULL
+	WHERE push_subscriptions.user_id = EXCLUDED.user_id OR push_subscriptions.deleted_at IS NOT NULL
+	RETURNING id, endpoint, user_agent, created_at`
+
+	var response entity.PushSubscription
+
+	err := r.DB.QueryRowContext(ctx, query, request.UserID, request.Endpoint, request.Keys.P256dh, request.Keys.Auth, request.UserAgent).Scan(
+		&response.ID,
+		&response.Endpoint,
+		&response.UserAgent,
+		&response.CreatedAt,
+	)
+	if err != nil {
+		return entity.PushSubscription{}, err
+	}
+
+	return response, nil
+}
+
+func (r *RepoPush) Subscriptions(ctx context.Context, userID int64) ([]entity.PushSubscription, error) {
+	query := `
+	SELECT id, endpoint, user_agent, created_at FROM push_subscriptions
+	WHERE user_id = ?0 AND deleted_at IS NULL
+	ORDER BY id`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	subscriptions := []entity.PushSubscription{}
+	for rows.Next() {
+		var subscription entity.PushSubscription
+
+		err := rows.Scan(&subscription.ID, &subscription.Endpoint, &subscription.UserAgent, &subscription.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *RepoPush) Unsubscribe(ctx context.Context, userID, subscriptionID int64) error {
+	query := `UPDATE push_subscriptions SET deleted_at = NOW() WHERE id = ?0 AND user_id = ?1 AND deleted_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, subscriptionID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RemoveSubscription drops a subscription the push service reported as expired
+func (r *RepoPush) RemoveSubscription(ctx context.Context, subscriptionID int64) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE push_subscriptions SET deleted_at = NOW() WHERE id = ?0 AND deleted_at IS NULL`, subscriptionID)
+
+	return err
+}
+
+func (r *RepoPush) CreateWebhook(ctx context.Context, request entity.CreateWebhookRequest, secret string) (entity.Webhook, error) {
+	query := `INSERT INTO webhooks (user_id, url, secret) VALUES (?0, ?1, ?2) RETURNING id, url, created_at`
+
+	var response entity.Webhook
+
+	err := r.DB.QueryRowContext(ctx, query, request.UserID, request.URL, secret).Scan(&response.ID, &response.URL, &response.CreatedAt)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+
+	return response, nil
+}
+
+func (r *RepoPush) Webhooks(ctx context.Context, userID int64) ([]entity.Webhook, error) {
+	query := `SELECT id, url, created_at FROM webhooks WHERE user_id = ?0 AND deleted_at IS NULL ORDER BY id`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	webhooks := []entity.Webhook{}
+	for rows.Next() {
+		var webhook entity.Webhook
+
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *RepoPush) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	query := `UPDATE webhooks SET deleted_at = NOW() WHERE id = ?0 AND user_id = ?1 AND deleted_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// QuietHours returns the quiet hours of the user, a disabled UTC window when none were set
+func (r *RepoPush) QuietHours(ctx context.Context, userID int64) (entity.QuietHours, error) {
+	query := `
+	SELECT enabled, TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI'), timezone
+	FROM quiet_hours WHERE user_id = ?0`
+
+	response := entity.QuietHours{
+		Start:    "22:00",
+		End:      "07:00",
+		Timezone: "UTC",
+	}
+
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&response.Enabled, &response.Start, &response.End, &response.Timezone)
+	if err != nil && err != sql.ErrNoRows {
+		return entity.QuietHours{}, err
+	}
+
+	return response, nil
+}
+
+func (r *RepoPush) SetQuietHours(ctx context.Context, quietHours entity.QuietHours) error {
+	query := `
+	INSERT INTO quiet_hours (user_id, enabled, start_time, end_time, timezone) VALUES (?0, ?1, ?2::TIME, ?3::TIME, ?4)
+	ON CONFLICT (user_id) DO UPDATE SET
+		enabled = EXCLUDED.enabled,
+		start_time = EXCLUDED.start_time,
+		end_time = EXCLUDED.end_time,
+		timezone = EXCLUDED.timezone,
+		updated_at = NOW()`
+
+	_, err := r.DB.ExecContext(ctx, query, quietHours.UserID, quietHours.Enabled, quietHours.Start, quietHours.End, quietHours.Timezone)
+
+	return err
+}
+
+// Enqueue queues the payload for every active subscription and webhook of the users, except those muting the chat
+func (r *RepoPush) Enqueue(ctx context.Context, chatID int64, userIDs []int, payload []byte) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO push_jobs (user_id, channel, subscription_id, webhook_id, payload)
+	SELECT t.user_id, t.channel, t.subscription_id, t.webhook_id, ?2::JSONB FROM (
+		SELECT s.user_id, 'webpush' AS channel, s.id AS subscription_id, NULL::INT AS webhook_id
+		FROM push_subscriptions AS s WHERE s.user_id IN (?1) AND s.deleted_at IS NULL
+		UNION ALL
+		SELECT w.user_id, 'webhook', NULL, w.id
+		FROM webhooks AS w WHERE w.user_id IN (?1) AND w.deleted_at IS NULL
+	) AS t
+	WHERE NOT EXISTS (
+		SELECT 1 FROM notifications AS n
+		WHERE n.user_id = t.user_id AND n.chat_id = ?0 AND n.muted AND (n.muted_until IS NULL OR n.muted_until > NOW())
+	)`
+
+	_, err := r.DB.ExecContext(ctx, query, chatID, bun.In(userIDs), string(payload))
+
+	return err
+}
+
+// ClaimJobs takes up to limit due jobs with their targets. The jobs are hidden from other workers for the lease,
+// so a job whose worker stopped is retried once the lease ends.
+func (r *RepoPush) ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]entity.PushDelivery, error) {
+	query := `
+	WITH claimed AS (
+		UPDATE push_jobs SET next_attempt_at = NOW() + MAKE_INTERVAL(secs => ?1)
+		WHERE id IN (
+			SELECT id FROM push_jobs
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT ?0
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	)
+	SELECT
+		c.id,
+		c.user_id,
+		c.channel,
+		COALESCE(c.subscription_id, c.webhook_id),
+		c.payload,
+		c.status,
+		c.attempts,
+		c.last_error,
+		c.next_attempt_at,
+		c.created_at,
+		CASE WHEN c.channel = 'webpush' THEN s.id IS NOT NULL AND s.deleted_at IS NULL
+			ELSE w.id IS NOT NULL AND w.deleted_at IS NULL END,
+		COALESCE(s.endpoint, ''),
+		COALESCE(s.p256dh, ''),
+		COALESCE(s.auth, ''),
+		COALESCE(w.url, ''),
+		COALESCE(w.secret, ''),
+		q.user_id IS NOT NULL,
+		COALESCE(TO_CHAR(q.start_time, 'HH24:MI'), ''),
+		COALESCE(TO_CHAR(q.end_time, 'HH24:MI'), ''),
+		COALESCE(q.timezone, '')
+	FROM claimed AS c
+	LEFT JOIN push_subscriptions AS s ON s.id = c.subscription_id
+	LEFT JOIN webhooks AS w ON w.id = c.webhook_id
+	LEFT JOIN quiet_hours AS q ON q.user_id = c.user_id AND q.enabled
+	ORDER BY c.next_attempt_at, c.id`
+
+	rows, err := r.DB.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	var deliveries []entity.PushDelivery
+	for rows.Next() {
+		var (
+			delivery   entity.PushDelivery
+			payload    []byte
+			quiet      bool
+			quietHours entity.QuietHours
+		)
+
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.UserID,
+			&delivery.Channel,
+			&delivery.TargetID,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.Active,
+			&delivery.Endpoint,
+			&delivery.Keys.P256dh,
+			&delivery.Keys.Auth,
+			&delivery.WebhookURL,
+			&delivery.WebhookSecret,
+			&quiet,
+			&quietHours.Start,
+			&quietHours.End,
+			&quietHours.Timezone,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.Payload = payload
+		if quiet {
+			quietHours.Enabled = true
+			quietHours.UserID = int64(delivery.UserID)
+			delivery.QuietHours = &quietHours
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// CompleteJob removes a delivered job
+func (r *RepoPush) CompleteJob(ctx context.Context, jobID int64) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM push_jobs WHERE id = ?0`, jobID)
+
+	return err
+}
+
+// RetryJob counts a failed attempt and schedules the next one after delay
+func (r *RepoPush) RetryJob(ctx context.Context, jobID int64, delay time.Duration, lastError string) error {
+	query := `
+	UPDATE push_jobs SET
+		attempts = attempts + 1,
+		last_error = ?2,
+		next_attempt_at = NOW() + MAKE_INTERVAL(secs => ?1),
+		updated_at = NOW()
+	WHERE id = ?0`
+
+	_, err := r.DB.ExecContext(ctx, query, jobID, delay.Seconds(), lastError)
+
+	return err
+}
+
+// DeferJob postpones a job without counting an attempt
+func (r *RepoPush) DeferJob(ctx context.Context, jobID int64, delay time.Duration) error {
+	query := `UPDATE push_jobs SET next_attempt_at = NOW() + MAKE_INTERVAL(secs => ?1), updated_at = NOW() WHERE id = ?0`
+
+	_, err := r.DB.ExecContext(ctx, query, jobID, delay.Seconds())
+
+	return err
+}
+
+// FailJob counts the last attempt and moves the job to the dead letters
+func (r *RepoPush) FailJob(ctx context.Context, jobID int64, lastError string) error {
+	query := `
+	UPDATE push_jobs SET status = 'dead', attempts = attempts + 1, last_error = ?1, updated_at = NOW()
+	WHERE id = ?0`
+
+	_, err := r.DB.ExecContext(ctx, query, jobID, lastError)
+
+	return err
+}
+
+// DeadJobs returns the latest dead jobs of the user
+func (r *RepoPush) DeadJobs(ctx context.Context, userID int64) ([]entity.PushJob, error) {
+	query := `
+	SELECT id, channel, COALESCE(subscription_id, webhook_id), payload, status, attempts, last_error, next_attempt_at, created_at
+	FROM push_jobs
+	WHERE user_id = ?0 AND status = 'dead'
+	ORDER BY updated_at DESC
+	LIMIT 100`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			_ = err
+		}
+	}(rows)
+
+	jobs := []entity.PushJob{}
+	for rows.Next() {
+		var (
+			job     entity.PushJob
+			payload []byte
+		)
+
+		err := rows.Scan(
+			&job.ID,
+			&job.Channel,
+			&job.TargetID,
+			&payload,
+			&job.Status,
+			&job.Attempts,
+			&job.LastError,
+			&job.NextAttemptAt,
+			&job.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		job.Payload = payload
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// RequeueJob gives a dead job of the user a new round of attempts
+func (r *RepoPush) RequeueJob(ctx context.Context, userID, jobID int64) error {
+	query := `
+	UPDATE push_jobs SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+	WHERE id = ?0 AND user_id = ?1 AND status = 'dead'`
+
+	result, err := r.DB.ExecContext(ctx, query, jobID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package retention
+
+import (
+	"archv1/internal/pkg/config"
+	"archv1/internal/service/chat"
+	"context"
+	"log"
+	"time"
+)
+
+// Purger deletes the messages older than the retention of their chat. A chat without its own
+// retention uses the global one, which keeps the messages forever when it is 0.
+type Purger struct {
+	chats     chat.ChatServiceI
+	days      int
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPurger(chats chat.ChatServiceI, cfg *config.Config) *Purger {
+	interval, err := time.ParseDuration(cfg.RetentionInterval)
+	if err != nil {
+		interval = time.Hour
+	}
+
+	purger := &Purger{
+		chats:     chats,
+		days:      cfg.RetentionDays,
+		interval:  interval,
+		batchSize: cfg.RetentionBatchSize,
+	}
+
+	if purger.batchSize <= 0 {
+		purger.batchSize = 500
+	}
+
+	return purger
+}
+
+// Run purges the expired messages on every interval until the context ends
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if purged := p.purge(ctx); purged > 0 {
+			log.Printf("retention: purged %d messages", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge deletes the expired messages batch by batch, it returns how many were deleted
+func (p *Purger) purge(ctx context.Context) int {
+	var total int
+
+	for ctx.Err() == nil {
+		purged, err := p.chats.PurgeMessages(ctx, p.days, p.batchSize)
+		if err != nil {
+			log.Println(err)
+			return total
+		}
+
+		total += purged
+		if purged < p.batchSize {
+			break
+		}
+	}
+
+	return total
+}
@@ -6,31 +6,41 @@ import (
 	fileStoreCont "archv1/internal/controller/fileStore"
 	fileCont "archv1/internal/controller/files"
 	menuCont "archv1/internal/controller/menu"
+	notificationCont "archv1/internal/controller/notification"
 	postCont "archv1/internal/controller/post"
+	pushCont "archv1/internal/controller/push"
 	userCont "archv1/internal/controller/user"
 	_ "archv1/internal/docs"
 	"archv1/internal/pkg/config"
 	"archv1/internal/pkg/middleware"
 	"archv1/internal/pkg/repo/cache"
 	"archv1/internal/pkg/repo/postgres"
+	"archv1/internal/pkg/storage"
 	"archv1/internal/pkg/tokens"
+	"archv1/internal/pkg/upload"
 	authRepo "archv1/internal/repository/postgres/auth"
 	chatRepo "archv1/internal/repository/postgres/chat"
 	fileStoreRepo "archv1/internal/repository/postgres/fileStore"
 	menuRepo "archv1/internal/repository/postgres/menu"
+	notificationRepo "archv1/internal/repository/postgres/notification"
 	postRepo "archv1/internal/repository/postgres/post"
+	pushRepo "archv1/internal/repository/postgres/push"
 	userRepo "archv1/internal/repository/postgres/user"
 	authService "archv1/internal/service/auth"
 	chatService "archv1/internal/service/chat"
 	fileStoreService "archv1/internal/service/fileStore"
 	menuService "archv1/internal/service/menu"
+	notificationService "archv1/internal/service/notification"
 	postService "archv1/internal/service/post"
+	pushService "archv1/internal/service/push"
 	userService "archv1/internal/service/user"
 	authUseCase "archv1/internal/usecase/auth"
 	chatUseCase "archv1/internal/usecase/chat"
 	fileStoreUseCase "archv1/internal/usecase/fileStore"
 	menuUseCase "archv1/internal/usecase/menu"
+	notificationUseCase "archv1/internal/usecase/notification"
 	postUseCase "archv1/internal/usecase/post"
+	pushUseCase "archv1/internal/usecase/push"
 	userUseCase "archv1/internal/usecase/user"
 	"archv1/internal/websocket"
 	"github.com/casbin/casbin/v2"
@@ -46,6 +56,7 @@ type Router struct {
 	PostgresDB *postgres.DB
 	RedisCache *cache.Redis
 	Enforcer   *casbin.Enforcer
+	BlobStore  storage.BlobStore
 }
 
 // New
@@ -77,6 +88,8 @@ func New(option *Router) *gin.Engine {
 	postRepository := postRepo.NewPostRepo(option.PostgresDB)
 	chatRepository := chatRepo.NewChatRepo(option.PostgresDB)
 	fileStoreRepository := fileStoreRepo.NewFileStoreRepo(option.PostgresDB)
+	notificationRepository := notificationRepo.NewNotificationRepo(option.PostgresDB)
+	pushRepository := pushRepo.NewPushRepo(option.PostgresDB)
 
 	userServiceI := userService.NewUserService(userRepository)
 	menuServiceI := menuService.NewMenuService(menuRepository)
@@ -84,13 +97,21 @@ func New(option *Router) *gin.Engine {
 	postServiceI := postService.NewPostService(postRepository)
 	chatServiceI := chatService.NewChatService(chatRepository)
 	fileStoreServiceI := fileStoreService.NewFilesStoreService(fileStoreRepository)
+	notificationServiceI := notificationService.NewNotificationService(notificationRepository)
+	pushServiceI := pushService.NewPushService(pushRepository)
+
+	uploadValidator := upload.NewValidator(option.Conf)
 
 	userUseCaseI := userUseCase.NewUserUseCase(userServiceI)
 	menuUseCaseI := menuUseCase.NewMenuUseCase(menuServiceI)
 	authUseCaseI := authUseCase.NewAuthUseCase(authServiceI)
 	postUseCaseI := postUseCase.NewPostUseCase(postServiceI)
-	chatUseCaseI := chatUseCase.NewChatUseCase(chatServiceI)
-	fileStoreUseCaseI := fileStoreUseCase.NewFilesStoreUseCase(fileStoreServiceI)
+	chatUseCaseI := chatUseCase.NewChatUseCase(chatServiceI, userServiceI, notificationServiceI, pushServiceI, option.Hub, option.Conf)
+	fileStoreUseCaseI := fileStoreUseCase.NewFilesStoreUseCase(fileStoreServiceI, option.BlobStore, uploadValidator, option.Conf)
+	notificationUseCaseI := notificationUseCase.NewNotificationUseCase(notificationServiceI, chatServiceI, chatUseCaseI)
+	pushUseCaseI := pushUseCase.NewPushUseCase(pushServiceI, option.Conf)
+
+	option.Hub.SetHandler(chatUseCaseI)
 
 	userController := userCont.NewUserController(&userCont.ControllerUser{
 		Conf:        option.Conf,
@@ -127,12 +148,14 @@ func New(option *Router) *gin.Engine {
 	})
 
 	fileController := fileCont.NewFileController(&fileCont.FileController{
-		Conf:        option.Conf,
-		Postgres:    option.PostgresDB,
-		Redis:       option.RedisCache,
-		Enforcer:    option.Enforcer,
-		PostUseCase: postUseCaseI,
-		MenuUseCase: menuUseCaseI,
+		Conf:             option.Conf,
+		Postgres:         option.PostgresDB,
+		Redis:            option.RedisCache,
+		Enforcer:         option.Enforcer,
+		PostUseCase:      postUseCaseI,
+		MenuUseCase:      menuUseCaseI,
+		FileStoreUseCase: fileStoreUseCaseI,
+		Uploads:          uploadValidator,
 	})
 
 	filesStoreController := fileStoreCont.NewFileStoreController(fileStoreCont.ControllerFileStore{
@@ -141,6 +164,7 @@ func New(option *Router) *gin.Engine {
 		Redis:       option.RedisCache,
 		Enforcer:    option.Enforcer,
 		FileUseCase: fileStoreUseCaseI,
+		Uploads:     uploadValidator,
 	})
 
 	chatController := chatCont.NewChatController(&chatCont.ChatController{
@@ -151,15 +175,29 @@ func New(option *Router) *gin.Engine {
 		Enforcer:     option.Enforcer,
 		ChatUseCaseI: chatUseCaseI,
 		UserUseCase:  userUseCaseI,
+		BlobStore:    option.BlobStore,
+		Uploads:      uploadValidator,
+		FileStore:    fileStoreUseCaseI,
 	})
 
-	router.GET("/ws", func(c *gin.Context) {
-		websocket.HandleConnection(option.Hub, c.Writer, c.Request)
+	notificationController := notificationCont.NewNotificationController(&notificationCont.NotificationController{
+		Conf:                option.Conf,
+		NotificationUseCase: notificationUseCaseI,
 	})
 
+	pushController := pushCont.NewPushController(&pushCont.PushController{
+		Conf:        option.Conf,
+		PushUseCase: pushUseCaseI,
+	})
+
+	router.GET("/ws", chatController.Connect)
+
 	router.POST("/v1/auth/register", authController.Register)
 	router.POST("/v1/auth/login", authController.Login)
 	router.GET("/v1/auth/new-access/:refresh", authController.NewAccessToken)
+	router.GET("/v1/download/:token", filesStoreController.Download)
+	router.GET("/v1/share/:token", filesStoreController.OpenShare)
+	router.GET("/v1/share/:token/content", filesStoreController.DownloadShare)
 
 	router.Use(middleware.NewAuthorizer(option.Enforcer, jwtHandler, *option.Conf))
 
@@ -174,14 +212,69 @@ func New(option *Router) *gin.Engine {
 	apiV1.DELETE("/group/:id", chatController.DeleteGroup)
 	apiV1.POST("/group/add-user", chatController.AddUserToGroup)
 	apiV1.DELETE("/group/remove-user", chatController.RemoveUserFromGroup)
+	apiV1.GET("/group/:id/members", chatController.GroupMembers)
+	apiV1.PUT("/group/role", chatController.SetGroupRole)
+	apiV1.POST("/group/transfer-ownership", chatController.TransferGroupOwnership)
+	apiV1.POST("/group/:id/invites", chatController.CreateInvite)
+	apiV1.GET("/group/:id/invites", chatController.GroupInvites)
+	apiV1.DELETE("/group/:id/invites/:invite_id", chatController.RevokeInvite)
+	apiV1.POST("/group/join/:token", chatController.JoinByInvite)
+	apiV1.POST("/group/join", chatController.JoinByUsername)
+	apiV1.GET("/group/:id/join-requests", chatController.JoinRequests)
+	apiV1.POST("/group/join-requests/:id", chatController.ReviewJoinRequest)
+	apiV1.POST("/group/:id/mutes", chatController.MuteMember)
+	apiV1.DELETE("/group/:id/mutes/:user_id", chatController.UnmuteMember)
+	apiV1.POST("/group/:id/bans", chatController.BanMember)
+	apiV1.DELETE("/group/:id/bans/:user_id", chatController.UnbanMember)
+	apiV1.GET("/group/:id/restrictions", chatController.GroupRestrictions)
+	apiV1.PUT("/group/:id/slow-mode", chatController.SetSlowMode)
+	apiV1.GET("/group/:id/filters", chatController.WordFilters)
+	apiV1.POST("/group/:id/filters", chatController.CreateWordFilter)
+	apiV1.DELETE("/group/:id/filters/:filter_id", chatController.DeleteWordFilter)
+	apiV1.GET("/group/:id/reports", chatController.MessageReports)
+	apiV1.POST("/group/reports/:id/review", chatController.ReviewReport)
 	apiV1.GET("/group/user-chats", chatController.UserChats)
+	apiV1.POST("/chat/direct/:user_id", chatController.OpenDirectChat)
 	apiV1.DELETE("/group/delete-chat", chatController.DeleteChat)
 	apiV1.POST("/send-message", chatController.SendMessage)
 	apiV1.PUT("/update-message", chatController.UpdateMessage)
 	apiV1.DELETE("/delete-message/:id", chatController.DeleteMessage)
 	apiV1.GET("/chat-messages/:id", chatController.GetChatMessages)
-	apiV1.GET("/get-notifications/:id", chatController.GetAllNotifications)
-	apiV1.DELETE("/delete-chat-notifications", chatController.DeleteChatNotifications)
+	apiV1.GET("/chat/search", chatController.SearchMessages)
+	apiV1.GET("/chat/:id/search", chatController.SearchChatMessages)
+	apiV1.POST("/chat/:id/read", chatController.ReadChat)
+	apiV1.GET("/chat/:id/receipts", chatController.ChatReceipts)
+	apiV1.POST("/chat/:id/upload", chatController.UploadChatFile)
+	apiV1.GET("/chat/files/:file_id", chatController.DownloadChatFile)
+	apiV1.POST("/chat/forward", chatController.ForwardMessage)
+	apiV1.POST("/chat/message/:id/reactions", chatController.ReactToMessage)
+	apiV1.POST("/chat/message/:id/pin", chatController.PinMessage)
+	apiV1.DELETE("/chat/message/:id/pin", chatController.UnpinMessage)
+	apiV1.GET("/chat/message/:id/history", chatController.MessageHistory)
+	apiV1.POST("/chat/message/:id/report", chatController.ReportMessage)
+	apiV1.GET("/chat/:id/pins", chatController.PinnedMessages)
+	apiV1.GET("/chat/:id/retention", chatController.ChatRetention)
+	apiV1.PUT("/chat/:id/retention", chatController.SetChatRetention)
+	apiV1.GET("/chat/:id/export", chatController.ExportChat)
+
+	// Notification APIs
+	apiV1.GET("/notifications", notificationController.Notifications)
+	apiV1.POST("/notifications/read", notificationController.ReadAll)
+	apiV1.POST("/notifications/:chat_id/read", notificationController.ReadChat)
+	apiV1.PUT("/notifications/:chat_id/mute", notificationController.MuteChat)
+
+	// Push APIs
+	apiV1.GET("/push/vapid-key", pushController.VAPIDKey)
+	apiV1.POST("/push/subscriptions", pushController.Subscribe)
+	apiV1.GET("/push/subscriptions", pushController.Subscriptions)
+	apiV1.DELETE("/push/subscriptions/:id", pushController.Unsubscribe)
+	apiV1.POST("/push/webhooks", pushController.CreateWebhook)
+	apiV1.GET("/push/webhooks", pushController.Webhooks)
+	apiV1.DELETE("/push/webhooks/:id", pushController.DeleteWebhook)
+	apiV1.GET("/push/quiet-hours", pushController.QuietHours)
+	apiV1.PUT("/push/quiet-hours", pushController.SetQuietHours)
+	apiV1.GET("/push/dead-letters", pushController.DeadJobs)
+	apiV1.POST("/push/dead-letters/:id/retry", pushController.RetryJob)
 
 	// User APIs
 	apiV1.GET("/user/list", userController.List)
@@ -199,6 +292,8 @@ func New(option *Router) *gin.Engine {
 	apiV1.PUT("/menu", menuController.Update)
 	apiV1.PATCH("/menu", menuController.UpdateColumns)
 	apiV1.DELETE("/menu/:id", menuController.Delete)
+	apiV1.POST("/menu/:id/files", menuController.AttachFile)
+	apiV1.DELETE("/menu/:id/files/:file_id", menuController.DetachFile)
 
 	// Post APIs
 	apiV1.GET("/post/list", postController.List)
@@ -207,10 +302,11 @@ func New(option *Router) *gin.Engine {
 	apiV1.PUT("/post", postController.Update)
 	apiV1.PATCH("/post", postController.UpdateColumns)
 	apiV1.DELETE("/post/:id", postController.Delete)
+	apiV1.POST("/post/:id/files", postController.AttachFile)
+	apiV1.DELETE("/post/:id/files/:file_id", postController.DetachFile)
 
-	// Upload and Download API
+	// Upload API
 	apiV1.POST("/upload", fileController.UploadFile)
-	apiV1.GET("/download", fileController.GetFile)
 
 	// File Store APIs
 	apiV1.GET("/folder/list", filesStoreController.ListFolder)
@@ -219,14 +315,43 @@ func New(option *Router) *gin.Engine {
 	apiV1.PUT("/folder", filesStoreController.UpdateFolder)
 	apiV1.PATCH("/folder", filesStoreController.UpdateFolderColumns)
 	apiV1.DELETE("/folder/:id", filesStoreController.DeleteFolder)
+	apiV1.GET("/folder/:id/children", filesStoreController.FolderChildren)
+	apiV1.GET("/folder/:id/path", filesStoreController.FolderPath)
+	apiV1.GET("/folder/:id/tree", filesStoreController.FolderTree)
+	apiV1.POST("/folder/:id/move", filesStoreController.MoveFolder)
+	apiV1.POST("/folder/:id/copy", filesStoreController.CopyFolder)
+	apiV1.POST("/folder/:id/restore", filesStoreController.RestoreFolder)
+	apiV1.GET("/folder/:id/access", filesStoreController.GetFolderAccess)
+	apiV1.PUT("/folder/:id/access", filesStoreController.GrantFolderAccess)
+	apiV1.DELETE("/folder/:id/access/:grant_id", filesStoreController.RevokeFolderAccess)
+	apiV1.POST("/folder/:id/share", filesStoreController.ShareFolder)
+	apiV1.DELETE("/folder/:id/share/:link_id", filesStoreController.RevokeFolderShare)
 
 	apiV1.GET("/file/list", filesStoreController.ListFile)
 	apiV1.GET("/file/:id", filesStoreController.GetFile)
 	apiV1.POST("/file", filesStoreController.CreateFile)
+	apiV1.POST("/file/upload", filesStoreController.UploadFile)
+	apiV1.GET("/file/:id/content", filesStoreController.DownloadFile)
+	apiV1.POST("/file/:id/download-link", filesStoreController.CreateDownloadLink)
+	apiV1.GET("/file/:id/variant", filesStoreController.GetFileVariant)
+	apiV1.GET("/file/:id/access", filesStoreController.GetFileAccess)
+	apiV1.PUT("/file/:id/access", filesStoreController.GrantFileAccess)
+	apiV1.DELETE("/file/:id/access/:grant_id", filesStoreController.RevokeFileAccess)
+	apiV1.POST("/file/:id/share", filesStoreController.ShareFile)
+	apiV1.DELETE("/file/:id/share/:link_id", filesStoreController.RevokeFileShare)
+	apiV1.POST("/file/uploads", filesStoreController.CreateUpload)
+	apiV1.HEAD("/file/uploads/:id", filesStoreController.UploadStatus)
+	apiV1.PATCH("/file/uploads/:id", filesStoreController.UploadChunk)
+	apiV1.POST("/file/uploads/:id/complete", filesStoreController.CompleteUpload)
+	apiV1.DELETE("/file/uploads/:id", filesStoreController.AbortUpload)
 	apiV1.PUT("/file", filesStoreController.UpdateFile)
 	apiV1.PATCH("/file", filesStoreController.UpdateFileColumns)
 	apiV1.DELETE("/file/:id", filesStoreController.DeleteFile)
 
+	apiV1.GET("/me/storage", filesStoreController.MyStorage)
+	apiV1.GET("/storage/report", filesStoreController.StorageReport)
+	apiV1.PUT("/storage/users/:user_id/quota", filesStoreController.SetStorageQuota)
+
 	url := ginSwagger.URL("swagger/doc.json")
 	apiV1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
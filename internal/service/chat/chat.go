@@ -52,6 +52,114 @@ func (ch *ChatService) RemoveUserFromGroup(ctx context.Context, userID, groupID
 	return ch.chatRepo.RemoveUserFromGroup(ctx, userID, groupID)
 }
 
+func (ch *ChatService) GroupMember(ctx context.Context, groupID, userID int64) (entity.GroupMember, error) {
+	return ch.chatRepo.GroupMember(ctx, groupID, userID)
+}
+
+func (ch *ChatService) GroupMembers(ctx context.Context, groupID int64) ([]entity.GroupMember, error) {
+	return ch.chatRepo.GroupMembers(ctx, groupID)
+}
+
+func (ch *ChatService) SetGroupRole(ctx context.Context, groupID, userID int64, role string) error {
+	return ch.chatRepo.SetGroupRole(ctx, groupID, userID, role)
+}
+
+func (ch *ChatService) TransferGroupOwnership(ctx context.Context, groupID, ownerID, userID int64) error {
+	return ch.chatRepo.TransferGroupOwnership(ctx, groupID, ownerID, userID)
+}
+
+func (ch *ChatService) GetGroupByUsername(ctx context.Context, username string) (entity.GetGroupResponse, error) {
+	return ch.chatRepo.GetGroupByUsername(ctx, username)
+}
+
+func (ch *ChatService) CreateInvite(ctx context.Context, request entity.CreateInviteRequest, token string) (entity.GroupInvite, error) {
+	return ch.chatRepo.CreateInvite(ctx, request, token)
+}
+
+func (ch *ChatService) GroupInvites(ctx context.Context, groupID int64) ([]entity.GroupInvite, error) {
+	return ch.chatRepo.GroupInvites(ctx, groupID)
+}
+
+func (ch *ChatService) GetInvite(ctx context.Context, token string) (entity.GroupInvite, error) {
+	return ch.chatRepo.GetInvite(ctx, token)
+}
+
+func (ch *ChatService) UseInvite(ctx context.Context, token string) error {
+	return ch.chatRepo.UseInvite(ctx, token)
+}
+
+func (ch *ChatService) RevokeInvite(ctx context.Context, groupID, inviteID, revokedBy int64) error {
+	return ch.chatRepo.RevokeInvite(ctx, groupID, inviteID, revokedBy)
+}
+
+func (ch *ChatService) CreateJoinRequest(ctx context.Context, groupID, userID int64) (entity.JoinRequest, error) {
+	return ch.chatRepo.CreateJoinRequest(ctx, groupID, userID)
+}
+
+func (ch *ChatService) GetJoinRequest(ctx context.Context, requestID int64) (entity.JoinRequest, error) {
+	return ch.chatRepo.GetJoinRequest(ctx, requestID)
+}
+
+func (ch *ChatService) JoinRequests(ctx context.Context, groupID int64) ([]entity.JoinRequest, error) {
+	return ch.chatRepo.JoinRequests(ctx, groupID)
+}
+
+func (ch *ChatService) ReviewJoinRequest(ctx context.Context, requestID, reviewedBy int64, status string) error {
+	return ch.chatRepo.ReviewJoinRequest(ctx, requestID, reviewedBy, status)
+}
+
+func (ch *ChatService) RestrictMember(ctx context.Context, request entity.RestrictMemberRequest, createdBy int64) (entity.GroupRestriction, error) {
+	return ch.chatRepo.RestrictMember(ctx, request, createdBy)
+}
+
+func (ch *ChatService) LiftRestriction(ctx context.Context, groupID, userID int64, kind string) error {
+	return ch.chatRepo.LiftRestriction(ctx, groupID, userID, kind)
+}
+
+func (ch *ChatService) ActiveRestriction(ctx context.Context, groupID, userID int64, kind string) (entity.GroupRestriction, error) {
+	return ch.chatRepo.ActiveRestriction(ctx, groupID, userID, kind)
+}
+
+func (ch *ChatService) GroupRestrictions(ctx context.Context, groupID int64) ([]entity.GroupRestriction, error) {
+	return ch.chatRepo.GroupRestrictions(ctx, groupID)
+}
+
+func (ch *ChatService) SetSlowMode(ctx context.Context, groupID, updatedBy int64, seconds int) error {
+	return ch.chatRepo.SetSlowMode(ctx, groupID, updatedBy, seconds)
+}
+
+func (ch *ChatService) SlowModeWait(ctx context.Context, groupID, chatID, userID int64) (int, error) {
+	return ch.chatRepo.SlowModeWait(ctx, groupID, chatID, userID)
+}
+
+func (ch *ChatService) WordFilters(ctx context.Context, groupID int64) ([]entity.WordFilter, error) {
+	return ch.chatRepo.WordFilters(ctx, groupID)
+}
+
+func (ch *ChatService) CreateWordFilter(ctx context.Context, request entity.CreateWordFilterRequest) (entity.WordFilter, error) {
+	return ch.chatRepo.CreateWordFilter(ctx, request)
+}
+
+func (ch *ChatService) DeleteWordFilter(ctx context.Context, groupID, filterID int64) error {
+	return ch.chatRepo.DeleteWordFilter(ctx, groupID, filterID)
+}
+
+func (ch *ChatService) ReportMessage(ctx context.Context, report entity.MessageReport) (entity.MessageReport, error) {
+	return ch.chatRepo.ReportMessage(ctx, report)
+}
+
+func (ch *ChatService) MessageReports(ctx context.Context, groupID int64, status string) ([]entity.MessageReport, error) {
+	return ch.chatRepo.MessageReports(ctx, groupID, status)
+}
+
+func (ch *ChatService) GetMessageReport(ctx context.Context, reportID int64) (entity.MessageReport, error) {
+	return ch.chatRepo.GetMessageReport(ctx, reportID)
+}
+
+func (ch *ChatService) ReviewReport(ctx context.Context, reportID, reviewedBy int64, status string) error {
+	return ch.chatRepo.ReviewReport(ctx, reportID, reviewedBy, status)
+}
+
 func (ch *ChatService) CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error) {
 	return ch.chatRepo.CreateChat(ctx, receiverID, creator, chatType)
 }
@@ -64,7 +172,27 @@ func (ch *ChatService) UserChats(ctx context.Context, userID int64) (entity.User
 	return ch.chatRepo.UserChats(ctx, userID)
 }
 
-func (ch *ChatService) SendMessage(ctx context.Context, message entity.SendMessageRequest) error {
+func (ch *ChatService) IsParticipant(ctx context.Context, chatID, userID int64) (bool, error) {
+	return ch.chatRepo.IsParticipant(ctx, chatID, userID)
+}
+
+func (ch *ChatService) ChatParticipants(ctx context.Context, chatID int64) ([]entity.GetUserResponse, error) {
+	return ch.chatRepo.ChatParticipants(ctx, chatID)
+}
+
+func (ch *ChatService) CreateChatFile(ctx context.Context, file entity.ChatFile) (entity.ChatFile, error) {
+	return ch.chatRepo.CreateChatFile(ctx, file)
+}
+
+func (ch *ChatService) GetChatFile(ctx context.Context, fileID int64) (entity.ChatFile, error) {
+	return ch.chatRepo.GetChatFile(ctx, fileID)
+}
+
+func (ch *ChatService) FileChats(ctx context.Context, fileID int64) ([]int64, error) {
+	return ch.chatRepo.FileChats(ctx, fileID)
+}
+
+func (ch *ChatService) SendMessage(ctx context.Context, message entity.SendMessageRequest) (entity.Message, error) {
 	return ch.chatRepo.SendMessage(ctx, message)
 }
 
@@ -72,12 +200,40 @@ func (ch *ChatService) UpdateMessage(ctx context.Context, message entity.UpdateM
 	return ch.chatRepo.UpdateMessage(ctx, message)
 }
 
-func (ch *ChatService) DeleteMessage(ctx context.Context, messageID int64) error {
-	return ch.chatRepo.DeleteMessage(ctx, messageID)
+func (ch *ChatService) DeleteMessage(ctx context.Context, messageID, deletedBy int64) error {
+	return ch.chatRepo.DeleteMessage(ctx, messageID, deletedBy)
+}
+
+func (ch *ChatService) HideMessage(ctx context.Context, messageID, userID int64) error {
+	return ch.chatRepo.HideMessage(ctx, messageID, userID)
+}
+
+func (ch *ChatService) MessageEdits(ctx context.Context, messageID int64) ([]entity.MessageEdit, error) {
+	return ch.chatRepo.MessageEdits(ctx, messageID)
 }
 
-func (ch *ChatService) GetChatMessages(ctx context.Context, chatID int64) (entity.ChatMessagesResponse, error) {
-	return ch.chatRepo.GetChatMessages(ctx, chatID)
+func (ch *ChatService) GetChatMessages(ctx context.Context, filter entity.MessageFilter) (entity.ChatMessagesResponse, error) {
+	return ch.chatRepo.GetChatMessages(ctx, filter)
+}
+
+func (ch *ChatService) ChatRetention(ctx context.Context, chatID int64) (entity.ChatRetention, error) {
+	return ch.chatRepo.ChatRetention(ctx, chatID)
+}
+
+func (ch *ChatService) SetChatRetention(ctx context.Context, chatID, updatedBy int64, days int) error {
+	return ch.chatRepo.SetChatRetention(ctx, chatID, updatedBy, days)
+}
+
+func (ch *ChatService) PurgeMessages(ctx context.Context, defaultDays, limit int) (int, error) {
+	return ch.chatRepo.PurgeMessages(ctx, defaultDays, limit)
+}
+
+func (ch *ChatService) ExportMessages(ctx context.Context, filter entity.MessageFilter) ([]entity.ChatMessage, error) {
+	return ch.chatRepo.ExportMessages(ctx, filter)
+}
+
+func (ch *ChatService) SearchMessages(ctx context.Context, request entity.SearchMessagesRequest) (entity.SearchMessagesResponse, error) {
+	return ch.chatRepo.SearchMessages(ctx, request)
 }
 
 func (ch *ChatService) GetChat(ctx context.Context, chatID int64) (entity.Chat, error) {
@@ -87,3 +243,35 @@ func (ch *ChatService) GetChat(ctx context.Context, chatID int64) (entity.Chat,
 func (ch *ChatService) GetMessage(ctx context.Context, messageID int64) (entity.Message, error) {
 	return ch.chatRepo.GetMessage(ctx, messageID)
 }
+
+func (ch *ChatService) MessageReactions(ctx context.Context, messageID, userID int64) ([]entity.ReactionCount, error) {
+	return ch.chatRepo.MessageReactions(ctx, messageID, userID)
+}
+
+func (ch *ChatService) ToggleReaction(ctx context.Context, messageID, userID int64, emoji string) (bool, error) {
+	return ch.chatRepo.ToggleReaction(ctx, messageID, userID, emoji)
+}
+
+func (ch *ChatService) PinMessage(ctx context.Context, chatID, messageID, pinnedBy int64) error {
+	return ch.chatRepo.PinMessage(ctx, chatID, messageID, pinnedBy)
+}
+
+func (ch *ChatService) UnpinMessage(ctx context.Context, chatID, messageID int64) error {
+	return ch.chatRepo.UnpinMessage(ctx, chatID, messageID)
+}
+
+func (ch *ChatService) PinnedMessages(ctx context.Context, chatID, userID int64) ([]entity.PinnedMessage, error) {
+	return ch.chatRepo.PinnedMessages(ctx, chatID, userID)
+}
+
+func (ch *ChatService) MarkDelivered(ctx context.Context, userID, chatID, messageID int64) error {
+	return ch.chatRepo.MarkDelivered(ctx, userID, chatID, messageID)
+}
+
+func (ch *ChatService) MarkRead(ctx context.Context, userID, chatID, messageID int64) (int64, error) {
+	return ch.chatRepo.MarkRead(ctx, userID, chatID, messageID)
+}
+
+func (ch *ChatService) ChatMembers(ctx context.Context, chatID int64) ([]entity.ChatMember, error) {
+	return ch.chatRepo.ChatMembers(ctx, chatID)
+}
@@ -4,6 +4,7 @@ import (
 	"archv1/internal/entity"
 	"archv1/internal/repository/postgres/fileStore"
 	"context"
+	"time"
 )
 
 type FilesStoreService struct {
@@ -16,8 +17,8 @@ func NewFilesStoreService(fileStoreRepo fileStore.FilesStoreRepository) FilesSto
 	}
 }
 
-func (f *FilesStoreService) ListFolder(ctx context.Context, filter entity.Filter) (entity.ListFolderResponse, error) {
-	return f.fileStoreRepo.ListFolder(ctx, filter)
+func (f *FilesStoreService) ListFolder(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFolderResponse, error) {
+	return f.fileStoreRepo.ListFolder(ctx, filter, access)
 }
 
 func (f *FilesStoreService) GetFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error) {
@@ -40,16 +41,44 @@ func (f *FilesStoreService) DeleteFolder(ctx context.Context, folderID, deletedB
 	return f.fileStoreRepo.DeleteFolder(ctx, folderID, deletedBy)
 }
 
-func (f *FilesStoreService) ListFile(ctx context.Context, filter entity.Filter) (entity.ListFileResponse, error) {
-	return f.fileStoreRepo.ListFile(ctx, filter)
+func (f *FilesStoreService) FolderChildren(ctx context.Context, folderID *int, access entity.FileAccess) (entity.FolderChildrenResponse, error) {
+	return f.fileStoreRepo.FolderChildren(ctx, folderID, access)
+}
+
+func (f *FilesStoreService) FolderPath(ctx context.Context, folderID int) ([]*entity.GetFolderResponse, error) {
+	return f.fileStoreRepo.FolderPath(ctx, folderID)
+}
+
+func (f *FilesStoreService) FolderSubtree(ctx context.Context, folderID int) (entity.FolderChildrenResponse, error) {
+	return f.fileStoreRepo.FolderSubtree(ctx, folderID)
+}
+
+func (f *FilesStoreService) MoveFolder(ctx context.Context, request entity.MoveFolderRequest) (entity.UpdateFolderResponse, error) {
+	return f.fileStoreRepo.MoveFolder(ctx, request)
+}
+
+func (f *FilesStoreService) CopyFolder(ctx context.Context, request entity.CopyFolderRequest) (entity.CreateFolderResponse, error) {
+	return f.fileStoreRepo.CopyFolder(ctx, request)
+}
+
+func (f *FilesStoreService) DeletedFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error) {
+	return f.fileStoreRepo.DeletedFolder(ctx, folderID)
+}
+
+func (f *FilesStoreService) RestoreFolder(ctx context.Context, folderID, restoredBy int) (entity.RestoreFolderResponse, error) {
+	return f.fileStoreRepo.RestoreFolder(ctx, folderID, restoredBy)
+}
+
+func (f *FilesStoreService) ListFile(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFileResponse, error) {
+	return f.fileStoreRepo.ListFile(ctx, filter, access)
 }
 
 func (f *FilesStoreService) GetFile(ctx context.Context, fileID int) (entity.GetFileResponse, error) {
 	return f.fileStoreRepo.GetFile(ctx, fileID)
 }
 
-func (f *FilesStoreService) CreateFile(ctx context.Context, file entity.CreateFileRequest) (entity.CreateFileResponse, error) {
-	return f.fileStoreRepo.CreateFile(ctx, file)
+func (f *FilesStoreService) CreateFile(ctx context.Context, file entity.CreateFileRequest, check func(usage entity.StorageUsage) error) (entity.CreateFileResponse, error) {
+	return f.fileStoreRepo.CreateFile(ctx, file, check)
 }
 
 func (f *FilesStoreService) UpdateFile(ctx context.Context, file entity.UpdateFileRequest) (entity.UpdateFileResponse, error) {
@@ -63,3 +92,127 @@ func (f *FilesStoreService) UpdateFileColumns(ctx context.Context, fields entity
 func (f *FilesStoreService) DeleteFile(ctx context.Context, fileID, deletedBy int) (entity.DeleteFileResponse, error) {
 	return f.fileStoreRepo.DeleteFile(ctx, fileID, deletedBy)
 }
+
+func (f *FilesStoreService) CreateUploadSession(ctx context.Context, session entity.UploadSession) (entity.UploadSession, error) {
+	return f.fileStoreRepo.CreateUploadSession(ctx, session)
+}
+
+func (f *FilesStoreService) GetUploadSession(ctx context.Context, sessionID string) (entity.UploadSession, error) {
+	return f.fileStoreRepo.GetUploadSession(ctx, sessionID)
+}
+
+func (f *FilesStoreService) AddUploadChunk(ctx context.Context, chunk entity.UploadChunk, expiresAt time.Time) (entity.UploadSession, error) {
+	return f.fileStoreRepo.AddUploadChunk(ctx, chunk, expiresAt)
+}
+
+func (f *FilesStoreService) UploadChunks(ctx context.Context, sessionID string) ([]entity.UploadChunk, error) {
+	return f.fileStoreRepo.UploadChunks(ctx, sessionID)
+}
+
+func (f *FilesStoreService) SetUploadStatus(ctx context.Context, sessionID, from, to string) error {
+	return f.fileStoreRepo.SetUploadStatus(ctx, sessionID, from, to)
+}
+
+func (f *FilesStoreService) DeleteUploadSession(ctx context.Context, sessionID string) error {
+	return f.fileStoreRepo.DeleteUploadSession(ctx, sessionID)
+}
+
+func (f *FilesStoreService) ExpiredUploadSessions(ctx context.Context, limit int) ([]entity.UploadSession, error) {
+	return f.fileStoreRepo.ExpiredUploadSessions(ctx, limit)
+}
+
+func (f *FilesStoreService) ExistingUploadSessions(ctx context.Context, sessionIDs []string) ([]string, error) {
+	return f.fileStoreRepo.ExistingUploadSessions(ctx, sessionIDs)
+}
+
+func (f *FilesStoreService) FileAttached(ctx context.Context, fileID int) (bool, error) {
+	return f.fileStoreRepo.FileAttached(ctx, fileID)
+}
+
+func (f *FilesStoreService) UseDownloadLink(ctx context.Context, nonce string, fileID int, expiresAt time.Time) (bool, error) {
+	return f.fileStoreRepo.UseDownloadLink(ctx, nonce, fileID, expiresAt)
+}
+
+func (f *FilesStoreService) PurgeDownloadLinks(ctx context.Context) (int, error) {
+	return f.fileStoreRepo.PurgeDownloadLinks(ctx)
+}
+
+func (f *FilesStoreService) AcquireBlob(ctx context.Context, blob entity.Blob) (entity.Blob, error) {
+	return f.fileStoreRepo.AcquireBlob(ctx, blob)
+}
+
+func (f *FilesStoreService) ReleaseBlob(ctx context.Context, key string) error {
+	return f.fileStoreRepo.ReleaseBlob(ctx, key)
+}
+
+func (f *FilesStoreService) PurgeDeletedFiles(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	return f.fileStoreRepo.PurgeDeletedFiles(ctx, deletedBefore, limit)
+}
+
+func (f *FilesStoreService) ReleasedBlobs(ctx context.Context, releasedBefore time.Time, limit int) ([]string, error) {
+	return f.fileStoreRepo.ReleasedBlobs(ctx, releasedBefore, limit)
+}
+
+func (f *FilesStoreService) DropBlob(ctx context.Context, key string, remove func(ctx context.Context) error) (bool, error) {
+	return f.fileStoreRepo.DropBlob(ctx, key, remove)
+}
+
+func (f *FilesStoreService) BlobsToVerify(ctx context.Context, verifiedBefore time.Time, limit int) ([]entity.Blob, error) {
+	return f.fileStoreRepo.BlobsToVerify(ctx, verifiedBefore, limit)
+}
+
+func (f *FilesStoreService) SetBlobVerified(ctx context.Context, key, checksum string, intact bool) error {
+	return f.fileStoreRepo.SetBlobVerified(ctx, key, checksum, intact)
+}
+
+func (f *FilesStoreService) FolderRole(ctx context.Context, folderID, userID int) (string, error) {
+	return f.fileStoreRepo.FolderRole(ctx, folderID, userID)
+}
+
+func (f *FilesStoreService) DeletedFolderRole(ctx context.Context, folderID, userID int) (string, error) {
+	return f.fileStoreRepo.DeletedFolderRole(ctx, folderID, userID)
+}
+
+func (f *FilesStoreService) FileRole(ctx context.Context, fileID, userID int) (string, error) {
+	return f.fileStoreRepo.FileRole(ctx, fileID, userID)
+}
+
+func (f *FilesStoreService) AccessGrants(ctx context.Context, target entity.AccessTarget) (entity.ListAccessResponse, error) {
+	return f.fileStoreRepo.AccessGrants(ctx, target)
+}
+
+func (f *FilesStoreService) GrantAccess(ctx context.Context, request entity.GrantAccessRequest) (entity.AccessGrant, error) {
+	return f.fileStoreRepo.GrantAccess(ctx, request)
+}
+
+func (f *FilesStoreService) RevokeAccess(ctx context.Context, target entity.AccessTarget, grantID int) error {
+	return f.fileStoreRepo.RevokeAccess(ctx, target, grantID)
+}
+
+func (f *FilesStoreService) CreateShareLink(ctx context.Context, link entity.ShareLink) (entity.ShareLink, error) {
+	return f.fileStoreRepo.CreateShareLink(ctx, link)
+}
+
+func (f *FilesStoreService) ShareLinks(ctx context.Context, target entity.AccessTarget) ([]*entity.ShareLink, error) {
+	return f.fileStoreRepo.ShareLinks(ctx, target)
+}
+
+func (f *FilesStoreService) GetShareLink(ctx context.Context, token string) (entity.ShareLink, error) {
+	return f.fileStoreRepo.GetShareLink(ctx, token)
+}
+
+func (f *FilesStoreService) RevokeShareLink(ctx context.Context, target entity.AccessTarget, linkID, revokedBy int) error {
+	return f.fileStoreRepo.RevokeShareLink(ctx, target, linkID, revokedBy)
+}
+
+func (f *FilesStoreService) StorageUsage(ctx context.Context, userID int) (entity.StorageUsage, error) {
+	return f.fileStoreRepo.StorageUsage(ctx, userID)
+}
+
+func (f *FilesStoreService) StorageReport(ctx context.Context, limit int) (entity.StorageReportResponse, error) {
+	return f.fileStoreRepo.StorageReport(ctx, limit)
+}
+
+func (f *FilesStoreService) SetStorageQuota(ctx context.Context, request entity.SetStorageQuotaRequest) error {
+	return f.fileStoreRepo.SetStorageQuota(ctx, request)
+}
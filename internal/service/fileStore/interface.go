@@ -3,19 +3,58 @@ package fileStore
 import (
 	"archv1/internal/entity"
 	"context"
+	"time"
 )
 
 type FilesStoreServiceI interface {
-	ListFolder(ctx context.Context, filter entity.Filter) (entity.ListFolderResponse, error)
+	ListFolder(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFolderResponse, error)
 	GetFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error)
 	CreateFolder(ctx context.Context, folder entity.CreateFolderRequest) (entity.CreateFolderResponse, error)
 	UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest) (entity.UpdateFolderResponse, error)
 	UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest) (entity.UpdateFolderResponse, error)
 	DeleteFolder(ctx context.Context, folderID, deletedBy int) (entity.DeleteFolderResponse, error)
-	ListFile(ctx context.Context, filter entity.Filter) (entity.ListFileResponse, error)
+	FolderChildren(ctx context.Context, folderID *int, access entity.FileAccess) (entity.FolderChildrenResponse, error)
+	FolderPath(ctx context.Context, folderID int) ([]*entity.GetFolderResponse, error)
+	FolderSubtree(ctx context.Context, folderID int) (entity.FolderChildrenResponse, error)
+	MoveFolder(ctx context.Context, request entity.MoveFolderRequest) (entity.UpdateFolderResponse, error)
+	CopyFolder(ctx context.Context, request entity.CopyFolderRequest) (entity.CreateFolderResponse, error)
+	DeletedFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error)
+	RestoreFolder(ctx context.Context, folderID, restoredBy int) (entity.RestoreFolderResponse, error)
+	ListFile(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFileResponse, error)
 	GetFile(ctx context.Context, fileID int) (entity.GetFileResponse, error)
-	CreateFile(ctx context.Context, file entity.CreateFileRequest) (entity.CreateFileResponse, error)
+	CreateFile(ctx context.Context, file entity.CreateFileRequest, check func(usage entity.StorageUsage) error) (entity.CreateFileResponse, error)
 	UpdateFile(ctx context.Context, file entity.UpdateFileRequest) (entity.UpdateFileResponse, error)
 	UpdateFileColumns(ctx context.Context, fields entity.UpdateFileColumnsRequest) (entity.UpdateFileResponse, error)
 	DeleteFile(ctx context.Context, fileID, deletedBy int) (entity.DeleteFileResponse, error)
+	CreateUploadSession(ctx context.Context, session entity.UploadSession) (entity.UploadSession, error)
+	GetUploadSession(ctx context.Context, sessionID string) (entity.UploadSession, error)
+	AddUploadChunk(ctx context.Context, chunk entity.UploadChunk, expiresAt time.Time) (entity.UploadSession, error)
+	UploadChunks(ctx context.Context, sessionID string) ([]entity.UploadChunk, error)
+	SetUploadStatus(ctx context.Context, sessionID, from, to string) error
+	DeleteUploadSession(ctx context.Context, sessionID string) error
+	ExpiredUploadSessions(ctx context.Context, limit int) ([]entity.UploadSession, error)
+	ExistingUploadSessions(ctx context.Context, sessionIDs []string) ([]string, error)
+	FileAttached(ctx context.Context, fileID int) (bool, error)
+	UseDownloadLink(ctx context.Context, nonce string, fileID int, expiresAt time.Time) (bool, error)
+	PurgeDownloadLinks(ctx context.Context) (int, error)
+	AcquireBlob(ctx context.Context, blob entity.Blob) (entity.Blob, error)
+	ReleaseBlob(ctx context.Context, key string) error
+	PurgeDeletedFiles(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+	ReleasedBlobs(ctx context.Context, releasedBefore time.Time, limit int) ([]string, error)
+	DropBlob(ctx context.Context, key string, remove func(ctx context.Context) error) (bool, error)
+	BlobsToVerify(ctx context.Context, verifiedBefore time.Time, limit int) ([]entity.Blob, error)
+	SetBlobVerified(ctx context.Context, key, checksum string, intact bool) error
+	FolderRole(ctx context.Context, folderID, userID int) (string, error)
+	DeletedFolderRole(ctx context.Context, folderID, userID int) (string, error)
+	FileRole(ctx context.Context, fileID, userID int) (string, error)
+	AccessGrants(ctx context.Context, target entity.AccessTarget) (entity.ListAccessResponse, error)
+	GrantAccess(ctx context.Context, request entity.GrantAccessRequest) (entity.AccessGrant, error)
+	RevokeAccess(ctx context.Context, target entity.AccessTarget, grantID int) error
+	CreateShareLink(ctx context.Context, link entity.ShareLink) (entity.ShareLink, error)
+	ShareLinks(ctx context.Context, target entity.AccessTarget) ([]*entity.ShareLink, error)
+	GetShareLink(ctx context.Context, token string) (entity.ShareLink, error)
+	RevokeShareLink(ctx context.Context, target entity.AccessTarget, linkID, revokedBy int) error
+	StorageUsage(ctx context.Context, userID int) (entity.StorageUsage, error)
+	StorageReport(ctx context.Context, limit int) (entity.StorageReportResponse, error)
+	SetStorageQuota(ctx context.Context, request entity.SetStorageQuotaRequest) error
 }
@@ -13,5 +13,6 @@ type MenuServiceI interface {
 	Update(ctx context.Context, menu entity.UpdateMenuRequest) (entity.UpdateMenuResponse, error)
 	UpdateColumns(ctx context.Context, fields entity.UpdateMenuColumnsRequest) (entity.UpdateMenuResponse, error)
 	Delete(ctx context.Context, menuID, deletedBy int) (entity.DeleteMenuResponse, error)
-	AddFile(ctx context.Context, fileURL string, menuID, updatedBy int) error
+	AttachFile(ctx context.Context, menuID, fileID, attachedBy int) error
+	DetachFile(ctx context.Context, menuID, fileID int) error
 }
@@ -79,6 +79,10 @@ func (u *MenuService) Delete(ctx context.Context, menuID, deletedBy int) (entity
 	return menuResponse, nil
 }
 
-func (u *MenuService) AddFile(ctx context.Context, fileURL string, menuID, updatedBy int) error {
-	return u.menuRepo.AddFile(ctx, fileURL, menuID, updatedBy)
+func (u *MenuService) AttachFile(ctx context.Context, menuID, fileID, attachedBy int) error {
+	return u.menuRepo.AttachFile(ctx, menuID, fileID, attachedBy)
+}
+
+func (u *MenuService) DetachFile(ctx context.Context, menuID, fileID int) error {
+	return u.menuRepo.DetachFile(ctx, menuID, fileID)
 }
@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/repository/postgres/notification"
+	"context"
+)
+
+type NotificationService struct {
+	notificationRepo notification.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo notification.NotificationRepository) NotificationServiceI {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (n *NotificationService) Notifications(ctx context.Context, userID int64) (entity.NotificationsResponse, error) {
+	return n.notificationRepo.Notifications(ctx, userID)
+}
+
+func (n *NotificationService) AddMessage(ctx context.Context, userIDs []int, message entity.MessageEvent) error {
+	return n.notificationRepo.AddMessage(ctx, userIDs, message)
+}
+
+func (n *NotificationService) UpdateMessage(ctx context.Context, messageID int64, content string) error {
+	return n.notificationRepo.UpdateMessage(ctx, messageID, content)
+}
+
+func (n *NotificationService) RemoveMessage(ctx context.Context, chatID, messageID int64) error {
+	return n.notificationRepo.RemoveMessage(ctx, chatID, messageID)
+}
+
+func (n *NotificationService) MarkRead(ctx context.Context, userID, chatID, lastRead int64) error {
+	return n.notificationRepo.MarkRead(ctx, userID, chatID, lastRead)
+}
+
+func (n *NotificationService) MuteChat(ctx context.Context, request entity.MuteChatRequest) (entity.MuteSetting, error) {
+	return n.notificationRepo.MuteChat(ctx, request)
+}
@@ -40,6 +40,10 @@ func (r *PostService) Delete(ctx context.Context, postID, deletedBy int) (entity
 	return r.postRepo.Delete(ctx, postID, deletedBy)
 }
 
-func (r *PostService) AddFile(ctx context.Context, fileURL string, postID, updatedBy int) error {
-	return r.postRepo.AddFile(ctx, fileURL, postID, updatedBy)
+func (r *PostService) AttachFile(ctx context.Context, postID, fileID, attachedBy int) error {
+	return r.postRepo.AttachFile(ctx, postID, fileID, attachedBy)
+}
+
+func (r *PostService) DetachFile(ctx context.Context, postID, fileID int) error {
+	return r.postRepo.DetachFile(ctx, postID, fileID)
 }
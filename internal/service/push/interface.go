@@ -0,0 +1,27 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"time"
+)
+
+type PushServiceI interface {
+	Subscribe(ctx context.Context, request entity.SubscribePushRequest) (entity.PushSubscription, error)
+	Subscriptions(ctx context.Context, userID int64) ([]entity.PushSubscription, error)
+	Unsubscribe(ctx context.Context, userID, subscriptionID int64) error
+	RemoveSubscription(ctx context.Context, subscriptionID int64) error
+	CreateWebhook(ctx context.Context, request entity.CreateWebhookRequest, secret string) (entity.Webhook, error)
+	Webhooks(ctx context.Context, userID int64) ([]entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID int64) error
+	QuietHours(ctx context.Context, userID int64) (entity.QuietHours, error)
+	SetQuietHours(ctx context.Context, quietHours entity.QuietHours) error
+	Enqueue(ctx context.Context, chatID int64, userIDs []int, payload []byte) error
+	ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]entity.PushDelivery, error)
+	CompleteJob(ctx context.Context, jobID int64) error
+	RetryJob(ctx context.Context, jobID int64, delay time.Duration, lastError string) error
+	DeferJob(ctx context.Context, jobID int64, delay time.Duration) error
+	FailJob(ctx context.Context, jobID int64, lastError string) error
+	DeadJobs(ctx context.Context, userID int64) ([]entity.PushJob, error)
+	RequeueJob(ctx context.Context, userID, jobID int64) error
+}
@@ -0,0 +1,86 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/repository/postgres/push"
+	"context"
+	"time"
+)
+
+type PushService struct {
+	pushRepo push.PushRepository
+}
+
+func NewPushService(pushRepo push.PushRepository) PushServiceI {
+	return &PushService{
+		pushRepo: pushRepo,
+	}
+}
+
+func (p *PushService) Subscribe(ctx context.Context, request entity.SubscribePushRequest) (entity.PushSubscription, error) {
+	return p.pushRepo.Subscribe(ctx, request)
+}
+
+func (p *PushService) Subscriptions(ctx context.Context, userID int64) ([]entity.PushSubscription, error) {
+	return p.pushRepo.Subscriptions(ctx, userID)
+}
+
+func (p *PushService) Unsubscribe(ctx context.Context, userID, subscriptionID int64) error {
+	return p.pushRepo.Unsubscribe(ctx, userID, subscriptionID)
+}
+
+func (p *PushService) RemoveSubscription(ctx context.Context, subscriptionID int64) error {
+	return p.pushRepo.RemoveSubscription(ctx, subscriptionID)
+}
+
+func (p *PushService) CreateWebhook(ctx context.Context, request entity.CreateWebhookRequest, secret string) (entity.Webhook, error) {
+	return p.pushRepo.CreateWebhook(ctx, request, secret)
+}
+
+func (p *PushService) Webhooks(ctx context.Context, userID int64) ([]entity.Webhook, error) {
+	return p.pushRepo.Webhooks(ctx, userID)
+}
+
+func (p *PushService) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	return p.pushRepo.DeleteWebhook(ctx, userID, webhookID)
+}
+
+func (p *PushService) QuietHours(ctx context.Context, userID int64) (entity.QuietHours, error) {
+	return p.pushRepo.QuietHours(ctx, userID)
+}
+
+func (p *PushService) SetQuietHours(ctx context.Context, quietHours entity.QuietHours) error {
+	return p.pushRepo.SetQuietHours(ctx, quietHours)
+}
+
+func (p *PushService) Enqueue(ctx context.Context, chatID int64, userIDs []int, payload []byte) error {
+	return p.pushRepo.Enqueue(ctx, chatID, userIDs, payload)
+}
+
+func (p *PushService) ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]entity.PushDelivery, error) {
+	return p.pushRepo.ClaimJobs(ctx, limit, lease)
+}
+
+func (p *PushService) CompleteJob(ctx context.Context, jobID int64) error {
+	return p.pushRepo.CompleteJob(ctx, jobID)
+}
+
+func (p *PushService) RetryJob(ctx context.Context, jobID int64, delay time.Duration, lastError string) error {
+	return p.pushRepo.RetryJob(ctx, jobID, delay, lastError)
+}
+
+func (p *PushService) DeferJob(ctx context.Context, jobID int64, delay time.Duration) error {
+	return p.pushRepo.DeferJob(ctx, jobID, delay)
+}
+
+func (p *PushService) FailJob(ctx context.Context, jobID int64, lastError string) error {
+	return p.pushRepo.FailJob(ctx, jobID, lastError)
+}
+
+func (p *PushService) DeadJobs(ctx context.Context, userID int64) ([]entity.PushJob, error) {
+	return p.pushRepo.DeadJobs(ctx, userID)
+}
+
+func (p *PushService) RequeueJob(ctx context.Context, userID, jobID int64) error {
+	return p.pushRepo.RequeueJob(ctx, userID, jobID)
+}
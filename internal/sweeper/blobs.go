@@ -0,0 +1,98 @@
+package sweeper
+
+import (
+	"archv1/internal/pkg/config"
+	"archv1/internal/usecase/fileStore"
+	"context"
+	"log"
+	"time"
+)
+
+// BlobVerifier hashes the stored blobs again to find the missing and corrupted ones, releases the content
+// of the files deleted longer ago than the trash retention and removes the content of the blobs no file holds anymore
+type BlobVerifier struct {
+	files    fileStore.FilesStoreUseCaseI
+	interval time.Duration
+	batch    int
+}
+
+func NewBlobVerifier(files fileStore.FilesStoreUseCaseI, cfg *config.Config) *BlobVerifier {
+	interval, err := time.ParseDuration(cfg.BlobVerifyInterval)
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	batch := cfg.BlobVerifyBatch
+	if batch <= 0 {
+		batch = 50
+	}
+
+	return &BlobVerifier{
+		files:    files,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run collects the released blobs and verifies a batch of the stored ones on every interval until the context ends
+func (v *BlobVerifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	for {
+		v.collect(ctx)
+
+		if verified, corrupted, err := v.files.VerifyBlobs(ctx, v.batch); err != nil {
+			log.Println(err)
+		} else if corrupted > 0 {
+			log.Printf("verifier: %d of %d blobs are missing or corrupted", corrupted, verified)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect purges the expired deleted files and removes the released blobs batch by batch
+func (v *BlobVerifier) collect(ctx context.Context) {
+	var purgedTotal int
+
+	for ctx.Err() == nil {
+		purged, err := v.files.PurgeDeletedFiles(ctx, batchSize)
+		if err != nil {
+			log.Println(err)
+			break
+		}
+
+		purgedTotal += purged
+		if purged < batchSize {
+			break
+		}
+	}
+
+	if purgedTotal > 0 {
+		log.Printf("verifier: purged %d deleted files", purgedTotal)
+	}
+
+	var total int
+
+	for ctx.Err() == nil {
+		collected, err := v.files.CollectBlobs(ctx, batchSize)
+		if err != nil {
+			log.Println(err)
+			break
+		}
+
+		total += collected
+		if collected < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("verifier: removed %d unused blobs", total)
+	}
+}
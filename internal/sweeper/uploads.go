@@ -0,0 +1,75 @@
+package sweeper
+
+import (
+	"archv1/internal/pkg/config"
+	"archv1/internal/usecase/fileStore"
+	"context"
+	"log"
+	"time"
+)
+
+// batchSize is how many expired uploads are dropped at once
+const batchSize = 100
+
+// UploadSweeper drops the resumable uploads that were not completed before they expired,
+// together with the chunks they received, and forgets the expired single use download links
+type UploadSweeper struct {
+	files    fileStore.FilesStoreUseCaseI
+	interval time.Duration
+}
+
+func NewUploadSweeper(files fileStore.FilesStoreUseCaseI, cfg *config.Config) *UploadSweeper {
+	interval, err := time.ParseDuration(cfg.UploadSweepInterval)
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	return &UploadSweeper{
+		files:    files,
+		interval: interval,
+	}
+}
+
+// Run sweeps the expired uploads on every interval until the context ends
+func (s *UploadSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if swept := s.sweep(ctx); swept > 0 {
+			log.Printf("sweeper: dropped %d expired uploads", swept)
+		}
+
+		if purged, err := s.files.PurgeDownloadLinks(ctx); err != nil {
+			log.Println(err)
+		} else if purged > 0 {
+			log.Printf("sweeper: forgot %d expired download links", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep drops the expired uploads batch by batch, it returns how many were dropped
+func (s *UploadSweeper) sweep(ctx context.Context) int {
+	var total int
+
+	for ctx.Err() == nil {
+		swept, err := s.files.SweepUploads(ctx, batchSize)
+		if err != nil {
+			log.Println(err)
+			return total
+		}
+
+		total += swept
+		if swept < batchSize {
+			break
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,129 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidMessageType = errors.New("property message_type must be 'text', 'image', 'file' or 'voice'")
+	ErrInvalidAttachment  = errors.New("attachments must be files uploaded by the sender to this chat and match the message type")
+	ErrEmptyMessage       = errors.New("a text message needs content and no attachments")
+)
+
+// CheckChatUpload fails unless the user participates in the chat and is allowed to post in it,
+// it lets an upload be rejected before its bytes are stored
+func (ch *ChatUseCase) CheckChatUpload(ctx context.Context, userID, chatID int64) error {
+	chatResponse, err := ch.participantChat(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if chatResponse.ChatType == "group" {
+		if _, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), userID, permPost); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UploadChatFile registers a file uploaded to the chat by a participant allowed to post in it
+func (ch *ChatUseCase) UploadChatFile(ctx context.Context, file entity.ChatFile) (entity.ChatFile, error) {
+	if err := ch.CheckChatUpload(ctx, int64(file.UploadedBy), int64(file.ChatID)); err != nil {
+		return entity.ChatFile{}, err
+	}
+
+	return ch.chatService.CreateChatFile(ctx, file)
+}
+
+// ChatFile returns a file to a participant of the chat it was uploaded or forwarded to
+func (ch *ChatUseCase) ChatFile(ctx context.Context, userID, fileID int64) (entity.ChatFile, error) {
+	file, err := ch.chatService.GetChatFile(ctx, fileID)
+	if err != nil {
+		return entity.ChatFile{}, err
+	}
+
+	chatIDs, err := ch.chatService.FileChats(ctx, fileID)
+	if err != nil {
+		return entity.ChatFile{}, err
+	}
+
+	for _, chatID := range chatIDs {
+		isParticipant, err := ch.chatService.IsParticipant(ctx, chatID, userID)
+		if err != nil {
+			return entity.ChatFile{}, err
+		}
+
+		if isParticipant {
+			return file, nil
+		}
+	}
+
+	return entity.ChatFile{}, ErrForbidden
+}
+
+// checkMessage validates the kind of the message against its content and attachments,
+// an empty kind is taken as text
+func (ch *ChatUseCase) checkMessage(ctx context.Context, message *entity.SendMessageRequest) error {
+	message.MessageType = strings.ToLower(message.MessageType)
+	if message.MessageType == "" {
+		message.MessageType = entity.MessageText
+	}
+
+	switch message.MessageType {
+	case entity.MessageText:
+		if len(message.Attachments) > 0 || strings.TrimSpace(message.Message) == "" {
+			return ErrEmptyMessage
+		}
+
+		return nil
+	case entity.MessageImage, entity.MessageFile, entity.MessageVoice:
+	default:
+		return ErrInvalidMessageType
+	}
+
+	if len(message.Attachments) == 0 || message.MessageType == entity.MessageVoice && len(message.Attachments) != 1 {
+		return ErrInvalidAttachment
+	}
+
+	seen := make(map[int]bool, len(message.Attachments))
+	for _, fileID := range message.Attachments {
+		if seen[fileID] {
+			return ErrInvalidAttachment
+		}
+		seen[fileID] = true
+
+		file, err := ch.chatService.GetChatFile(ctx, int64(fileID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidAttachment
+		}
+		if err != nil {
+			return err
+		}
+
+		if file.ChatID != message.ChatID || file.UploadedBy != message.Sender {
+			return ErrInvalidAttachment
+		}
+
+		if !matchesKind(message.MessageType, file.MimeType) {
+			return ErrInvalidAttachment
+		}
+	}
+
+	return nil
+}
+
+func matchesKind(messageType, mimeType string) bool {
+	switch messageType {
+	case entity.MessageImage:
+		return strings.HasPrefix(mimeType, "image/")
+	case entity.MessageVoice:
+		return strings.HasPrefix(mimeType, "audio/") || mimeType == "application/ogg" || mimeType == "video/webm"
+	default:
+		return true
+	}
+}
@@ -2,17 +2,55 @@ package chat
 
 import (
 	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
 	"archv1/internal/service/chat"
+	"archv1/internal/service/notification"
+	"archv1/internal/service/push"
+	"archv1/internal/service/user"
+	"archv1/internal/websocket"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// pushPreviewLength is the number of characters of a message shown in a push notification
+const pushPreviewLength = 500
+
+var (
+	ErrForbidden       = errors.New("you have no access to this chat")
+	ErrInvalidChatType = errors.New("property chat type must be 'private' or 'group'")
+	ErrSelfChat        = errors.New("a private chat needs two different users")
+	ErrInvalidSignal   = errors.New("property action must be 'typing', 'recording_voice' or 'stopped'")
 )
 
 type ChatUseCase struct {
-	chatService chat.ChatServiceI
+	chatService         chat.ChatServiceI
+	userService         user.UserServiceI
+	notificationService notification.NotificationServiceI
+	pushService         push.PushServiceI
+	hub                 *websocket.Hub
+	deleteWindow        time.Duration
+	retentionDays       int
 }
 
-func NewChatUseCase(chatService chat.ChatServiceI) ChatUseCaseI {
+func NewChatUseCase(chatService chat.ChatServiceI, userService user.UserServiceI, notificationService notification.NotificationServiceI, pushService push.PushServiceI, hub *websocket.Hub, cfg *config.Config) ChatUseCaseI {
+	deleteWindow, err := time.ParseDuration(cfg.DeleteForEveryoneWindow)
+	if err != nil {
+		deleteWindow = 48 * time.Hour
+	}
+
 	return &ChatUseCase{
-		chatService: chatService,
+		chatService:         chatService,
+		userService:         userService,
+		notificationService: notificationService,
+		pushService:         pushService,
+		hub:                 hub,
+		deleteWindow:        deleteWindow,
+		retentionDays:       cfg.RetentionDays,
 	}
 }
 
@@ -20,43 +58,165 @@ func (ch *ChatUseCase) UserGroups(ctx context.Context, userID int64) ([]entity.G
 	return ch.chatService.UserGroups(ctx, userID)
 }
 
-func (ch *ChatUseCase) GroupUsers(ctx context.Context, groupID int64) ([]entity.GetUserResponse, error) {
+// GroupUsers returns the members of a public group, or of a private group to its members
+func (ch *ChatUseCase) GroupUsers(ctx context.Context, userID, groupID int64) ([]entity.GetUserResponse, error) {
+	if _, err := ch.GetGroup(ctx, userID, groupID); err != nil {
+		return nil, err
+	}
+
 	return ch.chatService.GroupUsers(ctx, groupID)
 }
 
-func (ch *ChatUseCase) GetGroup(ctx context.Context, groupID int64) (entity.GetGroupResponse, error) {
-	return ch.chatService.GetGroup(ctx, groupID)
+// GetGroup returns a public group, a private group is only shown to its members
+func (ch *ChatUseCase) GetGroup(ctx context.Context, userID, groupID int64) (entity.GetGroupResponse, error) {
+	group, err := ch.chatService.GetGroup(ctx, groupID)
+	if err != nil || group.Visibility == entity.GroupPublic {
+		return group, err
+	}
+
+	isMember, err := ch.isGroupMember(ctx, groupID, userID)
+	if err != nil {
+		return entity.GetGroupResponse{}, err
+	}
+
+	if !isMember {
+		return entity.GetGroupResponse{}, ErrForbidden
+	}
+
+	return group, nil
 }
 
 func (ch *ChatUseCase) CreateGroup(ctx context.Context, group entity.CreateGroupRequest) (entity.CreateGroupResponse, error) {
+	if group.Visibility == "" {
+		group.Visibility = entity.GroupPrivate
+	}
+
+	if err := checkVisibility(group.Visibility); err != nil {
+		return entity.CreateGroupResponse{}, err
+	}
+
 	return ch.chatService.CreateGroup(ctx, group)
 }
 
+// UpdateGroup replaces the info of the group, an empty visibility keeps the stored one
 func (ch *ChatUseCase) UpdateGroup(ctx context.Context, group entity.UpdateGroupRequest) (entity.UpdateGroupResponse, error) {
+	if group.Visibility != "" {
+		if err := checkVisibility(group.Visibility); err != nil {
+			return entity.UpdateGroupResponse{}, err
+		}
+	}
+
+	if _, err := ch.authorize(ctx, int64(group.GroupID), int64(group.UpdatedBy), permEditInfo); err != nil {
+		return entity.UpdateGroupResponse{}, err
+	}
+
+	if group.Visibility == "" {
+		stored, err := ch.chatService.GetGroup(ctx, int64(group.GroupID))
+		if err != nil {
+			return entity.UpdateGroupResponse{}, err
+		}
+
+		group.Visibility = stored.Visibility
+	}
+
 	return ch.chatService.UpdateGroup(ctx, group)
 }
 
 func (ch *ChatUseCase) UpdateGroupColumns(ctx context.Context, fields entity.UpdateGroupColumns) (entity.UpdateGroupResponse, error) {
+	if visibility, ok := fields.Fields["visibility"]; ok {
+		if err := checkVisibility(visibility); err != nil {
+			return entity.UpdateGroupResponse{}, err
+		}
+	}
+
+	if _, err := ch.authorize(ctx, int64(fields.GroupID), int64(fields.UpdatedBy), permEditInfo); err != nil {
+		return entity.UpdateGroupResponse{}, err
+	}
+
 	return ch.chatService.UpdateGroupColumns(ctx, fields)
 }
 
 func (ch *ChatUseCase) DeleteGroup(ctx context.Context, groupID, deletedBy int64) (entity.DeleteGroupResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, deletedBy, permDeleteGroup); err != nil {
+		return entity.DeleteGroupResponse{}, err
+	}
+
 	return ch.chatService.DeleteGroup(ctx, groupID, deletedBy)
 }
 
-func (ch *ChatUseCase) AddUserToGroup(ctx context.Context, userID, groupID int64) error {
-	return ch.chatService.AddUserToGroup(ctx, userID, groupID)
+// AddUserToGroup adds the user to the group on behalf of a member allowed to invite
+func (ch *ChatUseCase) AddUserToGroup(ctx context.Context, actorID, userID, groupID int64) error {
+	if _, err := ch.authorize(ctx, groupID, actorID, permInvite); err != nil {
+		return err
+	}
+
+	if _, err := ch.userService.GetByID(ctx, int(userID)); err != nil {
+		return err
+	}
+
+	isMember, err := ch.isGroupMember(ctx, groupID, userID)
+	if err != nil || isMember {
+		return err
+	}
+
+	return ch.joinGroup(ctx, groupID, userID, actorID)
+}
+
+// RemoveUserFromGroup removes a member ranked below the actor, or lets the actor leave the group.
+// The remaining members and the removed user get a leave event.
+func (ch *ChatUseCase) RemoveUserFromGroup(ctx context.Context, actorID, userID, groupID int64) error {
+	if actorID == userID {
+		member, err := ch.authorize(ctx, groupID, actorID, permPost)
+		if err != nil {
+			return err
+		}
+
+		if member.Role == entity.GroupRoleOwner {
+			return ErrOwnerLeave
+		}
+	} else {
+		actor, err := ch.authorize(ctx, groupID, actorID, permRemoveMembers)
+		if err != nil {
+			return err
+		}
+
+		if _, err := ch.outranks(ctx, actor, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := ch.chatService.RemoveUserFromGroup(ctx, userID, groupID); err != nil {
+		return err
+	}
+
+	return ch.memberEvent(ctx, entity.FrameMemberLeft, groupID, userID, actorID)
 }
 
-func (ch *ChatUseCase) RemoveUserFromGroup(ctx context.Context, userID, groupID int64) error {
-	return ch.chatService.RemoveUserFromGroup(ctx, userID, groupID)
+func checkVisibility(visibility string) error {
+	if visibility != entity.GroupPublic && visibility != entity.GroupPrivate {
+		return ErrInvalidVisibility
+	}
+
+	return nil
 }
 
 func (ch *ChatUseCase) CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error) {
 	return ch.chatService.CreateChat(ctx, receiverID, creator, chatType)
 }
 
-func (ch *ChatUseCase) DeleteChat(ctx context.Context, chatID int64) error {
+// DeleteChat deletes the chat on behalf of a participant, in a group chat the user needs to be allowed to delete the group
+func (ch *ChatUseCase) DeleteChat(ctx context.Context, userID, chatID int64) error {
+	chatResponse, err := ch.participantChat(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if chatResponse.ChatType == "group" {
+		if _, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), userID, permDeleteGroup); err != nil {
+			return err
+		}
+	}
+
 	return ch.chatService.DeleteChat(ctx, chatID)
 }
 
@@ -64,20 +224,342 @@ func (ch *ChatUseCase) UserChats(ctx context.Context, userID int64) (entity.User
 	return ch.chatService.UserChats(ctx, userID)
 }
 
-func (ch *ChatUseCase) SendMessage(ctx context.Context, message entity.SendMessageRequest) error {
-	return ch.chatService.SendMessage(ctx, message)
+// OpenDirectChat returns the private chat between the two users, creating it on the first call
+func (ch *ChatUseCase) OpenDirectChat(ctx context.Context, userID, peerID int64) (entity.CreatedChatResponse, error) {
+	if userID == peerID {
+		return entity.CreatedChatResponse{}, ErrSelfChat
+	}
+
+	if _, err := ch.userService.GetByID(ctx, int(peerID)); err != nil {
+		return entity.CreatedChatResponse{}, err
+	}
+
+	return ch.chatService.CreateChat(ctx, peerID, userID, "private")
+}
+
+// SendMessage stores the message, opening the chat on the first message, and delivers it to the chat participants.
+// A group message has to pass the moderation rules of the group first.
+// Participants without a live connection get the message counted in their notifications.
+func (ch *ChatUseCase) SendMessage(ctx context.Context, message entity.SendMessageRequest) (entity.MessageEvent, error) {
+	chatResponse, err := ch.openChat(ctx, message)
+	if err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	message.ChatID = int(chatResponse.ID)
+	message.ChatType = chatResponse.ChatType
+
+	if err := ch.checkMessage(ctx, &message); err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	if err := ch.checkReply(ctx, message); err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	if err := ch.moderate(ctx, chatResponse, &message); err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	return ch.postMessage(ctx, chatResponse, message)
+}
+
+// postMessage stores a checked message and delivers it to the chat participants
+func (ch *ChatUseCase) postMessage(ctx context.Context, chatResponse entity.Chat, message entity.SendMessageRequest) (entity.MessageEvent, error) {
+	saved, err := ch.chatService.SendMessage(ctx, message)
+	if err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	// the message is stored from here on, a failure to announce it is only logged so the
+	// client is not told the send failed and does not send the message again
+	members, err := ch.chatService.ChatParticipants(ctx, chatResponse.ID)
+	if err != nil {
+		log.Println(err)
+	}
+
+	event := entity.MessageEvent{
+		MessageID:     saved.ID,
+		ChatID:        saved.ChatId,
+		ChatType:      chatResponse.ChatType,
+		Content:       saved.Content,
+		MessageType:   saved.MessageType,
+		Attachments:   saved.Attachments,
+		ReplyTo:       saved.ReplyTo,
+		ForwardedFrom: saved.ForwardedFrom,
+		Sender:        saved.Sender,
+		Receiver:      receiver(chatResponse, members, saved.Sender),
+	}
+
+	ch.announce(ctx, event, memberIDs(members, event.Sender), saved.CreatedAt)
+
+	return event, nil
+}
+
+// announce adds the new message to the inboxes of the recipients and delivers it to the sender and to
+// the recipients online, the ones offline get a push. Every failure is logged and the others go on.
+func (ch *ChatUseCase) announce(ctx context.Context, event entity.MessageEvent, recipients []int, createdAt time.Time) {
+	frame, err := websocket.NewFrame(entity.FrameMessageNew, "", event)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if err := ch.notificationService.AddMessage(ctx, recipients, event); err != nil {
+		log.Println(err)
+	}
+
+	ch.hub.SendToUser(event.Sender, frame)
+
+	var offline []int
+	for _, memberID := range recipients {
+		if !ch.hub.SendToUser(memberID, frame) {
+			offline = append(offline, memberID)
+			continue
+		}
+
+		if err := ch.delivered(ctx, memberID, event); err != nil {
+			log.Println(err)
+		}
+	}
+
+	if err := ch.push(ctx, event, createdAt, offline); err != nil {
+		log.Println(err)
+	}
+}
+
+// openChat returns the chat of a new message after checking that the sender participates in it.
+// Without a chat ID the private chat with the receiver or the chat of the receiver group is opened.
+func (ch *ChatUseCase) openChat(ctx context.Context, message entity.SendMessageRequest) (entity.Chat, error) {
+	if message.ChatID != 0 {
+		chatResponse, err := ch.participantChat(ctx, int64(message.ChatID), int64(message.Sender))
+		if err != nil {
+			return entity.Chat{}, err
+		}
+
+		if chatResponse.ChatType == "group" {
+			if _, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), int64(message.Sender), permPost); err != nil {
+				return entity.Chat{}, err
+			}
+		}
+
+		return chatResponse, nil
+	}
+
+	var (
+		created entity.CreatedChatResponse
+		err     error
+	)
+
+	switch strings.ToLower(message.ChatType) {
+	case "private":
+		created, err = ch.OpenDirectChat(ctx, int64(message.Sender), int64(message.Receiver))
+	case "group":
+		created, err = ch.openGroupChat(ctx, int64(message.Sender), int64(message.Receiver))
+	default:
+		return entity.Chat{}, ErrInvalidChatType
+	}
+	if err != nil {
+		return entity.Chat{}, err
+	}
+
+	return entity.Chat{
+		ID:         int64(created.ChatId),
+		ChatType:   created.ChatType,
+		ReceiverID: created.ReceiverID,
+	}, nil
+}
+
+// openGroupChat returns the chat of the group, creating it on the first message of a group member
+func (ch *ChatUseCase) openGroupChat(ctx context.Context, userID, groupID int64) (entity.CreatedChatResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permPost); err != nil {
+		return entity.CreatedChatResponse{}, err
+	}
+
+	return ch.chatService.CreateChat(ctx, groupID, userID, "group")
+}
+
+// delivered moves the delivery cursor of the member and sends the receipt to the sender
+func (ch *ChatUseCase) delivered(ctx context.Context, memberID int, event entity.MessageEvent) error {
+	err := ch.chatService.MarkDelivered(ctx, int64(memberID), int64(event.ChatID), int64(event.MessageID))
+	if err != nil {
+		return err
+	}
+
+	receipt, err := websocket.NewFrame(entity.FrameDelivered, "", entity.ReceiptEvent{
+		ChatID:    event.ChatID,
+		UserID:    memberID,
+		MessageID: event.MessageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	ch.hub.SendToUser(event.Sender, receipt)
+
+	return nil
+}
+
+// UpdateMessage changes the content of a message written by request.Sender and delivers the change to the chat members
+func (ch *ChatUseCase) UpdateMessage(ctx context.Context, request entity.UpdateMessageRequest) error {
+	message, err := ch.chatService.GetMessage(ctx, int64(request.MessageID))
+	if err != nil {
+		return err
+	}
+
+	if message.Sender != request.Sender {
+		return ErrForbidden
+	}
+
+	if message.MessageType == entity.MessageText && strings.TrimSpace(request.NewMessage) == "" {
+		return ErrEmptyMessage
+	}
+
+	chatResponse, err := ch.participantChat(ctx, int64(message.ChatId), int64(request.Sender))
+	if err != nil {
+		return err
+	}
+
+	if chatResponse.ChatType == "group" {
+		if err := ch.checkRestricted(ctx, int64(chatResponse.ReceiverID), int64(request.Sender), entity.RestrictionMute); err != nil {
+			return err
+		}
+
+		request.NewMessage, err = ch.filterContent(ctx, int64(chatResponse.ReceiverID), request.NewMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	request.ChatID = message.ChatId
+	if err := ch.chatService.UpdateMessage(ctx, request); err != nil {
+		return err
+	}
+
+	members, err := ch.chatService.ChatParticipants(ctx, chatResponse.ID)
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameMessageUpdated, "", entity.MessageEvent{
+		MessageID:   message.ID,
+		ChatID:      message.ChatId,
+		ChatType:    chatResponse.ChatType,
+		Content:     request.NewMessage,
+		MessageType: message.MessageType,
+		Edited:      true,
+		Sender:      message.Sender,
+		Receiver:    receiver(chatResponse, members, message.Sender),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ch.notificationService.UpdateMessage(ctx, int64(message.ID), request.NewMessage); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		ch.hub.SendToUser(member.Id, frame)
+	}
+
+	return nil
 }
 
-func (ch *ChatUseCase) UpdateMessage(ctx context.Context, message entity.UpdateMessageRequest) error {
-	return ch.chatService.UpdateMessage(ctx, message)
+// DeleteMessage hides a message for deletedBy, or deletes it for everyone. A message is deleted for everyone
+// by its sender within the delete window, or by a group member ranked above the sender, and the latest message
+// in members notifications is refreshed.
+func (ch *ChatUseCase) DeleteMessage(ctx context.Context, messageID, deletedBy int64, scope string) error {
+	if scope == "" {
+		scope = entity.DeleteForEveryone
+	}
+
+	if scope != entity.DeleteForMe && scope != entity.DeleteForEveryone {
+		return ErrInvalidDeleteScope
+	}
+
+	message, err := ch.chatService.GetMessage(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	chatResponse, err := ch.participantChat(ctx, int64(message.ChatId), deletedBy)
+	if err != nil {
+		return err
+	}
+
+	if scope == entity.DeleteForMe {
+		return ch.hideMessage(ctx, chatResponse, message, deletedBy)
+	}
+
+	if int64(message.Sender) == deletedBy {
+		if ch.deleteWindow > 0 && time.Since(message.CreatedAt) > ch.deleteWindow {
+			return ErrDeleteWindow
+		}
+	} else {
+		if chatResponse.ChatType != "group" {
+			return ErrForbidden
+		}
+
+		actor, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), deletedBy, permDeleteMessages)
+		if err != nil {
+			return err
+		}
+
+		if _, err := ch.outranks(ctx, actor, int64(message.Sender)); err != nil {
+			return err
+		}
+	}
+
+	if err := ch.chatService.DeleteMessage(ctx, messageID, deletedBy); err != nil {
+		return err
+	}
+
+	if err := ch.notificationService.RemoveMessage(ctx, int64(message.ChatId), messageID); err != nil {
+		return err
+	}
+
+	members, err := ch.chatService.ChatParticipants(ctx, chatResponse.ID)
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameMessageDeleted, "", entity.MessageEvent{
+		MessageID:   message.ID,
+		ChatID:      message.ChatId,
+		ChatType:    chatResponse.ChatType,
+		MessageType: message.MessageType,
+		Sender:      message.Sender,
+		Receiver:    receiver(chatResponse, members, message.Sender),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		ch.hub.SendToUser(member.Id, frame)
+	}
+
+	return nil
 }
 
-func (ch *ChatUseCase) DeleteMessage(ctx context.Context, messageID int64) error {
-	return ch.chatService.DeleteMessage(ctx, messageID)
+func (ch *ChatUseCase) GetChatMessages(ctx context.Context, filter entity.MessageFilter) (entity.ChatMessagesResponse, error) {
+	if _, err := ch.participantChat(ctx, filter.ChatID, filter.UserID); err != nil {
+		return entity.ChatMessagesResponse{}, err
+	}
+
+	return ch.chatService.GetChatMessages(ctx, filter)
 }
 
-func (ch *ChatUseCase) GetChatMessages(ctx context.Context, chatID int64) (entity.ChatMessagesResponse, error) {
-	return ch.chatService.GetChatMessages(ctx, chatID)
+// SearchMessages searches one chat of the user or, without a chat ID, every chat the user participates in
+func (ch *ChatUseCase) SearchMessages(ctx context.Context, request entity.SearchMessagesRequest) (entity.SearchMessagesResponse, error) {
+	if request.ChatID != 0 {
+		if _, err := ch.participantChat(ctx, request.ChatID, request.UserID); err != nil {
+			return entity.SearchMessagesResponse{}, err
+		}
+	}
+
+	return ch.chatService.SearchMessages(ctx, request)
 }
 
 func (ch *ChatUseCase) GetChat(ctx context.Context, chatID int64) (entity.Chat, error) {
@@ -87,3 +569,161 @@ func (ch *ChatUseCase) GetChat(ctx context.Context, chatID int64) (entity.Chat,
 func (ch *ChatUseCase) GetMessage(ctx context.Context, messageID int64) (entity.Message, error) {
 	return ch.chatService.GetMessage(ctx, messageID)
 }
+
+// Typing starts, refreshes or stops a typing or recording signal of the user in the chat. The signal reaches
+// the members which opened the chat, repeats are rate limited and the signal is never stored.
+func (ch *ChatUseCase) Typing(ctx context.Context, userID, chatID int64, action string) error {
+	if action == "" {
+		action = entity.SignalTyping
+	}
+
+	if action != entity.SignalTyping && action != entity.SignalRecording && action != entity.SignalStopped {
+		return ErrInvalidSignal
+	}
+
+	return ch.hub.Signal(int(chatID), int(userID), action, func() ([]int, error) {
+		if _, err := ch.participantChat(ctx, chatID, userID); err != nil {
+			return nil, err
+		}
+
+		members, err := ch.chatService.ChatParticipants(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+
+		return memberIDs(members, int(userID)), nil
+	})
+}
+
+// MarkRead moves the read cursor of the user in the chat up to messageID (the latest message when 0),
+// clears the chat notifications of the user and sends the read receipt to the other members
+func (ch *ChatUseCase) MarkRead(ctx context.Context, userID, chatID, messageID int64) error {
+	if _, err := ch.participantChat(ctx, chatID, userID); err != nil {
+		return err
+	}
+
+	if messageID != 0 {
+		message, err := ch.chatService.GetMessage(ctx, messageID)
+		if err != nil {
+			return err
+		}
+
+		if int64(message.ChatId) != chatID {
+			return sql.ErrNoRows
+		}
+	}
+
+	lastRead, err := ch.chatService.MarkRead(ctx, userID, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.notificationService.MarkRead(ctx, userID, chatID, lastRead); err != nil {
+		return err
+	}
+
+	members, err := ch.chatService.ChatParticipants(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameMarkRead, "", entity.ReceiptEvent{
+		ChatID:    int(chatID),
+		UserID:    int(userID),
+		MessageID: int(lastRead),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, memberID := range memberIDs(members, int(userID)) {
+		ch.hub.SendToUser(memberID, frame)
+	}
+
+	return nil
+}
+
+// ChatReceipts returns the delivery and read cursors of the chat members to a participant of the chat
+func (ch *ChatUseCase) ChatReceipts(ctx context.Context, userID, chatID int64) (entity.ChatReceiptsResponse, error) {
+	if _, err := ch.participantChat(ctx, chatID, userID); err != nil {
+		return entity.ChatReceiptsResponse{}, err
+	}
+
+	members, err := ch.chatService.ChatMembers(ctx, chatID)
+	if err != nil {
+		return entity.ChatReceiptsResponse{}, err
+	}
+
+	return entity.ChatReceiptsResponse{
+		Members: members,
+	}, nil
+}
+
+// participantChat returns the chat, or ErrForbidden when the user does not participate in it
+func (ch *ChatUseCase) participantChat(ctx context.Context, chatID, userID int64) (entity.Chat, error) {
+	chatResponse, err := ch.chatService.GetChat(ctx, chatID)
+	if err != nil {
+		return entity.Chat{}, err
+	}
+
+	isParticipant, err := ch.chatService.IsParticipant(ctx, chatID, userID)
+	if err != nil {
+		return entity.Chat{}, err
+	}
+
+	if !isParticipant {
+		return entity.Chat{}, ErrForbidden
+	}
+
+	return chatResponse, nil
+}
+
+// receiver returns the group of a group chat or the counterpart of the sender in a private chat
+func receiver(chatResponse entity.Chat, members []entity.GetUserResponse, sender int) int {
+	if chatResponse.ChatType == "private" {
+		if ids := memberIDs(members, sender); len(ids) > 0 {
+			return ids[0]
+		}
+	}
+
+	return chatResponse.ReceiverID
+}
+
+func memberIDs(members []entity.GetUserResponse, except int) []int {
+	var ids []int
+	for _, member := range members {
+		if member.Id != except {
+			ids = append(ids, member.Id)
+		}
+	}
+
+	return ids
+}
+
+// push queues the push notifications of a message for the members without a live connection.
+// The message is already stored and delivered, so a failure here does not fail sending it.
+func (ch *ChatUseCase) push(ctx context.Context, event entity.MessageEvent, sentAt time.Time, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	content := []rune(event.Content)
+	if len(content) > pushPreviewLength {
+		content = append(content[:pushPreviewLength], '…')
+	}
+
+	payload, err := json.Marshal(entity.PushPayload{
+		Event:     entity.FrameMessageNew,
+		ChatID:    event.ChatID,
+		ChatType:  event.ChatType,
+		MessageID: event.MessageID,
+		Sender:    event.Sender,
+		Content:   string(content),
+		SentAt:    sentAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ch.pushService.Enqueue(ctx, int64(event.ChatID), userIDs, payload)
+}
@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"database/sql"
+	"errors"
+)
+
+// ErrorCode sorts an error of the chat use case into the kind of failure it is, the websocket error frames
+// carry it and the HTTP controller turns it into a status, so every error is classified in one place
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entity.ErrCodeNotFound
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrDeleteWindow), errors.Is(err, ErrMuted), errors.Is(err, ErrBanned):
+		return entity.ErrCodeForbidden
+	case errors.Is(err, ErrSlowMode):
+		return entity.ErrCodeRateLimited
+	case isBadRequest(err):
+		return entity.ErrCodeBadRequest
+	default:
+		return entity.ErrCodeInternal
+	}
+}
+
+// isBadRequest reports whether the error is caused by invalid input of the caller
+func isBadRequest(err error) bool {
+	for _, target := range []error{
+		ErrInvalidChatType,
+		ErrSelfChat,
+		ErrInvalidGroupRole,
+		ErrOwnerLeave,
+		ErrInvalidVisibility,
+		ErrInvalidInvite,
+		ErrInvalidLimits,
+		ErrInvalidMessageType,
+		ErrInvalidAttachment,
+		ErrEmptyMessage,
+		ErrInvalidReply,
+		ErrInvalidReaction,
+		ErrInvalidDeleteScope,
+		ErrInvalidSignal,
+		ErrMessageBlocked,
+		ErrInvalidRestriction,
+		ErrInvalidSlowMode,
+		ErrInvalidFilter,
+		ErrTooManyFilters,
+		ErrInvalidReport,
+		ErrInvalidReportStatus,
+		ErrInvalidRetention,
+		ErrInvalidExportFormat,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
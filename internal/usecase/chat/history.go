@@ -0,0 +1,58 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/websocket"
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidDeleteScope = errors.New("property scope must be 'me' or 'everyone'")
+	ErrDeleteWindow       = errors.New("the message is too old to be deleted for everyone")
+)
+
+// hideMessage deletes the message for the user only, the other connections of the user are told to drop it
+func (ch *ChatUseCase) hideMessage(ctx context.Context, chatResponse entity.Chat, message entity.Message, userID int64) error {
+	if err := ch.chatService.HideMessage(ctx, int64(message.ID), userID); err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameMessageHidden, "", entity.MessageEvent{
+		MessageID:   message.ID,
+		ChatID:      message.ChatId,
+		ChatType:    chatResponse.ChatType,
+		MessageType: message.MessageType,
+		Sender:      message.Sender,
+	})
+	if err != nil {
+		return err
+	}
+
+	ch.hub.SendToUser(int(userID), frame)
+
+	return nil
+}
+
+// MessageHistory returns the edit history of a message to a participant of its chat
+func (ch *ChatUseCase) MessageHistory(ctx context.Context, userID, messageID int64) (entity.MessageHistoryResponse, error) {
+	message, err := ch.chatService.GetMessage(ctx, messageID)
+	if err != nil {
+		return entity.MessageHistoryResponse{}, err
+	}
+
+	if _, err := ch.participantChat(ctx, int64(message.ChatId), userID); err != nil {
+		return entity.MessageHistoryResponse{}, err
+	}
+
+	edits, err := ch.chatService.MessageEdits(ctx, messageID)
+	if err != nil {
+		return entity.MessageHistoryResponse{}, err
+	}
+
+	return entity.MessageHistoryResponse{
+		MessageID: message.ID,
+		Content:   message.Content,
+		Edits:     edits,
+	}, nil
+}
@@ -2,26 +2,66 @@ package chat
 
 import (
 	"archv1/internal/entity"
+	"archv1/internal/websocket"
 	"context"
 )
 
 type ChatUseCaseI interface {
 	UserGroups(ctx context.Context, userID int64) ([]entity.GetGroupResponse, error)
-	GroupUsers(ctx context.Context, groupID int64) ([]entity.GetUserResponse, error)
-	GetGroup(ctx context.Context, groupID int64) (entity.GetGroupResponse, error)
+	GroupUsers(ctx context.Context, userID, groupID int64) ([]entity.GetUserResponse, error)
+	GetGroup(ctx context.Context, userID, groupID int64) (entity.GetGroupResponse, error)
 	CreateGroup(ctx context.Context, group entity.CreateGroupRequest) (entity.CreateGroupResponse, error)
 	UpdateGroup(ctx context.Context, group entity.UpdateGroupRequest) (entity.UpdateGroupResponse, error)
 	UpdateGroupColumns(ctx context.Context, fields entity.UpdateGroupColumns) (entity.UpdateGroupResponse, error)
 	DeleteGroup(ctx context.Context, groupID, deletedBy int64) (entity.DeleteGroupResponse, error)
-	AddUserToGroup(ctx context.Context, userID, groupID int64) error
-	RemoveUserFromGroup(ctx context.Context, userID, groupID int64) error
+	AddUserToGroup(ctx context.Context, actorID, userID, groupID int64) error
+	RemoveUserFromGroup(ctx context.Context, actorID, userID, groupID int64) error
+	GroupMembers(ctx context.Context, userID, groupID int64) (entity.GroupMembersResponse, error)
+	SetGroupRole(ctx context.Context, actorID int64, request entity.SetGroupRoleRequest) error
+	TransferGroupOwnership(ctx context.Context, ownerID int64, request entity.TransferOwnershipRequest) error
+	CreateInvite(ctx context.Context, request entity.CreateInviteRequest) (entity.GroupInvite, error)
+	GroupInvites(ctx context.Context, userID, groupID int64) (entity.GroupInvitesResponse, error)
+	RevokeInvite(ctx context.Context, userID, groupID, inviteID int64) error
+	JoinByInvite(ctx context.Context, userID int64, token string) (entity.JoinGroupResponse, error)
+	JoinByUsername(ctx context.Context, userID int64, username string) (entity.JoinGroupResponse, error)
+	JoinRequests(ctx context.Context, userID, groupID int64) (entity.JoinRequestsResponse, error)
+	ReviewJoinRequest(ctx context.Context, userID, requestID int64, approve bool) error
+	RestrictMember(ctx context.Context, actorID int64, request entity.RestrictMemberRequest) (entity.GroupRestriction, error)
+	LiftRestriction(ctx context.Context, actorID, groupID, userID int64, kind string) error
+	GroupRestrictions(ctx context.Context, userID, groupID int64) (entity.GroupRestrictionsResponse, error)
+	SetSlowMode(ctx context.Context, userID, groupID int64, seconds int) error
+	WordFilters(ctx context.Context, userID, groupID int64) (entity.WordFiltersResponse, error)
+	CreateWordFilter(ctx context.Context, request entity.CreateWordFilterRequest) (entity.WordFilter, error)
+	DeleteWordFilter(ctx context.Context, userID, groupID, filterID int64) error
+	ReportMessage(ctx context.Context, request entity.ReportMessageRequest) (entity.MessageReport, error)
+	MessageReports(ctx context.Context, userID, groupID int64, status string) (entity.MessageReportsResponse, error)
+	ReviewReport(ctx context.Context, userID, reportID int64, status string) error
 	CreateChat(ctx context.Context, receiverID, creator int64, chatType string) (entity.CreatedChatResponse, error)
-	DeleteChat(ctx context.Context, chatID int64) error
+	DeleteChat(ctx context.Context, userID, chatID int64) error
 	UserChats(ctx context.Context, userID int64) (entity.UserChatsResponse, error)
-	SendMessage(ctx context.Context, message entity.SendMessageRequest) error
+	OpenDirectChat(ctx context.Context, userID, peerID int64) (entity.CreatedChatResponse, error)
+	CheckChatUpload(ctx context.Context, userID, chatID int64) error
+	UploadChatFile(ctx context.Context, file entity.ChatFile) (entity.ChatFile, error)
+	ChatFile(ctx context.Context, userID, fileID int64) (entity.ChatFile, error)
+	SendMessage(ctx context.Context, message entity.SendMessageRequest) (entity.MessageEvent, error)
+	ForwardMessage(ctx context.Context, request entity.ForwardMessageRequest) (entity.MessageEvent, error)
+	ToggleReaction(ctx context.Context, request entity.ReactionRequest) (entity.ReactionEvent, error)
+	PinMessage(ctx context.Context, userID, messageID int64) error
+	UnpinMessage(ctx context.Context, userID, messageID int64) error
+	PinnedMessages(ctx context.Context, userID, chatID int64) (entity.PinnedMessagesResponse, error)
 	UpdateMessage(ctx context.Context, message entity.UpdateMessageRequest) error
-	DeleteMessage(ctx context.Context, messageID int64) error
-	GetChatMessages(ctx context.Context, chatID int64) (entity.ChatMessagesResponse, error)
+	DeleteMessage(ctx context.Context, messageID, deletedBy int64, scope string) error
+	MessageHistory(ctx context.Context, userID, messageID int64) (entity.MessageHistoryResponse, error)
+	GetChatMessages(ctx context.Context, filter entity.MessageFilter) (entity.ChatMessagesResponse, error)
+	ChatRetention(ctx context.Context, userID, chatID int64) (entity.ChatRetention, error)
+	SetChatRetention(ctx context.Context, userID, chatID int64, days int) error
+	ChatExport(ctx context.Context, request entity.ExportChatRequest) (entity.ChatExport, error)
+	ExportMessages(ctx context.Context, export entity.ChatExport, write func(messages []entity.ChatMessage) error) error
+	SearchMessages(ctx context.Context, request entity.SearchMessagesRequest) (entity.SearchMessagesResponse, error)
 	GetChat(ctx context.Context, chatID int64) (entity.Chat, error)
 	GetMessage(ctx context.Context, messageID int64) (entity.Message, error)
+	Typing(ctx context.Context, userID, chatID int64, action string) error
+	MarkRead(ctx context.Context, userID, chatID, messageID int64) error
+	ChatReceipts(ctx context.Context, userID, chatID int64) (entity.ChatReceiptsResponse, error)
+	HandleFrame(ctx context.Context, conn *websocket.Connection, frame entity.Frame) entity.Frame
 }
@@ -0,0 +1,243 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/websocket"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+)
+
+var (
+	ErrInvalidVisibility = errors.New("property visibility must be 'public' or 'private'")
+	ErrInvalidInvite     = errors.New("the invite link is revoked, expired or used up")
+	ErrInvalidLimits     = errors.New("properties expires_in and usage_limit must not be negative")
+)
+
+// CreateInvite creates an invite link to the group on behalf of a member allowed to invite
+func (ch *ChatUseCase) CreateInvite(ctx context.Context, request entity.CreateInviteRequest) (entity.GroupInvite, error) {
+	if request.ExpiresIn < 0 || request.UsageLimit < 0 {
+		return entity.GroupInvite{}, ErrInvalidLimits
+	}
+
+	if _, err := ch.authorize(ctx, int64(request.GroupID), int64(request.CreatedBy), permInvite); err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	token, err := inviteToken()
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	invite, err := ch.chatService.CreateInvite(ctx, request, token)
+	if err != nil {
+		return entity.GroupInvite{}, err
+	}
+
+	invite.Link = inviteLink(invite.Token)
+
+	return invite, nil
+}
+
+// GroupInvites returns the usable invite links of the group to members managing join requests
+func (ch *ChatUseCase) GroupInvites(ctx context.Context, userID, groupID int64) (entity.GroupInvitesResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permManageJoins); err != nil {
+		return entity.GroupInvitesResponse{}, err
+	}
+
+	invites, err := ch.chatService.GroupInvites(ctx, groupID)
+	if err != nil {
+		return entity.GroupInvitesResponse{}, err
+	}
+
+	for i := range invites {
+		invites[i].Link = inviteLink(invites[i].Token)
+	}
+
+	return entity.GroupInvitesResponse{
+		Invites: invites,
+	}, nil
+}
+
+func (ch *ChatUseCase) RevokeInvite(ctx context.Context, userID, groupID, inviteID int64) error {
+	if _, err := ch.authorize(ctx, groupID, userID, permManageJoins); err != nil {
+		return err
+	}
+
+	return ch.chatService.RevokeInvite(ctx, groupID, inviteID, userID)
+}
+
+// JoinByInvite adds the user to the group of the invite link
+func (ch *ChatUseCase) JoinByInvite(ctx context.Context, userID int64, token string) (entity.JoinGroupResponse, error) {
+	invite, err := ch.chatService.GetInvite(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.JoinGroupResponse{}, ErrInvalidInvite
+	}
+	if err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	response := entity.JoinGroupResponse{
+		GroupID: invite.GroupID,
+		Status:  "joined",
+	}
+
+	isMember, err := ch.isGroupMember(ctx, int64(invite.GroupID), userID)
+	if err != nil || isMember {
+		return response, err
+	}
+
+	if err := ch.checkRestricted(ctx, int64(invite.GroupID), userID, entity.RestrictionBan); err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	err = ch.chatService.UseInvite(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.JoinGroupResponse{}, ErrInvalidInvite
+	}
+	if err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	return response, ch.joinGroup(ctx, int64(invite.GroupID), userID, userID)
+}
+
+// JoinByUsername adds the user to a public group, for a private group a join request is created
+// for the group admins to review
+func (ch *ChatUseCase) JoinByUsername(ctx context.Context, userID int64, username string) (entity.JoinGroupResponse, error) {
+	group, err := ch.chatService.GetGroupByUsername(ctx, username)
+	if err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	response := entity.JoinGroupResponse{
+		GroupID: group.GroupId,
+		Status:  "joined",
+	}
+
+	isMember, err := ch.isGroupMember(ctx, int64(group.GroupId), userID)
+	if err != nil || isMember {
+		return response, err
+	}
+
+	if err := ch.checkRestricted(ctx, int64(group.GroupId), userID, entity.RestrictionBan); err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	if group.Visibility == entity.GroupPublic {
+		return response, ch.joinGroup(ctx, int64(group.GroupId), userID, userID)
+	}
+
+	request, err := ch.chatService.CreateJoinRequest(ctx, int64(group.GroupId), userID)
+	if err != nil {
+		return entity.JoinGroupResponse{}, err
+	}
+
+	response.Status = request.Status
+	response.RequestID = request.ID
+
+	return response, nil
+}
+
+func (ch *ChatUseCase) JoinRequests(ctx context.Context, userID, groupID int64) (entity.JoinRequestsResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permManageJoins); err != nil {
+		return entity.JoinRequestsResponse{}, err
+	}
+
+	requests, err := ch.chatService.JoinRequests(ctx, groupID)
+	if err != nil {
+		return entity.JoinRequestsResponse{}, err
+	}
+
+	return entity.JoinRequestsResponse{
+		Requests: requests,
+	}, nil
+}
+
+// ReviewJoinRequest approves or rejects a pending join request, an approved user joins the group
+func (ch *ChatUseCase) ReviewJoinRequest(ctx context.Context, userID, requestID int64, approve bool) error {
+	request, err := ch.chatService.GetJoinRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ch.authorize(ctx, int64(request.GroupID), userID, permManageJoins); err != nil {
+		return err
+	}
+
+	status := entity.JoinRequestRejected
+	if approve {
+		status = entity.JoinRequestApproved
+	}
+
+	if err := ch.chatService.ReviewJoinRequest(ctx, requestID, userID, status); err != nil {
+		return err
+	}
+
+	if !approve {
+		return nil
+	}
+
+	return ch.joinGroup(ctx, int64(request.GroupID), int64(request.UserID), userID)
+}
+
+func (ch *ChatUseCase) isGroupMember(ctx context.Context, groupID, userID int64) (bool, error) {
+	_, err := ch.chatService.GroupMember(ctx, groupID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// joinGroup adds the user to the group and tells the members about it, a banned user cannot join
+func (ch *ChatUseCase) joinGroup(ctx context.Context, groupID, userID, actorID int64) error {
+	if err := ch.checkRestricted(ctx, groupID, userID, entity.RestrictionBan); err != nil {
+		return err
+	}
+
+	if err := ch.chatService.AddUserToGroup(ctx, userID, groupID); err != nil {
+		return err
+	}
+
+	return ch.memberEvent(ctx, entity.FrameMemberJoined, groupID, userID, actorID)
+}
+
+// memberEvent sends a join or leave event to the group members and to the user who joined or left
+func (ch *ChatUseCase) memberEvent(ctx context.Context, frameType string, groupID, userID, actorID int64) error {
+	members, err := ch.chatService.GroupUsers(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(frameType, "", entity.GroupMemberEvent{
+		GroupID: int(groupID),
+		UserID:  int(userID),
+		ActorID: int(actorID),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, memberID := range memberIDs(members, int(userID)) {
+		ch.hub.SendToUser(memberID, frame)
+	}
+	ch.hub.SendToUser(int(userID), frame)
+
+	return nil
+}
+
+func inviteToken() (string, error) {
+	token := make([]byte, 18)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func inviteLink(token string) string {
+	return "/v1/group/join/" + token
+}
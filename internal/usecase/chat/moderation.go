@@ -0,0 +1,473 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/websocket"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrMuted               = errors.New("muted members cannot post in this group")
+	ErrBanned              = errors.New("banned users cannot join this group")
+	ErrSlowMode            = errors.New("slow mode is on in this group")
+	ErrMessageBlocked      = errors.New("the message contains words blocked in this group")
+	ErrInvalidRestriction  = errors.New("property user_id must be another user and duration must not be negative")
+	ErrInvalidSlowMode     = errors.New("property seconds must be between 0 and 86400")
+	ErrInvalidFilter       = errors.New("property pattern must be a non-empty word or expression of up to 200 characters and action 'block' or 'mask'")
+	ErrTooManyFilters      = errors.New("a group can have at most 200 word filters")
+	ErrInvalidReport       = errors.New("only messages of other members in a group chat can be reported, with a reason of up to 1000 characters")
+	ErrInvalidReportStatus = errors.New("property status must be 'open', 'resolved' or 'dismissed', a review has to close the report")
+)
+
+const (
+	maxSlowMode     = 86400
+	maxWordFilters  = 200
+	maxReportReason = 1000
+)
+
+// RestrictMember mutes or bans a user ranked below the actor. A banned member is removed from the group,
+// the members and the restricted user get a restriction event.
+func (ch *ChatUseCase) RestrictMember(ctx context.Context, actorID int64, request entity.RestrictMemberRequest) (entity.GroupRestriction, error) {
+	if request.Kind != entity.RestrictionMute && request.Kind != entity.RestrictionBan {
+		return entity.GroupRestriction{}, ErrInvalidRestriction
+	}
+
+	if request.Duration < 0 || int64(request.UserID) == actorID {
+		return entity.GroupRestriction{}, ErrInvalidRestriction
+	}
+
+	actor, err := ch.authorize(ctx, int64(request.GroupID), actorID, permRestrictMembers)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	target, err := ch.outranks(ctx, actor, int64(request.UserID))
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	// a user can be banned before joining, only members can be muted
+	if target.UserID == 0 {
+		if request.Kind == entity.RestrictionMute {
+			return entity.GroupRestriction{}, sql.ErrNoRows
+		}
+
+		if _, err := ch.userService.GetByID(ctx, request.UserID); err != nil {
+			return entity.GroupRestriction{}, err
+		}
+	}
+
+	request.Reason = strings.TrimSpace(request.Reason)
+
+	restriction, err := ch.chatService.RestrictMember(ctx, request, actorID)
+	if err != nil {
+		return entity.GroupRestriction{}, err
+	}
+
+	if request.Kind == entity.RestrictionBan && target.UserID != 0 {
+		if err := ch.chatService.RemoveUserFromGroup(ctx, int64(request.UserID), int64(request.GroupID)); err != nil {
+			return entity.GroupRestriction{}, err
+		}
+
+		if err := ch.memberEvent(ctx, entity.FrameMemberLeft, int64(request.GroupID), int64(request.UserID), actorID); err != nil {
+			return entity.GroupRestriction{}, err
+		}
+	}
+
+	return restriction, ch.restrictionEvent(ctx, entity.RestrictionEvent{
+		GroupID:   restriction.GroupID,
+		UserID:    restriction.UserID,
+		ActorID:   int(actorID),
+		Kind:      restriction.Kind,
+		ExpiresAt: restriction.ExpiresAt,
+	})
+}
+
+// LiftRestriction removes a mute or ban before it expires, a lifted ban does not add the user back
+func (ch *ChatUseCase) LiftRestriction(ctx context.Context, actorID, groupID, userID int64, kind string) error {
+	actor, err := ch.authorize(ctx, groupID, actorID, permRestrictMembers)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ch.outranks(ctx, actor, userID); err != nil {
+		return err
+	}
+
+	if err := ch.chatService.LiftRestriction(ctx, groupID, userID, kind); err != nil {
+		return err
+	}
+
+	return ch.restrictionEvent(ctx, entity.RestrictionEvent{
+		GroupID: int(groupID),
+		UserID:  int(userID),
+		ActorID: int(actorID),
+		Kind:    kind,
+		Lifted:  true,
+	})
+}
+
+func (ch *ChatUseCase) GroupRestrictions(ctx context.Context, userID, groupID int64) (entity.GroupRestrictionsResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permRestrictMembers); err != nil {
+		return entity.GroupRestrictionsResponse{}, err
+	}
+
+	restrictions, err := ch.chatService.GroupRestrictions(ctx, groupID)
+	if err != nil {
+		return entity.GroupRestrictionsResponse{}, err
+	}
+
+	return entity.GroupRestrictionsResponse{
+		Restrictions: restrictions,
+	}, nil
+}
+
+// SetSlowMode limits members below moderator to one message per the given seconds, 0 turns it off
+func (ch *ChatUseCase) SetSlowMode(ctx context.Context, userID, groupID int64, seconds int) error {
+	if seconds < 0 || seconds > maxSlowMode {
+		return ErrInvalidSlowMode
+	}
+
+	if _, err := ch.authorize(ctx, groupID, userID, permConfigureModeration); err != nil {
+		return err
+	}
+
+	return ch.chatService.SetSlowMode(ctx, groupID, userID, seconds)
+}
+
+func (ch *ChatUseCase) WordFilters(ctx context.Context, userID, groupID int64) (entity.WordFiltersResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permConfigureModeration); err != nil {
+		return entity.WordFiltersResponse{}, err
+	}
+
+	filters, err := ch.chatService.WordFilters(ctx, groupID)
+	if err != nil {
+		return entity.WordFiltersResponse{}, err
+	}
+
+	return entity.WordFiltersResponse{
+		Filters: filters,
+	}, nil
+}
+
+// CreateWordFilter adds a word or regular expression filter to the group. An expression
+// matching the empty string is rejected as it would match every message.
+func (ch *ChatUseCase) CreateWordFilter(ctx context.Context, request entity.CreateWordFilterRequest) (entity.WordFilter, error) {
+	request.Pattern = strings.TrimSpace(request.Pattern)
+	if request.Pattern == "" || utf8.RuneCountInString(request.Pattern) > 200 {
+		return entity.WordFilter{}, ErrInvalidFilter
+	}
+
+	if request.Action != entity.FilterBlock && request.Action != entity.FilterMask {
+		return entity.WordFilter{}, ErrInvalidFilter
+	}
+
+	if request.Regex {
+		expression, err := regexp.Compile(request.Pattern)
+		if err != nil || expression.MatchString("") {
+			return entity.WordFilter{}, ErrInvalidFilter
+		}
+	}
+
+	if _, err := ch.authorize(ctx, int64(request.GroupID), int64(request.CreatedBy), permConfigureModeration); err != nil {
+		return entity.WordFilter{}, err
+	}
+
+	filters, err := ch.chatService.WordFilters(ctx, int64(request.GroupID))
+	if err != nil {
+		return entity.WordFilter{}, err
+	}
+
+	if len(filters) >= maxWordFilters {
+		return entity.WordFilter{}, ErrTooManyFilters
+	}
+
+	return ch.chatService.CreateWordFilter(ctx, request)
+}
+
+func (ch *ChatUseCase) DeleteWordFilter(ctx context.Context, userID, groupID, filterID int64) error {
+	if _, err := ch.authorize(ctx, groupID, userID, permConfigureModeration); err != nil {
+		return err
+	}
+
+	return ch.chatService.DeleteWordFilter(ctx, groupID, filterID)
+}
+
+// ReportMessage queues a message of another group member for the moderators to review,
+// the moderators online get a report event
+func (ch *ChatUseCase) ReportMessage(ctx context.Context, request entity.ReportMessageRequest) (entity.MessageReport, error) {
+	request.Reason = strings.TrimSpace(request.Reason)
+	if utf8.RuneCountInString(request.Reason) > maxReportReason {
+		return entity.MessageReport{}, ErrInvalidReport
+	}
+
+	message, err := ch.chatService.GetMessage(ctx, int64(request.MessageID))
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	chatResponse, err := ch.participantChat(ctx, int64(message.ChatId), int64(request.ReportedBy))
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	if chatResponse.ChatType != "group" || message.Sender == request.ReportedBy || message.MessageType == entity.MessageSystem {
+		return entity.MessageReport{}, ErrInvalidReport
+	}
+
+	report, err := ch.chatService.ReportMessage(ctx, entity.MessageReport{
+		GroupID:    chatResponse.ReceiverID,
+		ChatID:     message.ChatId,
+		MessageID:  message.ID,
+		Content:    message.Content,
+		ReportedBy: request.ReportedBy,
+		Reason:     request.Reason,
+	})
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	members, err := ch.chatService.GroupMembers(ctx, int64(report.GroupID))
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameReported, "", report)
+	if err != nil {
+		return entity.MessageReport{}, err
+	}
+
+	for _, member := range members {
+		if permReviewReports.grantedTo(member.Role) {
+			ch.hub.SendToUser(member.UserID, frame)
+		}
+	}
+
+	return report, nil
+}
+
+// MessageReports returns the reports of the group with the status, open reports by default
+func (ch *ChatUseCase) MessageReports(ctx context.Context, userID, groupID int64, status string) (entity.MessageReportsResponse, error) {
+	if status == "" {
+		status = entity.ReportOpen
+	}
+
+	if status != entity.ReportOpen && status != entity.ReportResolved && status != entity.ReportDismissed {
+		return entity.MessageReportsResponse{}, ErrInvalidReportStatus
+	}
+
+	if _, err := ch.authorize(ctx, groupID, userID, permReviewReports); err != nil {
+		return entity.MessageReportsResponse{}, err
+	}
+
+	reports, err := ch.chatService.MessageReports(ctx, groupID, status)
+	if err != nil {
+		return entity.MessageReportsResponse{}, err
+	}
+
+	return entity.MessageReportsResponse{
+		Reports: reports,
+	}, nil
+}
+
+// ReviewReport resolves or dismisses an open report, acting on the message is left to the moderator
+func (ch *ChatUseCase) ReviewReport(ctx context.Context, userID, reportID int64, status string) error {
+	if status != entity.ReportResolved && status != entity.ReportDismissed {
+		return ErrInvalidReportStatus
+	}
+
+	report, err := ch.chatService.GetMessageReport(ctx, reportID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ch.authorize(ctx, int64(report.GroupID), userID, permReviewReports); err != nil {
+		return err
+	}
+
+	return ch.chatService.ReviewReport(ctx, reportID, userID, status)
+}
+
+// moderate applies the group rules to a new message: muted members cannot post, members below
+// moderator have to wait out the slow mode and the word filters block or mask the content
+func (ch *ChatUseCase) moderate(ctx context.Context, chatResponse entity.Chat, message *entity.SendMessageRequest) error {
+	if chatResponse.ChatType != "group" {
+		return nil
+	}
+
+	groupID := int64(chatResponse.ReceiverID)
+
+	if err := ch.checkRestricted(ctx, groupID, int64(message.Sender), entity.RestrictionMute); err != nil {
+		return err
+	}
+
+	member, err := ch.chatService.GroupMember(ctx, groupID, int64(message.Sender))
+	if err != nil {
+		return err
+	}
+
+	if !permRestrictMembers.grantedTo(member.Role) {
+		wait, err := ch.chatService.SlowModeWait(ctx, groupID, chatResponse.ID, int64(message.Sender))
+		if err != nil {
+			return err
+		}
+
+		if wait > 0 {
+			return fmt.Errorf("%w, wait %d seconds", ErrSlowMode, wait)
+		}
+	}
+
+	content, err := ch.filterContent(ctx, groupID, message.Message)
+	if err != nil {
+		return err
+	}
+
+	message.Message = content
+
+	return nil
+}
+
+// checkRestricted returns ErrMuted or ErrBanned while the user has an active restriction of the kind
+func (ch *ChatUseCase) checkRestricted(ctx context.Context, groupID, userID int64, kind string) error {
+	_, err := ch.chatService.ActiveRestriction(ctx, groupID, userID, kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if kind == entity.RestrictionBan {
+		return ErrBanned
+	}
+
+	return ErrMuted
+}
+
+// filterContent rejects content matched by a block filter of the group and masks the matches of the
+// mask filters with asterisks. Block filters are checked first so masking cannot hide a blocked word.
+func (ch *ChatUseCase) filterContent(ctx context.Context, groupID int64, content string) (string, error) {
+	if content == "" {
+		return content, nil
+	}
+
+	filters, err := ch.chatService.WordFilters(ctx, groupID)
+	if err != nil {
+		return "", err
+	}
+
+	var masks [][]int
+	for _, filter := range filters {
+		matches, err := filterMatches(filter, content)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		if filter.Action == entity.FilterBlock {
+			return "", ErrMessageBlocked
+		}
+
+		masks = append(masks, matches...)
+	}
+
+	return mask(content, masks), nil
+}
+
+// filterMatches returns the byte ranges of the content matched by the filter. A plain pattern
+// matches whole words ignoring case, an expression is used as written.
+func filterMatches(filter entity.WordFilter, content string) ([][]int, error) {
+	pattern := filter.Pattern
+	if !filter.Regex {
+		pattern = "(?i)" + regexp.QuoteMeta(pattern)
+	}
+
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches [][]int
+	for _, match := range expression.FindAllStringIndex(content, -1) {
+		if match[0] == match[1] {
+			continue
+		}
+
+		if !filter.Regex && !isWholeWord(content, match[0], match[1]) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+func isWholeWord(content string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(content[:start])
+	after, _ := utf8.DecodeRuneInString(content[end:])
+
+	return (start == 0 || !isWordRune(before)) && (end == len(content) || !isWordRune(after))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// mask replaces every rune inside the ranges with an asterisk, the ranges may overlap
+func mask(content string, ranges [][]int) string {
+	if len(ranges) == 0 {
+		return content
+	}
+
+	masked := make([]bool, len(content))
+	for _, r := range ranges {
+		for i := r[0]; i < r[1]; i++ {
+			masked[i] = true
+		}
+	}
+
+	var builder strings.Builder
+	for i, r := range content {
+		if masked[i] {
+			builder.WriteByte('*')
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// restrictionEvent sends a restriction event to the group members and to the restricted user
+func (ch *ChatUseCase) restrictionEvent(ctx context.Context, event entity.RestrictionEvent) error {
+	members, err := ch.chatService.GroupUsers(ctx, int64(event.GroupID))
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(entity.FrameRestricted, "", event)
+	if err != nil {
+		return err
+	}
+
+	for _, memberID := range memberIDs(members, event.UserID) {
+		ch.hub.SendToUser(memberID, frame)
+	}
+	ch.hub.SendToUser(event.UserID, frame)
+
+	return nil
+}
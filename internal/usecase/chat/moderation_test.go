@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestFilterMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  entity.WordFilter
+		content string
+		want    [][]int
+	}{
+		{name: "a whole word", filter: entity.WordFilter{Pattern: "bad"}, content: "a bad day", want: [][]int{{2, 5}}},
+		{name: "any case", filter: entity.WordFilter{Pattern: "BAD"}, content: "so Bad", want: [][]int{{3, 6}}},
+		{name: "every occurrence", filter: entity.WordFilter{Pattern: "bad"}, content: "bad, bad", want: [][]int{{0, 3}, {5, 8}}},
+		{name: "inside a word", filter: entity.WordFilter{Pattern: "bad"}, content: "badge", want: nil},
+		{name: "next to digits and underscores", filter: entity.WordFilter{Pattern: "bad"}, content: "bad_ 1bad", want: nil},
+		{name: "a multibyte word", filter: entity.WordFilter{Pattern: "кот"}, content: "кот, котик", want: [][]int{{0, 6}}},
+		{name: "after a multibyte letter", filter: entity.WordFilter{Pattern: "bad"}, content: "ébad", want: nil},
+		{name: "between multibyte quotes", filter: entity.WordFilter{Pattern: "bad"}, content: "«bad»", want: [][]int{{2, 5}}},
+		{name: "special characters of a plain pattern", filter: entity.WordFilter{Pattern: "a.b"}, content: "axb a.b", want: [][]int{{4, 7}}},
+		{name: "an expression inside words", filter: entity.WordFilter{Pattern: "b.d", Regex: true}, content: "abide bud", want: [][]int{{1, 4}, {6, 9}}},
+		{name: "an expression matching nothing", filter: entity.WordFilter{Pattern: "x*", Regex: true}, content: "ab", want: nil},
+	}
+
+	for _, tt := range tests {
+		got, err := filterMatches(tt.filter, tt.content)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: filterMatches = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := filterMatches(entity.WordFilter{Pattern: "(", Regex: true}, "("); err == nil {
+		t.Errorf("filterMatches took a broken expression")
+	}
+}
+
+func TestIsWholeWord(t *testing.T) {
+	tests := []struct {
+		content    string
+		start, end int
+		want       bool
+	}{
+		{content: "bad", start: 0, end: 3, want: true},
+		{content: "a-bad-b", start: 2, end: 5, want: true},
+		{content: "abad", start: 1, end: 4, want: false},
+		{content: "bads", start: 0, end: 3, want: false},
+		{content: "жbad", start: 2, end: 5, want: false},
+		{content: "bad ж", start: 0, end: 3, want: true},
+		{content: "—bad—", start: 3, end: 6, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := isWholeWord(tt.content, tt.start, tt.end); got != tt.want {
+			t.Errorf("isWholeWord(%q, %d, %d) = %t, want %t", tt.content, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ranges  [][]int
+		want    string
+	}{
+		{name: "no ranges", content: "a bad day", want: "a bad day"},
+		{name: "one range", content: "a bad day", ranges: [][]int{{2, 5}}, want: "a *** day"},
+		{name: "overlapping ranges", content: "abcdef", ranges: [][]int{{1, 4}, {2, 5}}, want: "a****f"},
+		{name: "a range inside another", content: "abcdef", ranges: [][]int{{0, 6}, {2, 3}}, want: "******"},
+		{name: "touching ranges", content: "abcdef", ranges: [][]int{{0, 2}, {2, 4}}, want: "****ef"},
+		{name: "one asterisk a letter", content: "кот и пёс", ranges: [][]int{{0, 6}}, want: "*** и пёс"},
+		{name: "overlapping multibyte ranges", content: "пёс!", ranges: [][]int{{0, 4}, {2, 6}}, want: "***!"},
+	}
+
+	for _, tt := range tests {
+		if got := mask(tt.content, tt.ranges); got != tt.want {
+			t.Errorf("%s: mask = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/websocket"
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrInvalidReply    = errors.New("property reply_to must be a message of the same chat")
+	ErrInvalidReaction = errors.New("property emoji must be a single emoji of up to 32 bytes")
+)
+
+// checkReply checks that the message replied to belongs to the chat of the reply
+func (ch *ChatUseCase) checkReply(ctx context.Context, message entity.SendMessageRequest) error {
+	if message.ReplyTo == 0 {
+		return nil
+	}
+
+	original, err := ch.chatService.GetMessage(ctx, int64(message.ReplyTo))
+	if err != nil || original.ChatId != message.ChatID {
+		return ErrInvalidReply
+	}
+
+	return nil
+}
+
+// ForwardMessage copies a message the sender can read into another chat of the sender,
+// the copy keeps the original author and the attachments of the message
+func (ch *ChatUseCase) ForwardMessage(ctx context.Context, request entity.ForwardMessageRequest) (entity.MessageEvent, error) {
+	original, err := ch.chatService.GetMessage(ctx, int64(request.MessageID))
+	if err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	if _, err := ch.participantChat(ctx, int64(original.ChatId), int64(request.Sender)); err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	if original.MessageType == entity.MessageSystem {
+		return entity.MessageEvent{}, ErrInvalidMessageType
+	}
+
+	message := entity.SendMessageRequest{
+		ChatID:      request.ChatID,
+		Message:     original.Content,
+		MessageType: original.MessageType,
+		Sender:      request.Sender,
+		ForwardedFrom: &entity.ForwardOrigin{
+			UserID:    original.Sender,
+			MessageID: original.ID,
+		},
+	}
+
+	if original.ForwardedFrom != nil {
+		message.ForwardedFrom = original.ForwardedFrom
+	}
+
+	for _, attachment := range original.Attachments {
+		message.Attachments = append(message.Attachments, attachment.FileID)
+	}
+
+	chatResponse, err := ch.openChat(ctx, message)
+	if err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	message.ChatType = chatResponse.ChatType
+
+	if err := ch.moderate(ctx, chatResponse, &message); err != nil {
+		return entity.MessageEvent{}, err
+	}
+
+	return ch.postMessage(ctx, chatResponse, message)
+}
+
+// ToggleReaction adds the reaction of the user to a message or removes it when it was already given,
+// the chat members get the new reaction counts
+func (ch *ChatUseCase) ToggleReaction(ctx context.Context, request entity.ReactionRequest) (entity.ReactionEvent, error) {
+	request.Emoji = strings.TrimSpace(request.Emoji)
+	if !isEmoji(request.Emoji) {
+		return entity.ReactionEvent{}, ErrInvalidReaction
+	}
+
+	message, err := ch.chatService.GetMessage(ctx, int64(request.MessageID))
+	if err != nil {
+		return entity.ReactionEvent{}, err
+	}
+
+	if _, err := ch.participantChat(ctx, int64(message.ChatId), int64(request.UserID)); err != nil {
+		return entity.ReactionEvent{}, err
+	}
+
+	added, err := ch.chatService.ToggleReaction(ctx, int64(message.ID), int64(request.UserID), request.Emoji)
+	if err != nil {
+		return entity.ReactionEvent{}, err
+	}
+
+	reactions, err := ch.chatService.MessageReactions(ctx, int64(message.ID), 0)
+	if err != nil {
+		return entity.ReactionEvent{}, err
+	}
+
+	event := entity.ReactionEvent{
+		ChatID:    message.ChatId,
+		MessageID: message.ID,
+		UserID:    request.UserID,
+		Emoji:     request.Emoji,
+		Added:     added,
+		Reactions: reactions,
+	}
+
+	return event, ch.broadcast(ctx, int64(message.ChatId), entity.FrameReaction, event)
+}
+
+// PinMessage pins a message in its chat, in a group chat the user needs the pin permission
+func (ch *ChatUseCase) PinMessage(ctx context.Context, userID, messageID int64) error {
+	message, err := ch.pinnable(ctx, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.chatService.PinMessage(ctx, int64(message.ChatId), messageID, userID); err != nil {
+		return err
+	}
+
+	return ch.broadcast(ctx, int64(message.ChatId), entity.FramePinned, entity.PinEvent{
+		ChatID:    message.ChatId,
+		MessageID: message.ID,
+		UserID:    int(userID),
+	})
+}
+
+func (ch *ChatUseCase) UnpinMessage(ctx context.Context, userID, messageID int64) error {
+	message, err := ch.pinnable(ctx, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.chatService.UnpinMessage(ctx, int64(message.ChatId), messageID); err != nil {
+		return err
+	}
+
+	return ch.broadcast(ctx, int64(message.ChatId), entity.FrameUnpinned, entity.PinEvent{
+		ChatID:    message.ChatId,
+		MessageID: message.ID,
+		UserID:    int(userID),
+	})
+}
+
+// PinnedMessages returns the pinned messages of the chat to a participant of the chat
+func (ch *ChatUseCase) PinnedMessages(ctx context.Context, userID, chatID int64) (entity.PinnedMessagesResponse, error) {
+	if _, err := ch.participantChat(ctx, chatID, userID); err != nil {
+		return entity.PinnedMessagesResponse{}, err
+	}
+
+	messages, err := ch.chatService.PinnedMessages(ctx, chatID, userID)
+	if err != nil {
+		return entity.PinnedMessagesResponse{}, err
+	}
+
+	return entity.PinnedMessagesResponse{
+		Messages: messages,
+	}, nil
+}
+
+// pinnable returns the message when the user may pin and unpin it
+func (ch *ChatUseCase) pinnable(ctx context.Context, userID, messageID int64) (entity.Message, error) {
+	message, err := ch.chatService.GetMessage(ctx, messageID)
+	if err != nil {
+		return entity.Message{}, err
+	}
+
+	chatResponse, err := ch.participantChat(ctx, int64(message.ChatId), userID)
+	if err != nil {
+		return entity.Message{}, err
+	}
+
+	if chatResponse.ChatType == "group" {
+		if _, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), userID, permPin); err != nil {
+			return entity.Message{}, err
+		}
+	}
+
+	return message, nil
+}
+
+// broadcast sends an event to every participant of the chat
+func (ch *ChatUseCase) broadcast(ctx context.Context, chatID int64, frameType string, payload interface{}) error {
+	members, err := ch.chatService.ChatParticipants(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	frame, err := websocket.NewFrame(frameType, "", payload)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		ch.hub.SendToUser(member.Id, frame)
+	}
+
+	return nil
+}
+
+// isEmoji accepts a short string of symbols, a reaction carries no letters, digits or spaces
+func isEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > 32 {
+		return false
+	}
+
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,135 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRetention    = errors.New("property days must be between 0 and 3650")
+	ErrInvalidExportFormat = errors.New("query format must be 'json' or 'html'")
+)
+
+const (
+	maxRetentionDays = 3650
+	exportPageSize   = 500
+)
+
+// ChatRetention returns the retention applied to the chat, the global one when the chat has none
+func (ch *ChatUseCase) ChatRetention(ctx context.Context, userID, chatID int64) (entity.ChatRetention, error) {
+	if _, err := ch.participantChat(ctx, chatID, userID); err != nil {
+		return entity.ChatRetention{}, err
+	}
+
+	retention, err := ch.chatService.ChatRetention(ctx, chatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ChatRetention{
+			ChatID:    int(chatID),
+			Days:      ch.retentionDays,
+			Inherited: true,
+		}, nil
+	}
+
+	return retention, err
+}
+
+// SetChatRetention sets the days the messages of the chat are kept for, 0 falls back to the global retention.
+// In a group chat the user needs the retention permission, both users of a private chat may set it.
+func (ch *ChatUseCase) SetChatRetention(ctx context.Context, userID, chatID int64, days int) error {
+	if days < 0 || days > maxRetentionDays {
+		return ErrInvalidRetention
+	}
+
+	chatResponse, err := ch.participantChat(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if chatResponse.ChatType == "group" {
+		if _, err := ch.authorize(ctx, int64(chatResponse.ReceiverID), userID, permManageRetention); err != nil {
+			return err
+		}
+	}
+
+	return ch.chatService.SetChatRetention(ctx, chatID, userID, days)
+}
+
+// ChatExport returns the header of a chat archive to a participant of the chat or an admin
+func (ch *ChatUseCase) ChatExport(ctx context.Context, request entity.ExportChatRequest) (entity.ChatExport, error) {
+	if request.Format == "" {
+		request.Format = entity.ExportJSON
+	}
+
+	if request.Format != entity.ExportJSON && request.Format != entity.ExportHTML {
+		return entity.ChatExport{}, ErrInvalidExportFormat
+	}
+
+	var (
+		chatResponse entity.Chat
+		err          error
+	)
+
+	if request.Admin {
+		chatResponse, err = ch.chatService.GetChat(ctx, int64(request.ChatID))
+	} else {
+		chatResponse, err = ch.participantChat(ctx, int64(request.ChatID), int64(request.UserID))
+	}
+	if err != nil {
+		return entity.ChatExport{}, err
+	}
+
+	participants, err := ch.chatService.ChatParticipants(ctx, chatResponse.ID)
+	if err != nil {
+		return entity.ChatExport{}, err
+	}
+
+	members := make([]entity.ExportMember, 0, len(participants))
+	for _, participant := range participants {
+		members = append(members, entity.ExportMember{
+			UserID:   participant.Id,
+			Username: participant.Username,
+		})
+	}
+
+	return entity.ChatExport{
+		Chat:       chatResponse,
+		Format:     request.Format,
+		ExportedBy: request.UserID,
+		ExportedAt: time.Now().UTC(),
+		Members:    members,
+	}, nil
+}
+
+// ExportMessages passes the messages of the exported chat to write page by page from the oldest,
+// the messages the exporting user deleted for themselves are left out
+func (ch *ChatUseCase) ExportMessages(ctx context.Context, export entity.ChatExport, write func(messages []entity.ChatMessage) error) error {
+	filter := entity.MessageFilter{
+		ChatID: export.Chat.ID,
+		UserID: int64(export.ExportedBy),
+		Limit:  exportPageSize,
+	}
+
+	for {
+		messages, err := ch.chatService.ExportMessages(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		if err := write(messages); err != nil {
+			return err
+		}
+
+		if len(messages) < exportPageSize {
+			return nil
+		}
+
+		filter.After = int64(messages[len(messages)-1].MessageID)
+	}
+}
@@ -0,0 +1,153 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrInvalidGroupRole = errors.New("property role must be 'admin', 'moderator' or 'member'")
+	ErrOwnerLeave       = errors.New("the owner has to transfer the ownership before leaving the group")
+)
+
+type permission int
+
+const (
+	permPost permission = iota
+	permInvite
+	permPin
+	permDeleteMessages
+	permRemoveMembers
+	permManageJoins
+	permRestrictMembers
+	permReviewReports
+	permEditInfo
+	permConfigureModeration
+	permManageRetention
+	permManageRoles
+	permDeleteGroup
+	permTransferOwnership
+)
+
+var roleRanks = map[string]int{
+	entity.GroupRoleOwner:     4,
+	entity.GroupRoleAdmin:     3,
+	entity.GroupRoleModerator: 2,
+	entity.GroupRoleMember:    1,
+}
+
+// permissionRoles holds the least privileged role granted each permission
+var permissionRoles = map[permission]string{
+	permPost:                entity.GroupRoleMember,
+	permInvite:              entity.GroupRoleMember,
+	permPin:                 entity.GroupRoleModerator,
+	permDeleteMessages:      entity.GroupRoleModerator,
+	permRemoveMembers:       entity.GroupRoleModerator,
+	permManageJoins:         entity.GroupRoleModerator,
+	permRestrictMembers:     entity.GroupRoleModerator,
+	permReviewReports:       entity.GroupRoleModerator,
+	permEditInfo:            entity.GroupRoleAdmin,
+	permConfigureModeration: entity.GroupRoleAdmin,
+	permManageRetention:     entity.GroupRoleAdmin,
+	permManageRoles:         entity.GroupRoleAdmin,
+	permDeleteGroup:         entity.GroupRoleOwner,
+	permTransferOwnership:   entity.GroupRoleOwner,
+}
+
+func (p permission) grantedTo(role string) bool {
+	return roleRanks[role] >= roleRanks[permissionRoles[p]]
+}
+
+// authorize returns the membership of the user in the group when the user's role grants the permission
+func (ch *ChatUseCase) authorize(ctx context.Context, groupID, userID int64, p permission) (entity.GroupMember, error) {
+	member, err := ch.chatService.GroupMember(ctx, groupID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.GroupMember{}, ErrForbidden
+	}
+	if err != nil {
+		return entity.GroupMember{}, err
+	}
+
+	if !p.grantedTo(member.Role) {
+		return entity.GroupMember{}, ErrForbidden
+	}
+
+	return member, nil
+}
+
+// outranks checks that the actor may act on the target user of the group, a former member has no rank
+func (ch *ChatUseCase) outranks(ctx context.Context, actor entity.GroupMember, targetID int64) (entity.GroupMember, error) {
+	target, err := ch.chatService.GroupMember(ctx, int64(actor.GroupID), targetID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return entity.GroupMember{}, err
+	}
+
+	if roleRanks[actor.Role] <= roleRanks[target.Role] {
+		return entity.GroupMember{}, ErrForbidden
+	}
+
+	return target, nil
+}
+
+// GroupMembers returns the members of the group with their roles to a member of the group
+func (ch *ChatUseCase) GroupMembers(ctx context.Context, userID, groupID int64) (entity.GroupMembersResponse, error) {
+	if _, err := ch.authorize(ctx, groupID, userID, permPost); err != nil {
+		return entity.GroupMembersResponse{}, err
+	}
+
+	members, err := ch.chatService.GroupMembers(ctx, groupID)
+	if err != nil {
+		return entity.GroupMembersResponse{}, err
+	}
+
+	return entity.GroupMembersResponse{
+		Members: members,
+	}, nil
+}
+
+// SetGroupRole changes the role of a group member. The actor has to outrank both
+// the current and the new role of the member, ownership moves only by transfer.
+func (ch *ChatUseCase) SetGroupRole(ctx context.Context, actorID int64, request entity.SetGroupRoleRequest) error {
+	if request.Role == entity.GroupRoleOwner || roleRanks[request.Role] == 0 {
+		return ErrInvalidGroupRole
+	}
+
+	actor, err := ch.authorize(ctx, int64(request.GroupID), actorID, permManageRoles)
+	if err != nil {
+		return err
+	}
+
+	target, err := ch.outranks(ctx, actor, int64(request.UserID))
+	if err != nil {
+		return err
+	}
+
+	if target.UserID == 0 {
+		return sql.ErrNoRows
+	}
+
+	if roleRanks[actor.Role] <= roleRanks[request.Role] {
+		return ErrForbidden
+	}
+
+	return ch.chatService.SetGroupRole(ctx, int64(request.GroupID), int64(request.UserID), request.Role)
+}
+
+// TransferGroupOwnership hands the group over to another member, the previous owner stays as an admin
+func (ch *ChatUseCase) TransferGroupOwnership(ctx context.Context, ownerID int64, request entity.TransferOwnershipRequest) error {
+	if _, err := ch.authorize(ctx, int64(request.GroupID), ownerID, permTransferOwnership); err != nil {
+		return err
+	}
+
+	if int64(request.UserID) == ownerID {
+		return ErrForbidden
+	}
+
+	if _, err := ch.chatService.GroupMember(ctx, int64(request.GroupID), int64(request.UserID)); err != nil {
+		return err
+	}
+
+	return ch.chatService.TransferGroupOwnership(ctx, int64(request.GroupID), ownerID, int64(request.UserID))
+}
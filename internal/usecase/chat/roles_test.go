@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/service/chat"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// fakeChatService keeps the roles of the members of group 1
+type fakeChatService struct {
+	chat.ChatServiceI
+
+	roles map[int64]string
+}
+
+func (s *fakeChatService) GroupMember(_ context.Context, groupID, userID int64) (entity.GroupMember, error) {
+	role, ok := s.roles[userID]
+	if !ok {
+		return entity.GroupMember{}, sql.ErrNoRows
+	}
+
+	return entity.GroupMember{GroupID: int(groupID), UserID: int(userID), Role: role}, nil
+}
+
+func TestGrantedTo(t *testing.T) {
+	tests := []struct {
+		permission permission
+		role       string
+		want       bool
+	}{
+		{permission: permPost, role: entity.GroupRoleMember, want: true},
+		{permission: permPost, role: "", want: false},
+		{permission: permPin, role: entity.GroupRoleMember, want: false},
+		{permission: permPin, role: entity.GroupRoleModerator, want: true},
+		{permission: permPin, role: entity.GroupRoleOwner, want: true},
+		{permission: permEditInfo, role: entity.GroupRoleModerator, want: false},
+		{permission: permEditInfo, role: entity.GroupRoleAdmin, want: true},
+		{permission: permManageRoles, role: entity.GroupRoleAdmin, want: true},
+		{permission: permDeleteGroup, role: entity.GroupRoleAdmin, want: false},
+		{permission: permDeleteGroup, role: entity.GroupRoleOwner, want: true},
+		{permission: permTransferOwnership, role: entity.GroupRoleOwner, want: true},
+		{permission: permTransferOwnership, role: "banned", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.permission.grantedTo(tt.role); got != tt.want {
+			t.Errorf("permission %d granted to %q = %t, want %t", tt.permission, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestOutranks(t *testing.T) {
+	ch := &ChatUseCase{chatService: &fakeChatService{roles: map[int64]string{
+		1: entity.GroupRoleOwner,
+		2: entity.GroupRoleAdmin,
+		3: entity.GroupRoleAdmin,
+		4: entity.GroupRoleModerator,
+		5: entity.GroupRoleModerator,
+		6: entity.GroupRoleMember,
+	}}}
+
+	tests := []struct {
+		name   string
+		actor  string
+		target int64
+		want   error
+	}{
+		{name: "the owner over an admin", actor: entity.GroupRoleOwner, target: 2},
+		{name: "an admin over a moderator", actor: entity.GroupRoleAdmin, target: 4},
+		{name: "a moderator over a member", actor: entity.GroupRoleModerator, target: 6},
+		{name: "a moderator over a former member", actor: entity.GroupRoleModerator, target: 9},
+		{name: "an admin over another admin", actor: entity.GroupRoleAdmin, target: 3, want: ErrForbidden},
+		{name: "a moderator over another moderator", actor: entity.GroupRoleModerator, target: 5, want: ErrForbidden},
+		{name: "an admin over the owner", actor: entity.GroupRoleAdmin, target: 1, want: ErrForbidden},
+		{name: "a member over a member", actor: entity.GroupRoleMember, target: 6, want: ErrForbidden},
+		{name: "a member over a former member", actor: entity.GroupRoleMember, target: 9},
+	}
+
+	for _, tt := range tests {
+		_, err := ch.outranks(context.Background(), entity.GroupMember{GroupID: 1, UserID: 100, Role: tt.actor}, tt.target)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: outranks = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	target, err := ch.outranks(context.Background(), entity.GroupMember{GroupID: 1, Role: entity.GroupRoleOwner}, 6)
+	if err != nil || target.UserID != 6 || target.Role != entity.GroupRoleMember {
+		t.Errorf("outranks = %+v, %v, want the member 6", target, err)
+	}
+}
@@ -0,0 +1,126 @@
+package chat
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/websocket"
+	"context"
+	"encoding/json"
+)
+
+// HandleFrame executes a command received over the websocket on behalf of the connected user
+// and answers with an ack frame carrying the result or an error frame.
+func (ch *ChatUseCase) HandleFrame(ctx context.Context, conn *websocket.Connection, frame entity.Frame) entity.Frame {
+	if frame.Version != 0 && frame.Version != entity.ProtocolVersion {
+		return websocket.ErrorFrame(frame.ID, entity.ErrCodeUnsupported, "unsupported protocol version")
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+
+	switch frame.Type {
+	case entity.FrameSendMessage:
+		var command entity.SendMessageRequest
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		command.Sender = conn.UserID
+		result, err = ch.SendMessage(ctx, command)
+	case entity.FrameEditMessage:
+		var command entity.UpdateMessageRequest
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		command.Sender = conn.UserID
+		err = ch.UpdateMessage(ctx, command)
+		result = entity.ResponseWithStatus{Status: err == nil}
+	case entity.FrameDeleteMessage:
+		var command entity.DeleteMessageCommand
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		err = ch.DeleteMessage(ctx, int64(command.MessageID), int64(conn.UserID), command.Scope)
+		result = entity.ResponseWithStatus{Status: err == nil}
+	case entity.FrameTyping:
+		var command entity.TypingCommand
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		err = ch.Typing(ctx, int64(conn.UserID), int64(command.ChatID), command.Action)
+		result = entity.ResponseWithStatus{Status: err == nil}
+	case entity.FrameMarkRead:
+		var command entity.MarkReadCommand
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		err = ch.MarkRead(ctx, int64(conn.UserID), int64(command.ChatID), int64(command.MessageID))
+		result = entity.ResponseWithStatus{Status: err == nil}
+	case entity.FrameSubscribe:
+		var command entity.SubscribeCommand
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		err = ch.subscribe(ctx, conn, command.ChatIDs)
+		result = entity.ResponseWithStatus{Status: err == nil}
+	case entity.FrameForward:
+		var command entity.ForwardMessageRequest
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		command.Sender = conn.UserID
+		result, err = ch.ForwardMessage(ctx, command)
+	case entity.FrameReact:
+		var command entity.ReactionRequest
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		command.UserID = conn.UserID
+		result, err = ch.ToggleReaction(ctx, command)
+	case entity.FramePin, entity.FrameUnpin:
+		var command entity.PinCommand
+		if err := json.Unmarshal(frame.Payload, &command); err != nil {
+			return websocket.ErrorFrame(frame.ID, entity.ErrCodeBadRequest, err.Error())
+		}
+
+		if frame.Type == entity.FramePin {
+			err = ch.PinMessage(ctx, int64(conn.UserID), int64(command.MessageID))
+		} else {
+			err = ch.UnpinMessage(ctx, int64(conn.UserID), int64(command.MessageID))
+		}
+		result = entity.ResponseWithStatus{Status: err == nil}
+	default:
+		return websocket.ErrorFrame(frame.ID, entity.ErrCodeUnsupported, "unknown frame type: "+frame.Type)
+	}
+
+	if err != nil {
+		return websocket.ErrorFrame(frame.ID, ErrorCode(err), err.Error())
+	}
+
+	ack, err := websocket.NewFrame(entity.FrameAck, frame.ID, result)
+	if err != nil {
+		return websocket.ErrorFrame(frame.ID, entity.ErrCodeInternal, err.Error())
+	}
+
+	return ack
+}
+
+func (ch *ChatUseCase) subscribe(ctx context.Context, conn *websocket.Connection, chatIDs []int) error {
+	for _, chatID := range chatIDs {
+		if _, err := ch.participantChat(ctx, int64(chatID), int64(conn.UserID)); err != nil {
+			return err
+		}
+	}
+
+	conn.Subscribe(chatIDs...)
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"time"
+)
+
+// StoreBlob stores the body once per content for the uploads kept outside of the file store, the caller
+// records the key of the returned blob with its file or hands the reference back with ReleaseBlob
+func (f *FilesStoreUseCase) StoreBlob(ctx context.Context, body io.Reader, size int64, mimeType string) (entity.Blob, error) {
+	return f.storeBlob(ctx, body, size, mimeType)
+}
+
+// ReleaseBlob drops a reference taken by StoreBlob, the content is removed once nobody holds it
+func (f *FilesStoreUseCase) ReleaseBlob(ctx context.Context, key string) error {
+	return f.fileStoreService.ReleaseBlob(ctx, key)
+}
+
+// storeBlob hashes the body into a staging blob and moves it to the key of its content, unless the same
+// content is stored already. The returned blob holds a reference the caller hands over to a file or releases.
+func (f *FilesStoreUseCase) storeBlob(ctx context.Context, body io.Reader, size int64, mimeType string) (entity.Blob, error) {
+	staging := storage.StagingPrefix + uuid.NewString()
+
+	checksum := sha256.New()
+	if _, err := f.blobs.Put(ctx, staging, io.TeeReader(body, checksum), size, mimeType); err != nil {
+		return entity.Blob{}, err
+	}
+	// the staging blob is gone once it was moved, then this is a no-op
+	defer func() { _ = f.blobs.Delete(context.Background(), staging) }()
+
+	sum := hex.EncodeToString(checksum.Sum(nil))
+
+	blob, err := f.fileStoreService.AcquireBlob(ctx, entity.Blob{
+		Key:      storage.ContentKey(sum),
+		Checksum: sum,
+		Size:     size,
+	})
+	if err != nil {
+		return entity.Blob{}, err
+	}
+
+	// the reference keeps the collector away, a copy the verification found corrupted is replaced
+	_, err = f.blobs.Stat(ctx, blob.Key)
+	if err == nil && blob.CorruptedAt == nil {
+		return blob, nil
+	}
+
+	if err == nil || errors.Is(err, storage.ErrNotFound) {
+		err = f.blobs.Move(ctx, staging, blob.Key)
+	}
+	if err == nil && blob.CorruptedAt != nil {
+		err = f.fileStoreService.SetBlobVerified(ctx, blob.Key, sum, true)
+	}
+	if err != nil {
+		_ = f.fileStoreService.ReleaseBlob(context.Background(), blob.Key)
+		return entity.Blob{}, err
+	}
+
+	blob.CorruptedAt = nil
+
+	return blob, nil
+}
+
+// PurgeDeletedFiles releases the content of the files deleted longer ago than the trash retention,
+// their folders can not bring them back from then on
+func (f *FilesStoreUseCase) PurgeDeletedFiles(ctx context.Context, limit int) (int, error) {
+	return f.fileStoreService.PurgeDeletedFiles(ctx, time.Now().Add(-f.trashRetention), limit)
+}
+
+// CollectBlobs removes the content of the blobs nobody held during the grace period, together with
+// their image variants and the staging blobs of uploads that died before their content was moved
+func (f *FilesStoreUseCase) CollectBlobs(ctx context.Context, limit int) (int, error) {
+	keys, err := f.fileStoreService.ReleasedBlobs(ctx, time.Now().Add(-f.blobGrace), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var collected int
+	for _, key := range keys {
+		dropped, err := f.fileStoreService.DropBlob(ctx, key, func(ctx context.Context) error {
+			if err := f.blobs.Delete(ctx, key); err != nil {
+				return err
+			}
+
+			return f.deleteVariants(ctx, key)
+		})
+		if err != nil {
+			return collected, err
+		}
+
+		if dropped {
+			collected++
+		}
+	}
+
+	staged, err := f.blobs.List(ctx, storage.StagingPrefix)
+	if err != nil {
+		return collected, err
+	}
+
+	for _, blob := range staged {
+		if time.Since(blob.ModTime) < f.blobGrace {
+			continue
+		}
+
+		if err := f.blobs.Delete(ctx, blob.Key); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return collected, nil
+}
+
+// VerifyBlobs hashes the stored blobs that were not verified recently and compares them with their
+// checksums, it returns how many were verified and how many of them are missing or corrupted
+func (f *FilesStoreUseCase) VerifyBlobs(ctx context.Context, limit int) (int, int, error) {
+	blobs, err := f.fileStoreService.BlobsToVerify(ctx, time.Now().Add(-f.verifyAge), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var corrupted int
+	for _, blob := range blobs {
+		sum, err := f.hashBlob(ctx, blob.Key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return 0, corrupted, err
+		}
+
+		// a blob stored before the checksums were kept is trusted on its first verification
+		intact := err == nil && (blob.Checksum == "" || blob.Checksum == sum)
+		if !intact {
+			corrupted++
+			log.Printf("blob %s is missing or corrupted, held by %d files", blob.Key, blob.RefCount)
+		}
+
+		if err := f.fileStoreService.SetBlobVerified(ctx, blob.Key, sum, intact); err != nil {
+			return 0, corrupted, err
+		}
+	}
+
+	return len(blobs), corrupted, nil
+}
+
+func (f *FilesStoreUseCase) hashBlob(ctx context.Context, key string) (string, error) {
+	body, _, err := f.blobs.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	checksum := sha256.New()
+	if _, err := io.Copy(checksum, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(checksum.Sum(nil)), nil
+}
+
+// fileBlob is the key of the content of a file, files created with an outside link have none
+func fileBlob(file entity.GetFileResponse) (string, error) {
+	if file.BlobKey == "" {
+		return "", storage.ErrNotFound
+	}
+
+	return file.BlobKey, nil
+}
@@ -0,0 +1,153 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/signedurl"
+	"archv1/internal/pkg/storage"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrForbidden     = errors.New("you have no access to this file")
+	ErrInvalidExpiry = errors.New("property expires_in must not be negative or exceed the maximum link lifetime")
+	ErrLinkUsed      = errors.New("the download link was already used")
+	ErrLinkAddress   = errors.New("the download link was issued for another address")
+)
+
+// CreateDownloadLink signs a link to the content of a file, everyone who may see the file may create one
+func (f *FilesStoreUseCase) CreateDownloadLink(ctx context.Context, request entity.DownloadLinkRequest) (entity.DownloadLinkResponse, error) {
+	ttl := f.linkTTL
+	if request.ExpiresIn != 0 {
+		ttl = time.Duration(request.ExpiresIn) * time.Second
+	}
+
+	if request.ExpiresIn < 0 || ttl > f.maxLinkTTL {
+		return entity.DownloadLinkResponse{}, ErrInvalidExpiry
+	}
+
+	file, err := f.fileStoreService.GetFile(ctx, request.FileID)
+	if err != nil {
+		return entity.DownloadLinkResponse{}, err
+	}
+
+	if err := f.checkFileAccess(ctx, file, request.UserID, request.Admin); err != nil {
+		return entity.DownloadLinkResponse{}, err
+	}
+
+	// files created with an outside link have no content to download
+	key, err := fileBlob(file)
+	if err != nil {
+		return entity.DownloadLinkResponse{}, err
+	}
+
+	if _, err := f.blobs.Stat(ctx, key); err != nil {
+		return entity.DownloadLinkResponse{}, err
+	}
+
+	claims := signedurl.Claims{
+		FileID:    file.ID,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+		SingleUse: request.SingleUse,
+		Inline:    request.Inline,
+	}
+
+	if request.BindIP {
+		claims.IP = request.IP
+	}
+
+	token, err := f.signer.Sign(claims)
+	if err != nil {
+		return entity.DownloadLinkResponse{}, err
+	}
+
+	return entity.DownloadLinkResponse{
+		URL:       "/v1/download/" + token,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+		SingleUse: request.SingleUse,
+	}, nil
+}
+
+// OpenDownload verifies a download link and opens the file it points to. A single use link
+// is spent only once the content was found, so a failed attempt does not burn it.
+func (f *FilesStoreUseCase) OpenDownload(ctx context.Context, token, ip string) (entity.Download, *storage.Seeker, error) {
+	claims, err := f.signer.Verify(token, time.Now())
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	if claims.IP != "" && claims.IP != ip {
+		return entity.Download{}, nil, ErrLinkAddress
+	}
+
+	file, err := f.fileStoreService.GetFile(ctx, claims.FileID)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	key, err := fileBlob(file)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	content, err := storage.OpenSeeker(ctx, f.blobs, key)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	if claims.SingleUse {
+		unused, err := f.fileStoreService.UseDownloadLink(ctx, claims.Nonce, claims.FileID, time.Unix(claims.ExpiresAt, 0))
+		if err != nil {
+			return entity.Download{}, nil, err
+		}
+
+		if !unused {
+			return entity.Download{}, nil, ErrLinkUsed
+		}
+	}
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = content.Info().ContentType
+	}
+
+	return entity.Download{
+		Name:     file.Name,
+		MimeType: mimeType,
+		Inline:   claims.Inline,
+	}, content, nil
+}
+
+// PurgeDownloadLinks forgets the spent single use links that expired, returning how many
+func (f *FilesStoreUseCase) PurgeDownloadLinks(ctx context.Context) (int, error) {
+	return f.fileStoreService.PurgeDownloadLinks(ctx)
+}
+
+// checkFileAccess lets the admins, the owner of the file and whoever holds a role on it, directly or through
+// its folders, see the file. The files attached to posts and menus are seen by everyone.
+func (f *FilesStoreUseCase) checkFileAccess(ctx context.Context, file entity.GetFileResponse, userID int, admin bool) error {
+	if admin || (file.CreatedBy != nil && *file.CreatedBy == userID) {
+		return nil
+	}
+
+	role, err := f.fileStoreService.FileRole(ctx, file.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if role != "" {
+		return nil
+	}
+
+	attached, err := f.fileStoreService.FileAttached(ctx, file.ID)
+	if err != nil {
+		return err
+	}
+
+	if !attached {
+		return ErrForbidden
+	}
+
+	return nil
+}
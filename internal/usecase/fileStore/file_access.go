@@ -0,0 +1,335 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/bcrypt"
+	"archv1/internal/pkg/storage"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+var (
+	ErrFolderForbidden = errors.New("you have no access to this folder")
+	ErrRoleTooLow      = errors.New("your role on this folder or file does not allow this")
+	ErrInvalidRole     = errors.New("property role must be 'viewer', 'editor' or 'owner'")
+	ErrInvalidGrantee  = errors.New("exactly one of properties user_id and group_id must be set")
+	ErrUnknownGrantee  = errors.New("the user or the group to grant the role to does not exist")
+	ErrShareExpiry     = errors.New("property expires_in must not be negative")
+	ErrInvalidShare    = errors.New("the share link is revoked or expired")
+	ErrSharePassword   = errors.New("the share link needs the right password")
+	ErrNotShared       = errors.New("the file is not shared through this link")
+)
+
+// roleRanks orders the roles, every role allows what the weaker ones do
+var roleRanks = map[string]int{
+	entity.FileRoleViewer: 1,
+	entity.FileRoleEditor: 2,
+	entity.FileRoleOwner:  3,
+}
+
+// ListAccess returns the owner of a folder or a file, the grants on it and above it and its share links to its owners
+func (f *FilesStoreUseCase) ListAccess(ctx context.Context, target entity.AccessTarget, access entity.FileAccess) (entity.ListAccessResponse, error) {
+	if err := f.requireRole(ctx, target, access, entity.FileRoleOwner); err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	response, err := f.fileStoreService.AccessGrants(ctx, target)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	response.Links, err = f.fileStoreService.ShareLinks(ctx, target)
+	if err != nil {
+		return entity.ListAccessResponse{}, err
+	}
+
+	for _, link := range response.Links {
+		link.Link = shareLink(link.Token)
+	}
+
+	return response, nil
+}
+
+// GrantAccess gives a user or the members of a chat group a role on a folder or a file, on behalf of one of its owners
+func (f *FilesStoreUseCase) GrantAccess(ctx context.Context, request entity.GrantAccessRequest, access entity.FileAccess) (entity.AccessGrant, error) {
+	if _, ok := roleRanks[request.Role]; !ok {
+		return entity.AccessGrant{}, ErrInvalidRole
+	}
+
+	if (request.UserID == nil) == (request.GroupID == nil) {
+		return entity.AccessGrant{}, ErrInvalidGrantee
+	}
+
+	if err := f.requireRole(ctx, request.Target, access, entity.FileRoleOwner); err != nil {
+		return entity.AccessGrant{}, err
+	}
+
+	grant, err := f.fileStoreService.GrantAccess(ctx, request)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.AccessGrant{}, ErrUnknownGrantee
+	}
+
+	return grant, err
+}
+
+func (f *FilesStoreUseCase) RevokeAccess(ctx context.Context, target entity.AccessTarget, grantID int, access entity.FileAccess) error {
+	if err := f.requireRole(ctx, target, access, entity.FileRoleOwner); err != nil {
+		return err
+	}
+
+	return f.fileStoreService.RevokeAccess(ctx, target, grantID)
+}
+
+// CreateShareLink creates a link that opens a folder or a file without a token, on behalf of one of its owners.
+// Only a hash of the password is kept.
+func (f *FilesStoreUseCase) CreateShareLink(ctx context.Context, request entity.CreateShareLinkRequest, access entity.FileAccess) (entity.ShareLink, error) {
+	if request.ExpiresIn < 0 {
+		return entity.ShareLink{}, ErrShareExpiry
+	}
+
+	if err := f.requireRole(ctx, request.Target, access, entity.FileRoleOwner); err != nil {
+		return entity.ShareLink{}, err
+	}
+
+	token, err := shareToken()
+	if err != nil {
+		return entity.ShareLink{}, err
+	}
+
+	link := entity.ShareLink{
+		Token:     token,
+		FolderID:  request.Target.FolderID,
+		FileID:    request.Target.FileID,
+		CreatedBy: request.CreatedBy,
+	}
+
+	if request.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(request.ExpiresIn) * time.Second)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if request.Password != "" {
+		link.PasswordHash, err = bcrypt.HashPassword(request.Password)
+		if err != nil {
+			return entity.ShareLink{}, err
+		}
+	}
+
+	link, err = f.fileStoreService.CreateShareLink(ctx, link)
+	if err != nil {
+		return entity.ShareLink{}, err
+	}
+
+	link.Link = shareLink(link.Token)
+
+	return link, nil
+}
+
+func (f *FilesStoreUseCase) RevokeShareLink(ctx context.Context, target entity.AccessTarget, linkID int, access entity.FileAccess) error {
+	if err := f.requireRole(ctx, target, access, entity.FileRoleOwner); err != nil {
+		return err
+	}
+
+	return f.fileStoreService.RevokeShareLink(ctx, target, linkID, access.UserID)
+}
+
+// OpenShare returns the file or the folder, with everything below it, that a share link opens
+func (f *FilesStoreUseCase) OpenShare(ctx context.Context, token, password string) (entity.SharedItemResponse, error) {
+	link, err := f.openShareLink(ctx, token, password)
+	if err != nil {
+		return entity.SharedItemResponse{}, err
+	}
+
+	response := entity.SharedItemResponse{
+		ExpiresAt: link.ExpiresAt,
+	}
+
+	if link.FileID != nil {
+		file, err := f.fileStoreService.GetFile(ctx, *link.FileID)
+		if err != nil {
+			return entity.SharedItemResponse{}, err
+		}
+
+		response.File = &file
+
+		return response, nil
+	}
+
+	tree, err := f.FolderTree(ctx, *link.FolderID, entity.FileAccess{Admin: true})
+	if err != nil {
+		return entity.SharedItemResponse{}, err
+	}
+
+	response.Folder = &tree
+
+	return response, nil
+}
+
+// OpenSharedFile opens the content of the file a share link opens, or of a file below the folder it opens
+func (f *FilesStoreUseCase) OpenSharedFile(ctx context.Context, token, password string, fileID int) (entity.Download, *storage.Seeker, error) {
+	link, err := f.openShareLink(ctx, token, password)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	if link.FileID != nil {
+		if fileID != 0 && fileID != *link.FileID {
+			return entity.Download{}, nil, ErrNotShared
+		}
+
+		fileID = *link.FileID
+	}
+
+	file, err := f.fileStoreService.GetFile(ctx, fileID)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	if link.FolderID != nil {
+		if err := f.checkSharedFolder(ctx, file, *link.FolderID); err != nil {
+			return entity.Download{}, nil, err
+		}
+	}
+
+	key, err := fileBlob(file)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	content, err := storage.OpenSeeker(ctx, f.blobs, key)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = content.Info().ContentType
+	}
+
+	return entity.Download{
+		Name:     file.Name,
+		MimeType: mimeType,
+	}, content, nil
+}
+
+// openShareLink finds a share link that is neither revoked nor expired and checks its password
+func (f *FilesStoreUseCase) openShareLink(ctx context.Context, token, password string) (entity.ShareLink, error) {
+	link, err := f.fileStoreService.GetShareLink(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ShareLink{}, ErrInvalidShare
+	}
+	if err != nil {
+		return entity.ShareLink{}, err
+	}
+
+	if link.PasswordHash != "" && !bcrypt.CheckPasswordHash(password, link.PasswordHash) {
+		return entity.ShareLink{}, ErrSharePassword
+	}
+
+	return link, nil
+}
+
+// checkSharedFolder fails with ErrNotShared unless the file is in the folder or below it
+func (f *FilesStoreUseCase) checkSharedFolder(ctx context.Context, file entity.GetFileResponse, folderID int) error {
+	if file.FolderID == nil {
+		return ErrNotShared
+	}
+
+	path, err := f.fileStoreService.FolderPath(ctx, *file.FolderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotShared
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, folder := range path {
+		if *folder.ID == folderID {
+			return nil
+		}
+	}
+
+	return ErrNotShared
+}
+
+// targetRole returns the strongest role the user holds on the folder or the file, the admins own everything
+func (f *FilesStoreUseCase) targetRole(ctx context.Context, target entity.AccessTarget, access entity.FileAccess) (string, error) {
+	var (
+		role string
+		err  error
+	)
+
+	if target.FolderID != nil {
+		role, err = f.fileStoreService.FolderRole(ctx, *target.FolderID, access.UserID)
+	} else {
+		role, err = f.fileStoreService.FileRole(ctx, *target.FileID, access.UserID)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if access.Admin {
+		return entity.FileRoleOwner, nil
+	}
+
+	return role, nil
+}
+
+// requireRole fails unless the user holds the role, or a stronger one, on the folder or the file
+func (f *FilesStoreUseCase) requireRole(ctx context.Context, target entity.AccessTarget, access entity.FileAccess, role string) error {
+	held, err := f.targetRole(ctx, target, access)
+	if err != nil {
+		return err
+	}
+
+	return checkRole(target, held, role)
+}
+
+// requireDeletedRole is requireRole for a deleted folder, with the role the user held on it before it was deleted
+func (f *FilesStoreUseCase) requireDeletedRole(ctx context.Context, folderID int, access entity.FileAccess, role string) error {
+	held, err := f.fileStoreService.DeletedFolderRole(ctx, folderID, access.UserID)
+	if err != nil {
+		return err
+	}
+
+	if access.Admin {
+		held = entity.FileRoleOwner
+	}
+
+	return checkRole(entity.AccessTarget{FolderID: &folderID}, held, role)
+}
+
+// checkRole fails unless the held role is the role or a stronger one
+func checkRole(target entity.AccessTarget, held, role string) error {
+	if held == "" && target.FolderID != nil {
+		return ErrFolderForbidden
+	}
+
+	if held == "" {
+		return ErrForbidden
+	}
+
+	if roleRanks[held] < roleRanks[role] {
+		return ErrRoleTooLow
+	}
+
+	return nil
+}
+
+func shareToken() (string, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func shareLink(token string) string {
+	return "/v1/share/" + token
+}
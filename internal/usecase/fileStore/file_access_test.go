@@ -0,0 +1,383 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/service/fileStore"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// fakeService keeps the roles, folders and usage the use case asks the service for and records what it changes
+type fakeService struct {
+	fileStore.FilesStoreServiceI
+
+	folderRoles  map[int]string
+	deletedRoles map[int]string
+	fileRoles    map[int]string
+	folders      map[int]entity.GetFolderResponse
+	deleted      map[int]entity.GetFolderResponse
+	files        map[int]entity.GetFileResponse
+	subtree      entity.FolderChildrenResponse
+
+	usage entity.StorageUsage
+	// onAcquire runs when a blob is acquired, before the file is recorded
+	onAcquire func()
+	// onMove runs in place of the move of a folder, as the repository checking the move again
+	onMove  func() error
+	refs    map[string]int
+	created []entity.CreateFileRequest
+	calls   []string
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		folderRoles:  map[int]string{},
+		deletedRoles: map[int]string{},
+		fileRoles:    map[int]string{},
+		folders:      map[int]entity.GetFolderResponse{},
+		deleted:      map[int]entity.GetFolderResponse{},
+		files:        map[int]entity.GetFileResponse{},
+		refs:         map[string]int{},
+	}
+}
+
+func (s *fakeService) addFolder(id int, parentID *int, role string) {
+	s.folders[id] = entity.GetFolderResponse{ID: &id, Name: "folder", ParentID: parentID}
+	s.folderRoles[id] = role
+}
+
+func (s *fakeService) FolderRole(_ context.Context, folderID, _ int) (string, error) {
+	role, ok := s.folderRoles[folderID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (s *fakeService) DeletedFolderRole(_ context.Context, folderID, _ int) (string, error) {
+	role, ok := s.deletedRoles[folderID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (s *fakeService) FileRole(_ context.Context, fileID, _ int) (string, error) {
+	role, ok := s.fileRoles[fileID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (s *fakeService) GetFolder(_ context.Context, folderID int) (entity.GetFolderResponse, error) {
+	folder, ok := s.folders[folderID]
+	if !ok {
+		return entity.GetFolderResponse{}, sql.ErrNoRows
+	}
+
+	return folder, nil
+}
+
+func (s *fakeService) DeletedFolder(_ context.Context, folderID int) (entity.GetFolderResponse, error) {
+	folder, ok := s.deleted[folderID]
+	if !ok {
+		return entity.GetFolderResponse{}, sql.ErrNoRows
+	}
+
+	return folder, nil
+}
+
+func (s *fakeService) GetFile(_ context.Context, fileID int) (entity.GetFileResponse, error) {
+	file, ok := s.files[fileID]
+	if !ok {
+		return entity.GetFileResponse{}, sql.ErrNoRows
+	}
+
+	return file, nil
+}
+
+// FolderPath returns the folder and the folders above it, nearest last
+func (s *fakeService) FolderPath(ctx context.Context, folderID int) ([]*entity.GetFolderResponse, error) {
+	folder, err := s.GetFolder(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := []*entity.GetFolderResponse{&folder}
+	for folder.ParentID != nil {
+		if folder, err = s.GetFolder(ctx, *folder.ParentID); err != nil {
+			return nil, err
+		}
+
+		path = append([]*entity.GetFolderResponse{&folder}, path...)
+	}
+
+	return path, nil
+}
+
+func (s *fakeService) FolderSubtree(context.Context, int) (entity.FolderChildrenResponse, error) {
+	return s.subtree, nil
+}
+
+func (s *fakeService) StorageUsage(context.Context, int) (entity.StorageUsage, error) {
+	return s.usage, nil
+}
+
+func (s *fakeService) DeleteFolder(context.Context, int, int) (entity.DeleteFolderResponse, error) {
+	s.calls = append(s.calls, "DeleteFolder")
+	return entity.DeleteFolderResponse{}, nil
+}
+
+func (s *fakeService) DeleteFile(context.Context, int, int) (entity.DeleteFileResponse, error) {
+	s.calls = append(s.calls, "DeleteFile")
+	return entity.DeleteFileResponse{}, nil
+}
+
+func (s *fakeService) UpdateFile(context.Context, entity.UpdateFileRequest) (entity.UpdateFileResponse, error) {
+	s.calls = append(s.calls, "UpdateFile")
+	return entity.UpdateFileResponse{}, nil
+}
+
+func (s *fakeService) MoveFolder(context.Context, entity.MoveFolderRequest) (entity.UpdateFolderResponse, error) {
+	s.calls = append(s.calls, "MoveFolder")
+
+	if s.onMove != nil {
+		return entity.UpdateFolderResponse{}, s.onMove()
+	}
+
+	return entity.UpdateFolderResponse{}, nil
+}
+
+func (s *fakeService) CopyFolder(context.Context, entity.CopyFolderRequest) (entity.CreateFolderResponse, error) {
+	s.calls = append(s.calls, "CopyFolder")
+	return entity.CreateFolderResponse{}, nil
+}
+
+func (s *fakeService) RestoreFolder(context.Context, int, int) (entity.RestoreFolderResponse, error) {
+	s.calls = append(s.calls, "RestoreFolder")
+	return entity.RestoreFolderResponse{}, nil
+}
+
+// called reports whether the use case went on to the change and forgets the calls
+func (s *fakeService) called(name string) bool {
+	defer func() { s.calls = nil }()
+
+	for _, call := range s.calls {
+		if call == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestRequireRole(t *testing.T) {
+	service := newFakeService()
+	service.folderRoles[1] = entity.FileRoleViewer
+	service.folderRoles[2] = ""
+	service.fileRoles[3] = entity.FileRoleEditor
+	service.fileRoles[4] = ""
+
+	f := &FilesStoreUseCase{fileStoreService: service}
+	user := entity.FileAccess{UserID: 10}
+	admin := entity.FileAccess{UserID: 11, Admin: true}
+
+	tests := []struct {
+		name   string
+		target entity.AccessTarget
+		access entity.FileAccess
+		role   string
+		want   error
+	}{
+		{name: "the held role", target: entity.AccessTarget{FolderID: intPtr(1)}, access: user, role: entity.FileRoleViewer},
+		{name: "a weaker role", target: entity.AccessTarget{FileID: intPtr(3)}, access: user, role: entity.FileRoleViewer},
+		{name: "a stronger role", target: entity.AccessTarget{FolderID: intPtr(1)}, access: user, role: entity.FileRoleEditor, want: ErrRoleTooLow},
+		{name: "no role on a folder", target: entity.AccessTarget{FolderID: intPtr(2)}, access: user, role: entity.FileRoleViewer, want: ErrFolderForbidden},
+		{name: "no role on a file", target: entity.AccessTarget{FileID: intPtr(4)}, access: user, role: entity.FileRoleViewer, want: ErrForbidden},
+		{name: "admins own everything", target: entity.AccessTarget{FolderID: intPtr(2)}, access: admin, role: entity.FileRoleOwner},
+		{name: "a missing folder", target: entity.AccessTarget{FolderID: intPtr(9)}, access: admin, role: entity.FileRoleViewer, want: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		if err := f.requireRole(context.Background(), tt.target, tt.access, tt.role); !errors.Is(err, tt.want) {
+			t.Errorf("%s: requireRole = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestChangesTakeTheirRole(t *testing.T) {
+	service := newFakeService()
+	service.addFolder(1, nil, entity.FileRoleOwner)
+	service.addFolder(2, intPtr(1), entity.FileRoleEditor)
+	service.addFolder(3, nil, entity.FileRoleViewer)
+	service.fileRoles[5] = entity.FileRoleEditor
+	service.files[5] = entity.GetFileResponse{ID: 5, FolderID: intPtr(3)}
+	service.deletedRoles[6] = entity.FileRoleEditor
+	service.deleted[6] = entity.GetFolderResponse{ID: intPtr(6), ParentID: intPtr(1)}
+	service.deletedRoles[7] = entity.FileRoleViewer
+	service.deleted[7] = entity.GetFolderResponse{ID: intPtr(7)}
+
+	f := &FilesStoreUseCase{fileStoreService: service}
+	ctx := context.Background()
+	user := entity.FileAccess{UserID: 10}
+
+	tests := []struct {
+		name string
+		run  func() error
+		call string
+		want error
+	}{
+		{
+			name: "an editor renames a file in a folder they only view",
+			run: func() error {
+				_, err := f.UpdateFile(ctx, entity.UpdateFileRequest{ID: 5, FolderID: intPtr(3), Name: "new"}, user)
+				return err
+			},
+			call: "UpdateFile",
+		},
+		{
+			name: "an editor moves a file into a folder they edit",
+			run: func() error {
+				_, err := f.UpdateFile(ctx, entity.UpdateFileRequest{ID: 5, FolderID: intPtr(2)}, user)
+				return err
+			},
+			call: "UpdateFile",
+		},
+		{
+			name: "a viewer can not move a file into their folder",
+			run: func() error {
+				service.fileRoles[8] = entity.FileRoleViewer
+				service.files[8] = entity.GetFileResponse{ID: 8}
+				_, err := f.UpdateFile(ctx, entity.UpdateFileRequest{ID: 8, FolderID: intPtr(1)}, user)
+				return err
+			},
+			call: "UpdateFile",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "an editor can not delete the file",
+			run: func() error {
+				_, err := f.DeleteFile(ctx, 5, user)
+				return err
+			},
+			call: "DeleteFile",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "an editor can not delete the folder",
+			run: func() error {
+				_, err := f.DeleteFolder(ctx, 2, user)
+				return err
+			},
+			call: "DeleteFolder",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "an owner deletes the folder",
+			run: func() error {
+				_, err := f.DeleteFolder(ctx, 1, user)
+				return err
+			},
+			call: "DeleteFolder",
+		},
+		{
+			name: "a move takes the editor role on the new parent",
+			run: func() error {
+				_, err := f.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: 2, ParentID: intPtr(3)}, user)
+				return err
+			},
+			call: "MoveFolder",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "a move takes the editor role on the folder",
+			run: func() error {
+				_, err := f.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: 3}, user)
+				return err
+			},
+			call: "MoveFolder",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "an editor renames the folder where it is",
+			run: func() error {
+				_, err := f.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: 2, ParentID: intPtr(1), Name: "new"}, user)
+				return err
+			},
+			call: "MoveFolder",
+		},
+		{
+			name: "a folder is not moved into itself",
+			run: func() error {
+				_, err := f.MoveFolder(ctx, entity.MoveFolderRequest{FolderID: 1, ParentID: intPtr(2)}, user)
+				return err
+			},
+			call: "MoveFolder",
+			want: ErrFolderCycle,
+		},
+		{
+			name: "a viewer copies the folder into a folder they edit",
+			run: func() error {
+				_, err := f.CopyFolder(ctx, entity.CopyFolderRequest{FolderID: 3, ParentID: intPtr(2)}, user)
+				return err
+			},
+			call: "CopyFolder",
+		},
+		{
+			name: "a copy takes the editor role on the new parent",
+			run: func() error {
+				_, err := f.CopyFolder(ctx, entity.CopyFolderRequest{FolderID: 2, ParentID: intPtr(3)}, user)
+				return err
+			},
+			call: "CopyFolder",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "a restore takes the role held before the delete",
+			run: func() error {
+				_, err := f.RestoreFolder(ctx, 6, user)
+				return err
+			},
+			call: "RestoreFolder",
+		},
+		{
+			name: "a viewer can not restore the folder",
+			run: func() error {
+				_, err := f.RestoreFolder(ctx, 7, user)
+				return err
+			},
+			call: "RestoreFolder",
+			want: ErrRoleTooLow,
+		},
+		{
+			name: "a folder that is not deleted is not restored",
+			run: func() error {
+				_, err := f.RestoreFolder(ctx, 1, user)
+				return err
+			},
+			call: "RestoreFolder",
+			want: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.run()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+
+		if called := service.called(tt.call); called != (tt.want == nil) {
+			t.Errorf("%s: %s called is %t, want %t", tt.name, tt.call, called, tt.want == nil)
+		}
+	}
+}
@@ -2,64 +2,378 @@ package fileStore
 
 import (
 	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	"archv1/internal/pkg/signedurl"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/upload"
 	"archv1/internal/service/fileStore"
 	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type FilesStoreUseCase struct {
 	fileStoreService fileStore.FilesStoreServiceI
+	blobs            storage.BlobStore
+	validator        *upload.Validator
+	uploadTTL        time.Duration
+	signer           *signedurl.Signer
+	linkTTL          time.Duration
+	maxLinkTTL       time.Duration
+	imagePresets     []config.ImagePreset
+	imageMaxSide     int
+	imageMaxPixels   int64
+	jpegQuality      int
+	imageSlots       chan struct{}
+	verifyAge        time.Duration
+	blobGrace        time.Duration
+	trashRetention   time.Duration
+	roleQuotas       map[string]int64
+	defaultQuota     int64
 }
 
-func NewFilesStoreUseCase(service fileStore.FilesStoreServiceI) FilesStoreUseCaseI {
+func NewFilesStoreUseCase(service fileStore.FilesStoreServiceI, blobs storage.BlobStore, validator *upload.Validator, cfg *config.Config) FilesStoreUseCaseI {
+	uploadTTL, err := time.ParseDuration(cfg.UploadSessionTTL)
+	if err != nil || uploadTTL <= 0 {
+		uploadTTL = 24 * time.Hour
+	}
+
+	linkTTL, err := time.ParseDuration(cfg.DownloadLinkTTL)
+	if err != nil || linkTTL <= 0 {
+		linkTTL = 15 * time.Minute
+	}
+
+	maxLinkTTL, err := time.ParseDuration(cfg.DownloadLinkMaxTTL)
+	if err != nil || maxLinkTTL < linkTTL {
+		maxLinkTTL = max(linkTTL, 7*24*time.Hour)
+	}
+
+	secret := cfg.DownloadSecret
+	if secret == "" {
+		secret = cfg.JWTSecret
+	}
+
+	imageMaxSide := cfg.ImageMaxSide
+	if imageMaxSide <= 0 {
+		imageMaxSide = 2560
+	}
+
+	imageMaxPixels := cfg.ImageMaxPixels
+	if imageMaxPixels <= 0 {
+		imageMaxPixels = 50_000_000
+	}
+
+	imageWorkers := cfg.ImageWorkers
+	if imageWorkers <= 0 {
+		imageWorkers = 2
+	}
+
+	verifyAge, err := time.ParseDuration(cfg.BlobVerifyAge)
+	if err != nil || verifyAge <= 0 {
+		verifyAge = 7 * 24 * time.Hour
+	}
+
+	blobGrace, err := time.ParseDuration(cfg.BlobGCGrace)
+	if err != nil || blobGrace < 0 {
+		blobGrace = time.Hour
+	}
+
+	trashRetention, err := time.ParseDuration(cfg.FileTrashRetention)
+	if err != nil || trashRetention < 0 {
+		trashRetention = 30 * 24 * time.Hour
+	}
+
+	defaultQuota := max(cfg.StorageDefaultQuota, 0)
+
 	return &FilesStoreUseCase{
 		fileStoreService: service,
+		blobs:            blobs,
+		validator:        validator,
+		uploadTTL:        uploadTTL,
+		signer:           signedurl.NewSigner(secret),
+		linkTTL:          linkTTL,
+		maxLinkTTL:       maxLinkTTL,
+		imagePresets:     cfg.ImagePresets,
+		imageMaxSide:     imageMaxSide,
+		imageMaxPixels:   imageMaxPixels,
+		jpegQuality:      cfg.ImageJPEGQuality,
+		imageSlots:       make(chan struct{}, imageWorkers),
+		verifyAge:        verifyAge,
+		blobGrace:        blobGrace,
+		trashRetention:   trashRetention,
+		roleQuotas:       cfg.StorageQuotas,
+		defaultQuota:     defaultQuota,
 	}
 }
 
-func (f *FilesStoreUseCase) ListFolder(ctx context.Context, filter entity.Filter) (entity.ListFolderResponse, error) {
-	return f.fileStoreService.ListFolder(ctx, filter)
+// ListFolder lists the folders the user created or holds a role on, with every folder below them
+func (f *FilesStoreUseCase) ListFolder(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFolderResponse, error) {
+	return f.fileStoreService.ListFolder(ctx, filter, access)
 }
 
-func (f *FilesStoreUseCase) GetFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error) {
+func (f *FilesStoreUseCase) GetFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.GetFolderResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &folderID}, access, entity.FileRoleViewer); err != nil {
+		return entity.GetFolderResponse{}, err
+	}
+
 	return f.fileStoreService.GetFolder(ctx, folderID)
 }
 
-func (f *FilesStoreUseCase) CreateFolder(ctx context.Context, folder entity.CreateFolderRequest) (entity.CreateFolderResponse, error) {
+// CreateFolder creates a folder in the root, or in a folder the user holds the editor role on
+func (f *FilesStoreUseCase) CreateFolder(ctx context.Context, folder entity.CreateFolderRequest, access entity.FileAccess) (entity.CreateFolderResponse, error) {
+	if folder.ParentID != nil {
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: folder.ParentID}, access, entity.FileRoleEditor); err != nil {
+			return entity.CreateFolderResponse{}, err
+		}
+	}
+
 	return f.fileStoreService.CreateFolder(ctx, folder)
 }
 
-func (f *FilesStoreUseCase) UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest) (entity.UpdateFolderResponse, error) {
-	return f.fileStoreService.UpdateFolder(ctx, folder)
+// UpdateFolder renames a folder and puts it into another one, refusing to nest it in itself.
+// It takes the editor role on the folder and on the new parent.
+func (f *FilesStoreUseCase) UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error) {
+	if folder.ID == nil {
+		return entity.UpdateFolderResponse{}, sql.ErrNoRows
+	}
+
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: folder.ID}, access, entity.FileRoleEditor); err != nil {
+		return entity.UpdateFolderResponse{}, err
+	}
+
+	if folder.ParentID != nil {
+		if err := f.checkFolderParent(ctx, *folder.ID, *folder.ParentID); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+
+		if err := f.requireFolderDestination(ctx, *folder.ID, folder.ParentID, access); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+	}
+
+	response, err := f.fileStoreService.UpdateFolder(ctx, folder)
+	if err != nil && folder.ParentID != nil {
+		return entity.UpdateFolderResponse{}, f.checkMovedFolder(ctx, *folder.ID, *folder.ParentID, err)
+	}
+
+	return response, err
 }
 
-func (f *FilesStoreUseCase) UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest) (entity.UpdateFolderResponse, error) {
-	return f.fileStoreService.UpdateFolderColumns(ctx, fields)
+// UpdateFolderColumns updates the given columns of a folder, refusing to nest it in itself.
+// It takes the editor role on the folder and on the new parent.
+func (f *FilesStoreUseCase) UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &fields.FolderID}, access, entity.FileRoleEditor); err != nil {
+		return entity.UpdateFolderResponse{}, err
+	}
+
+	parentID, parentErr := strconv.Atoi(fields.Fields["parent_id"])
+	if parentErr == nil {
+		if err := f.checkFolderParent(ctx, fields.FolderID, parentID); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+
+		if err := f.requireFolderDestination(ctx, fields.FolderID, &parentID, access); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+	}
+
+	response, err := f.fileStoreService.UpdateFolderColumns(ctx, fields)
+	if err != nil && parentErr == nil {
+		return entity.UpdateFolderResponse{}, f.checkMovedFolder(ctx, fields.FolderID, parentID, err)
+	}
+
+	return response, err
 }
 
-func (f *FilesStoreUseCase) DeleteFolder(ctx context.Context, folderID, deletedBy int) (entity.DeleteFolderResponse, error) {
-	return f.fileStoreService.DeleteFolder(ctx, folderID, deletedBy)
+// DeleteFolder deletes the folder with everything below it on behalf of one of its owners, the files keep their
+// content for the trash retention so the folder can be restored until PurgeDeletedFiles drops it
+func (f *FilesStoreUseCase) DeleteFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.DeleteFolderResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &folderID}, access, entity.FileRoleOwner); err != nil {
+		return entity.DeleteFolderResponse{}, err
+	}
+
+	return f.fileStoreService.DeleteFolder(ctx, folderID, access.UserID)
 }
 
-func (f *FilesStoreUseCase) ListFile(ctx context.Context, filter entity.Filter) (entity.ListFileResponse, error) {
-	return f.fileStoreService.ListFile(ctx, filter)
+// ListFile lists the files the user may see, see checkFileAccess
+func (f *FilesStoreUseCase) ListFile(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFileResponse, error) {
+	return f.fileStoreService.ListFile(ctx, filter, access)
 }
 
-func (f *FilesStoreUseCase) GetFile(ctx context.Context, fileID int) (entity.GetFileResponse, error) {
-	return f.fileStoreService.GetFile(ctx, fileID)
+func (f *FilesStoreUseCase) GetFile(ctx context.Context, fileID int, access entity.FileAccess) (entity.GetFileResponse, error) {
+	file, err := f.fileStoreService.GetFile(ctx, fileID)
+	if err != nil {
+		return entity.GetFileResponse{}, err
+	}
+
+	if err := f.checkFileAccess(ctx, file, access.UserID, access.Admin); err != nil {
+		return entity.GetFileResponse{}, err
+	}
+
+	return file, nil
 }
 
-func (f *FilesStoreUseCase) CreateFile(ctx context.Context, file entity.CreateFileRequest) (entity.CreateFileResponse, error) {
-	return f.fileStoreService.CreateFile(ctx, file)
+// CreateFile records a file in the root, or in a folder the user holds the editor role on
+func (f *FilesStoreUseCase) CreateFile(ctx context.Context, file entity.CreateFileRequest, access entity.FileAccess) (entity.CreateFileResponse, error) {
+	if file.FolderID != nil {
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: file.FolderID}, access, entity.FileRoleEditor); err != nil {
+			return entity.CreateFileResponse{}, err
+		}
+	}
+
+	return f.fileStoreService.CreateFile(ctx, file, nil)
 }
 
-func (f *FilesStoreUseCase) UpdateFile(ctx context.Context, file entity.UpdateFileRequest) (entity.UpdateFileResponse, error) {
+// UpdateFile changes a file and puts it into another folder, it takes the editor role on the file and on the folder
+func (f *FilesStoreUseCase) UpdateFile(ctx context.Context, file entity.UpdateFileRequest, access entity.FileAccess) (entity.UpdateFileResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FileID: &file.ID}, access, entity.FileRoleEditor); err != nil {
+		return entity.UpdateFileResponse{}, err
+	}
+
+	if err := f.requireFileDestination(ctx, file.ID, file.FolderID, access); err != nil {
+		return entity.UpdateFileResponse{}, err
+	}
+
 	return f.fileStoreService.UpdateFile(ctx, file)
 }
 
-func (f *FilesStoreUseCase) UpdateFileColumns(ctx context.Context, fields entity.UpdateFileColumnsRequest) (entity.UpdateFileResponse, error) {
+// UpdateFileColumns updates the given columns of a file, it takes the editor role on the file and on a new folder
+func (f *FilesStoreUseCase) UpdateFileColumns(ctx context.Context, fields entity.UpdateFileColumnsRequest, access entity.FileAccess) (entity.UpdateFileResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FileID: &fields.FileID}, access, entity.FileRoleEditor); err != nil {
+		return entity.UpdateFileResponse{}, err
+	}
+
+	if value, ok := fields.Fields["folder_id"]; ok {
+		if folderID, err := strconv.Atoi(value); err == nil {
+			if err := f.requireFileDestination(ctx, fields.FileID, &folderID, access); err != nil {
+				return entity.UpdateFileResponse{}, err
+			}
+		}
+	}
+
 	return f.fileStoreService.UpdateFileColumns(ctx, fields)
 }
 
-func (f *FilesStoreUseCase) DeleteFile(ctx context.Context, fileID, deletedBy int) (entity.DeleteFileResponse, error) {
-	return f.fileStoreService.DeleteFile(ctx, fileID, deletedBy)
+// DeleteFile deletes the file for good on behalf of one of its owners, a single file can not be restored
+// so its content is released at once
+func (f *FilesStoreUseCase) DeleteFile(ctx context.Context, fileID int, access entity.FileAccess) (entity.DeleteFileResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FileID: &fileID}, access, entity.FileRoleOwner); err != nil {
+		return entity.DeleteFileResponse{}, err
+	}
+
+	return f.fileStoreService.DeleteFile(ctx, fileID, access.UserID)
+}
+
+// requireFolderDestination takes the editor role on the folder a folder is moved into, leaving it where it is takes none
+func (f *FilesStoreUseCase) requireFolderDestination(ctx context.Context, folderID int, parentID *int, access entity.FileAccess) error {
+	if parentID == nil {
+		return nil
+	}
+
+	folder, err := f.fileStoreService.GetFolder(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	if folder.ParentID != nil && *folder.ParentID == *parentID {
+		return nil
+	}
+
+	return f.requireRole(ctx, entity.AccessTarget{FolderID: parentID}, access, entity.FileRoleEditor)
+}
+
+// requireFileDestination takes the editor role on the folder a file is moved into, leaving it where it is takes none
+func (f *FilesStoreUseCase) requireFileDestination(ctx context.Context, fileID int, folderID *int, access entity.FileAccess) error {
+	if folderID == nil {
+		return nil
+	}
+
+	file, err := f.fileStoreService.GetFile(ctx, fileID)
+	if err != nil {
+		return err
+	}
+
+	if file.FolderID != nil && *file.FolderID == *folderID {
+		return nil
+	}
+
+	return f.requireRole(ctx, entity.AccessTarget{FolderID: folderID}, access, entity.FileRoleEditor)
+}
+
+// UploadFile stores the body once per content and records its name, size, type and SHA-256 in the folder,
+// a file with the same content shares the stored blob. The reference to the blob is dropped again when the
+// row can not be created. The preset variants of an image are made in the background.
+// Uploading into a folder takes the editor role on it.
+func (f *FilesStoreUseCase) UploadFile(ctx context.Context, upload entity.UploadFileRequest, body io.Reader) (entity.CreateFileResponse, error) {
+	if upload.FolderID != nil {
+		access := entity.FileAccess{UserID: upload.CreatedBy, Admin: upload.Admin}
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: upload.FolderID}, access, entity.FileRoleEditor); err != nil {
+			return entity.CreateFileResponse{}, err
+		}
+	}
+
+	return f.uploadFile(ctx, upload, body)
+}
+
+// uploadFile is UploadFile after the role on the folder was checked, the quota of the user is checked before the
+// body is stored and again, one upload of the user at a time, when the file is recorded
+func (f *FilesStoreUseCase) uploadFile(ctx context.Context, upload entity.UploadFileRequest, body io.Reader) (entity.CreateFileResponse, error) {
+	if err := f.CheckQuota(ctx, upload.CreatedBy, upload.Size); err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	blob, err := f.storeBlob(ctx, body, upload.Size, upload.MimeType)
+	if err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	response, err := f.fileStoreService.CreateFile(ctx, entity.CreateFileRequest{
+		Type:      upload.MimeType,
+		Link:      uuid.NewString() + strings.ToLower(path.Ext(upload.Name)),
+		FolderID:  upload.FolderID,
+		Name:      upload.Name,
+		Size:      upload.Size,
+		MimeType:  upload.MimeType,
+		Checksum:  blob.Checksum,
+		BlobKey:   blob.Key,
+		CreatedBy: upload.CreatedBy,
+	}, f.quotaCheck(upload.Size))
+	if err != nil {
+		_ = f.fileStoreService.ReleaseBlob(context.Background(), blob.Key)
+		return entity.CreateFileResponse{}, err
+	}
+
+	if len(f.imagePresets) > 0 && isImageSource(upload.MimeType) {
+		go f.warmVariants(blob.Key, upload.MimeType)
+	}
+
+	return response, nil
+}
+
+// DiscardFile deletes an upload that could not be attached, deleting it drops its reference to the stored
+// content and the content is removed once no other file holds it
+func (f *FilesStoreUseCase) DiscardFile(ctx context.Context, fileID, deletedBy int) error {
+	_, err := f.fileStoreService.DeleteFile(ctx, fileID, deletedBy)
+
+	return err
+}
+
+// OpenFile opens the stored content of a file the user may see, files created with an outside link have none
+func (f *FilesStoreUseCase) OpenFile(ctx context.Context, fileID int, access entity.FileAccess) (io.ReadCloser, storage.BlobInfo, error) {
+	file, err := f.GetFile(ctx, fileID, access)
+	if err != nil {
+		return nil, storage.BlobInfo{}, err
+	}
+
+	key, err := fileBlob(file)
+	if err != nil {
+		return nil, storage.BlobInfo{}, err
+	}
+
+	return f.blobs.Get(ctx, key)
 }
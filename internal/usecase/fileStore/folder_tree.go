@@ -0,0 +1,214 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrFolderCycle   = errors.New("a folder can not be moved or copied into itself or one of its subfolders")
+	ErrParentDeleted = errors.New("the parent folder is deleted, restore it first")
+)
+
+// FolderChildren returns the folders and files right inside a folder, a nil folder is the root
+// where only what the user may see is listed
+func (f *FilesStoreUseCase) FolderChildren(ctx context.Context, folderID *int, access entity.FileAccess) (entity.FolderChildrenResponse, error) {
+	if folderID != nil {
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: folderID}, access, entity.FileRoleViewer); err != nil {
+			return entity.FolderChildrenResponse{}, err
+		}
+	}
+
+	return f.fileStoreService.FolderChildren(ctx, folderID, access)
+}
+
+// FolderPath returns the breadcrumb of a folder, from the root down to the folder. The folders above
+// the ones the user holds a role on are left out.
+func (f *FilesStoreUseCase) FolderPath(ctx context.Context, folderID int, access entity.FileAccess) (entity.FolderPathResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &folderID}, access, entity.FileRoleViewer); err != nil {
+		return entity.FolderPathResponse{}, err
+	}
+
+	path, err := f.fileStoreService.FolderPath(ctx, folderID)
+	if err != nil {
+		return entity.FolderPathResponse{}, err
+	}
+
+	// roles are inherited downwards, so the folders the user may see are the end of the path
+	for !access.Admin && len(path) > 1 {
+		role, err := f.fileStoreService.FolderRole(ctx, *path[0].ID, access.UserID)
+		if err != nil {
+			return entity.FolderPathResponse{}, err
+		}
+
+		if role != "" {
+			break
+		}
+
+		path = path[1:]
+	}
+
+	return entity.FolderPathResponse{
+		Path: path,
+	}, nil
+}
+
+// FolderTree returns a folder with all its subfolders and files nested in it, a role on the folder covers all of them
+func (f *FilesStoreUseCase) FolderTree(ctx context.Context, folderID int, access entity.FileAccess) (entity.FolderTree, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &folderID}, access, entity.FileRoleViewer); err != nil {
+		return entity.FolderTree{}, err
+	}
+
+	subtree, err := f.fileStoreService.FolderSubtree(ctx, folderID)
+	if err != nil {
+		return entity.FolderTree{}, err
+	}
+
+	// the folders come nearest first, so every parent is in the map before its children
+	nodes := make(map[int]*entity.FolderTree, len(subtree.Folders))
+	for _, folder := range subtree.Folders {
+		node := &entity.FolderTree{
+			ID:       folder.ID,
+			Name:     folder.Name,
+			ParentID: folder.ParentID,
+			Folders:  []*entity.FolderTree{},
+			Files:    []*entity.GetFileResponse{},
+		}
+
+		if parent, ok := nodes[derefInt(folder.ParentID)]; ok && *folder.ID != folderID {
+			parent.Folders = append(parent.Folders, node)
+		}
+
+		nodes[*folder.ID] = node
+	}
+
+	for _, file := range subtree.Files {
+		if node, ok := nodes[derefInt(file.FolderID)]; ok {
+			node.Files = append(node.Files, file)
+		}
+	}
+
+	return *nodes[folderID], nil
+}
+
+// MoveFolder puts a folder into another one or the root, renaming it when a name is given.
+// It takes the editor role on the folder and on the new parent.
+func (f *FilesStoreUseCase) MoveFolder(ctx context.Context, request entity.MoveFolderRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &request.FolderID}, access, entity.FileRoleEditor); err != nil {
+		return entity.UpdateFolderResponse{}, err
+	}
+
+	if request.ParentID != nil {
+		if err := f.checkFolderParent(ctx, request.FolderID, *request.ParentID); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+
+		if err := f.requireFolderDestination(ctx, request.FolderID, request.ParentID, access); err != nil {
+			return entity.UpdateFolderResponse{}, err
+		}
+	}
+
+	response, err := f.fileStoreService.MoveFolder(ctx, request)
+	if err != nil && request.ParentID != nil {
+		return entity.UpdateFolderResponse{}, f.checkMovedFolder(ctx, request.FolderID, *request.ParentID, err)
+	}
+
+	return response, err
+}
+
+// CopyFolder copies a folder with its subfolders and files into another one or the root, it takes the viewer
+// role on the folder and the editor role on the new parent. The copies count in full against the quota of the
+// user copying them.
+func (f *FilesStoreUseCase) CopyFolder(ctx context.Context, request entity.CopyFolderRequest, access entity.FileAccess) (entity.CreateFolderResponse, error) {
+	if err := f.requireRole(ctx, entity.AccessTarget{FolderID: &request.FolderID}, access, entity.FileRoleViewer); err != nil {
+		return entity.CreateFolderResponse{}, err
+	}
+
+	if request.ParentID != nil {
+		if err := f.checkFolderParent(ctx, request.FolderID, *request.ParentID); err != nil {
+			return entity.CreateFolderResponse{}, err
+		}
+
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: request.ParentID}, access, entity.FileRoleEditor); err != nil {
+			return entity.CreateFolderResponse{}, err
+		}
+	}
+
+	subtree, err := f.fileStoreService.FolderSubtree(ctx, request.FolderID)
+	if err != nil {
+		return entity.CreateFolderResponse{}, err
+	}
+
+	var size int64
+	for _, file := range subtree.Files {
+		size += file.Size
+	}
+
+	if err := f.CheckQuota(ctx, request.CreatedBy, size); err != nil {
+		return entity.CreateFolderResponse{}, err
+	}
+
+	return f.fileStoreService.CopyFolder(ctx, request)
+}
+
+// RestoreFolder restores a deleted folder with everything deleted together with it, its parent has to exist.
+// It takes the editor role the user held on the folder when it was deleted.
+func (f *FilesStoreUseCase) RestoreFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.RestoreFolderResponse, error) {
+	if err := f.requireDeletedRole(ctx, folderID, access, entity.FileRoleEditor); err != nil {
+		return entity.RestoreFolderResponse{}, err
+	}
+
+	folder, err := f.fileStoreService.DeletedFolder(ctx, folderID)
+	if err != nil {
+		return entity.RestoreFolderResponse{}, err
+	}
+
+	if folder.ParentID != nil {
+		if _, err := f.fileStoreService.GetFolder(ctx, *folder.ParentID); errors.Is(err, sql.ErrNoRows) {
+			return entity.RestoreFolderResponse{}, ErrParentDeleted
+		} else if err != nil {
+			return entity.RestoreFolderResponse{}, err
+		}
+	}
+
+	return f.fileStoreService.RestoreFolder(ctx, folderID, access.UserID)
+}
+
+// checkFolderParent fails with ErrFolderCycle when the parent is the folder or below it,
+// and with sql.ErrNoRows when the parent does not exist
+func (f *FilesStoreUseCase) checkFolderParent(ctx context.Context, folderID, parentID int) error {
+	path, err := f.fileStoreService.FolderPath(ctx, parentID)
+	if err != nil {
+		return err
+	}
+
+	for _, folder := range path {
+		if *folder.ID == folderID {
+			return ErrFolderCycle
+		}
+	}
+
+	return nil
+}
+
+// checkMovedFolder tells why the repository refused a move checked by checkFolderParent: the repository checks
+// again while moving and refuses with sql.ErrNoRows when another move put the parent below the folder meanwhile
+func (f *FilesStoreUseCase) checkMovedFolder(ctx context.Context, folderID, parentID int, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		if cycleErr := f.checkFolderParent(ctx, folderID, parentID); errors.Is(cycleErr, ErrFolderCycle) {
+			return cycleErr
+		}
+	}
+
+	return err
+}
+
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
@@ -0,0 +1,37 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestMoveFolderOvertakenByAnotherMove(t *testing.T) {
+	service := newFakeService()
+	service.addFolder(1, nil, entity.FileRoleEditor)
+	service.addFolder(2, nil, entity.FileRoleEditor)
+
+	f := &FilesStoreUseCase{fileStoreService: service}
+	move := entity.MoveFolderRequest{FolderID: 1, ParentID: intPtr(2)}
+
+	// another move put the new parent below the folder after the check, the repository refuses the move
+	service.onMove = func() error {
+		service.addFolder(2, intPtr(1), entity.FileRoleEditor)
+		return sql.ErrNoRows
+	}
+
+	if _, err := f.MoveFolder(context.Background(), move, entity.FileAccess{UserID: 10}); !errors.Is(err, ErrFolderCycle) {
+		t.Errorf("a move into a folder moved below it meanwhile = %v, want ErrFolderCycle", err)
+	}
+
+	service.addFolder(2, nil, entity.FileRoleEditor)
+	service.onMove = func() error {
+		return sql.ErrNoRows
+	}
+
+	if _, err := f.MoveFolder(context.Background(), move, entity.FileAccess{UserID: 10}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("a move of a folder deleted meanwhile = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -2,20 +2,57 @@ package fileStore
 
 import (
 	"archv1/internal/entity"
+	"archv1/internal/pkg/storage"
 	"context"
+	"io"
 )
 
 type FilesStoreUseCaseI interface {
-	ListFolder(ctx context.Context, filter entity.Filter) (entity.ListFolderResponse, error)
-	GetFolder(ctx context.Context, folderID int) (entity.GetFolderResponse, error)
-	CreateFolder(ctx context.Context, folder entity.CreateFolderRequest) (entity.CreateFolderResponse, error)
-	UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest) (entity.UpdateFolderResponse, error)
-	UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest) (entity.UpdateFolderResponse, error)
-	DeleteFolder(ctx context.Context, folderID, deletedBy int) (entity.DeleteFolderResponse, error)
-	ListFile(ctx context.Context, filter entity.Filter) (entity.ListFileResponse, error)
-	GetFile(ctx context.Context, fileID int) (entity.GetFileResponse, error)
-	CreateFile(ctx context.Context, file entity.CreateFileRequest) (entity.CreateFileResponse, error)
-	UpdateFile(ctx context.Context, file entity.UpdateFileRequest) (entity.UpdateFileResponse, error)
-	UpdateFileColumns(ctx context.Context, fields entity.UpdateFileColumnsRequest) (entity.UpdateFileResponse, error)
-	DeleteFile(ctx context.Context, fileID, deletedBy int) (entity.DeleteFileResponse, error)
+	ListFolder(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFolderResponse, error)
+	GetFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.GetFolderResponse, error)
+	CreateFolder(ctx context.Context, folder entity.CreateFolderRequest, access entity.FileAccess) (entity.CreateFolderResponse, error)
+	UpdateFolder(ctx context.Context, folder entity.UpdateFolderRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error)
+	UpdateFolderColumns(ctx context.Context, fields entity.UpdateFolderColumnsRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error)
+	DeleteFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.DeleteFolderResponse, error)
+	FolderChildren(ctx context.Context, folderID *int, access entity.FileAccess) (entity.FolderChildrenResponse, error)
+	FolderPath(ctx context.Context, folderID int, access entity.FileAccess) (entity.FolderPathResponse, error)
+	FolderTree(ctx context.Context, folderID int, access entity.FileAccess) (entity.FolderTree, error)
+	MoveFolder(ctx context.Context, request entity.MoveFolderRequest, access entity.FileAccess) (entity.UpdateFolderResponse, error)
+	CopyFolder(ctx context.Context, request entity.CopyFolderRequest, access entity.FileAccess) (entity.CreateFolderResponse, error)
+	RestoreFolder(ctx context.Context, folderID int, access entity.FileAccess) (entity.RestoreFolderResponse, error)
+	ListFile(ctx context.Context, filter entity.Filter, access entity.FileAccess) (entity.ListFileResponse, error)
+	GetFile(ctx context.Context, fileID int, access entity.FileAccess) (entity.GetFileResponse, error)
+	CreateFile(ctx context.Context, file entity.CreateFileRequest, access entity.FileAccess) (entity.CreateFileResponse, error)
+	UpdateFile(ctx context.Context, file entity.UpdateFileRequest, access entity.FileAccess) (entity.UpdateFileResponse, error)
+	UpdateFileColumns(ctx context.Context, fields entity.UpdateFileColumnsRequest, access entity.FileAccess) (entity.UpdateFileResponse, error)
+	DeleteFile(ctx context.Context, fileID int, access entity.FileAccess) (entity.DeleteFileResponse, error)
+	UploadFile(ctx context.Context, upload entity.UploadFileRequest, body io.Reader) (entity.CreateFileResponse, error)
+	DiscardFile(ctx context.Context, fileID, deletedBy int) error
+	OpenFile(ctx context.Context, fileID int, access entity.FileAccess) (io.ReadCloser, storage.BlobInfo, error)
+	CreateUpload(ctx context.Context, request entity.CreateUploadRequest) (entity.UploadSession, error)
+	UploadSession(ctx context.Context, sessionID string, userID int) (entity.UploadSession, error)
+	UploadChunk(ctx context.Context, chunk entity.UploadChunkRequest) (entity.UploadSession, error)
+	CompleteUpload(ctx context.Context, sessionID string, userID int) (entity.CreateFileResponse, error)
+	AbortUpload(ctx context.Context, sessionID string, userID int) error
+	SweepUploads(ctx context.Context, limit int) (int, error)
+	CreateDownloadLink(ctx context.Context, request entity.DownloadLinkRequest) (entity.DownloadLinkResponse, error)
+	OpenDownload(ctx context.Context, token, ip string) (entity.Download, *storage.Seeker, error)
+	PurgeDownloadLinks(ctx context.Context) (int, error)
+	OpenVariant(ctx context.Context, request entity.VariantRequest) (entity.Download, *storage.Seeker, error)
+	StoreBlob(ctx context.Context, body io.Reader, size int64, mimeType string) (entity.Blob, error)
+	ReleaseBlob(ctx context.Context, key string) error
+	PurgeDeletedFiles(ctx context.Context, limit int) (int, error)
+	CollectBlobs(ctx context.Context, limit int) (int, error)
+	VerifyBlobs(ctx context.Context, limit int) (int, int, error)
+	ListAccess(ctx context.Context, target entity.AccessTarget, access entity.FileAccess) (entity.ListAccessResponse, error)
+	GrantAccess(ctx context.Context, request entity.GrantAccessRequest, access entity.FileAccess) (entity.AccessGrant, error)
+	RevokeAccess(ctx context.Context, target entity.AccessTarget, grantID int, access entity.FileAccess) error
+	CreateShareLink(ctx context.Context, request entity.CreateShareLinkRequest, access entity.FileAccess) (entity.ShareLink, error)
+	RevokeShareLink(ctx context.Context, target entity.AccessTarget, linkID int, access entity.FileAccess) error
+	OpenShare(ctx context.Context, token, password string) (entity.SharedItemResponse, error)
+	OpenSharedFile(ctx context.Context, token, password string, fileID int) (entity.Download, *storage.Seeker, error)
+	StorageUsage(ctx context.Context, userID int) (entity.StorageUsage, error)
+	StorageReport(ctx context.Context, limit int, access entity.FileAccess) (entity.StorageReportResponse, error)
+	SetStorageQuota(ctx context.Context, request entity.SetStorageQuotaRequest, access entity.FileAccess) (entity.StorageUsage, error)
+	CheckQuota(ctx context.Context, userID int, size int64) error
 }
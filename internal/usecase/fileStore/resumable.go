@@ -0,0 +1,388 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/storage"
+	"archv1/internal/pkg/upload"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// uploadCategory is the upload rule the resumable uploads are checked against
+const uploadCategory = "file"
+
+var (
+	ErrUploadOffset     = errors.New("the offset does not match the bytes received so far")
+	ErrUploadChecksum   = errors.New("the checksum does not match the chunk")
+	ErrUploadIncomplete = errors.New("the upload has not received all of its bytes yet")
+	ErrUploadBusy       = errors.New("the upload is being completed")
+	ErrInvalidChunk     = errors.New("the chunk is empty or runs past the size of the upload")
+)
+
+// CreateUpload opens a resumable upload, the size and name are checked against the file rules, the role on
+// the folder and the quota of the user up front so a client does not send a file that can never be completed
+func (f *FilesStoreUseCase) CreateUpload(ctx context.Context, request entity.CreateUploadRequest) (entity.UploadSession, error) {
+	if err := f.validator.Check(uploadCategory, request.Name, request.Size); err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	if request.FolderID != nil {
+		access := entity.FileAccess{UserID: request.UserID, Admin: request.Admin}
+		if err := f.requireRole(ctx, entity.AccessTarget{FolderID: request.FolderID}, access, entity.FileRoleEditor); err != nil {
+			return entity.UploadSession{}, err
+		}
+	}
+
+	if err := f.CheckQuota(ctx, request.UserID, request.Size); err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	return f.fileStoreService.CreateUploadSession(ctx, entity.UploadSession{
+		ID:        uuid.NewString(),
+		UserID:    request.UserID,
+		Name:      request.Name,
+		Size:      request.Size,
+		FolderID:  request.FolderID,
+		ExpiresAt: time.Now().Add(f.uploadTTL),
+	})
+}
+
+// UploadSession returns an unexpired upload of the user, the uploads of others are sql.ErrNoRows
+func (f *FilesStoreUseCase) UploadSession(ctx context.Context, sessionID string, userID int) (entity.UploadSession, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return entity.UploadSession{}, sql.ErrNoRows
+	}
+
+	session, err := f.fileStoreService.GetUploadSession(ctx, sessionID)
+	if err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	if session.UserID != userID {
+		return entity.UploadSession{}, sql.ErrNoRows
+	}
+
+	return session, nil
+}
+
+// UploadChunk stores the chunk at the offset the upload has reached and extends the expiry
+// of the upload. A retried chunk that was already stored is answered with ErrUploadOffset,
+// the client resumes from the offset of the session.
+func (f *FilesStoreUseCase) UploadChunk(ctx context.Context, chunk entity.UploadChunkRequest) (entity.UploadSession, error) {
+	session, err := f.UploadSession(ctx, chunk.SessionID, chunk.UserID)
+	if err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	if session.Status != entity.UploadOpen {
+		return session, ErrUploadBusy
+	}
+
+	if chunk.Offset != session.Received {
+		return session, ErrUploadOffset
+	}
+
+	size := int64(len(chunk.Data))
+	if size == 0 || chunk.Offset+size > session.Size {
+		return session, ErrInvalidChunk
+	}
+
+	sum := sha256.Sum256(chunk.Data)
+	checksum := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(checksum, chunk.Checksum) {
+		return session, ErrUploadChecksum
+	}
+
+	key := fmt.Sprintf("%s%s/%d-%s", storage.ChunksPrefix, session.ID, chunk.Offset, uuid.NewString())
+	if _, err := f.blobs.Put(ctx, key, bytes.NewReader(chunk.Data), size, "application/octet-stream"); err != nil {
+		return entity.UploadSession{}, err
+	}
+
+	session, err = f.fileStoreService.AddUploadChunk(ctx, entity.UploadChunk{
+		SessionID: chunk.SessionID,
+		Position:  chunk.Offset,
+		Size:      size,
+		Checksum:  checksum,
+		BlobKey:   key,
+	}, time.Now().Add(f.uploadTTL))
+	if err != nil {
+		_ = f.blobs.Delete(context.Background(), key)
+
+		// another chunk for the same offset got in first
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.UploadSession{}, ErrUploadOffset
+		}
+
+		return entity.UploadSession{}, err
+	}
+
+	return session, nil
+}
+
+// CompleteUpload inspects the received bytes like a form upload and stores them as a file of the
+// folder of the upload. A file the rules reject is dropped together with the upload, any other
+// failure leaves the upload open so completing can be retried.
+func (f *FilesStoreUseCase) CompleteUpload(ctx context.Context, sessionID string, userID int) (entity.CreateFileResponse, error) {
+	session, err := f.UploadSession(ctx, sessionID, userID)
+	if err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	if session.Status != entity.UploadOpen {
+		return entity.CreateFileResponse{}, ErrUploadBusy
+	}
+
+	if session.Received != session.Size {
+		return entity.CreateFileResponse{}, ErrUploadIncomplete
+	}
+
+	err = f.fileStoreService.SetUploadStatus(ctx, session.ID, entity.UploadOpen, entity.UploadCompleting)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CreateFileResponse{}, ErrUploadBusy
+		}
+
+		return entity.CreateFileResponse{}, err
+	}
+
+	response, err := f.completeUpload(ctx, session)
+	if err != nil {
+		_ = f.fileStoreService.SetUploadStatus(context.Background(), session.ID, entity.UploadCompleting, entity.UploadOpen)
+		return entity.CreateFileResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (f *FilesStoreUseCase) completeUpload(ctx context.Context, session entity.UploadSession) (entity.CreateFileResponse, error) {
+	chunks, err := f.fileStoreService.UploadChunks(ctx, session.ID)
+	if err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	head, err := f.readChunks(ctx, chunks, 0, min(session.Size, upload.ScanWindow))
+	if err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	tail := head
+	if session.Size > upload.ScanWindow {
+		if tail, err = f.readChunks(ctx, chunks, session.Size-upload.ScanWindow, upload.ScanWindow); err != nil {
+			return entity.CreateFileResponse{}, err
+		}
+	}
+
+	inspection, err := f.validator.InspectContent(uploadCategory, session.Name, session.Size, head, tail)
+	if err != nil {
+		f.discardUpload(ctx, session.ID, chunks)
+		return entity.CreateFileResponse{}, err
+	}
+
+	body := &chunkReader{ctx: ctx, blobs: f.blobs, chunks: chunks}
+	defer body.Close()
+
+	// the role on the folder was checked when the upload was created
+	response, err := f.uploadFile(ctx, entity.UploadFileRequest{
+		Name:      inspection.Name,
+		Size:      session.Size,
+		MimeType:  inspection.MimeType,
+		FolderID:  session.FolderID,
+		CreatedBy: session.UserID,
+	}, body)
+	if err != nil {
+		return entity.CreateFileResponse{}, err
+	}
+
+	f.discardUpload(ctx, session.ID, chunks)
+
+	return response, nil
+}
+
+// AbortUpload drops an upload of the user together with the chunks it received
+func (f *FilesStoreUseCase) AbortUpload(ctx context.Context, sessionID string, userID int) error {
+	session, err := f.UploadSession(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if session.Status != entity.UploadOpen {
+		return ErrUploadBusy
+	}
+
+	chunks, err := f.fileStoreService.UploadChunks(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	f.discardUpload(ctx, session.ID, chunks)
+
+	return nil
+}
+
+// SweepUploads drops up to limit expired uploads and returns how many it dropped. Chunks
+// older than the expiry that belong to no upload, left behind by failed deletes, go as well.
+func (f *FilesStoreUseCase) SweepUploads(ctx context.Context, limit int) (int, error) {
+	sessions, err := f.fileStoreService.ExpiredUploadSessions(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		chunks, err := f.fileStoreService.UploadChunks(ctx, session.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		f.discardUpload(ctx, session.ID, chunks)
+	}
+
+	return len(sessions), f.sweepOrphanChunks(ctx)
+}
+
+func (f *FilesStoreUseCase) sweepOrphanChunks(ctx context.Context) error {
+	blobs, err := f.blobs.List(ctx, storage.ChunksPrefix)
+	if err != nil {
+		return err
+	}
+
+	stale := make(map[string][]string)
+	for _, blob := range blobs {
+		if time.Since(blob.ModTime) < f.uploadTTL {
+			continue
+		}
+
+		sessionID, _, ok := strings.Cut(strings.TrimPrefix(blob.Key, storage.ChunksPrefix), "/")
+		if !ok {
+			continue
+		}
+
+		stale[sessionID] = append(stale[sessionID], blob.Key)
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	sessionIDs := make([]string, 0, len(stale))
+	for sessionID := range stale {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	existing, err := f.fileStoreService.ExistingUploadSessions(ctx, sessionIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range existing {
+		delete(stale, sessionID)
+	}
+
+	for _, keys := range stale {
+		for _, key := range keys {
+			if err := f.blobs.Delete(ctx, key); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// discardUpload deletes the upload before its chunks, a chunk that fails to delete is left to the sweep
+func (f *FilesStoreUseCase) discardUpload(ctx context.Context, sessionID string, chunks []entity.UploadChunk) {
+	if err := f.fileStoreService.DeleteUploadSession(ctx, sessionID); err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, chunk := range chunks {
+		if err := f.blobs.Delete(ctx, chunk.BlobKey); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// readChunks reads size bytes of the upload starting at offset
+func (f *FilesStoreUseCase) readChunks(ctx context.Context, chunks []entity.UploadChunk, offset, size int64) ([]byte, error) {
+	for len(chunks) > 0 && chunks[0].Position+chunks[0].Size <= offset {
+		chunks = chunks[1:]
+	}
+
+	if len(chunks) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	reader := &chunkReader{ctx: ctx, blobs: f.blobs, chunks: chunks}
+	defer reader.Close()
+
+	if _, err := io.CopyN(io.Discard, reader, offset-chunks[0].Position); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// chunkReader reads the chunks of an upload one after another, opening each blob only when it is reached
+type chunkReader struct {
+	ctx     context.Context
+	blobs   storage.BlobStore
+	chunks  []entity.UploadChunk
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+
+			body, _, err := r.blobs.Get(r.ctx, r.chunks[0].BlobKey)
+			if err != nil {
+				return 0, err
+			}
+
+			r.current, r.chunks = body, r.chunks[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+
+			if n == 0 {
+				continue
+			}
+
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	err := r.current.Close()
+	r.current = nil
+
+	return err
+}
@@ -0,0 +1,91 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// storeChunks stores the parts as the chunks of an upload, one after another
+func storeChunks(t *testing.T, blobs storage.BlobStore, parts ...string) []entity.UploadChunk {
+	t.Helper()
+
+	var (
+		chunks   []entity.UploadChunk
+		position int64
+	)
+
+	for i, part := range parts {
+		key := fmt.Sprintf("chunks/session/%d", i)
+		if _, err := blobs.Put(context.Background(), key, strings.NewReader(part), int64(len(part)), ""); err != nil {
+			t.Fatalf("storing the chunk %d: %v", i, err)
+		}
+
+		chunks = append(chunks, entity.UploadChunk{SessionID: "session", Position: position, Size: int64(len(part)), BlobKey: key})
+		position += int64(len(part))
+	}
+
+	return chunks
+}
+
+func TestReadChunks(t *testing.T) {
+	blobs := storage.NewLocalStore(t.TempDir())
+	f := &FilesStoreUseCase{blobs: blobs}
+	chunks := storeChunks(t, blobs, "hello", " ", "world!")
+
+	tests := []struct {
+		name    string
+		offset  int64
+		size    int64
+		want    string
+		wantErr error
+	}{
+		{name: "the first chunk", offset: 0, size: 5, want: "hello"},
+		{name: "inside a chunk", offset: 1, size: 3, want: "ell"},
+		{name: "across every chunk", offset: 3, size: 5, want: "lo wo"},
+		{name: "from the start of a chunk", offset: 5, size: 1, want: " "},
+		{name: "from the middle of a later chunk", offset: 8, size: 4, want: "rld!"},
+		{name: "the whole upload", offset: 0, size: 12, want: "hello world!"},
+		{name: "past the end", offset: 10, size: 5, wantErr: io.ErrUnexpectedEOF},
+		{name: "after the last chunk", offset: 12, size: 1, wantErr: io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		got, err := f.readChunks(context.Background(), chunks, tt.offset, tt.size)
+		if !errors.Is(err, tt.wantErr) || string(got) != tt.want {
+			t.Errorf("%s: readChunks(%d, %d) = %q, %v, want %q, %v", tt.name, tt.offset, tt.size, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestChunkReader(t *testing.T) {
+	blobs := storage.NewLocalStore(t.TempDir())
+	chunks := storeChunks(t, blobs, "ab", "c", "def")
+
+	got, err := io.ReadAll(iotest.OneByteReader(&chunkReader{ctx: context.Background(), blobs: blobs, chunks: chunks}))
+	if err != nil || string(got) != "abcdef" {
+		t.Fatalf("reading byte by byte = %q, %v, want abcdef", got, err)
+	}
+
+	if err := blobs.Delete(context.Background(), chunks[2].BlobKey); err != nil {
+		t.Fatalf("deleting the last chunk: %v", err)
+	}
+
+	reader := &chunkReader{ctx: context.Background(), blobs: blobs, chunks: chunks}
+	defer reader.Close()
+
+	head := make([]byte, 3)
+	if _, err := io.ReadFull(reader, head); err != nil || string(head) != "abc" {
+		t.Fatalf("the chunks before a missing one = %q, %v, want abc since a chunk is opened only when reached", head, err)
+	}
+
+	if _, err := io.ReadAll(reader); err == nil {
+		t.Errorf("reading into a missing chunk succeeded")
+	}
+}
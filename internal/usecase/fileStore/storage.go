@@ -0,0 +1,116 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("the storage quota would be exceeded")
+	ErrStorageAdmin  = errors.New("only the admins may see or change the storage of other users")
+	ErrInvalidQuota  = errors.New("property quota_bytes must not be negative")
+)
+
+const (
+	defaultStorageReportLimit = 20
+	maxStorageReportLimit     = 100
+)
+
+// StorageUsage returns what the user keeps in the file store against their quota
+func (f *FilesStoreUseCase) StorageUsage(ctx context.Context, userID int) (entity.StorageUsage, error) {
+	usage, err := f.fileStoreService.StorageUsage(ctx, userID)
+	if err != nil {
+		return entity.StorageUsage{}, err
+	}
+
+	f.applyQuota(&usage)
+
+	return usage, nil
+}
+
+// StorageReport lists the users keeping the most bytes to the admins
+func (f *FilesStoreUseCase) StorageReport(ctx context.Context, limit int, access entity.FileAccess) (entity.StorageReportResponse, error) {
+	if !access.Admin {
+		return entity.StorageReportResponse{}, ErrStorageAdmin
+	}
+
+	if limit <= 0 {
+		limit = defaultStorageReportLimit
+	}
+
+	report, err := f.fileStoreService.StorageReport(ctx, min(limit, maxStorageReportLimit))
+	if err != nil {
+		return entity.StorageReportResponse{}, err
+	}
+
+	for _, usage := range report.Users {
+		f.applyQuota(usage)
+	}
+
+	return report, nil
+}
+
+// SetStorageQuota sets the quota of a user on behalf of an admin and returns their usage against it,
+// the files they keep above a lowered quota stay but nothing new is accepted
+func (f *FilesStoreUseCase) SetStorageQuota(ctx context.Context, request entity.SetStorageQuotaRequest, access entity.FileAccess) (entity.StorageUsage, error) {
+	if !access.Admin {
+		return entity.StorageUsage{}, ErrStorageAdmin
+	}
+
+	if request.Quota != nil && *request.Quota < 0 {
+		return entity.StorageUsage{}, ErrInvalidQuota
+	}
+
+	if err := f.fileStoreService.SetStorageQuota(ctx, request); err != nil {
+		return entity.StorageUsage{}, err
+	}
+
+	return f.StorageUsage(ctx, request.UserID)
+}
+
+// CheckQuota fails with ErrQuotaExceeded when size more bytes would take the user over their quota.
+// The uploads are checked again when their file is recorded, this lets them stop before the bytes are stored.
+func (f *FilesStoreUseCase) CheckQuota(ctx context.Context, userID int, size int64) error {
+	usage, err := f.fileStoreService.StorageUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return f.quotaCheck(size)(usage)
+}
+
+// quotaCheck returns the check CreateFile runs on the usage of the creator while it is locked
+func (f *FilesStoreUseCase) quotaCheck(size int64) func(usage entity.StorageUsage) error {
+	return func(usage entity.StorageUsage) error {
+		f.applyQuota(&usage)
+
+		if usage.Quota == 0 || usage.UsedBytes+size <= usage.Quota {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %d of %d bytes are used and %d more are needed", ErrQuotaExceeded, usage.UsedBytes, usage.Quota, size)
+	}
+}
+
+// applyQuota fills in the quota of the user, the one set on them or else the one of their role, and what is left of it
+func (f *FilesStoreUseCase) applyQuota(usage *entity.StorageUsage) {
+	quota, ok := f.roleQuotas[usage.Role]
+	if !ok {
+		quota = f.defaultQuota
+	}
+
+	if usage.UserQuota != nil {
+		quota = *usage.UserQuota
+	}
+
+	usage.Quota = quota
+	if quota == 0 {
+		return
+	}
+
+	remaining := max(quota-usage.UsedBytes, 0)
+	usage.Remaining = &remaining
+	usage.OverQuota = usage.UsedBytes > quota
+}
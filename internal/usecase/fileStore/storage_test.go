@@ -0,0 +1,197 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/storage"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func (s *fakeService) AcquireBlob(_ context.Context, blob entity.Blob) (entity.Blob, error) {
+	s.refs[blob.Key]++
+
+	if s.onAcquire != nil {
+		s.onAcquire()
+	}
+
+	return blob, nil
+}
+
+func (s *fakeService) ReleaseBlob(_ context.Context, key string) error {
+	s.refs[key]--
+	return nil
+}
+
+// CreateFile runs the quota check on the usage and adds the file to it, as the repository does in one transaction
+func (s *fakeService) CreateFile(_ context.Context, file entity.CreateFileRequest, check func(usage entity.StorageUsage) error) (entity.CreateFileResponse, error) {
+	if check != nil {
+		if err := check(s.usage); err != nil {
+			return entity.CreateFileResponse{}, err
+		}
+	}
+
+	s.created = append(s.created, file)
+	s.usage.UsedBytes += file.Size
+	s.usage.FileCount++
+
+	return entity.CreateFileResponse{ID: len(s.created), Size: file.Size, CreatedBy: &file.CreatedBy}, nil
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func TestApplyQuota(t *testing.T) {
+	f := &FilesStoreUseCase{
+		roleQuotas:   map[string]int64{"admin": 0, "user": 1000},
+		defaultQuota: 500,
+	}
+
+	tests := []struct {
+		name          string
+		usage         entity.StorageUsage
+		wantQuota     int64
+		wantRemaining *int64
+		wantOver      bool
+	}{
+		{name: "the quota of the role", usage: entity.StorageUsage{Role: "user", UsedBytes: 200}, wantQuota: 1000, wantRemaining: int64Ptr(800)},
+		{name: "the default quota", usage: entity.StorageUsage{Role: "guest", UsedBytes: 200}, wantQuota: 500, wantRemaining: int64Ptr(300)},
+		{name: "an unlimited role", usage: entity.StorageUsage{Role: "admin", UsedBytes: 1 << 40}},
+		{name: "the quota of the user wins", usage: entity.StorageUsage{Role: "user", UsedBytes: 200, UserQuota: int64Ptr(2000)}, wantQuota: 2000, wantRemaining: int64Ptr(1800)},
+		{name: "a lowered quota", usage: entity.StorageUsage{Role: "user", UsedBytes: 200, UserQuota: int64Ptr(100)}, wantQuota: 100, wantRemaining: int64Ptr(0), wantOver: true},
+		{name: "an unlimited user", usage: entity.StorageUsage{Role: "user", UsedBytes: 5000, UserQuota: int64Ptr(0)}},
+	}
+
+	for _, tt := range tests {
+		usage := tt.usage
+		f.applyQuota(&usage)
+
+		if usage.Quota != tt.wantQuota || usage.OverQuota != tt.wantOver || (usage.Remaining == nil) != (tt.wantRemaining == nil) ||
+			(usage.Remaining != nil && *usage.Remaining != *tt.wantRemaining) {
+			t.Errorf("%s: quota %d, remaining %v, over %t, want %d, %v, %t", tt.name, usage.Quota, usage.Remaining, usage.OverQuota,
+				tt.wantQuota, tt.wantRemaining, tt.wantOver)
+		}
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	service := newFakeService()
+	service.usage = entity.StorageUsage{Role: "user", UsedBytes: 900}
+
+	f := &FilesStoreUseCase{
+		fileStoreService: service,
+		roleQuotas:       map[string]int64{"user": 1000},
+	}
+
+	if err := f.CheckQuota(context.Background(), 1, 100); err != nil {
+		t.Errorf("filling the quota exactly: %v", err)
+	}
+
+	if err := f.CheckQuota(context.Background(), 1, 101); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("going a byte over the quota = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func newUploadUseCase(t *testing.T, service *fakeService, quota int64) *FilesStoreUseCase {
+	t.Helper()
+
+	return &FilesStoreUseCase{
+		fileStoreService: service,
+		blobs:            storage.NewLocalStore(t.TempDir()),
+		roleQuotas:       map[string]int64{"user": quota},
+	}
+}
+
+func uploadText(f *FilesStoreUseCase, content string) (entity.CreateFileResponse, error) {
+	return f.UploadFile(context.Background(), entity.UploadFileRequest{
+		Name:      "notes.txt",
+		Size:      int64(len(content)),
+		MimeType:  "text/plain",
+		CreatedBy: 1,
+	}, bytes.NewBufferString(content))
+}
+
+func TestUploadCountsAgainstTheQuota(t *testing.T) {
+	service := newFakeService()
+	service.usage = entity.StorageUsage{Role: "user"}
+	f := newUploadUseCase(t, service, 10)
+
+	if _, err := uploadText(f, "hello"); err != nil {
+		t.Fatalf("the first upload: %v", err)
+	}
+
+	if _, err := uploadText(f, "hello"); err != nil {
+		t.Fatalf("the same content again: %v", err)
+	}
+
+	if service.usage.UsedBytes != 10 || service.usage.FileCount != 2 {
+		t.Fatalf("the user keeps %d bytes in %d files, want each file counted in full", service.usage.UsedBytes, service.usage.FileCount)
+	}
+
+	if len(service.refs) != 1 {
+		t.Errorf("the same content was stored %d times, want once", len(service.refs))
+	}
+
+	if _, err := uploadText(f, "!"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("an upload over the quota = %v, want ErrQuotaExceeded", err)
+	}
+
+	if service.usage.UsedBytes != 10 || len(service.created) != 2 || len(service.refs) != 1 {
+		t.Errorf("a refused upload was stored or recorded")
+	}
+}
+
+func TestUploadIsCheckedAgainWhenRecorded(t *testing.T) {
+	service := newFakeService()
+	service.usage = entity.StorageUsage{Role: "user"}
+	f := newUploadUseCase(t, service, 10)
+
+	// another upload of the user is recorded while this one is stored
+	service.onAcquire = func() {
+		service.usage.UsedBytes = 8
+	}
+
+	if _, err := uploadText(f, "hello"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("an upload overtaken by another = %v, want ErrQuotaExceeded", err)
+	}
+
+	if len(service.created) != 0 {
+		t.Errorf("the file was recorded")
+	}
+
+	for key, refs := range service.refs {
+		if refs != 0 {
+			t.Errorf("the blob %s keeps %d references, want the one of the refused upload released", key, refs)
+		}
+	}
+}
+
+func TestCopyCountsAgainstTheQuota(t *testing.T) {
+	service := newFakeService()
+	service.addFolder(1, nil, entity.FileRoleViewer)
+	service.usage = entity.StorageUsage{Role: "user", UsedBytes: 600}
+	service.subtree = entity.FolderChildrenResponse{
+		Files: []*entity.GetFileResponse{{Size: 300}, {Size: 200}},
+	}
+
+	f := &FilesStoreUseCase{
+		fileStoreService: service,
+		roleQuotas:       map[string]int64{"user": 1000},
+	}
+
+	if _, err := f.CopyFolder(context.Background(), entity.CopyFolderRequest{FolderID: 1, CreatedBy: 10}, entity.FileAccess{UserID: 10}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("copying 500 bytes with 400 left = %v, want ErrQuotaExceeded", err)
+	}
+
+	if service.called("CopyFolder") {
+		t.Errorf("the folder was copied")
+	}
+
+	service.usage.UsedBytes = 500
+
+	if _, err := f.CopyFolder(context.Background(), entity.CopyFolderRequest{FolderID: 1, CreatedBy: 10}, entity.FileAccess{UserID: 10}); err != nil {
+		t.Fatalf("copying 500 bytes with 500 left: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package fileStore
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/imaging"
+	"archv1/internal/pkg/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+)
+
+// warmTimeout bounds making the preset variants of one upload
+const warmTimeout = 2 * time.Minute
+
+var (
+	ErrNotImage       = errors.New("the file is not a JPEG, PNG or GIF image")
+	ErrInvalidVariant = errors.New("properties w and h must not be negative or exceed the maximum side, fit must be contain or cover")
+)
+
+// variant is a validated VariantRequest, a zero side always comes with the contain fit so equal variants share a key
+type variant struct {
+	width  int
+	height int
+	fit    string
+	format string
+}
+
+// OpenVariant opens a resized copy of an image file. The copy is made on its first request and stored
+// next to the original, so later requests are served from the store. The copy is upright and carries no EXIF.
+func (f *FilesStoreUseCase) OpenVariant(ctx context.Context, request entity.VariantRequest) (entity.Download, *storage.Seeker, error) {
+	file, err := f.fileStoreService.GetFile(ctx, request.FileID)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	if err := f.checkFileAccess(ctx, file, request.UserID, request.Admin); err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	v, err := f.newVariant(file.MimeType, request.Width, request.Height, request.Fit, request.Format)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	blobKey, err := fileBlob(file)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	key, err := f.makeVariant(ctx, blobKey, v)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	content, err := storage.OpenSeeker(ctx, f.blobs, key)
+	if err != nil {
+		return entity.Download{}, nil, err
+	}
+
+	name := strings.TrimSuffix(file.Name, path.Ext(file.Name))
+
+	return entity.Download{
+		Name:     fmt.Sprintf("%s-%dx%d%s", name, v.width, v.height, imaging.Extension(v.format)),
+		MimeType: imaging.MimeType(v.format),
+		Inline:   true,
+	}, content, nil
+}
+
+func (f *FilesStoreUseCase) newVariant(mimeType string, width, height int, fit, format string) (variant, error) {
+	if !isImageSource(mimeType) {
+		return variant{}, ErrNotImage
+	}
+
+	if width < 0 || height < 0 || width > f.imageMaxSide || height > f.imageMaxSide {
+		return variant{}, ErrInvalidVariant
+	}
+
+	switch fit {
+	case "":
+		fit = imaging.FitContain
+	case imaging.FitContain, imaging.FitCover:
+	default:
+		return variant{}, ErrInvalidVariant
+	}
+
+	if width == 0 || height == 0 {
+		fit = imaging.FitContain
+	}
+
+	switch format {
+	case "":
+		format = imaging.OutputFormat(strings.TrimPrefix(mimeType, "image/"))
+	case "jpg":
+		format = imaging.FormatJPEG
+	case imaging.FormatJPEG, imaging.FormatPNG:
+	default:
+		return variant{}, imaging.ErrUnsupportedFormat
+	}
+
+	return variant{
+		width:  width,
+		height: height,
+		fit:    fit,
+		format: format,
+	}, nil
+}
+
+// makeVariant returns the key of the variant of the content stored under blobKey, making it when it is not stored
+// yet. Files with the same content share their variants. At most image_workers variants are made at once,
+// decoding a large image takes a lot of memory.
+func (f *FilesStoreUseCase) makeVariant(ctx context.Context, blobKey string, v variant) (string, error) {
+	key := variantKey(blobKey, v)
+
+	if _, err := f.blobs.Stat(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+		return key, err
+	}
+
+	select {
+	case f.imageSlots <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-f.imageSlots }()
+
+	// another request may have made it while this one waited
+	if _, err := f.blobs.Stat(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+		return key, err
+	}
+
+	blob, _, err := f.blobs.Get(ctx, blobKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(blob)
+	_ = blob.Close()
+	if err != nil {
+		return "", err
+	}
+
+	img, _, err := imaging.Decode(data, f.imageMaxPixels)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, imaging.Resize(img, v.width, v.height, v.fit), v.format, f.jpegQuality); err != nil {
+		return "", err
+	}
+
+	if _, err := f.blobs.Put(ctx, key, &buf, int64(buf.Len()), imaging.MimeType(v.format)); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// warmVariants makes the preset variants of a new upload, so the first page showing the image does not wait for them
+func (f *FilesStoreUseCase) warmVariants(blobKey, mimeType string) {
+	ctx, cancel := context.WithTimeout(context.Background(), warmTimeout)
+	defer cancel()
+
+	for _, preset := range f.imagePresets {
+		v, err := f.newVariant(mimeType, preset.Width, preset.Height, preset.Fit, preset.Format)
+		if err != nil {
+			log.Printf("image preset %dx%d: %v", preset.Width, preset.Height, err)
+			continue
+		}
+
+		if _, err := f.makeVariant(ctx, blobKey, v); err != nil {
+			log.Printf("image variant of %s: %v", blobKey, err)
+			return
+		}
+	}
+}
+
+// deleteVariants removes the variants made of the content stored under blobKey
+func (f *FilesStoreUseCase) deleteVariants(ctx context.Context, blobKey string) error {
+	blobs, err := f.blobs.List(ctx, variantDir(blobKey))
+	if err != nil {
+		return err
+	}
+
+	for _, blob := range blobs {
+		if err := f.blobs.Delete(ctx, blob.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isImageSource(mimeType string) bool {
+	return mimeType == "image/jpeg" || mimeType == "image/png" || mimeType == "image/gif"
+}
+
+func variantDir(blobKey string) string {
+	return storage.VariantsPrefix + strings.TrimSuffix(blobKey, path.Ext(blobKey)) + "/"
+}
+
+func variantKey(blobKey string, v variant) string {
+	return fmt.Sprintf("%s%dx%d-%s%s", variantDir(blobKey), v.width, v.height, v.fit, imaging.Extension(v.format))
+}
@@ -79,6 +79,10 @@ func (u *MenuUseCase) Delete(ctx context.Context, menuID, deletedBy int) (entity
 	return menuResponse, nil
 }
 
-func (u *MenuUseCase) AddFile(ctx context.Context, fileURL string, menuID, updatedBy int) error {
-	return u.menuService.AddFile(ctx, fileURL, menuID, updatedBy)
+func (u *MenuUseCase) AttachFile(ctx context.Context, menuID, fileID, attachedBy int) error {
+	return u.menuService.AttachFile(ctx, menuID, fileID, attachedBy)
+}
+
+func (u *MenuUseCase) DetachFile(ctx context.Context, menuID, fileID int) error {
+	return u.menuService.DetachFile(ctx, menuID, fileID)
 }
@@ -0,0 +1,13 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"context"
+)
+
+type NotificationUseCaseI interface {
+	Notifications(ctx context.Context, userID int64) (entity.NotificationsResponse, error)
+	MarkRead(ctx context.Context, userID, chatID int64) error
+	MarkAllRead(ctx context.Context, userID int64) error
+	MuteChat(ctx context.Context, request entity.MuteChatRequest) (entity.MuteSetting, error)
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/service/chat"
+	"archv1/internal/service/notification"
+	chatUseCase "archv1/internal/usecase/chat"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrInvalidDuration = errors.New("property duration must not be negative")
+
+type NotificationUseCase struct {
+	notificationService notification.NotificationServiceI
+	chatService         chat.ChatServiceI
+	chatUseCase         chatUseCase.ChatUseCaseI
+}
+
+func NewNotificationUseCase(notificationService notification.NotificationServiceI, chatService chat.ChatServiceI, chatUseCase chatUseCase.ChatUseCaseI) NotificationUseCaseI {
+	return &NotificationUseCase{
+		notificationService: notificationService,
+		chatService:         chatService,
+		chatUseCase:         chatUseCase,
+	}
+}
+
+// Notifications returns the inbox of the user
+func (n *NotificationUseCase) Notifications(ctx context.Context, userID int64) (entity.NotificationsResponse, error) {
+	response, err := n.notificationService.Notifications(ctx, userID)
+	if err != nil {
+		return entity.NotificationsResponse{}, err
+	}
+
+	if response.Notifications == nil {
+		response.Notifications = []entity.Notification{}
+	}
+
+	return response, nil
+}
+
+// MarkRead reads the chat up to its latest message, which clears its inbox entry and sends the read receipt
+func (n *NotificationUseCase) MarkRead(ctx context.Context, userID, chatID int64) error {
+	return n.chatUseCase.MarkRead(ctx, userID, chatID, 0)
+}
+
+// MarkAllRead reads every chat of the user inbox. The entries of chats the user left or that are gone
+// are cleared up to their latest message without a read receipt, so they do not fail the others.
+func (n *NotificationUseCase) MarkAllRead(ctx context.Context, userID int64) error {
+	response, err := n.notificationService.Notifications(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range response.Notifications {
+		chatID := int64(notification.ChatID)
+
+		err := n.chatUseCase.MarkRead(ctx, userID, chatID, 0)
+		if errors.Is(err, chatUseCase.ErrForbidden) || errors.Is(err, sql.ErrNoRows) {
+			err = n.notificationService.MarkRead(ctx, userID, chatID, int64(notification.LatestMessageID))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *NotificationUseCase) MuteChat(ctx context.Context, request entity.MuteChatRequest) (entity.MuteSetting, error) {
+	if request.Duration < 0 {
+		return entity.MuteSetting{}, ErrInvalidDuration
+	}
+
+	if err := n.participant(ctx, request.UserID, request.ChatID); err != nil {
+		return entity.MuteSetting{}, err
+	}
+
+	return n.notificationService.MuteChat(ctx, request)
+}
+
+func (n *NotificationUseCase) participant(ctx context.Context, userID, chatID int64) error {
+	if _, err := n.chatService.GetChat(ctx, chatID); err != nil {
+		return err
+	}
+
+	isParticipant, err := n.chatService.IsParticipant(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !isParticipant {
+		return chatUseCase.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/service/notification"
+	chatUseCase "archv1/internal/usecase/chat"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+type fakeNotificationService struct {
+	notification.NotificationServiceI
+
+	inbox []entity.Notification
+	// cleared holds the read cursor each chat of the inbox was cleared up to
+	cleared map[int64]int64
+}
+
+func (s *fakeNotificationService) Notifications(context.Context, int64) (entity.NotificationsResponse, error) {
+	return entity.NotificationsResponse{Notifications: s.inbox}, nil
+}
+
+func (s *fakeNotificationService) MarkRead(_ context.Context, _, chatID, lastRead int64) error {
+	s.cleared[chatID] = lastRead
+	return nil
+}
+
+// fakeChatUseCase reads the chats with no error in errs
+type fakeChatUseCase struct {
+	chatUseCase.ChatUseCaseI
+
+	errs map[int64]error
+	read []int64
+}
+
+func (c *fakeChatUseCase) MarkRead(_ context.Context, _, chatID, _ int64) error {
+	if err := c.errs[chatID]; err != nil {
+		return err
+	}
+
+	c.read = append(c.read, chatID)
+
+	return nil
+}
+
+func TestMarkAllReadSkipsLeftChats(t *testing.T) {
+	service := &fakeNotificationService{
+		inbox: []entity.Notification{
+			{ChatID: 1, LatestMessageID: 10},
+			{ChatID: 2, LatestMessageID: 20},
+			{ChatID: 3, LatestMessageID: 30},
+			{ChatID: 4, LatestMessageID: 40},
+		},
+		cleared: map[int64]int64{},
+	}
+
+	chats := &fakeChatUseCase{errs: map[int64]error{
+		2: chatUseCase.ErrForbidden,
+		3: sql.ErrNoRows,
+	}}
+
+	n := &NotificationUseCase{notificationService: service, chatUseCase: chats}
+
+	if err := n.MarkAllRead(context.Background(), 7); err != nil {
+		t.Fatalf("MarkAllRead: %v", err)
+	}
+
+	if len(chats.read) != 2 || chats.read[0] != 1 || chats.read[1] != 4 {
+		t.Errorf("read the chats %v, want 1 and 4", chats.read)
+	}
+
+	if len(service.cleared) != 2 || service.cleared[2] != 20 || service.cleared[3] != 30 {
+		t.Errorf("cleared %v, want the left chat 2 and the gone chat 3 up to their latest messages", service.cleared)
+	}
+
+	failure := errors.New("database is down")
+	chats.errs[4] = failure
+
+	if err := n.MarkAllRead(context.Background(), 7); !errors.Is(err, failure) {
+		t.Errorf("MarkAllRead with a failing chat = %v, want its error", err)
+	}
+}
@@ -12,5 +12,6 @@ type PostUseCaseI interface {
 	Update(ctx context.Context, menu entity.UpdatePostRequest) (entity.UpdatePostResponse, error)
 	UpdateColumns(ctx context.Context, fields entity.UpdatePostColumnsRequest) (entity.UpdatePostResponse, error)
 	Delete(ctx context.Context, menuID, deletedBy int) (entity.DeletePostResponse, error)
-	AddFile(ctx context.Context, fileURL string, postID, updatedBy int) error
+	AttachFile(ctx context.Context, postID, fileID, attachedBy int) error
+	DetachFile(ctx context.Context, postID, fileID int) error
 }
@@ -40,6 +40,10 @@ func (r *PostUseCase) Delete(ctx context.Context, postID, deletedBy int) (entity
 	return r.postService.Delete(ctx, postID, deletedBy)
 }
 
-func (r *PostUseCase) AddFile(ctx context.Context, fileURL string, postID, updatedBy int) error {
-	return r.postService.AddFile(ctx, fileURL, postID, updatedBy)
+func (r *PostUseCase) AttachFile(ctx context.Context, postID, fileID, attachedBy int) error {
+	return r.postService.AttachFile(ctx, postID, fileID, attachedBy)
+}
+
+func (r *PostUseCase) DetachFile(ctx context.Context, postID, fileID int) error {
+	return r.postService.DetachFile(ctx, postID, fileID)
 }
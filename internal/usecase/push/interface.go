@@ -0,0 +1,20 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"context"
+)
+
+type PushUseCaseI interface {
+	VAPIDKey() (entity.VAPIDKeyResponse, error)
+	Subscribe(ctx context.Context, request entity.SubscribePushRequest) (entity.PushSubscription, error)
+	Subscriptions(ctx context.Context, userID int64) (entity.PushSubscriptionsResponse, error)
+	Unsubscribe(ctx context.Context, userID, subscriptionID int64) error
+	CreateWebhook(ctx context.Context, request entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error)
+	Webhooks(ctx context.Context, userID int64) (entity.WebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID int64) error
+	QuietHours(ctx context.Context, userID int64) (entity.QuietHours, error)
+	SetQuietHours(ctx context.Context, quietHours entity.QuietHours) (entity.QuietHours, error)
+	DeadJobs(ctx context.Context, userID int64) (entity.PushJobsResponse, error)
+	RetryJob(ctx context.Context, userID, jobID int64) error
+}
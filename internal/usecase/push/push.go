@@ -0,0 +1,155 @@
+package push
+
+import (
+	"archv1/internal/entity"
+	"archv1/internal/pkg/config"
+	"archv1/internal/push"
+	pushService "archv1/internal/service/push"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrPushDisabled        = errors.New("web push is not configured")
+	ErrInvalidSubscription = errors.New("property endpoint must be a public https URL and keys must be the browser subscription keys")
+	ErrSubscriptionTaken   = errors.New("the endpoint is subscribed by another user")
+	ErrInvalidWebhook      = errors.New("property url must be a public https URL")
+	ErrInvalidQuietHours   = errors.New("properties start and end must be HH:MM and timezone an IANA time zone")
+)
+
+type PushUseCase struct {
+	pushService  pushService.PushServiceI
+	publicKey    string
+	allowHTTP    bool
+	allowPrivate bool
+}
+
+func NewPushUseCase(pushService pushService.PushServiceI, cfg *config.Config) PushUseCaseI {
+	return &PushUseCase{
+		pushService:  pushService,
+		publicKey:    cfg.VAPIDPublicKey,
+		allowHTTP:    cfg.PushAllowHTTP,
+		allowPrivate: cfg.PushAllowPrivate,
+	}
+}
+
+// VAPIDKey returns the application server key browsers subscribe with
+func (p *PushUseCase) VAPIDKey() (entity.VAPIDKeyResponse, error) {
+	if p.publicKey == "" {
+		return entity.VAPIDKeyResponse{}, ErrPushDisabled
+	}
+
+	return entity.VAPIDKeyResponse{PublicKey: p.publicKey}, nil
+}
+
+func (p *PushUseCase) Subscribe(ctx context.Context, request entity.SubscribePushRequest) (entity.PushSubscription, error) {
+	if p.publicKey == "" {
+		return entity.PushSubscription{}, ErrPushDisabled
+	}
+
+	if !push.ValidURL(request.Endpoint, p.allowHTTP, p.allowPrivate) || !push.ValidKeys(request.Keys) {
+		return entity.PushSubscription{}, ErrInvalidSubscription
+	}
+
+	subscription, err := p.pushService.Subscribe(ctx, request)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.PushSubscription{}, ErrSubscriptionTaken
+	}
+
+	return subscription, err
+}
+
+func (p *PushUseCase) Subscriptions(ctx context.Context, userID int64) (entity.PushSubscriptionsResponse, error) {
+	subscriptions, err := p.pushService.Subscriptions(ctx, userID)
+	if err != nil {
+		return entity.PushSubscriptionsResponse{}, err
+	}
+
+	return entity.PushSubscriptionsResponse{Subscriptions: subscriptions}, nil
+}
+
+func (p *PushUseCase) Unsubscribe(ctx context.Context, userID, subscriptionID int64) error {
+	return p.pushService.Unsubscribe(ctx, userID, subscriptionID)
+}
+
+// CreateWebhook registers the URL with a new signing secret
+func (p *PushUseCase) CreateWebhook(ctx context.Context, request entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error) {
+	if !push.ValidURL(request.URL, p.allowHTTP, p.allowPrivate) {
+		return entity.CreateWebhookResponse{}, ErrInvalidWebhook
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return entity.CreateWebhookResponse{}, err
+	}
+
+	webhook, err := p.pushService.CreateWebhook(ctx, request, hex.EncodeToString(secret))
+	if err != nil {
+		return entity.CreateWebhookResponse{}, err
+	}
+
+	return entity.CreateWebhookResponse{
+		Webhook: webhook,
+		Secret:  hex.EncodeToString(secret),
+	}, nil
+}
+
+func (p *PushUseCase) Webhooks(ctx context.Context, userID int64) (entity.WebhooksResponse, error) {
+	webhooks, err := p.pushService.Webhooks(ctx, userID)
+	if err != nil {
+		return entity.WebhooksResponse{}, err
+	}
+
+	return entity.WebhooksResponse{Webhooks: webhooks}, nil
+}
+
+func (p *PushUseCase) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	return p.pushService.DeleteWebhook(ctx, userID, webhookID)
+}
+
+func (p *PushUseCase) QuietHours(ctx context.Context, userID int64) (entity.QuietHours, error) {
+	return p.pushService.QuietHours(ctx, userID)
+}
+
+// SetQuietHours validates and stores the quiet hours, the time zone defaults to UTC
+func (p *PushUseCase) SetQuietHours(ctx context.Context, quietHours entity.QuietHours) (entity.QuietHours, error) {
+	if quietHours.Timezone == "" {
+		quietHours.Timezone = "UTC"
+	}
+
+	if _, err := time.Parse("15:04", quietHours.Start); err != nil {
+		return entity.QuietHours{}, ErrInvalidQuietHours
+	}
+
+	if _, err := time.Parse("15:04", quietHours.End); err != nil {
+		return entity.QuietHours{}, ErrInvalidQuietHours
+	}
+
+	if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+		return entity.QuietHours{}, ErrInvalidQuietHours
+	}
+
+	if err := p.pushService.SetQuietHours(ctx, quietHours); err != nil {
+		return entity.QuietHours{}, err
+	}
+
+	return quietHours, nil
+}
+
+// DeadJobs returns the deliveries of the user which failed every attempt
+func (p *PushUseCase) DeadJobs(ctx context.Context, userID int64) (entity.PushJobsResponse, error) {
+	jobs, err := p.pushService.DeadJobs(ctx, userID)
+	if err != nil {
+		return entity.PushJobsResponse{}, err
+	}
+
+	return entity.PushJobsResponse{Jobs: jobs}, nil
+}
+
+func (p *PushUseCase) RetryJob(ctx context.Context, userID, jobID int64) error {
+	return p.pushService.RequeueJob(ctx, userID, jobID)
+}
@@ -0,0 +1,36 @@
+package websocket
+
+import (
+	"archv1/internal/entity"
+	"encoding/json"
+	"github.com/google/uuid"
+)
+
+// NewFrame wraps the payload into a server frame of the given type
+func NewFrame(frameType, correlationID string, payload interface{}) (entity.Frame, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return entity.Frame{}, err
+	}
+
+	return entity.Frame{
+		Version:       entity.ProtocolVersion,
+		Type:          frameType,
+		ID:            uuid.NewString(),
+		CorrelationID: correlationID,
+		Payload:       data,
+	}, nil
+}
+
+// ErrorFrame builds an error frame answering the command with the given ID
+func ErrorFrame(correlationID, code, message string) entity.Frame {
+	frame, err := NewFrame(entity.FrameError, correlationID, entity.ErrorPayload{
+		Code:    code,
+		Message: message,
+	})
+	if err != nil {
+		return entity.Frame{Version: entity.ProtocolVersion, Type: entity.FrameError, CorrelationID: correlationID}
+	}
+
+	return frame
+}
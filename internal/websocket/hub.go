@@ -1,44 +1,315 @@
 package websocket
 
 import (
+	"archv1/internal/entity"
+	"context"
+	"encoding/json"
 	"github.com/gorilla/websocket"
 	"log"
 	"net/http"
+	"sync"
 )
 
+// Handler processes command frames sent by clients and returns the reply frame
+type Handler interface {
+	HandleFrame(ctx context.Context, conn *Connection, frame entity.Frame) entity.Frame
+}
+
+// EventLog stores the events sent to a user, so a reconnecting client can replay what it missed
+type EventLog interface {
+	Append(ctx context.Context, userID int, frame entity.Frame) (entity.Frame, error)
+	Since(ctx context.Context, userID int, seq int64) ([]entity.Frame, int64, bool, error)
+	Latest(ctx context.Context, userID int) (int64, error)
+}
+
 type Connection struct {
-	WS       *websocket.Conn
-	Send     chan []byte
-	Username string
+	WS     *websocket.Conn
+	Send   chan []byte
+	UserID int
+
+	mu    sync.RWMutex
+	chats map[int]bool
+
+	// the live events sent while the backlog is read wait in pending and are queued after it
+	resumeMu sync.Mutex
+	resuming bool
+	pending  []entity.Frame
+}
+
+// Subscribe marks chats as opened on this connection, so it receives their ephemeral events
+func (c *Connection) Subscribe(chatIDs ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, chatID := range chatIDs {
+		c.chats[chatID] = true
+	}
+}
+
+func (c *Connection) Subscribed(chatID int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.chats[chatID]
+}
+
+// queue sends the frame, or holds it back while the backlog of the connection is read.
+// It reports false when the connection is too slow to take it.
+func (c *Connection) queue(frame entity.Frame, data []byte) bool {
+	c.resumeMu.Lock()
+	defer c.resumeMu.Unlock()
+
+	if c.resuming {
+		c.pending = append(c.pending, frame)
+		return true
+	}
+
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		return false
+	}
 }
 
 type Hub struct {
-	Connections map[*Connection]bool
-	Broadcast   chan []byte
+	mu          sync.RWMutex
+	connections map[int]map[*Connection]bool
+	handler     Handler
+	events      EventLog
+
+	signalsMu sync.Mutex
+	signals   map[signalKey]*signal
 }
 
-func NewHub() *Hub {
+func NewHub(events EventLog) *Hub {
 	return &Hub{
-		Connections: make(map[*Connection]bool),
-		Broadcast:   make(chan []byte),
+		connections: make(map[int]map[*Connection]bool),
+		events:      events,
+		signals:     make(map[signalKey]*signal),
+	}
+}
+
+func (h *Hub) SetHandler(handler Handler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handler = handler
+}
+
+// register adds the connection and queues the session frame followed by the events missed since the
+// given sequence number. The backlog is read without holding the hub, the live events sent meanwhile are
+// held by the connection and queued after the backlog, leaving out the ones the backlog already holds.
+func (h *Hub) register(ws *websocket.Conn, userID int, since *int64) *Connection {
+	conn := &Connection{
+		WS:       ws,
+		UserID:   userID,
+		chats:    make(map[int]bool),
+		resuming: true,
+	}
+
+	h.mu.Lock()
+	if h.connections[userID] == nil {
+		h.connections[userID] = make(map[*Connection]bool)
+	}
+	h.connections[userID][conn] = true
+	h.mu.Unlock()
+
+	backlog, session := h.backlog(userID, since)
+
+	conn.resumeMu.Lock()
+	defer conn.resumeMu.Unlock()
+
+	var replayed int64
+	if since != nil {
+		replayed = *since
+	}
+	if len(backlog) > 0 {
+		replayed = backlog[len(backlog)-1].Seq
 	}
+
+	frames := backlog
+	for _, frame := range conn.pending {
+		if frame.Seq == 0 || frame.Seq > replayed {
+			frames = append(frames, frame)
+		}
+	}
+
+	sessionFrame, err := NewFrame(entity.FrameSession, "", session)
+	if err != nil {
+		log.Println(err)
+	} else {
+		frames = append([]entity.Frame{sessionFrame}, frames...)
+	}
+
+	conn.Send = make(chan []byte, len(frames)+256)
+	conn.resuming, conn.pending = false, nil
+
+	for _, frame := range frames {
+		data, err := json.Marshal(frame)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		conn.Send <- data
+	}
+
+	return conn
 }
 
-func (h *Hub) Run() {
-	for {
-		message := <-h.Broadcast
-		for conn := range h.Connections {
-			select {
-			case conn.Send <- message:
-			default:
-				close(conn.Send)
-				delete(h.Connections, conn)
+// backlog returns the events of the user stored after since with the session frame describing them,
+// only the latest sequence number when since is nil
+func (h *Hub) backlog(userID int, since *int64) ([]entity.Frame, entity.SessionEvent) {
+	var (
+		backlog []entity.Frame
+		session entity.SessionEvent
+		err     error
+	)
+
+	if since == nil {
+		session.Seq, err = h.events.Latest(context.Background(), userID)
+	} else {
+		var complete bool
+		backlog, session.Seq, complete, err = h.events.Since(context.Background(), userID, *since)
+		session.SyncRequired = !complete
+		session.Replayed = len(backlog)
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, entity.SessionEvent{SyncRequired: since != nil}
+	}
+
+	return backlog, session
+}
+
+// unregister closes the connection, the signals of the user end with the last connection
+func (h *Hub) unregister(conn *Connection) {
+	h.mu.Lock()
+
+	if !h.connections[conn.UserID][conn] {
+		h.mu.Unlock()
+		return
+	}
+
+	delete(h.connections[conn.UserID], conn)
+
+	offline := len(h.connections[conn.UserID]) == 0
+	if offline {
+		delete(h.connections, conn.UserID)
+	}
+	close(conn.Send)
+
+	h.mu.Unlock()
+
+	if offline {
+		h.stopUserSignals(conn.UserID)
+	}
+}
+
+// IsOnline reports whether the user has at least one open connection
+func (h *Hub) IsOnline(userID int) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.connections[userID]) > 0
+}
+
+// SendToUser stores the frame in the user's event log and delivers it to every connection of the user.
+// It reports whether any connection received it.
+func (h *Hub) SendToUser(userID int, frame entity.Frame) bool {
+	frame, err := h.events.Append(context.Background(), userID, frame)
+	if err != nil {
+		log.Println(err)
+	}
+
+	return h.deliver([]int{userID}, frame, func(*Connection) bool { return true })
+}
+
+// SendToChat delivers the frame to connections of the given users which subscribed to the chat.
+// These frames are ephemeral and are not stored in the event log.
+func (h *Hub) SendToChat(chatID int, userIDs []int, frame entity.Frame) bool {
+	return h.deliver(userIDs, frame, func(conn *Connection) bool { return conn.Subscribed(chatID) })
+}
+
+func (h *Hub) deliver(userIDs []int, frame entity.Frame, accept func(*Connection) bool) bool {
+	if frame.Version == 0 {
+		frame.Version = entity.ProtocolVersion
+	}
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+
+	var (
+		isSend bool
+		slow   []*Connection
+	)
+
+	h.mu.RLock()
+	for _, userID := range userIDs {
+		for conn := range h.connections[userID] {
+			if !accept(conn) {
+				continue
+			}
+
+			if conn.queue(frame, data) {
+				isSend = true
+			} else {
+				slow = append(slow, conn)
 			}
 		}
 	}
+	h.mu.RUnlock()
+
+	for _, conn := range slow {
+		h.unregister(conn)
+	}
+
+	return isSend
+}
+
+func (h *Hub) reply(conn *Connection, frame entity.Frame) {
+	h.mu.RLock()
+	handler := h.handler
+	h.mu.RUnlock()
+
+	var response entity.Frame
+	if handler == nil {
+		response = ErrorFrame(frame.ID, entity.ErrCodeUnsupported, "commands are not accepted")
+	} else {
+		response = handler.HandleFrame(context.Background(), conn, frame)
+	}
+
+	h.write(conn, response)
 }
 
-func HandleConnection(h *Hub, w http.ResponseWriter, r *http.Request) {
+func (h *Hub) write(conn *Connection, frame entity.Frame) {
+	frame.Version = entity.ProtocolVersion
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !h.connections[conn.UserID][conn] {
+		return
+	}
+
+	select {
+	case conn.Send <- data:
+	default:
+	}
+}
+
+// HandleConnection upgrades the request and serves the user's connection.
+// When since is set, events stored after that sequence number are replayed first.
+func HandleConnection(h *Hub, w http.ResponseWriter, r *http.Request, userID int, since *int64) {
 	socketUpgrade := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
@@ -47,51 +318,41 @@ func HandleConnection(h *Hub, w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	username := r.URL.Query().Get("username")
-
-	for connection := range h.Connections {
-		if connection.Username == username {
-			delete(h.Connections, connection)
-			err := connection.WS.Close()
-			if err != nil {
-				return
-			}
-		}
-	}
-
 	conn, err := socketUpgrade.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
-	connection := &Connection{
-		WS:       conn,
-		Send:     make(chan []byte),
-		Username: username,
-	}
-
-	h.Connections[connection] = true
+	connection := h.register(conn, userID, since)
 
 	go func() {
-		defer func() {
-			delete(h.Connections, connection)
-			err := connection.WS.Close()
-			if err != nil {
-				return
-			}
-		}()
+		defer h.unregister(connection)
 
 		for {
 			_, message, err := connection.WS.ReadMessage()
 			if err != nil {
 				break
 			}
-			h.Broadcast <- message
+
+			var frame entity.Frame
+			if err := json.Unmarshal(message, &frame); err != nil {
+				h.write(connection, ErrorFrame("", entity.ErrCodeBadRequest, "malformed frame"))
+				continue
+			}
+
+			h.reply(connection, frame)
 		}
 	}()
 
 	go func() {
+		defer func() {
+			err := connection.WS.Close()
+			if err != nil {
+				return
+			}
+		}()
+
 		for msg := range connection.Send {
 			if err := connection.WS.WriteMessage(websocket.TextMessage, msg); err != nil {
 				break
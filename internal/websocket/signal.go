@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"archv1/internal/entity"
+	"log"
+	"time"
+)
+
+const (
+	// signalInterval is the least time between two fan-outs of the same signal, repeats in between only keep it alive
+	signalInterval = 3 * time.Second
+	// signalTTL is how long a signal lasts without being refreshed
+	signalTTL = 6 * time.Second
+)
+
+type signalKey struct {
+	userID int
+	chatID int
+}
+
+// signal is an active chat signal of a user, it lives in memory only
+type signal struct {
+	action     string
+	sentAt     time.Time
+	recipients []int
+	timer      *time.Timer
+}
+
+// Signal starts, refreshes or stops a chat signal of the user. The signal is sent to the chat connections
+// of the recipients, which are only looked up when the signal has to be sent. Repeating an active signal
+// within signalInterval only extends it, a signal which is not refreshed expires after signalTTL.
+func (h *Hub) Signal(chatID, userID int, action string, recipients func() ([]int, error)) error {
+	key := signalKey{userID: userID, chatID: chatID}
+
+	if action == entity.SignalStopped {
+		h.stopSignal(key, nil)
+		return nil
+	}
+
+	h.signalsMu.Lock()
+	if current := h.signals[key]; current != nil && current.action == action && time.Since(current.sentAt) < signalInterval {
+		current.timer.Reset(signalTTL)
+		h.signalsMu.Unlock()
+		return nil
+	}
+	h.signalsMu.Unlock()
+
+	userIDs, err := recipients()
+	if err != nil {
+		return err
+	}
+
+	started := &signal{
+		action:     action,
+		sentAt:     time.Now(),
+		recipients: userIDs,
+	}
+	started.timer = time.AfterFunc(signalTTL, func() { h.stopSignal(key, started) })
+
+	h.signalsMu.Lock()
+	if current := h.signals[key]; current != nil {
+		current.timer.Stop()
+	}
+	h.signals[key] = started
+	h.signalsMu.Unlock()
+
+	h.sendSignal(key, action, userIDs)
+
+	return nil
+}
+
+// stopSignal ends the active signal of the key, or only the given one when it is still active
+func (h *Hub) stopSignal(key signalKey, only *signal) {
+	h.signalsMu.Lock()
+	current := h.signals[key]
+	if current == nil || only != nil && current != only {
+		h.signalsMu.Unlock()
+		return
+	}
+
+	current.timer.Stop()
+	delete(h.signals, key)
+	h.signalsMu.Unlock()
+
+	h.sendSignal(key, entity.SignalStopped, current.recipients)
+}
+
+// stopUserSignals ends the signals of a user who has no connection left
+func (h *Hub) stopUserSignals(userID int) {
+	h.signalsMu.Lock()
+	var keys []signalKey
+	for key := range h.signals {
+		if key.userID == userID {
+			keys = append(keys, key)
+		}
+	}
+	h.signalsMu.Unlock()
+
+	for _, key := range keys {
+		h.stopSignal(key, nil)
+	}
+}
+
+func (h *Hub) sendSignal(key signalKey, action string, recipients []int) {
+	event := entity.TypingEvent{
+		ChatID: key.chatID,
+		UserID: key.userID,
+		Action: action,
+	}
+
+	if action != entity.SignalStopped {
+		event.ExpiresIn = int(signalTTL / time.Second)
+	}
+
+	frame, err := NewFrame(entity.FrameTyping, "", event)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	h.SendToChat(key.chatID, recipients, frame)
+}